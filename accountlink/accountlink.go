@@ -0,0 +1,117 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package accountlink exchanges the tokens bank linking vendors return after a customer signs
+// into their bank for the routing and account numbers of the accounts they chose. Each vendor
+// is a Provider so services can switch or combine vendors without changing how linked accounts
+// are handled.
+//
+//	accounts, err := accountlink.Exchange(ctx, provider, publicToken)
+//	for _, acct := range accounts {
+//		// save acct.RoutingNumber, acct.AccountNumber and acct.Type
+//	}
+//
+// MockProvider links accounts in tests without calling a vendor.
+package accountlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/moov-io/base/validate"
+)
+
+var (
+	// ErrInvalidToken is returned by providers for tokens which are unknown, expired or were
+	// already exchanged
+	ErrInvalidToken = errors.New("invalid account link token")
+)
+
+// AccountType is the kind of a linked Account
+type AccountType string
+
+const (
+	Checking AccountType = "checking"
+	Savings  AccountType = "savings"
+)
+
+// Account is a bank account the customer linked
+type Account struct {
+	RoutingNumber string      `json:"routingNumber"`
+	AccountNumber string      `json:"accountNumber"`
+	Type          AccountType `json:"type"`
+
+	// HolderName and InstitutionName are returned by providers which know them
+	HolderName      string `json:"holderName,omitempty"`
+	InstitutionName string `json:"institutionName,omitempty"`
+}
+
+var (
+	routingNumberRe = regexp.MustCompile(`^\d{9}$`)
+	accountNumberRe = regexp.MustCompile(`^\d{4,17}$`)
+)
+
+// Validate checks the account's numbers and type
+func (a Account) Validate() error {
+	v := validate.New()
+	v.Field("routingNumber", validate.Required(a.RoutingNumber), validate.Match(a.RoutingNumber, routingNumberRe), validate.RoutingNumber(a.RoutingNumber))
+	v.Field("accountNumber", validate.Required(a.AccountNumber), validate.Match(a.AccountNumber, accountNumberRe))
+	v.Field("type", validate.OneOf(a.Type, Checking, Savings))
+	return v.Err()
+}
+
+// Mask returns the last four digits of the account number for display
+func (a Account) Mask() string {
+	if len(a.AccountNumber) <= 4 {
+		return a.AccountNumber
+	}
+	return a.AccountNumber[len(a.AccountNumber)-4:]
+}
+
+// Provider is a bank linking vendor
+type Provider interface {
+	// Name identifies the vendor in errors and logs
+	Name() string
+
+	// Exchange returns the accounts linked with token, or ErrInvalidToken. Vendors' tokens
+	// can usually only be exchanged once.
+	Exchange(ctx context.Context, token string) ([]Account, error)
+}
+
+// ProviderError is returned by Exchange when a provider fails or returns an invalid account
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("accountlink: %s: %v", e.Provider, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Exchange returns the accounts p linked with token after validating each of them, so vendors
+// returning malformed account numbers are caught before they're saved.
+func Exchange(ctx context.Context, p Provider, token string) ([]Account, error) {
+	if token == "" {
+		return nil, &ProviderError{Provider: p.Name(), Err: ErrInvalidToken}
+	}
+	accounts, err := p.Exchange(ctx, token)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Err: err}
+	}
+	if len(accounts) == 0 {
+		return nil, &ProviderError{Provider: p.Name(), Err: errors.New("no accounts linked")}
+	}
+	for i := range accounts {
+		if err := accounts[i].Validate(); err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Err: fmt.Errorf("account %d: %w", i, err)}
+		}
+	}
+	return accounts, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package accountlink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testAccount = Account{
+	RoutingNumber:   "231380104",
+	AccountNumber:   "123456789",
+	Type:            Checking,
+	InstitutionName: "Citadel Federal Credit Union",
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+	provider := NewMockProvider()
+
+	savings := testAccount
+	savings.AccountNumber, savings.Type = "987654", Savings
+	token := provider.Link(testAccount, savings)
+
+	accounts, err := Exchange(ctx, provider, token)
+	require.NoError(t, err)
+	require.Equal(t, []Account{testAccount, savings}, accounts)
+
+	// tokens are single use
+	_, err = Exchange(ctx, provider, token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.ErrorContains(t, err, "accountlink: mock: invalid account link token")
+
+	_, err = Exchange(ctx, provider, "")
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = Exchange(ctx, provider, provider.Link())
+	require.ErrorContains(t, err, "no accounts linked")
+
+	provider.Err = errors.New("vendor unavailable")
+	_, err = Exchange(ctx, provider, provider.Link(testAccount))
+	var perr *ProviderError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "mock", perr.Provider)
+	require.ErrorIs(t, err, provider.Err)
+}
+
+func TestExchange__Invalid(t *testing.T) {
+	provider := NewMockProvider()
+
+	bad := testAccount
+	bad.RoutingNumber = "231380105"
+	_, err := Exchange(context.Background(), provider, provider.Link(testAccount, bad))
+	require.ErrorContains(t, err, "account 1: /routingNumber: has an invalid check digit")
+}
+
+func TestAccount(t *testing.T) {
+	require.NoError(t, testAccount.Validate())
+	require.Equal(t, "6789", testAccount.Mask())
+	require.Equal(t, "123", Account{AccountNumber: "123"}.Mask())
+
+	err := Account{RoutingNumber: "1234567890", AccountNumber: "12", Type: "loan"}.Validate()
+	require.ErrorContains(t, err, "/routingNumber: must match")
+	require.ErrorContains(t, err, "/accountNumber: must match")
+	require.ErrorContains(t, err, "/type: must be one of checking, savings")
+
+	require.ErrorContains(t, Account{}.Validate(), "/routingNumber: is required")
+}
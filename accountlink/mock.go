@@ -0,0 +1,58 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package accountlink
+
+import (
+	"context"
+	"sync"
+
+	"github.com/moov-io/base/randx"
+)
+
+// MockProvider is a Provider for tests. Link returns a token for accounts which can be
+// exchanged once, like a vendor's public token.
+type MockProvider struct {
+	// Err is returned by every Exchange when set
+	Err error
+
+	mu     sync.Mutex
+	tokens map[string][]Account
+}
+
+// NewMockProvider returns a MockProvider without any linked accounts
+func NewMockProvider() *MockProvider {
+	return &MockProvider{tokens: make(map[string][]Account)}
+}
+
+// Name returns "mock"
+func (p *MockProvider) Name() string {
+	return "mock"
+}
+
+// Link returns a token which exchanges for accounts
+func (p *MockProvider) Link(accounts ...Account) string {
+	token := "mock-" + randx.String(24, "0123456789abcdefghijklmnopqrstuvwxyz")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens[token] = append([]Account(nil), accounts...)
+	return token
+}
+
+// Exchange returns and forgets the accounts linked with token
+func (p *MockProvider) Exchange(ctx context.Context, token string) ([]Account, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	accounts, ok := p.tokens[token]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	delete(p.tokens, token)
+	return accounts, nil
+}
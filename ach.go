@@ -0,0 +1,72 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ACHCenturyPivot is the first two digit year read as the 1900s by ParseACHDate. Years below it
+// are in the 2000s, so "691231" is 2069-12-31 and "700101" is 1970-01-01.
+const ACHCenturyPivot = 70
+
+// ParseACHDate reads the YYMMDD date and optional HHMM time fields of an ACH file (i.e. the File
+// Creation Date and Time) as wall clock time in loc. America/New_York is used when loc is nil.
+//
+// Unlike parsing the fields with time.Parse, invalid dates such as "210230" are rejected rather than
+// read as the zero time or normalized into the next month.
+func ParseACHDate(yymmdd, hhmm string, loc *time.Location) (Time, error) {
+	if loc == nil {
+		loc = eastern()
+	}
+	if len(yymmdd) != 6 {
+		return Time{}, fmt.Errorf("invalid ACH date %q: must be YYMMDD", yymmdd)
+	}
+	yy, err1 := digits(yymmdd[0:2])
+	mm, err2 := digits(yymmdd[2:4])
+	dd, err3 := digits(yymmdd[4:6])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return Time{}, fmt.Errorf("invalid ACH date %q: must be YYMMDD", yymmdd)
+	}
+
+	year := 2000 + yy
+	if yy >= ACHCenturyPivot {
+		year = 1900 + yy
+	}
+	if mm < 1 || mm > 12 || dd < 1 || dd > daysIn(time.Month(mm), year) {
+		return Time{}, fmt.Errorf("invalid ACH date %q: no such day", yymmdd)
+	}
+
+	var hour, minute int
+	if hhmm != "" {
+		if len(hhmm) != 4 {
+			return Time{}, fmt.Errorf("invalid ACH time %q: must be HHMM", hhmm)
+		}
+		h, err1 := digits(hhmm[0:2])
+		m, err2 := digits(hhmm[2:4])
+		if err1 != nil || err2 != nil || h > 23 || m > 59 {
+			return Time{}, fmt.Errorf("invalid ACH time %q: must be HHMM", hhmm)
+		}
+		hour, minute = h, m
+	}
+
+	return NewTime(time.Date(year, time.Month(mm), dd, hour, minute, 0, 0, loc)), nil
+}
+
+// digits parses s when it's only ASCII digits
+func digits(s string) (int, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
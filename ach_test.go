@@ -0,0 +1,66 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseACHDate(t *testing.T) {
+	tests := []struct {
+		yymmdd, hhmm string
+		expected     time.Time
+	}{
+		{"210304", "1530", time.Date(2021, time.March, 4, 15, 30, 0, 0, est)},
+		{"210304", "", time.Date(2021, time.March, 4, 0, 0, 0, 0, est)},
+		{"200229", "0000", time.Date(2020, time.February, 29, 0, 0, 0, 0, est)},
+		{"691231", "2359", time.Date(2069, time.December, 31, 23, 59, 0, 0, est)},
+		{"700101", "0001", time.Date(1970, time.January, 1, 0, 1, 0, 0, est)},
+	}
+	for _, test := range tests {
+		got, err := ParseACHDate(test.yymmdd, test.hhmm, nil)
+		if err != nil {
+			t.Errorf("%s %s: %v", test.yymmdd, test.hhmm, err)
+			continue
+		}
+		if !got.Time.Equal(test.expected) {
+			t.Errorf("%s %s: expected %v, got %v", test.yymmdd, test.hhmm, test.expected, got.Time)
+		}
+	}
+
+	// the wall clock is read in loc
+	got, err := ParseACHDate("210304", "1530", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2021, time.March, 4, 15, 30, 0, 0, time.UTC); !got.Time.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got.Time)
+	}
+}
+
+func TestParseACHDate__Invalid(t *testing.T) {
+	tests := []struct {
+		yymmdd, hhmm string
+	}{
+		{"", ""},
+		{"000000", ""},
+		{"2103", ""},
+		{"2103041", ""},
+		{"21-304", ""},
+		{"211304", ""},
+		{"210230", ""},
+		{"210229", ""},
+		{"210304", "2400"},
+		{"210304", "1260"},
+		{"210304", "930"},
+		{"210304", "+930"},
+	}
+	for _, test := range tests {
+		if got, err := ParseACHDate(test.yymmdd, test.hhmm, nil); err == nil {
+			t.Errorf("%q %q: expected error, got %v", test.yymmdd, test.hhmm, got)
+		}
+	}
+}
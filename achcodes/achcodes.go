@@ -0,0 +1,107 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package achcodes is the registry of Nacha return reason codes (R01 through R85) and
+// notification of change codes (C01 through C69).
+//
+//	code, ok := achcodes.LookupReturn(entry.Addenda99.ReturnCode)
+//	if ok && code.Category == achcodes.Unauthorized {
+//		// counts towards the unauthorized return rate
+//	}
+//
+// Windows are settlement.ReturnWindow values, so a code's window can be passed to
+// settlement.Calculate for its deadline.
+package achcodes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/moov-io/base/settlement"
+)
+
+// Category groups return codes the way Nacha's return rate thresholds do
+type Category string
+
+const (
+	// Administrative returns (R02, R03, R04) count towards the 3% administrative return rate
+	Administrative Category = "administrative"
+
+	// Unauthorized returns (R05, R07, R10, R11, R29, R51) count towards the 0.5% unauthorized return rate
+	Unauthorized Category = "unauthorized"
+
+	// Dishonored returns are sent by the ODFI refusing a return
+	Dishonored Category = "dishonored"
+
+	// Contested returns are sent by the RDFI contesting a dishonored return
+	Contested Category = "contested"
+
+	// Other is every other return, such as R01 insufficient funds
+	Other Category = "other"
+)
+
+// ReturnCode describes a return reason code
+type ReturnCode struct {
+	Code        string   `json:"code"`
+	Reason      string   `json:"reason"`
+	Description string   `json:"description"`
+	Category    Category `json:"category"`
+
+	// Window is how many banking days after settlement the return is due. It's zero when
+	// the window is agreed between the ODFI and RDFI instead (R06, R31).
+	Window settlement.ReturnWindow `json:"window"`
+}
+
+// ChangeCode describes a notification of change code
+type ChangeCode struct {
+	Code        string `json:"code"`
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+
+	// Refused is true for the codes an ODFI uses to refuse a notification of change (C61 through C69)
+	Refused bool `json:"refused"`
+
+	// Retired codes are no longer sent but may be found in older files
+	Retired bool `json:"retired,omitempty"`
+}
+
+// ChangeDeadline is how many banking days an Originator has to apply a notification of change,
+// or until its next entry to the Receiver when that's later.
+const ChangeDeadline = 6
+
+// LookupReturn returns the ReturnCode of code, such as "R01"
+func LookupReturn(code string) (ReturnCode, bool) {
+	r, ok := returnCodes[normalize(code)]
+	return r, ok
+}
+
+// LookupChange returns the ChangeCode of code, such as "C01"
+func LookupChange(code string) (ChangeCode, bool) {
+	c, ok := changeCodes[normalize(code)]
+	return c, ok
+}
+
+// Returns returns every ReturnCode ordered by code
+func Returns() []ReturnCode {
+	out := make([]ReturnCode, 0, len(returnCodes))
+	for _, r := range returnCodes {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// Changes returns every ChangeCode ordered by code
+func Changes() []ChangeCode {
+	out := make([]ChangeCode, 0, len(changeCodes))
+	for _, c := range changeCodes {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+func normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package achcodes
+
+import (
+	"testing"
+
+	"github.com/moov-io/base/settlement"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupReturn(t *testing.T) {
+	code, ok := LookupReturn(" r01")
+	require.True(t, ok)
+	require.Equal(t, "Insufficient Funds", code.Reason)
+	require.Equal(t, Other, code.Category)
+	require.Equal(t, settlement.StandardReturnWindow, code.Window)
+
+	code, ok = LookupReturn("R10")
+	require.True(t, ok)
+	require.Equal(t, Unauthorized, code.Category)
+	require.Equal(t, settlement.ExtendedReturnWindow, code.Window)
+
+	code, ok = LookupReturn("R06")
+	require.True(t, ok)
+	require.Zero(t, code.Window)
+
+	_, ok = LookupReturn("R99")
+	require.False(t, ok)
+}
+
+func TestReturns(t *testing.T) {
+	codes := Returns()
+	require.Len(t, codes, len(returnCodes))
+	require.Equal(t, "R01", codes[0].Code)
+	require.Equal(t, "R85", codes[len(codes)-1].Code)
+
+	counts := make(map[Category]int)
+	for _, c := range codes {
+		require.NotEmpty(t, c.Reason, c.Code)
+		require.NotEmpty(t, c.Description, c.Code)
+		counts[c.Category]++
+	}
+	require.Equal(t, 3, counts[Administrative])
+	require.Equal(t, 6, counts[Unauthorized])
+}
+
+func TestLookupChange(t *testing.T) {
+	code, ok := LookupChange("C02")
+	require.True(t, ok)
+	require.Equal(t, "Incorrect Routing Number", code.Reason)
+	require.False(t, code.Refused)
+
+	code, ok = LookupChange("c61")
+	require.True(t, ok)
+	require.True(t, code.Refused)
+
+	_, ok = LookupChange("C15")
+	require.False(t, ok)
+
+	changes := Changes()
+	require.Equal(t, "C01", changes[0].Code)
+	require.Equal(t, "C69", changes[len(changes)-1].Code)
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package achcodes
+
+import (
+	"github.com/moov-io/base/settlement"
+)
+
+const (
+	standard = settlement.StandardReturnWindow
+	extended = settlement.ExtendedReturnWindow
+
+	// dishonoredWindow is how many banking days an ODFI has to dishonor a return
+	dishonoredWindow settlement.ReturnWindow = 5
+
+	// agreed windows are set between the ODFI and RDFI
+	agreed settlement.ReturnWindow = 0
+)
+
+var returnCodes = indexReturns([]ReturnCode{
+	{"R01", "Insufficient Funds", "Available balance is not sufficient to cover the amount of the debit entry.", Other, standard},
+	{"R02", "Account Closed", "Previously active account has been closed by the customer or RDFI.", Administrative, standard},
+	{"R03", "No Account/Unable to Locate Account", "Account number structure is valid but doesn't match an individual or open account.", Administrative, standard},
+	{"R04", "Invalid Account Number Structure", "Account number structure is not valid.", Administrative, standard},
+	{"R05", "Unauthorized Debit to Consumer Account Using Corporate SEC Code", "A CCD or CTX debit was made to a consumer account without the Receiver's authorization.", Unauthorized, extended},
+	{"R06", "Returned per ODFI's Request", "The ODFI asked the RDFI to return an erroneous entry or one initiated under questionable circumstances.", Other, agreed},
+	{"R07", "Authorization Revoked by Customer", "The consumer revoked the authorization they gave the Originator.", Unauthorized, extended},
+	{"R08", "Payment Stopped", "The Receiver placed a stop payment order on the debit entry.", Other, standard},
+	{"R09", "Uncollected Funds", "The ledger balance is sufficient but the available balance is not.", Other, standard},
+	{"R10", "Customer Advises Unauthorized, Improper, Ineligible, or Part of an Incomplete Transaction", "The Receiver didn't authorize the debit, or it was improper or incomplete.", Unauthorized, extended},
+	{"R11", "Customer Advises Entry Not in Accordance with the Terms of the Authorization", "The debit was authorized but not made as the authorization describes, such as on the wrong date or for the wrong amount.", Unauthorized, extended},
+	{"R12", "Account Sold to Another DFI", "The branch holding the account was sold to another financial institution.", Other, standard},
+	{"R13", "Invalid ACH Routing Number", "The entry contains a routing number which is not valid.", Other, standard},
+	{"R14", "Representative Payee Deceased or Unable to Continue in That Capacity", "The representative payee is deceased or can no longer act for the beneficiary.", Other, standard},
+	{"R15", "Beneficiary or Account Holder Deceased", "The beneficiary or account holder is deceased.", Other, standard},
+	{"R16", "Account Frozen/Entry Returned per OFAC Instruction", "Funds are unavailable because of an action by the RDFI, a legal action or OFAC.", Other, standard},
+	{"R17", "File Record Edit Criteria/Entry with Invalid Account Number Initiated Under Questionable Circumstances", "Fields couldn't be processed, or the RDFI believes the entry was initiated under questionable circumstances.", Other, standard},
+	{"R18", "Improper Effective Entry Date", "The effective entry date is more than two banking days after the ACH operator processed it, or it's invalid.", Other, standard},
+	{"R19", "Amount Field Error", "The amount is zero for an entry which must have an amount, or it's not numeric.", Other, standard},
+	{"R20", "Non-Transaction Account", "ACH entries are not allowed to the account, such as some savings accounts.", Other, standard},
+	{"R21", "Invalid Company Identification", "The company identification is not valid for CIE and MTE entries.", Other, standard},
+	{"R22", "Invalid Individual ID Number", "The Receiver has told the RDFI the individual ID number in the entry is not correct.", Other, standard},
+	{"R23", "Credit Entry Refused by Receiver", "The Receiver refused the credit entry.", Other, standard},
+	{"R24", "Duplicate Entry", "The entry is a duplicate of one previously received by the RDFI.", Other, standard},
+	{"R25", "Addenda Error", "The addenda record indicator or addenda record is not valid.", Other, standard},
+	{"R26", "Mandatory Field Error", "A mandatory field has bad or missing data.", Other, standard},
+	{"R27", "Trace Number Error", "The original entry trace number is not valid or doesn't match.", Other, standard},
+	{"R28", "Routing Number Check Digit Error", "The check digit of the routing number is not valid.", Other, standard},
+	{"R29", "Corporate Customer Advises Not Authorized", "A corporate Receiver told the RDFI the entry was not authorized.", Unauthorized, standard},
+	{"R30", "RDFI Not Participant in Check Truncation Program", "The RDFI doesn't participate in a check truncation program.", Other, standard},
+	{"R31", "Permissible Return Entry (CCD and CTX only)", "The ODFI agreed to accept a late return of a CCD or CTX entry.", Other, agreed},
+	{"R32", "RDFI Non-Settlement", "The RDFI isn't able to settle the entry.", Other, standard},
+	{"R33", "Return of XCK Entry", "The RDFI is returning a destroyed check entry.", Other, extended},
+	{"R34", "Limited Participation DFI", "The RDFI's participation has been limited by a federal or state supervisor.", Other, standard},
+	{"R35", "Return of Improper Debit Entry", "Debits other than reversals are not allowed for CIE entries or to loan accounts.", Other, standard},
+	{"R36", "Return of Improper Credit Entry", "Credits other than reversals are not allowed for ARC, BOC, POP, RCK, TEL, WEB and XCK entries.", Other, standard},
+	{"R37", "Source Document Presented for Payment", "The check used for an ARC, BOC or POP entry was also presented for payment.", Other, extended},
+	{"R38", "Stop Payment on Source Document", "A stop payment was placed on the check used for an ARC or BOC entry.", Other, extended},
+	{"R39", "Improper Source Document/Source Document Presented for Payment", "The RDFI determined the check used for the entry is not eligible or was presented for payment.", Other, standard},
+	{"R40", "Return of ENR Entry by Federal Government Agency", "The federal agency is returning an automated enrollment entry.", Other, standard},
+	{"R41", "Invalid Transaction Code", "The transaction code of an ENR entry is not valid.", Other, standard},
+	{"R42", "Routing Number/Check Digit Error", "The routing number or check digit of an ENR entry is not valid.", Other, standard},
+	{"R43", "Invalid DFI Account Number", "The account number of an ENR entry is not valid.", Other, standard},
+	{"R44", "Invalid Individual ID Number/Identification Number", "The individual ID number of an ENR entry doesn't match the agency's records.", Other, standard},
+	{"R45", "Invalid Individual Name/Company Name", "The name of an ENR entry is not consistent with the agency's records.", Other, standard},
+	{"R46", "Invalid Representative Payee Indicator", "The representative payee indicator of an ENR entry is not valid.", Other, standard},
+	{"R47", "Duplicate Enrollment", "The ENR entry is a duplicate of one the agency previously received.", Other, standard},
+	{"R50", "State Law Affecting RCK Acceptance", "The RDFI is in a state which doesn't allow RCK entries, or the check was drawn on a non-transaction account.", Other, standard},
+	{"R51", "Item Related to RCK Entry is Ineligible or RCK Entry is Improper", "The check used for an RCK entry is not eligible, or the RCK entry was improper.", Unauthorized, extended},
+	{"R52", "Stop Payment on Item Related to RCK Entry", "A stop payment was placed on the check used for an RCK entry.", Other, extended},
+	{"R53", "Item and RCK Entry Presented for Payment", "The check used for an RCK entry was also presented for payment.", Other, extended},
+	{"R61", "Misrouted Return", "The return was sent to the wrong financial institution.", Dishonored, dishonoredWindow},
+	{"R62", "Return of Erroneous or Reversing Debit", "The ODFI is dishonoring a return of an erroneous or reversing debit.", Dishonored, dishonoredWindow},
+	{"R67", "Duplicate Return", "The ODFI received more than one return of the same entry.", Dishonored, dishonoredWindow},
+	{"R68", "Untimely Return", "The return was not sent within its return window.", Dishonored, dishonoredWindow},
+	{"R69", "Field Error(s)", "One or more fields of the return don't match the original entry.", Dishonored, dishonoredWindow},
+	{"R70", "Permissible Return Entry Not Accepted/Return Not Requested by ODFI", "The ODFI didn't agree to an R31 return or request an R06 return.", Dishonored, dishonoredWindow},
+	{"R71", "Misrouted Dishonored Return", "The dishonored return was sent to the wrong financial institution.", Contested, standard},
+	{"R72", "Untimely Dishonored Return", "The dishonored return was not sent within its window.", Contested, standard},
+	{"R73", "Timely Original Return", "The RDFI certifies the original return was sent within its window.", Contested, standard},
+	{"R74", "Corrected Return", "The RDFI is correcting a return dishonored for field errors.", Contested, standard},
+	{"R75", "Return Not a Duplicate", "The RDFI certifies the return dishonored as R67 was not a duplicate.", Contested, standard},
+	{"R76", "No Errors Found", "The RDFI certifies the return dishonored as R69 had no errors.", Contested, standard},
+	{"R77", "Non-Acceptance of R62 Dishonored Return", "The RDFI refuses an R62 dishonored return because the Receiver's funds aren't available.", Contested, standard},
+	{"R80", "IAT Entry Coding Error", "The IAT entry is missing information or is coded incorrectly.", Other, standard},
+	{"R81", "Non-Participant in IAT Program", "The gateway doesn't have an agreement with the foreign correspondent's ACH operator.", Other, standard},
+	{"R82", "Invalid Foreign Receiving DFI Identification", "The foreign receiving DFI identification is not valid.", Other, standard},
+	{"R83", "Foreign Receiving DFI Unable to Settle", "The IAT entry was returned because of the foreign receiving DFI's settlement.", Other, standard},
+	{"R84", "Entry Not Processed by Gateway", "The gateway didn't process an outbound IAT entry, such as because of OFAC sanctions.", Other, standard},
+	{"R85", "Incorrectly Coded Outbound International Payment", "The entry is an outbound international payment which should have been coded as IAT.", Other, standard},
+})
+
+var changeCodes = indexChanges([]ChangeCode{
+	{Code: "C01", Reason: "Incorrect DFI Account Number", Description: "The account number is incorrect or formatted incorrectly."},
+	{Code: "C02", Reason: "Incorrect Routing Number", Description: "The routing number is incorrect, such as after a merger or consolidation."},
+	{Code: "C03", Reason: "Incorrect Routing Number and Incorrect DFI Account Number", Description: "The routing number and account number are both incorrect."},
+	{Code: "C04", Reason: "Incorrect Individual Name/Receiving Company Name", Description: "The individual or company name is incorrect.", Retired: true},
+	{Code: "C05", Reason: "Incorrect Transaction Code", Description: "The transaction code is incorrect, such as a checking code for a savings account."},
+	{Code: "C06", Reason: "Incorrect DFI Account Number and Incorrect Transaction Code", Description: "The account number and transaction code are both incorrect."},
+	{Code: "C07", Reason: "Incorrect Routing Number, Incorrect DFI Account Number, and Incorrect Transaction Code", Description: "The routing number, account number and transaction code are all incorrect."},
+	{Code: "C08", Reason: "Incorrect Receiving DFI Identification (IAT Only)", Description: "The foreign receiving DFI identification of an IAT entry is incorrect."},
+	{Code: "C09", Reason: "Incorrect Individual Identification Number", Description: "The Receiver's identification number is incorrect."},
+	{Code: "C10", Reason: "Incorrect Company Name", Description: "The company name is incorrect.", Retired: true},
+	{Code: "C11", Reason: "Incorrect Company Identification", Description: "The company identification is incorrect.", Retired: true},
+	{Code: "C12", Reason: "Incorrect Company Name and Company Identification", Description: "The company name and identification are both incorrect.", Retired: true},
+	{Code: "C13", Reason: "Addenda Format Error", Description: "The addenda was formatted incorrectly but the entry was processed."},
+	{Code: "C14", Reason: "Incorrect SEC Code for Outbound International Payment", Description: "The entry is an outbound international payment and should have been coded as IAT."},
+	{Code: "C61", Reason: "Misrouted Notification of Change", Description: "The notification of change was sent to the wrong financial institution.", Refused: true},
+	{Code: "C62", Reason: "Incorrect Trace Number", Description: "The original entry trace number of the notification of change is incorrect.", Refused: true},
+	{Code: "C63", Reason: "Incorrect Company Identification Number", Description: "The company identification of the notification of change is incorrect.", Refused: true},
+	{Code: "C64", Reason: "Incorrect Individual Identification Number/Identification Number", Description: "The individual identification number of the notification of change is incorrect.", Refused: true},
+	{Code: "C65", Reason: "Incorrectly Formatted Corrected Data", Description: "The corrected data is not formatted as its change code requires.", Refused: true},
+	{Code: "C66", Reason: "Incorrect Discretionary Data", Description: "The discretionary data of the notification of change is incorrect.", Refused: true},
+	{Code: "C67", Reason: "Routing Number Not From Original Entry Detail Record", Description: "The routing number doesn't match the original entry.", Refused: true},
+	{Code: "C68", Reason: "DFI Account Number Not From Original Entry Detail Record", Description: "The account number doesn't match the original entry.", Refused: true},
+	{Code: "C69", Reason: "Incorrect Transaction Code", Description: "The transaction code of the notification of change is incorrect.", Refused: true},
+})
+
+func indexReturns(codes []ReturnCode) map[string]ReturnCode {
+	out := make(map[string]ReturnCode, len(codes))
+	for _, c := range codes {
+		out[c.Code] = c
+	}
+	return out
+}
+
+func indexChanges(codes []ChangeCode) map[string]ChangeCode {
+	out := make(map[string]ChangeCode, len(codes))
+	for _, c := range codes {
+		out[c.Code] = c
+	}
+	return out
+}
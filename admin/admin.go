@@ -16,29 +16,87 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/moov-io/base/calendar"
+	"github.com/moov-io/base/opts"
 )
 
 // NewServer returns an admin Server instance that handles Prometheus metrics
 // and pprof requests.
 // Callers can use ':0' to bind onto a random port and call BindAddr() for the address.
 func NewServer(addr string) *Server {
-	timeout, _ := time.ParseDuration("45s")
+	listener, _ := listen(addr)
+	return newServer(listener)
+}
+
+// Option configures a Server from New
+type Option = opts.Option[Server]
+
+// WithTimeout sets the read, write and idle timeouts of the HTTP server. It defaults to 45 seconds.
+func WithTimeout(d time.Duration) Option {
+	return opts.New("timeout", func(s *Server) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid timeout %v", d)
+		}
+		s.svc.ReadTimeout = d
+		s.svc.WriteTimeout = d
+		s.svc.IdleTimeout = d
+		return nil
+	})
+}
 
-	var listener net.Listener
+// WithHandler adds an http.HandlerFunc, see AddHandler.
+func WithHandler(path string, hf http.HandlerFunc) Option {
+	return opts.New("handler "+path, func(s *Server) error {
+		s.AddHandler(path, hf)
+		return nil
+	})
+}
+
+// WithVersion adds 'GET /version', see AddVersionHandler.
+func WithVersion(version string) Option {
+	return opts.New("version", func(s *Server) error {
+		s.AddVersionHandler(version)
+		return nil
+	})
+}
+
+// New returns an admin Server like NewServer which is configured by options. Unlike NewServer
+// an error is returned when addr can't be bound.
+func New(addr string, options ...Option) (*Server, error) {
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, fmt.Errorf("admin: %v", err)
+	}
+	svc := newServer(listener)
+	if err := opts.Apply(svc, options...); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return svc, nil
+}
+
+func listen(addr string) (net.Listener, error) {
 	if addr == ":0" {
-		listener, _ = net.Listen("tcp", "127.0.0.1:0")
-	} else {
-		listener, _ = net.Listen("tcp", addr)
+		return net.Listen("tcp", "127.0.0.1:0")
 	}
+	return net.Listen("tcp", addr)
+}
+
+func newServer(listener net.Listener) *Server {
+	timeout, _ := time.ParseDuration("45s")
 
 	router := handler()
 	svc := &Server{
 		router:   router,
 		listener: listener,
+		logLevel: &logLevelOverride{},
+		done:     make(chan struct{}),
 		svc: &http.Server{
 			Addr:         listener.Addr().String(),
 			Handler:      router,
@@ -50,6 +108,11 @@ func NewServer(addr string) *Server {
 
 	svc.AddHandler("/live", svc.livenessHandler())
 	svc.AddHandler("/ready", svc.readinessHandler())
+	svc.AddHandler("/debug/log-level", svc.logLevel.handler())
+	svc.AddHandler("/debug/runtime", runtimeHandler())
+	svc.AddHandler("/debug/databases", svc.databases.handler())
+	svc.AddHandler("/debug/config", svc.config.handler())
+	svc.AddHandler("/calendar.ics", calendar.Options{}.Handler())
 	return svc
 }
 
@@ -62,6 +125,14 @@ type Server struct {
 
 	liveChecks  []*healthCheck
 	readyChecks []*healthCheck
+	nonCritical map[string]bool
+
+	logLevel  *logLevelOverride
+	databases databasePools
+	config    configView
+
+	done     chan struct{}
+	shutdown sync.Once
 }
 
 // BindAddr returns the server's bind address. This is in Go's format so :8080 is valid.
@@ -85,6 +156,9 @@ func (s *Server) Shutdown() {
 	if s == nil || s.svc == nil {
 		return
 	}
+	s.shutdown.Do(func() {
+		close(s.done)
+	})
 	s.svc.Shutdown(context.TODO())
 }
 
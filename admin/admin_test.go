@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestAdmin__pprof(t *testing.T) {
@@ -146,3 +147,45 @@ func TestAdmin__BindAddr(t *testing.T) {
 		t.Errorf("bogus HTTP status code: %d", resp.StatusCode)
 	}
 }
+
+func TestAdmin__New(t *testing.T) {
+	svc, err := New(":0", WithTimeout(10*time.Second), WithVersion("v1.2.3"), WithHandler("/test/ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	if svc.svc.ReadTimeout != 10*time.Second {
+		t.Errorf("unexpected timeout: %v", svc.svc.ReadTimeout)
+	}
+
+	resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(bs) != "v1.2.3" {
+		t.Errorf("unexpected version: %q", bs)
+	}
+
+	resp, err = http.DefaultClient.Get("http://" + svc.BindAddr() + "/test/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("bogus HTTP status code: %d", resp.StatusCode)
+	}
+
+	// the address is already bound
+	if _, err := New(svc.BindAddr()); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := New(":0", WithTimeout(0)); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultCertificateWarning is how close to expiring a certificate is before TLSCertificateCheck fails
+	DefaultCertificateWarning = 14 * 24 * time.Hour
+
+	checkDialTimeout = 5 * time.Second
+)
+
+// SFTPCheck returns a readiness check which connects to addr (host:port) and expects an SSH server's
+// version banner. No authentication is attempted, so credentials aren't needed to probe connectivity.
+func SFTPCheck(addr string) func() error {
+	return func() error {
+		conn, err := net.DialTimeout("tcp", addr, checkDialTimeout)
+		if err != nil {
+			return fmt.Errorf("sftp: %v", err)
+		}
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(checkDialTimeout))
+
+		// Servers may send other lines before their version, see RFC 4253 section 4.2
+		scanner := bufio.NewScanner(conn)
+		for i := 0; i < 10 && scanner.Scan(); i++ {
+			if strings.HasPrefix(scanner.Text(), "SSH-") {
+				return nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("sftp: reading banner from %s: %v", addr, err)
+		}
+		return fmt.Errorf("sftp: %s did not send an SSH banner", addr)
+	}
+}
+
+// TLSCertificateCheck returns a readiness check which connects to addr (host:port) and fails when
+// the server's certificate expires within warnWithin (DefaultCertificateWarning when zero) or has
+// already expired.
+//
+// Register it with NonCritical to be warned about a partner's certificate without marking the
+// service unready.
+func TLSCertificateCheck(addr string, warnWithin time.Duration) func() error {
+	if warnWithin <= 0 {
+		warnWithin = DefaultCertificateWarning
+	}
+	return func() error {
+		dialer := &net.Dialer{Timeout: checkDialTimeout}
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+			// expired certificates fail verification, check them ourselves
+			InsecureSkipVerify: true,
+		})
+		if err != nil {
+			return fmt.Errorf("tls: %v", err)
+		}
+		defer conn.Close()
+
+		certs := conn.ConnectionState().PeerCertificates
+		if len(certs) == 0 {
+			return fmt.Errorf("tls: %s sent no certificates", addr)
+		}
+		return checkExpiry(certs[0].Subject.CommonName, certs[0].NotAfter, warnWithin, time.Now())
+	}
+}
+
+func checkExpiry(name string, notAfter time.Time, warnWithin time.Duration, now time.Time) error {
+	left := notAfter.Sub(now)
+	if left <= 0 {
+		return fmt.Errorf("tls: certificate %s expired on %s", name, notAfter.Format(time.RFC3339))
+	}
+	if left < warnWithin {
+		return fmt.Errorf("tls: certificate %s expires in %d days on %s", name, int(left.Hours()/24), notAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// DNSCheck returns a readiness check which fails when host does not resolve to any addresses.
+func DNSCheck(host string) func() error {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), checkDialTimeout)
+		defer cancel()
+
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("dns: %v", err)
+		}
+		if len(addrs) == 0 {
+			return errors.New("dns: no addresses found for " + host)
+		}
+		return nil
+	}
+}
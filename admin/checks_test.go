@@ -0,0 +1,132 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveBanner(t *testing.T, banner string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(banner))
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSFTPCheck(t *testing.T) {
+	addr := serveBanner(t, "SSH-2.0-OpenSSH_8.0\r\n")
+	if err := SFTPCheck(addr)(); err != nil {
+		t.Error(err)
+	}
+
+	addr = serveBanner(t, "220 ftp.example.com FTP server ready\r\n")
+	if err := SFTPCheck(addr)(); err == nil || !strings.Contains(err.Error(), "did not send an SSH banner") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// nothing listening
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	ln.Close()
+	if err := SFTPCheck(ln.Addr().String())(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTLSCertificateCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	addr := strings.TrimPrefix(server.URL, "https://")
+	if err := TLSCertificateCheck(addr, 0)(); err != nil {
+		t.Error(err)
+	}
+
+	// the test certificate expires in 2084
+	if err := TLSCertificateCheck(addr, 200*365*24*time.Hour)(); err == nil || !strings.Contains(err.Error(), "expires in") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckExpiry(t *testing.T) {
+	now := time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := checkExpiry("partner", now.Add(30*24*time.Hour), DefaultCertificateWarning, now); err != nil {
+		t.Error(err)
+	}
+	err := checkExpiry("partner", now.Add(10*24*time.Hour), DefaultCertificateWarning, now)
+	if err == nil || err.Error() != "tls: certificate partner expires in 10 days on 2020-12-11T10:00:00Z" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = checkExpiry("partner", now.Add(-time.Hour), DefaultCertificateWarning, now)
+	if err == nil || !strings.Contains(err.Error(), "expired on") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDNSCheck(t *testing.T) {
+	if err := DNSCheck("localhost")(); err != nil {
+		t.Error(err)
+	}
+	if err := DNSCheck("moov.invalid")(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestHealth__ReadyNonCritical(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	svc.AddReadinessCheck("database", func() error { return nil })
+	svc.AddReadinessCheckWithCriticality("partner-cert", NonCritical, func() error {
+		return errors.New("expires in 3 days")
+	})
+
+	get := func() (int, map[string]string) {
+		resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/ready")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var kv map[string]string
+		json.NewDecoder(resp.Body).Decode(&kv)
+		return resp.StatusCode, kv
+	}
+
+	status, kv := get()
+	if status != http.StatusOK || kv["database"] != "good" || kv["partner-cert"] != "expires in 3 days" {
+		t.Errorf("unexpected response: %d %v", status, kv)
+	}
+
+	svc.AddReadinessCheckWithCriticality("sftp", Critical, func() error {
+		return errors.New("connection refused")
+	})
+	if status, kv = get(); status != http.StatusBadRequest {
+		t.Errorf("unexpected response: %d %v", status, kv)
+	}
+}
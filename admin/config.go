@@ -0,0 +1,217 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/moov-io/base/config"
+	"github.com/moov-io/base/redact"
+)
+
+// configView holds the configuration served from 'GET /debug/config'
+type configView struct {
+	mu      sync.RWMutex
+	config  interface{}
+	sources map[string]config.Source
+}
+
+type configValue struct {
+	Key      string        `json:"key"`
+	Value    interface{}   `json:"value"`
+	Source   config.Source `json:"source,omitempty"`
+	Redacted bool          `json:"redacted,omitempty"`
+}
+
+// AddConfig registers the loaded configuration to be returned from 'GET /debug/config' along
+// with the source of each value, usually from config.Service.Sources.
+//
+// Keys are the dotted field names, or mapstructure tags, viper loads each value from and
+// elements of slices of structs are keyed by their index, such as "Partners.0.Password".
+// Values loaded from the secrets file are replaced with redact.Mask, as are those redact.Config
+// hides: fields tagged for the redact package and fields named like passwords, secrets, tokens and keys.
+func (s *Server) AddConfig(cfg interface{}, sources map[string]config.Source) {
+	s.config.mu.Lock()
+	defer s.config.mu.Unlock()
+
+	s.config.config = cfg
+	s.config.sources = sources
+}
+
+// handler serves 'GET /debug/config' with each configuration value as its dotted key, value and source
+func (v *configView) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		v.mu.RLock()
+		values, err := v.values()
+		v.mu.RUnlock()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, values)
+	}
+}
+
+func (v *configView) values() ([]configValue, error) {
+	values := []configValue{}
+	if v.config == nil {
+		return values, nil
+	}
+	v.flatten("", reflect.ValueOf(redact.Config(v.config)), &values)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+	return values, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// flatten walks doc by the names viper unmarshals into, the field name or its mapstructure tag,
+// so keys match those in sources. Slices of structs or maps are walked with their index as a
+// key part so each leaf is redacted on its own.
+func (v *configView) flatten(prefix string, doc reflect.Value, out *[]configValue) {
+	for doc.Kind() == reflect.Ptr || doc.Kind() == reflect.Interface {
+		if doc.IsNil() {
+			v.leaf(prefix, reflect.Value{}, out)
+			return
+		}
+		doc = doc.Elem()
+	}
+	if !doc.IsValid() || marshaler(doc.Type()) {
+		v.leaf(prefix, doc, out)
+		return
+	}
+
+	switch doc.Kind() {
+	case reflect.Struct:
+		found := false
+		for i := 0; i < doc.NumField(); i++ {
+			field := doc.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, squash := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			found = true
+			if squash {
+				v.flatten(prefix, doc.Field(i), out)
+			} else {
+				v.flatten(join(prefix, name), doc.Field(i), out)
+			}
+		}
+		if found {
+			return
+		}
+
+	case reflect.Map:
+		if doc.Len() > 0 {
+			iter := doc.MapRange()
+			for iter.Next() {
+				v.flatten(join(prefix, fmt.Sprintf("%v", iter.Key().Interface())), iter.Value(), out)
+			}
+			return
+		}
+
+	case reflect.Slice, reflect.Array:
+		if !scalars(doc) {
+			for i := 0; i < doc.Len(); i++ {
+				v.flatten(join(prefix, strconv.Itoa(i)), doc.Index(i), out)
+			}
+			return
+		}
+	}
+	v.leaf(prefix, doc, out)
+}
+
+func (v *configView) leaf(key string, doc reflect.Value, out *[]configValue) {
+	value := configValue{
+		Key:    key,
+		Source: v.source(key),
+	}
+	if doc.IsValid() && doc.CanInterface() {
+		value.Value = doc.Interface()
+	}
+	if value.Source == config.SourceSecrets || redact.SecretName(key) {
+		value.Value = redact.Mask
+		value.Redacted = true
+	}
+	*out = append(*out, value)
+}
+
+// source returns where key was loaded from. viper keeps slices as one key, so the closest
+// parent is used for their elements.
+func (v *configView) source(key string) config.Source {
+	key = strings.ToLower(key)
+	for {
+		if src, ok := v.sources[key]; ok {
+			return src
+		}
+		idx := strings.LastIndex(key, ".")
+		if idx < 0 {
+			return ""
+		}
+		key = key[:idx]
+	}
+}
+
+// fieldName returns the key viper unmarshals field from and if it's squashed into its parent
+func fieldName(field reflect.StructField) (string, bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	squash := strings.Contains(","+opts+",", ",squash,")
+	if name == "" {
+		name = field.Name
+	}
+	return name, squash
+}
+
+func marshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PtrTo(t).Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+// scalars reports whether every element of doc, a slice or array, is a plain value
+func scalars(doc reflect.Value) bool {
+	for i := 0; i < doc.Len(); i++ {
+		elem := doc.Index(i)
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				break
+			}
+			elem = elem.Elem()
+		}
+		switch elem.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			if !marshaler(elem.Type()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
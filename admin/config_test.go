@@ -0,0 +1,110 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/moov-io/base/config"
+)
+
+type testConfig struct {
+	Database struct {
+		Address  string
+		Password string
+		Pool     struct {
+			Size int
+		}
+	}
+	Webhook struct {
+		URL        string
+		SigningKey string
+	}
+	Partner struct {
+		Name    string
+		Account string `redact:"mask"`
+	}
+	Hosts []string
+	OAuth struct {
+		ClientID string `mapstructure:"client_id" json:"clientId"`
+	}
+	Banks []struct {
+		Name     string
+		Password string
+	}
+}
+
+func TestAdmin__Config(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	get := func() []configValue {
+		t.Helper()
+
+		resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/debug/config")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("bogus HTTP status: %d", resp.StatusCode)
+		}
+		var values []configValue
+		if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+			t.Fatal(err)
+		}
+		return values
+	}
+	if values := get(); len(values) != 0 {
+		t.Errorf("unexpected values: %#v", values)
+	}
+
+	var cfg testConfig
+	cfg.Database.Address = "mysql:3306"
+	cfg.Database.Password = "hunter2"
+	cfg.Database.Pool.Size = 10
+	cfg.Webhook.URL = "https://example.com/hooks"
+	cfg.Webhook.SigningKey = "abc123"
+	cfg.Partner.Name = "Acme"
+	cfg.Partner.Account = "1234567890"
+	cfg.Hosts = []string{"a", "b"}
+	cfg.OAuth.ClientID = "moov"
+	cfg.Banks = append(cfg.Banks, struct {
+		Name     string
+		Password string
+	}{Name: "First Bank", Password: "hunter3"})
+
+	svc.AddConfig(&cfg, map[string]config.Source{
+		"database.address":   config.SourceDefault,
+		"database.password":  config.SourceFile,
+		"database.pool.size": config.SourceFile,
+		"webhook.url":        config.SourceSecrets,
+		"partner.name":       config.SourceFile,
+		"oauth.client_id":    config.SourceFile,
+		"banks":              config.SourceFile,
+	})
+
+	expected := []configValue{
+		{Key: "Banks.0.Name", Value: "First Bank", Source: config.SourceFile},
+		{Key: "Banks.0.Password", Value: "****", Source: config.SourceFile, Redacted: true},
+		{Key: "Database.Address", Value: "mysql:3306", Source: config.SourceDefault},
+		{Key: "Database.Password", Value: "****", Source: config.SourceFile, Redacted: true},
+		{Key: "Database.Pool.Size", Value: float64(10), Source: config.SourceFile},
+		{Key: "Hosts", Value: []interface{}{"a", "b"}},
+		{Key: "OAuth.client_id", Value: "moov", Source: config.SourceFile},
+		{Key: "Partner.Account", Value: "****7890"},
+		{Key: "Partner.Name", Value: "Acme", Source: config.SourceFile},
+		{Key: "Webhook.SigningKey", Value: "****", Redacted: true},
+		{Key: "Webhook.URL", Value: "****", Source: config.SourceSecrets, Redacted: true},
+	}
+	if values := get(); !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values:\n%#v", values)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base/log"
+)
+
+type runtimeStats struct {
+	Goroutines int       `json:"goroutines"`
+	Heap       heapStats `json:"heap"`
+	GC         gcStats   `json:"gc"`
+}
+
+type heapStats struct {
+	AllocBytes    uint64 `json:"allocBytes"`
+	SysBytes      uint64 `json:"sysBytes"`
+	IdleBytes     uint64 `json:"idleBytes"`
+	InuseBytes    uint64 `json:"inuseBytes"`
+	ReleasedBytes uint64 `json:"releasedBytes"`
+	Objects       uint64 `json:"objects"`
+}
+
+type gcStats struct {
+	Count      uint32        `json:"count"`
+	PauseTotal time.Duration `json:"pauseTotalNs"`
+	Last       *time.Time    `json:"last,omitempty"`
+}
+
+func readRuntimeStats() runtimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := runtimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		Heap: heapStats{
+			AllocBytes:    mem.HeapAlloc,
+			SysBytes:      mem.HeapSys,
+			IdleBytes:     mem.HeapIdle,
+			InuseBytes:    mem.HeapInuse,
+			ReleasedBytes: mem.HeapReleased,
+			Objects:       mem.HeapObjects,
+		},
+		GC: gcStats{
+			Count:      mem.NumGC,
+			PauseTotal: time.Duration(mem.PauseTotalNs),
+		},
+	}
+	if mem.LastGC > 0 {
+		last := time.Unix(0, int64(mem.LastGC)).UTC()
+		stats.GC.Last = &last
+	}
+	return stats
+}
+
+// runtimeHandler serves 'GET /debug/runtime' with goroutine and heap statistics
+func runtimeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, readRuntimeStats())
+	}
+}
+
+type databasePools struct {
+	mu  sync.RWMutex
+	dbs map[string]*sql.DB
+}
+
+type databaseStats struct {
+	MaxOpenConnections int           `json:"maxOpenConnections"`
+	OpenConnections    int           `json:"openConnections"`
+	InUse              int           `json:"inUse"`
+	Idle               int           `json:"idle"`
+	WaitCount          int64         `json:"waitCount"`
+	WaitDuration       time.Duration `json:"waitDurationNs"`
+	MaxIdleClosed      int64         `json:"maxIdleClosed"`
+	MaxIdleTimeClosed  int64         `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed  int64         `json:"maxLifetimeClosed"`
+}
+
+// AddDatabaseStats registers db to be included in 'GET /debug/databases' under name.
+func (s *Server) AddDatabaseStats(name string, db *sql.DB) {
+	s.databases.mu.Lock()
+	defer s.databases.mu.Unlock()
+
+	if s.databases.dbs == nil {
+		s.databases.dbs = make(map[string]*sql.DB)
+	}
+	s.databases.dbs[name] = db
+}
+
+// handler serves 'GET /debug/databases' with the connection pool stats of each database
+func (p *databasePools) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		p.mu.RLock()
+		out := make(map[string]databaseStats, len(p.dbs))
+		for name, db := range p.dbs {
+			stats := db.Stats()
+			out[name] = databaseStats{
+				MaxOpenConnections: stats.MaxOpenConnections,
+				OpenConnections:    stats.OpenConnections,
+				InUse:              stats.InUse,
+				Idle:               stats.Idle,
+				WaitCount:          stats.WaitCount,
+				WaitDuration:       stats.WaitDuration,
+				MaxIdleClosed:      stats.MaxIdleClosed,
+				MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+				MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+			}
+		}
+		p.mu.RUnlock()
+
+		writeJSON(w, out)
+	}
+}
+
+// WatchGoroutines samples the goroutine count every interval and logs a warning when it has
+// grown on each of the last samples checks, which usually points to a goroutine leak.
+// Watching stops when the Server is Shutdown.
+func (s *Server) WatchGoroutines(logger log.Logger, interval time.Duration, samples int) {
+	go watchGoroutines(logger, interval, samples, runtime.NumGoroutine, s.done)
+}
+
+func watchGoroutines(logger log.Logger, interval time.Duration, samples int, count func() int, done <-chan struct{}) {
+	if logger == nil {
+		logger = log.NewDefaultLogger()
+	}
+	if samples < 2 {
+		samples = 2
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var history []int
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			n := count()
+			if len(history) > 0 && n <= history[len(history)-1] {
+				history = history[:0]
+			}
+			history = append(history, n)
+
+			if len(history) >= samples {
+				logger.Warn().With(log.Fields{
+					"goroutines":      log.Int(n),
+					"goroutines_from": log.Int(history[0]),
+					"samples":         log.Int(len(history)),
+				}).Logf("goroutine count grew over the last %v", interval*time.Duration(len(history)-1))
+
+				// start a new window so a steady leak warns once per window
+				history = history[len(history)-1:]
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/moov-io/base/log"
+)
+
+func TestAdmin__Runtime(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/debug/runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("bogus HTTP status: %d", resp.StatusCode)
+	}
+	var stats runtimeStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Goroutines <= 0 || stats.Heap.AllocBytes == 0 || stats.Heap.SysBytes == 0 {
+		t.Errorf("unexpected stats: %#v", stats)
+	}
+}
+
+func TestAdmin__Databases(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	svc.AddDatabaseStats("ach", db)
+
+	resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/debug/databases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var stats map[string]databaseStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := stats["ach"]; !ok || s.MaxOpenConnections != 3 || s.OpenConnections != 1 || s.Idle != 1 {
+		t.Errorf("unexpected stats: %#v", stats)
+	}
+}
+
+func TestWatchGoroutines(t *testing.T) {
+	counts := []int{10, 11, 9, 12, 13, 14, 14, 14}
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := counts[calls%len(counts)]
+		calls++
+		return n
+	}
+
+	buf, logger := log.NewBufferLogger()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		watchGoroutines(logger, time.Millisecond, 3, count, done)
+		close(finished)
+	}()
+
+	for i := 0; i < 500; i++ {
+		mu.Lock()
+		n := calls
+		mu.Unlock()
+		if n >= len(counts) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(done)
+	<-finished
+
+	// 9 -> 12 -> 13 is the only growth across 3 samples
+	out := buf.String()
+	if !strings.Contains(out, "goroutines=13") || !strings.Contains(out, "goroutines_from=9") {
+		t.Errorf("unexpected logs:\n%s", out)
+	}
+	if strings.Contains(out, "goroutines_from=10") {
+		t.Errorf("unexpected warning:\n%s", out)
+	}
+}
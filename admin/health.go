@@ -73,6 +73,17 @@ func (s *Server) livenessHandler() http.HandlerFunc {
 	}
 }
 
+// Criticality controls how a failed readiness check affects 'GET /ready'
+type Criticality int
+
+const (
+	// Critical checks fail the readiness endpoint when they return an error
+	Critical Criticality = iota
+
+	// NonCritical checks report their error but the service remains ready
+	NonCritical
+)
+
 // AddReadinessCheck will register a new health check that is executed on every
 // HTTP request of 'GET /ready' against the admin server.
 //
@@ -80,10 +91,23 @@ func (s *Server) livenessHandler() http.HandlerFunc {
 //
 // These checks are designed to be unhealthy while the application is starting.
 func (s *Server) AddReadinessCheck(name string, f func() error) {
+	s.AddReadinessCheckWithCriticality(name, Critical, f)
+}
+
+// AddReadinessCheckWithCriticality registers a readiness check like AddReadinessCheck. Errors from
+// NonCritical checks are included in the response without marking the service unready, which suits
+// optional partners or warnings such as an expiring certificate.
+func (s *Server) AddReadinessCheckWithCriticality(name string, level Criticality, f func() error) {
 	s.readyChecks = append(s.readyChecks, &healthCheck{
 		name:  name,
 		check: f,
 	})
+	if level != Critical {
+		if s.nonCritical == nil {
+			s.nonCritical = make(map[string]bool)
+		}
+		s.nonCritical[name] = true
+	}
 }
 
 func (s *Server) readinessHandler() http.HandlerFunc {
@@ -97,7 +121,9 @@ func (s *Server) readinessHandler() http.HandlerFunc {
 		kv := make(map[string]string)
 		for i := range results {
 			if results[i].err != nil {
-				status = http.StatusBadRequest
+				if !s.nonCritical[results[i].name] {
+					status = http.StatusBadRequest
+				}
 				kv[results[i].name] = results[i].err.Error()
 			} else {
 				kv[results[i].name] = "good"
@@ -0,0 +1,138 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base/log"
+)
+
+var (
+	defaultLogLevelTTL = 15 * time.Minute
+	maxLogLevelTTL     = 24 * time.Hour
+)
+
+// logLevelOverride tracks a temporary change of log.CurrentLevel() and reverts it after a TTL.
+type logLevelOverride struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	original log.Level
+	revertAt time.Time
+}
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+	TTL   string `json:"ttl"`
+}
+
+type logLevelResponse struct {
+	Level    log.Level  `json:"level"`
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+// handler serves 'GET /debug/log-level' and 'PUT /debug/log-level'.
+//
+// PUT requests change the level of every log.Logger in the process, for example to debug an incident:
+//
+//	curl -XPUT localhost:9090/debug/log-level -d '{"level":"debug","ttl":"30m"}'
+//
+// The previous level is restored after the TTL (15 minutes by default, at most 24 hours) or when
+// the original level is PUT back.
+func (o *logLevelOverride) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req logLevelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, fmt.Errorf("invalid request: %v", err))
+				return
+			}
+			if err := o.set(req); err != nil {
+				writeError(w, err)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		o.respond(w)
+	}
+}
+
+func (o *logLevelOverride) set(req logLevelRequest) error {
+	level, err := log.ParseLevel(req.Level)
+	if err != nil {
+		return err
+	}
+	ttl := defaultLogLevelTTL
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid ttl: %v", err)
+		}
+	}
+	if ttl <= 0 || ttl > maxLogLevelTTL {
+		return fmt.Errorf("ttl must be between 0s and %v", maxLogLevelTTL)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.timer == nil {
+		o.original = log.CurrentLevel()
+	} else {
+		o.timer.Stop()
+		o.timer = nil
+	}
+	if _, err := log.SetLevel(level); err != nil {
+		return err
+	}
+	if level == o.original {
+		return nil
+	}
+
+	o.revertAt = time.Now().Add(ttl)
+	o.timer = time.AfterFunc(ttl, o.revert)
+	return nil
+}
+
+func (o *logLevelOverride) revert() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.timer == nil {
+		return
+	}
+	o.timer = nil
+	log.SetLevel(o.original)
+}
+
+func (o *logLevelOverride) respond(w http.ResponseWriter) {
+	o.mu.Lock()
+	resp := logLevelResponse{
+		Level: log.CurrentLevel(),
+	}
+	if o.timer != nil {
+		revertAt := o.revertAt
+		resp.RevertAt = &revertAt
+	}
+	o.mu.Unlock()
+
+	writeJSON(w, resp)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+)
+
+func putLogLevel(t *testing.T, svc *Server, body string) (*http.Response, logLevelResponse) {
+	t.Helper()
+
+	req, err := http.NewRequest("PUT", "http://"+svc.BindAddr()+"/debug/log-level", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out logLevelResponse
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp, out
+}
+
+func TestAdmin__LogLevel(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	resp, err := http.DefaultClient.Get("http://" + svc.BindAddr() + "/debug/log-level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var current logLevelResponse
+	json.NewDecoder(resp.Body).Decode(&current)
+	resp.Body.Close()
+	if current.Level != log.Info || current.RevertAt != nil {
+		t.Errorf("unexpected level: %#v", current)
+	}
+
+	resp, out := putLogLevel(t, svc, `{"level":"debug","ttl":"50ms"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("bogus HTTP status: %d", resp.StatusCode)
+	}
+	if out.Level != log.Debug || out.RevertAt == nil {
+		t.Errorf("unexpected response: %#v", out)
+	}
+	if l := log.CurrentLevel(); l != log.Debug {
+		t.Errorf("unexpected level: %s", l)
+	}
+
+	// wait for the TTL to revert
+	for i := 0; i < 100 && log.CurrentLevel() != log.Info; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if l := log.CurrentLevel(); l != log.Info {
+		t.Errorf("expected level to revert, got %s", l)
+	}
+}
+
+func TestAdmin__LogLevelRestore(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	_, out := putLogLevel(t, svc, `{"level":"debug"}`)
+	if out.Level != log.Debug || out.RevertAt == nil || time.Until(*out.RevertAt) < 14*time.Minute {
+		t.Errorf("unexpected response: %#v", out)
+	}
+	_, out = putLogLevel(t, svc, `{"level":"warn","ttl":"1h"}`)
+	if out.Level != log.Warn || out.RevertAt == nil {
+		t.Errorf("unexpected response: %#v", out)
+	}
+
+	// setting the original level cancels the revert
+	_, out = putLogLevel(t, svc, `{"level":"info"}`)
+	if out.Level != log.Info || out.RevertAt != nil {
+		t.Errorf("unexpected response: %#v", out)
+	}
+}
+
+func TestAdmin__LogLevelErrors(t *testing.T) {
+	svc := NewServer(":0")
+	go svc.Listen()
+	defer svc.Shutdown()
+
+	for _, body := range []string{
+		`{"level":"verbose"}`,
+		`{"level":"debug","ttl":"forever"}`,
+		`{"level":"debug","ttl":"-1m"}`,
+		`{"level":"debug","ttl":"48h"}`,
+		`not json`,
+	} {
+		resp, _ := putLogLevel(t, svc, body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: bogus HTTP status: %d", body, resp.StatusCode)
+		}
+	}
+	if l := log.CurrentLevel(); l != log.Info {
+		t.Errorf("unexpected level: %s", l)
+	}
+
+	resp, err := http.DefaultClient.Post("http://"+svc.BindAddr()+"/debug/log-level", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("bogus HTTP status: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Amount is a monetary value stored as an integer number of the currency's minor
+// units (i.e. cents for USD) along with its ISO 4217 currency code.
+//
+// Amounts are never stored as float64 dollars so totals computed across systems
+// (ledgers, files, metrics) stay exact.
+type Amount struct {
+	Value    int64  `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// NewAmount returns an Amount of value minor units in the given currency.
+// The currency code is uppercased.
+func NewAmount(value int64, currency string) Amount {
+	return Amount{
+		Value:    value,
+		Currency: strings.ToUpper(strings.TrimSpace(currency)),
+	}
+}
+
+// String returns the Amount in major units with its currency code (i.e. "USD 12.34")
+func (a Amount) String() string {
+	exp := CurrencyExponent(a.Currency)
+	if exp == 0 {
+		return fmt.Sprintf("%s %d", a.Currency, a.Value)
+	}
+
+	sign, value := "", a.Value
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	div := pow10(exp)
+	return fmt.Sprintf("%s %s%d.%0*d", a.Currency, sign, value/div, exp, value%div)
+}
+
+// minorUnits holds the currencies whose number of minor units isn't 2.
+var minorUnits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// CurrencyExponent returns the number of minor unit digits for an ISO 4217 currency code.
+// Unknown currencies default to 2.
+func CurrencyExponent(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return 2
+}
+
+func pow10(n int) int64 {
+	out := int64(1)
+	for i := 0; i < n; i++ {
+		out *= 10
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CurrencyPlacement is where Format writes the currency
+type CurrencyPlacement int
+
+const (
+	// SymbolBefore writes the currency's symbol before the value ("$1,234.56"), or its code
+	// when it has no known symbol.
+	SymbolBefore CurrencyPlacement = iota
+
+	// CodeBefore writes the ISO 4217 code before the value ("USD 1,234.56")
+	CodeBefore
+
+	// CodeAfter writes the ISO 4217 code after the value ("1,234.56 USD")
+	CodeAfter
+
+	// NoCurrency writes only the value ("1,234.56")
+	NoCurrency
+)
+
+// NegativeStyle is how Format writes amounts below zero
+type NegativeStyle int
+
+const (
+	// NegativeMinus writes a leading minus sign ("-$12.34")
+	NegativeMinus NegativeStyle = iota
+
+	// NegativeParentheses wraps the amount in parentheses ("($12.34)") as accounting statements do
+	NegativeParentheses
+)
+
+// FormatOptions describes how an Amount is written by Format
+type FormatOptions struct {
+	Placement CurrencyPlacement
+	Negative  NegativeStyle
+
+	// NoGrouping leaves out the thousands separators
+	NoGrouping bool
+
+	// Width right aligns the amount to at least Width characters for printed columns. Positive
+	// amounts have a trailing space with NegativeParentheses so their digits line up with negatives.
+	Width int
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "INR": "₹", "KRW": "₩",
+	"CAD": "CA$", "AUD": "A$", "MXN": "MX$",
+}
+
+// Format returns the Amount in major units as it's printed on statements and receipts
+func (a Amount) Format(opts FormatOptions) string {
+	value := a.Value
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	digits := strconv.FormatUint(uint64(value), 10)
+	exp := CurrencyExponent(a.Currency)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	major, minor := digits[:len(digits)-exp], digits[len(digits)-exp:]
+	if !opts.NoGrouping {
+		major = group(major)
+	}
+	number := major
+	if exp > 0 {
+		number += "." + minor
+	}
+
+	switch opts.Placement {
+	case SymbolBefore:
+		if symbol, ok := currencySymbols[a.Currency]; ok {
+			number = symbol + number
+		} else {
+			number = a.Currency + " " + number
+		}
+	case CodeBefore:
+		number = a.Currency + " " + number
+	case CodeAfter:
+		number = number + " " + a.Currency
+	}
+
+	switch {
+	case negative && opts.Negative == NegativeParentheses:
+		number = "(" + number + ")"
+	case negative:
+		number = "-" + number
+	case opts.Negative == NegativeParentheses && opts.Width > 0:
+		number += " "
+	}
+
+	if pad := opts.Width - utf8.RuneCountInString(number); pad > 0 {
+		number = strings.Repeat(" ", pad) + number
+	}
+	return number
+}
+
+// group adds thousands separators to a string of digits
+func group(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var buf strings.Builder
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	buf.WriteString(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		buf.WriteByte(',')
+		buf.WriteString(digits[i : i+3])
+	}
+	return buf.String()
+}
+
+// Words returns the Amount as it's written on a check, with the minor units as a fraction
+// (i.e. "one thousand two hundred and 34/100"). Negative amounts return an error.
+func (a Amount) Words() (string, error) {
+	if a.Value < 0 {
+		return "", errors.New("negative amounts can't be written in words")
+	}
+	exp := CurrencyExponent(a.Currency)
+	div := pow10(exp)
+
+	words := numberWords(a.Value / div)
+	if exp == 0 {
+		return words, nil
+	}
+	return fmt.Sprintf("%s and %0*d/%d", words, exp, a.Value%div, div), nil
+}
+
+var (
+	smallNumbers = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	tens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+// numberWords spells out n, which must not be negative
+func numberWords(n int64) string {
+	if n == 0 {
+		return "zero"
+	}
+	var groups []string
+	for scale := 0; n > 0; scale++ {
+		if chunk := int(n % 1000); chunk > 0 {
+			words := hundredsWords(chunk)
+			if scales[scale] != "" {
+				words += " " + scales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	return strings.Join(groups, " ")
+}
+
+// hundredsWords spells out 1 through 999
+func hundredsWords(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, smallNumbers[n/100]+" hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		parts = append(parts, tens[n/10]+"-"+smallNumbers[n%10])
+	case n >= 20:
+		parts = append(parts, tens[n/10])
+	case n > 0:
+		parts = append(parts, smallNumbers[n])
+	}
+	return strings.Join(parts, " ")
+}
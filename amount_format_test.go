@@ -0,0 +1,68 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAmount__Format(t *testing.T) {
+	tests := []struct {
+		amt      Amount
+		opts     FormatOptions
+		expected string
+	}{
+		{NewAmount(123456, "USD"), FormatOptions{}, "$1,234.56"},
+		{NewAmount(-1234, "USD"), FormatOptions{}, "-$12.34"},
+		{NewAmount(-1234, "USD"), FormatOptions{Negative: NegativeParentheses}, "($12.34)"},
+		{NewAmount(5, "USD"), FormatOptions{}, "$0.05"},
+		{NewAmount(123456789, "USD"), FormatOptions{NoGrouping: true}, "$1234567.89"},
+		{NewAmount(123456, "USD"), FormatOptions{Placement: CodeBefore}, "USD 1,234.56"},
+		{NewAmount(123456, "EUR"), FormatOptions{Placement: CodeAfter}, "1,234.56 EUR"},
+		{NewAmount(123456, "EUR"), FormatOptions{Placement: NoCurrency}, "1,234.56"},
+		{NewAmount(1500, "JPY"), FormatOptions{}, "¥1,500"},
+		{NewAmount(12345, "KWD"), FormatOptions{}, "KWD 12.345"},
+		{NewAmount(math.MinInt64, "USD"), FormatOptions{}, "-$92,233,720,368,547,758.08"},
+		// aligned columns
+		{NewAmount(123456, "EUR"), FormatOptions{Width: 12}, "   €1,234.56"},
+		{NewAmount(-1234, "USD"), FormatOptions{Width: 12, Negative: NegativeParentheses}, "    ($12.34)"},
+		{NewAmount(1234, "USD"), FormatOptions{Width: 12, Negative: NegativeParentheses}, "     $12.34 "},
+		{NewAmount(123456, "USD"), FormatOptions{Width: 4}, "$1,234.56"},
+	}
+	for _, test := range tests {
+		if v := test.amt.Format(test.opts); v != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.amt, test.expected, v)
+		}
+	}
+}
+
+func TestAmount__Words(t *testing.T) {
+	tests := []struct {
+		amt      Amount
+		expected string
+	}{
+		{NewAmount(120034, "USD"), "one thousand two hundred and 34/100"},
+		{NewAmount(0, "USD"), "zero and 00/100"},
+		{NewAmount(1105, "USD"), "eleven and 05/100"},
+		{NewAmount(10000000000, "USD"), "one hundred million and 00/100"},
+		{NewAmount(2147483647, "USD"), "twenty-one million four hundred seventy-four thousand eight hundred thirty-six and 47/100"},
+		{NewAmount(9000, "JPY"), "nine thousand"},
+		{NewAmount(1001, "KWD"), "one and 001/1000"},
+	}
+	for _, test := range tests {
+		v, err := test.amt.Words()
+		if err != nil {
+			t.Errorf("%v: %v", test.amt, err)
+		}
+		if v != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.amt, test.expected, v)
+		}
+	}
+
+	if _, err := NewAmount(-1, "USD").Words(); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmount__String(t *testing.T) {
+	tests := []struct {
+		amt      Amount
+		expected string
+	}{
+		{NewAmount(1234, "usd"), "USD 12.34"},
+		{NewAmount(-5, "USD"), "USD -0.05"},
+		{NewAmount(0, "USD"), "USD 0.00"},
+		{NewAmount(1500, "JPY"), "JPY 1500"},
+		{NewAmount(12345, "KWD"), "KWD 12.345"},
+	}
+	for _, test := range tests {
+		if v := test.amt.String(); v != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, v)
+		}
+	}
+}
+
+func TestAmount__JSON(t *testing.T) {
+	bs, err := json.Marshal(NewAmount(1234, "USD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := string(bs); v != `{"value":1234,"currency":"USD"}` {
+		t.Errorf("unexpected JSON: %s", v)
+	}
+
+	var amt Amount
+	if err := json.Unmarshal(bs, &amt); err != nil {
+		t.Fatal(err)
+	}
+	if amt != NewAmount(1234, "USD") {
+		t.Errorf("unexpected amount: %#v", amt)
+	}
+}
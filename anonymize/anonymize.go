@@ -0,0 +1,165 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package anonymize replaces personal data with pseudonyms which keep its shape, for building
+// staging datasets from production or removing a customer's details after a deletion request.
+//
+// Pseudonyms are an HMAC of the value, so the same input always produces the same pseudonym
+// and joins across tables keep working. Without the key pseudonyms can't be reversed or
+// recomputed, discarding it makes them permanent.
+//
+//	p, err := anonymize.New(key)
+//	p.Name("Jane Doe")             // a name such as "Maria Lopez"
+//	p.Email("Jane.Doe@mail.com")   // an address such as "maria.lopez.5c1e@example.com"
+//	p.AccountNumber("0012-345678") // other digits in the same format, such as "7731-902254"
+//	p.RoutingNumber("231380104")   // a routing number with a valid check digit
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/moov-io/base/validate"
+)
+
+// MinKeySize is the shortest key New accepts
+const MinKeySize = 32
+
+// Pseudonymizer produces consistent pseudonyms from a secret key
+type Pseudonymizer struct {
+	key []byte
+}
+
+// New returns a Pseudonymizer using key, which must be at least MinKeySize bytes
+func New(key []byte) (*Pseudonymizer, error) {
+	if len(key) < MinKeySize {
+		return nil, errors.New("anonymize: key is too short")
+	}
+	return &Pseudonymizer{key: append([]byte(nil), key...)}, nil
+}
+
+// Token returns a 32 character hex pseudonym of value. Different kinds, such as "customer"
+// and "account", give different tokens for the same value.
+func (p *Pseudonymizer) Token(kind, value string) string {
+	return hex.EncodeToString(p.sum(kind, value, 0)[:16])
+}
+
+// Name returns a full name chosen by the pseudonym of name
+func (p *Pseudonymizer) Name(name string) string {
+	s := p.stream("name", normalize(name))
+	return firstNames[s.intn(len(firstNames))] + " " + lastNames[s.intn(len(lastNames))]
+}
+
+// Email returns an address at example.com built from the pseudonym of email, which is compared
+// without regard to case.
+func (p *Pseudonymizer) Email(email string) string {
+	email = normalize(email)
+	s := p.stream("email", email)
+	first := strings.ToLower(firstNames[s.intn(len(firstNames))])
+	last := strings.ToLower(lastNames[s.intn(len(lastNames))])
+	return first + "." + last + "." + p.Token("email", email)[:4] + "@example.com"
+}
+
+// AccountNumber replaces each digit of number, keeping its length and any separators
+func (p *Pseudonymizer) AccountNumber(number string) string {
+	return p.digits("account", number)
+}
+
+// Digits replaces each digit of value, keeping its length and any other characters, such as
+// a phone number or tax ID.
+func (p *Pseudonymizer) Digits(value string) string {
+	return p.digits("digits", value)
+}
+
+// RoutingNumber returns a nine digit routing number with a valid ABA check digit
+func (p *Pseudonymizer) RoutingNumber(number string) string {
+	s := p.stream("routing", strings.TrimSpace(number))
+	digits := make([]byte, 9)
+	// the first two digits of Federal Reserve routing numbers are 01 through 12
+	prefix := 1 + s.intn(12)
+	digits[0], digits[1] = byte('0'+prefix/10), byte('0'+prefix%10)
+	for i := 2; i < 8; i++ {
+		digits[i] = byte('0' + s.intn(10))
+	}
+	digits[8] = byte('0' + validate.RoutingCheckDigit(string(digits[:8])))
+	return string(digits)
+}
+
+func (p *Pseudonymizer) digits(kind, value string) string {
+	s := p.stream(kind, strings.TrimSpace(value))
+	out := []rune(strings.TrimSpace(value))
+	for i, r := range out {
+		if unicode.IsDigit(r) {
+			out[i] = rune('0' + s.intn(10))
+		}
+	}
+	return string(out)
+}
+
+func (p *Pseudonymizer) sum(kind, value string, counter uint32) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	var c [4]byte
+	binary.BigEndian.PutUint32(c[:], counter)
+	mac.Write(c[:])
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// stream returns deterministic bytes derived from kind and value
+func (p *Pseudonymizer) stream(kind, value string) *stream {
+	return &stream{p: p, kind: kind, value: value}
+}
+
+type stream struct {
+	p           *Pseudonymizer
+	kind, value string
+	buf         []byte
+	counter     uint32
+}
+
+func (s *stream) byte() byte {
+	if len(s.buf) == 0 {
+		s.buf = s.p.sum(s.kind, s.value, s.counter)
+		s.counter++
+	}
+	b := s.buf[0]
+	s.buf = s.buf[1:]
+	return b
+}
+
+// intn returns a number less than n, which must be under 256
+func (s *stream) intn(n int) int {
+	// reject values past the largest multiple of n to avoid bias
+	max := 256 - 256%n
+	for {
+		if b := int(s.byte()); b < max {
+			return b % n
+		}
+	}
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+var firstNames = []string{
+	"Alice", "Amara", "Ben", "Carlos", "Chen", "Daniel", "Elena", "Fatima", "Grace", "Hiro",
+	"Isaac", "Jamal", "Julia", "Kofi", "Laura", "Liam", "Maria", "Mei", "Nadia", "Noah",
+	"Olivia", "Omar", "Priya", "Rosa", "Samuel", "Sofia", "Tariq", "Uma", "Victor", "Wei",
+	"Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Adams", "Baker", "Campbell", "Diaz", "Evans", "Fischer", "Garcia", "Hughes", "Ito", "Johnson",
+	"Kim", "Lopez", "Martin", "Nguyen", "Okafor", "Patel", "Quinn", "Rossi", "Singh", "Tanaka",
+	"Usman", "Vargas", "Walker", "Xu", "Young", "Zhang", "Silva", "Novak", "Moreau", "Haddad",
+	"Cohen", "Ortiz",
+}
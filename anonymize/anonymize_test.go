@@ -0,0 +1,71 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package anonymize
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pseudonymizer(t *testing.T, seed byte) *Pseudonymizer {
+	t.Helper()
+	p, err := New(bytes.Repeat([]byte{seed}, MinKeySize))
+	require.NoError(t, err)
+	return p
+}
+
+func TestNew(t *testing.T) {
+	_, err := New([]byte("short"))
+	require.Error(t, err)
+}
+
+func TestPseudonymizer__Consistent(t *testing.T) {
+	p, other := pseudonymizer(t, 1), pseudonymizer(t, 2)
+
+	require.Equal(t, p.Name("Jane Doe"), p.Name("  jane   DOE "))
+	require.Equal(t, p.Email("Jane.Doe@mail.com"), p.Email("jane.doe@MAIL.com"))
+	require.Equal(t, p.Token("customer", "c1"), p.Token("customer", "c1"))
+	require.NotEqual(t, p.Token("customer", "c1"), p.Token("account", "c1"))
+	require.NotEqual(t, p.Token("customer", "c1"), other.Token("customer", "c1"))
+	require.Len(t, p.Token("customer", "c1"), 32)
+
+	// different inputs are spread out
+	seen := make(map[string]bool)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		seen[p.Email(name+"@mail.com")] = true
+	}
+	require.Len(t, seen, 8)
+}
+
+func TestPseudonymizer__Shapes(t *testing.T) {
+	p := pseudonymizer(t, 1)
+
+	name := p.Name("Jane Doe")
+	require.Len(t, strings.Fields(name), 2)
+
+	require.Regexp(t, regexp.MustCompile(`^[a-z]+\.[a-z]+\.[0-9a-f]{4}@example\.com$`), p.Email("Jane.Doe@mail.com"))
+
+	acct := p.AccountNumber("0012-345678")
+	require.Regexp(t, regexp.MustCompile(`^\d{4}-\d{6}$`), acct)
+	require.NotEqual(t, "0012-345678", acct)
+
+	require.Regexp(t, regexp.MustCompile(`^\(\d{3}\) \d{3}-\d{4}$`), p.Digits("(555) 867-5309"))
+
+	for _, in := range []string{"231380104", "121000358", "011000015"} {
+		rtn := p.RoutingNumber(in)
+		require.Len(t, rtn, 9)
+		weights := []int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+		sum := 0
+		for i, w := range weights {
+			sum += int(rtn[i]-'0') * w
+		}
+		require.Zero(t, sum%10, rtn)
+		require.True(t, rtn[:2] >= "01" && rtn[:2] <= "12", rtn)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package apikey generates and verifies API keys for partner facing APIs.
+//
+// Keys look like "moov_3Xk9aP2q" + 26 secret characters + a 6 character checksum. The prefix
+// tells people (and secret scanners) what a leaked key is for, the first 8 characters after it
+// identify the key for lookups and the checksum rejects mistyped keys without a database query.
+// Only a hash of the key is stored.
+//
+//	key, err := apikey.Generate("moov")
+//	// show key.Plaintext to the partner once, then store key.ID and key.Hash
+//
+//	handler := apikey.Middleware(apikey.Config{Store: store}, router)
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+const (
+	alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	idLength       = 8
+	secretLength   = 26
+	checksumLength = 6
+)
+
+var (
+	// ErrInvalidKey is returned for keys which are malformed or fail their checksum
+	ErrInvalidKey = errors.New("invalid API key")
+)
+
+// Key is a newly generated API key
+type Key struct {
+	// Plaintext is given to the key's owner. It can't be recovered after it's generated.
+	Plaintext string
+
+	// ID is the public part of the key used to look it up
+	ID string
+
+	// Hash is stored and compared with Verify
+	Hash string
+}
+
+// Generate returns a new key starting with prefix and an underscore. Prefixes may only contain
+// letters and digits.
+func Generate(prefix string) (Key, error) {
+	if prefix == "" || strings.Trim(prefix, alphabet) != "" {
+		return Key{}, fmt.Errorf("apikey: invalid prefix %q", prefix)
+	}
+	body, err := random(idLength + secretLength)
+	if err != nil {
+		return Key{}, fmt.Errorf("apikey: generating key: %v", err)
+	}
+	plaintext := prefix + "_" + body + checksum(prefix+"_"+body)
+
+	return Key{
+		Plaintext: plaintext,
+		ID:        body[:idLength],
+		Hash:      Hash(plaintext),
+	}, nil
+}
+
+// random returns n characters of alphabet read from crypto/rand. Keys don't use randx because
+// tests can seed it, which would make keys predictable.
+func random(n int) (string, error) {
+	// bytes past the largest multiple of len(alphabet) are skipped to avoid modulo bias
+	limit := 256 - 256%len(alphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(out) < n {
+				out = append(out, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(out), nil
+}
+
+// Parsed is the public part of a key
+type Parsed struct {
+	Prefix string
+	ID     string
+}
+
+// Parse checks the format and checksum of key, returning ErrInvalidKey when either is wrong.
+func Parse(key string) (Parsed, error) {
+	idx := strings.LastIndex(key, "_")
+	if idx <= 0 {
+		return Parsed{}, ErrInvalidKey
+	}
+	prefix, rest := key[:idx], key[idx+1:]
+	if len(rest) != idLength+secretLength+checksumLength || strings.Trim(rest, alphabet) != "" {
+		return Parsed{}, ErrInvalidKey
+	}
+	body, sum := rest[:idLength+secretLength], rest[idLength+secretLength:]
+	if subtle.ConstantTimeCompare([]byte(checksum(prefix+"_"+body)), []byte(sum)) != 1 {
+		return Parsed{}, ErrInvalidKey
+	}
+	return Parsed{Prefix: prefix, ID: body[:idLength]}, nil
+}
+
+// checksum encodes the CRC32 of s in base62
+func checksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s))
+	out := make([]byte, checksumLength)
+	for i := checksumLength - 1; i >= 0; i-- {
+		out[i] = alphabet[sum%uint32(len(alphabet))]
+		sum /= uint32(len(alphabet))
+	}
+	return string(out)
+}
+
+// Hash returns the hex encoded SHA-256 of key which is stored instead of the key. Generated
+// keys are long and random so a slow password hash isn't needed.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify reports whether key matches a stored hash in constant time
+func Verify(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
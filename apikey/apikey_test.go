@@ -0,0 +1,136 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/randx/randxtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := Generate("moov")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key.Plaintext, "moov_"))
+	require.Len(t, key.Plaintext, len("moov_")+idLength+secretLength+checksumLength)
+	require.Len(t, key.Hash, 64)
+	require.NotContains(t, key.Hash, key.Plaintext)
+
+	parsed, err := Parse(key.Plaintext)
+	require.NoError(t, err)
+	require.Equal(t, Parsed{Prefix: "moov", ID: key.ID}, parsed)
+
+	require.True(t, Verify(key.Plaintext, key.Hash))
+	require.False(t, Verify(key.Plaintext+"x", key.Hash))
+
+	other, err := Generate("moov")
+	require.NoError(t, err)
+	require.NotEqual(t, key.Plaintext, other.Plaintext)
+
+	_, err = Generate("moov_live")
+	require.Error(t, err)
+	_, err = Generate("")
+	require.Error(t, err)
+}
+
+func TestGenerate__Seeded(t *testing.T) {
+	// keys are read from crypto/rand, so seeding randx doesn't repeat them
+	randxtest.Seed(t, 42)
+	first, err := Generate("test")
+	require.NoError(t, err)
+
+	randxtest.Seed(t, 42)
+	second, err := Generate("test")
+	require.NoError(t, err)
+	require.NotEqual(t, first.Plaintext, second.Plaintext)
+	require.NotEqual(t, first.ID, second.ID)
+}
+
+func TestParse__Invalid(t *testing.T) {
+	key, err := Generate("moov")
+	require.NoError(t, err)
+
+	// change one character of the secret
+	typo := []byte(key.Plaintext)
+	i := len("moov_") + idLength + 1
+	if typo[i] == 'a' {
+		typo[i] = 'b'
+	} else {
+		typo[i] = 'a'
+	}
+
+	for _, k := range []string{"", "moov", "_" + key.Plaintext[5:], key.Plaintext[:len(key.Plaintext)-1], string(typo), key.Plaintext + "!"} {
+		_, err := Parse(k)
+		require.ErrorIs(t, err, ErrInvalidKey, "key %q", k)
+	}
+}
+
+type memoryStore map[string]Record
+
+func (s memoryStore) Lookup(ctx context.Context, id string) (Record, error) {
+	rec, ok := s[id]
+	if !ok {
+		return Record{}, ErrUnknownKey
+	}
+	return rec, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	store := memoryStore{}
+	add := func(rec Record) string {
+		key, err := Generate("moov")
+		require.NoError(t, err)
+		rec.ID, rec.Hash = key.ID, key.Hash
+		store[key.ID] = rec
+		return key.Plaintext
+	}
+	active := add(Record{Principal: Principal{ID: "partner-1"}})
+	revoked := add(Record{Principal: Principal{ID: "partner-2"}, Revoked: true})
+	expired := add(Record{Principal: Principal{ID: "partner-3"}, ExpiresAt: time.Now().Add(-time.Minute)})
+	unknown, err := Generate("moov")
+	require.NoError(t, err)
+
+	tracker := NewTracker()
+	handler := Middleware(Config{Store: store, OnUse: tracker.Touch}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := PrincipalFrom(r.Context())
+		w.Write([]byte(p.ID))
+	}))
+
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/transfers", nil)
+		if value != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("X-Api-Key", active)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "partner-1", w.Body.String())
+
+	w = serve("Authorization", "Bearer "+active)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	for _, key := range []string{"", "garbage", revoked, expired, unknown.Plaintext} {
+		w = serve("X-Api-Key", key)
+		require.Equal(t, http.StatusUnauthorized, w.Code, "key %q", key)
+		require.JSONEq(t, `{"error":"invalid API key"}`, w.Body.String())
+	}
+
+	used := tracker.Flush()
+	require.Len(t, used, 1)
+	parsed, _ := Parse(active)
+	require.Contains(t, used, parsed.ID)
+	require.Empty(t, tracker.Flush())
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base/ctxkeys"
+	"github.com/moov-io/base/log"
+)
+
+var (
+	// ErrUnknownKey is returned by a Store when no key has the ID
+	ErrUnknownKey = errors.New("unknown API key")
+
+	principalKey = ctxkeys.New[Principal]("apikey-principal")
+)
+
+// Principal is who a key acts on behalf of, such as a partner
+type Principal struct {
+	ID    string
+	Roles []string
+}
+
+// Record is a stored key
+type Record struct {
+	ID        string
+	Hash      string
+	Principal Principal
+
+	// ExpiresAt is when the key stops working, the key never expires when it's zero
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+// Store looks up keys by their ID. ErrUnknownKey is returned when there's no key.
+type Store interface {
+	Lookup(ctx context.Context, id string) (Record, error)
+}
+
+// PrincipalFrom returns the Principal of the key which authenticated a request
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	return principalKey.Get(ctx)
+}
+
+// Config describes how Middleware authenticates requests
+type Config struct {
+	Store Store
+
+	// Header holds the key, it defaults to X-Api-Key. An "Authorization: Bearer" header is
+	// also accepted.
+	Header string
+
+	// OnUse is called after a key authenticates a request, such as with Tracker.Touch to
+	// record when keys were last used. It's called on the request's goroutine so must be fast.
+	OnUse func(ctx context.Context, rec Record)
+
+	// Logger records lookup failures when set
+	Logger log.Logger
+}
+
+// Middleware authenticates requests by their API key and stores the Principal in the request's
+// context. Requests with a missing, invalid, expired or revoked key are rejected with a 401
+// response which doesn't say which.
+func Middleware(cfg Config, next http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = "X-Api-Key"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec, err := cfg.authenticate(r)
+		if err != nil {
+			if cfg.Logger != nil && !errors.Is(err, ErrInvalidKey) && !errors.Is(err, ErrUnknownKey) {
+				cfg.Logger.Error().LogErrorf("apikey: looking up key: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid API key"}` + "\n"))
+			return
+		}
+		if cfg.OnUse != nil {
+			cfg.OnUse(r.Context(), rec)
+		}
+		next.ServeHTTP(w, r.WithContext(principalKey.Set(r.Context(), rec.Principal)))
+	})
+}
+
+func (cfg Config) authenticate(r *http.Request) (Record, error) {
+	key := strings.TrimSpace(r.Header.Get(cfg.Header))
+	if key == "" {
+		if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+			key = strings.TrimSpace(auth[7:])
+		}
+	}
+	parsed, err := Parse(key)
+	if err != nil {
+		return Record{}, err
+	}
+	rec, err := cfg.Store.Lookup(r.Context(), parsed.ID)
+	if err != nil {
+		return Record{}, err
+	}
+	if !Verify(key, rec.Hash) || rec.Revoked {
+		return Record{}, ErrInvalidKey
+	}
+	if !rec.ExpiresAt.IsZero() && time.Now().After(rec.ExpiresAt) {
+		return Record{}, ErrInvalidKey
+	}
+	return rec, nil
+}
+
+// Tracker batches when keys were last used so each request doesn't write to the database.
+//
+//	tracker := apikey.NewTracker()
+//	cfg := apikey.Config{Store: store, OnUse: tracker.Touch}
+//
+//	// periodically, such as from a jobs.Pool
+//	for id, at := range tracker.Flush() {
+//		repo.SetLastUsed(ctx, id, at)
+//	}
+type Tracker struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+	now  func() time.Time
+}
+
+// NewTracker returns an empty Tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		used: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Touch records rec was used now. Its signature matches Config.OnUse.
+func (t *Tracker) Touch(ctx context.Context, rec Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.used[rec.ID] = t.now()
+}
+
+// Flush returns when each key was last used since the previous Flush
+func (t *Tracker) Flush() map[string]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	used := t.used
+	t.used = make(map[string]time.Time)
+	return used
+}
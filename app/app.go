@@ -0,0 +1,266 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package app starts a service's components after the components they depend on and stops them
+// in reverse, so main() doesn't have to be kept in the right order by hand.
+//
+//	a, err := app.New(logger,
+//		app.Component{Name: "http", DependsOn: []string{"migrations"}, Start: server.Start, Stop: server.Shutdown},
+//		app.Component{Name: "db", DependsOn: []string{"config"}, Start: openDB, Stop: closeDB},
+//		app.Component{Name: "config", Start: loadConfig},
+//		app.Component{Name: "migrations", DependsOn: []string{"db"}, Start: migrate, Timeout: 5 * time.Minute},
+//	)
+//	if err := a.Run(ctx); err != nil { // stops when ctx is cancelled
+//		...
+//	}
+//
+// When a component fails to start the ones already started are stopped before Start returns.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/log"
+)
+
+// DefaultTimeout is how long a component may take to start or stop unless it sets a Timeout
+const DefaultTimeout = 30 * time.Second
+
+// Component is a part of the service which is started and stopped
+type Component struct {
+	Name string
+
+	// DependsOn are the names of components which must start before this one, and are stopped after it
+	DependsOn []string
+
+	// Start is required and is given the context passed to App.Start, so work it begins can
+	// keep using it after Start returns. Start is abandoned, and Stop is called, when it doesn't
+	// return within Timeout, so Stop must handle a Start which is still running.
+	Start func(ctx context.Context) error
+
+	// Stop is optional and is given a context which is cancelled after Timeout
+	Stop func(ctx context.Context) error
+
+	// Timeout limits Start and Stop, DefaultTimeout when zero
+	Timeout time.Duration
+}
+
+// ComponentError is returned when a component fails to start or stop
+type ComponentError struct {
+	Component string
+	Op        string // "start" or "stop"
+	Err       error
+}
+
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("app: %s %s: %v", e.Op, e.Component, e.Err)
+}
+
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// StartError is returned from Start when a component fails to start. Rollback holds the errors
+// of components which then failed to stop.
+type StartError struct {
+	*ComponentError
+	Rollback base.ErrorList
+}
+
+func (e *StartError) Error() string {
+	if e.Rollback.Empty() {
+		return e.ComponentError.Error()
+	}
+	return fmt.Sprintf("%v (rollback: %v)", e.ComponentError, e.Rollback)
+}
+
+// App starts and stops components in dependency order
+type App struct {
+	logger     log.Logger
+	components []Component
+	started    []Component
+}
+
+// New orders components so each starts after its dependencies. Components without a path
+// between them start in the order given. Unknown dependencies and cycles are errors.
+func New(logger log.Logger, components ...Component) (*App, error) {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		if c.Name == "" {
+			return nil, errors.New("app: component without a name")
+		}
+		if _, ok := byName[c.Name]; ok {
+			return nil, fmt.Errorf("app: duplicate component %s", c.Name)
+		}
+		if c.Start == nil {
+			return nil, fmt.Errorf("app: %s has no Start", c.Name)
+		}
+		byName[c.Name] = c
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(components))
+	ordered := make([]Component, 0, len(components))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("app: dependency cycle %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		c := byName[name]
+		path = append(append([]string(nil), path...), name)
+		for _, dep := range c.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("app: %s depends on unknown component %s", name, dep)
+			}
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		ordered = append(ordered, c)
+		return nil
+	}
+	for _, c := range components {
+		if err := visit(c.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &App{
+		logger:     logger.Set("component", log.String("app")),
+		components: ordered,
+	}, nil
+}
+
+// Order returns the names of components in the order they start
+func (a *App) Order() []string {
+	names := make([]string, len(a.components))
+	for i, c := range a.components {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Start starts each component in order. When one fails, exceeds its timeout or ctx is cancelled
+// the components already started, including one still starting, are stopped in reverse and a
+// *StartError is returned.
+func (a *App) Start(ctx context.Context) error {
+	for _, c := range a.components {
+		started := time.Now()
+		if abandoned, err := call(ctx, c, c.Start, false); err != nil {
+			// a Start which hasn't returned may still bring the component up, so it's stopped too
+			if abandoned {
+				a.started = append(a.started, c)
+			}
+
+			a.logger.Error().With(log.Fields{
+				"name": log.String(c.Name),
+			}).LogErrorf("starting component failed: %v", err)
+
+			serr := &StartError{
+				ComponentError: &ComponentError{Component: c.Name, Op: "start", Err: err},
+			}
+			if err := a.Stop(context.Background()); err != nil {
+				var list base.ErrorList
+				if errors.As(err, &list) {
+					serr.Rollback = list
+				}
+			}
+			return serr
+		}
+		a.started = append(a.started, c)
+
+		a.logger.Info().With(log.Fields{
+			"name":        log.String(c.Name),
+			"duration_ms": log.Int(int(time.Since(started).Milliseconds())),
+		}).Log("started component")
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Every component is stopped even when
+// others fail, and their errors are returned as a base.ErrorList of *ComponentError.
+func (a *App) Stop(ctx context.Context) error {
+	var failed base.ErrorList
+	for i := len(a.started) - 1; i >= 0; i-- {
+		c := a.started[i]
+		if c.Stop == nil {
+			continue
+		}
+		if _, err := call(ctx, c, c.Stop, true); err != nil {
+			a.logger.Error().With(log.Fields{
+				"name": log.String(c.Name),
+			}).LogErrorf("stopping component failed: %v", err)
+
+			failed.Add(&ComponentError{Component: c.Name, Op: "stop", Err: err})
+			continue
+		}
+		a.logger.Info().With(log.Fields{
+			"name": log.String(c.Name),
+		}).Log("stopped component")
+	}
+	a.started = nil
+
+	if failed.Empty() {
+		return nil
+	}
+	return failed
+}
+
+// Run starts the components, waits for ctx to be cancelled and then stops them. Components
+// are given their own timeouts to stop rather than the cancelled ctx.
+func (a *App) Run(ctx context.Context) error {
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return a.Stop(context.Background())
+}
+
+// call waits up to c's timeout for fn and reports if fn was abandoned. fn is given ctx cancelled
+// at the deadline when limit is set, otherwise ctx itself. fn is abandoned when it doesn't return
+// by the deadline or before ctx is cancelled, as some clients ignore their context while
+// connecting.
+func call(ctx context.Context, c Component, fn func(context.Context) error, limit bool) (bool, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	wait, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	fnCtx := ctx
+	if limit {
+		fnCtx = wait
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- fn(fnCtx)
+	}()
+	select {
+	case err := <-result:
+		return false, err
+	case <-wait.Done():
+		if ctx.Err() == nil {
+			return true, fmt.Errorf("timed out after %v: %w", timeout, wait.Err())
+		}
+		return true, ctx.Err()
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) component(name string, deps ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: deps,
+		Start:     r.call("start " + name),
+		Stop:      r.call("stop " + name),
+	}
+}
+
+func (r *recorder) call(name string) func(context.Context) error {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, name)
+		return nil
+	}
+}
+
+func TestApp(t *testing.T) {
+	r := &recorder{}
+	a, err := New(nil,
+		r.component("http", "migrations", "config"),
+		r.component("db", "config"),
+		r.component("config"),
+		r.component("migrations", "db"),
+		r.component("metrics"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"config", "db", "migrations", "http", "metrics"}, a.Order())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.calls) == 5
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, []string{
+		"start config", "start db", "start migrations", "start http", "start metrics",
+		"stop metrics", "stop http", "stop migrations", "stop db", "stop config",
+	}, r.calls)
+}
+
+func TestApp__Rollback(t *testing.T) {
+	r := &recorder{}
+	migrations := r.component("migrations", "db")
+	migrations.Start = func(context.Context) error {
+		return errors.New("bad migration")
+	}
+	db := r.component("db")
+	db.Stop = func(context.Context) error {
+		return errors.New("close failed")
+	}
+
+	a, err := New(nil, r.component("config"), db, migrations, r.component("http", "migrations"))
+	require.NoError(t, err)
+
+	err = a.Start(context.Background())
+	var serr *StartError
+	require.True(t, errors.As(err, &serr))
+	require.Equal(t, "migrations", serr.Component)
+	require.Len(t, serr.Rollback, 1)
+	require.EqualError(t, err, "app: start migrations: bad migration (rollback: app: stop db: close failed)")
+	require.Equal(t, []string{"start config", "start db", "stop config"}, r.calls)
+
+	// nothing is left to stop
+	require.NoError(t, a.Stop(context.Background()))
+}
+
+func TestApp__Timeout(t *testing.T) {
+	r := &recorder{}
+	slow := Component{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Start: func(ctx context.Context) error {
+			time.Sleep(time.Second) // ignores ctx
+			return nil
+		},
+		Stop: r.call("stop slow"),
+	}
+	a, err := New(nil, r.component("config"), slow)
+	require.NoError(t, err)
+
+	err = a.Start(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "app: start slow: timed out after 10ms")
+	require.Equal(t, []string{"start config", "stop slow", "stop config"}, r.calls)
+}
+
+func TestApp__StartCancelled(t *testing.T) {
+	r := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := Component{
+		Name: "slow",
+		Start: func(context.Context) error {
+			cancel()
+			time.Sleep(time.Second) // still coming up when ctx is cancelled
+			return nil
+		},
+		Stop: r.call("stop slow"),
+	}
+	a, err := New(nil, r.component("config"), slow)
+	require.NoError(t, err)
+
+	err = a.Start(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []string{"start config", "stop slow", "stop config"}, r.calls)
+}
+
+func TestApp__StartContext(t *testing.T) {
+	var started context.Context
+	a, err := New(nil, Component{
+		Name:    "worker",
+		Timeout: 10 * time.Millisecond,
+		Start: func(ctx context.Context) error {
+			started = ctx
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, a.Start(ctx))
+
+	// the context outlives Start and its timeout
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, started.Err())
+
+	cancel()
+	require.Error(t, started.Err())
+}
+
+func TestApp__Stop(t *testing.T) {
+	r := &recorder{}
+	a, err := New(nil, r.component("a"), Component{Name: "b", Start: r.call("start b")}, r.component("c"))
+	require.NoError(t, err)
+	require.NoError(t, a.Start(context.Background()))
+
+	failing := errors.New("failed")
+	a.started[2].Stop = func(context.Context) error { return failing }
+	a.started[0].Stop = func(context.Context) error { return failing }
+
+	err = a.Stop(context.Background())
+	var list base.ErrorList
+	require.True(t, errors.As(err, &list))
+	require.Len(t, list, 2)
+	require.ErrorIs(t, list[0], failing)
+
+	var cerr *ComponentError
+	require.True(t, errors.As(list[1], &cerr))
+	require.Equal(t, "a", cerr.Component)
+}
+
+func TestNew__Errors(t *testing.T) {
+	r := &recorder{}
+	start := r.call("start")
+
+	_, err := New(nil, Component{Start: start})
+	require.ErrorContains(t, err, "component without a name")
+
+	_, err = New(nil, Component{Name: "a", Start: start}, Component{Name: "a", Start: start})
+	require.ErrorContains(t, err, "duplicate component a")
+
+	_, err = New(nil, Component{Name: "a"})
+	require.ErrorContains(t, err, "a has no Start")
+
+	_, err = New(nil, Component{Name: "a", DependsOn: []string{"b"}, Start: start})
+	require.ErrorContains(t, err, "a depends on unknown component b")
+
+	_, err = New(nil,
+		Component{Name: "a", DependsOn: []string{"b"}, Start: start},
+		Component{Name: "b", DependsOn: []string{"c"}, Start: start},
+		Component{Name: "c", DependsOn: []string{"a"}, Start: start},
+	)
+	require.ErrorContains(t, err, "dependency cycle a -> b -> c -> a")
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package authz implements role based authorization. A Checker decides if a Subject holds a
+// Permission, and Middleware stores both in the request's context so handlers and services
+// check permissions the same way.
+//
+//	policy, err := authz.NewStaticPolicy(cfg.Authz)
+//	handler := authz.Middleware(policy, subjectFromJWT, router)
+//
+//	func (s *service) CreateTransfer(ctx context.Context, xfer Transfer) error {
+//		if err := authz.RequirePermission(ctx, "transfers.create"); err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Policies are loaded from config, where a permission ending in ".*" grants everything beneath it:
+//
+//	Authz:
+//	  Roles:
+//	    operator: ["transfers.*", "files.read"]
+//	    auditor: ["transfers.read", "files.read"]
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/moov-io/base/ctxkeys"
+)
+
+// Permission names an action, such as "transfers.create"
+type Permission string
+
+// Role is a named set of permissions
+type Role string
+
+// Subject is the caller being authorized
+type Subject struct {
+	ID    string
+	Roles []Role
+}
+
+var (
+	// ErrUnauthenticated is returned when there's no Subject to authorize
+	ErrUnauthenticated = errors.New("authentication required")
+
+	// ErrForbidden is returned when a Subject lacks a Permission
+	ErrForbidden = errors.New("permission denied")
+)
+
+// Checker decides if a subject holds a permission. Check returns nil when it does and an error
+// wrapping ErrForbidden when it doesn't.
+type Checker interface {
+	Check(ctx context.Context, subject Subject, permission Permission) error
+}
+
+type authorization struct {
+	checker Checker
+	subject Subject
+}
+
+var authzKey = ctxkeys.New[authorization]("authz")
+
+// WithSubject returns a copy of ctx where permissions of subject are checked by checker
+func WithSubject(ctx context.Context, checker Checker, subject Subject) context.Context {
+	return authzKey.Set(ctx, authorization{checker: checker, subject: subject})
+}
+
+// SubjectFrom returns the Subject stored by WithSubject or Middleware
+func SubjectFrom(ctx context.Context) (Subject, bool) {
+	a, ok := authzKey.Get(ctx)
+	return a.subject, ok
+}
+
+// RequirePermission returns nil when the subject of ctx holds permission. ErrUnauthenticated is
+// returned when ctx has no subject.
+func RequirePermission(ctx context.Context, permission Permission) error {
+	a, ok := authzKey.Get(ctx)
+	if !ok || a.checker == nil {
+		return ErrUnauthenticated
+	}
+	return a.checker.Check(ctx, a.subject, permission)
+}
+
+// Middleware stores the Subject returned by authenticate in each request's context for
+// RequirePermission. Requests without a subject are served so public routes keep working,
+// permission checks then return ErrUnauthenticated.
+func Middleware(checker Checker, authenticate func(r *http.Request) (Subject, bool), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subject, ok := authenticate(r); ok {
+			r = r.WithContext(WithSubject(r.Context(), checker, subject))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require returns an http.Handler which only serves requests whose subject holds permission.
+// Others are rejected with 401 or 403.
+func Require(permission Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := RequirePermission(r.Context(), permission); err != nil {
+			WriteError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteError responds with 401 for ErrUnauthenticated, 403 for ErrForbidden and 500 otherwise.
+func WriteError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		status = http.StatusForbidden
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// PolicyConfig lists the permissions of each role
+type PolicyConfig struct {
+	Roles map[string][]string
+}
+
+// StaticPolicy is a Checker of roles loaded from config
+type StaticPolicy struct {
+	roles map[Role]map[Permission]bool
+}
+
+// NewStaticPolicy returns a Checker granting each role its permissions in cfg
+func NewStaticPolicy(cfg PolicyConfig) (*StaticPolicy, error) {
+	p := &StaticPolicy{
+		roles: make(map[Role]map[Permission]bool, len(cfg.Roles)),
+	}
+	for role, permissions := range cfg.Roles {
+		granted := make(map[Permission]bool, len(permissions))
+		for _, perm := range permissions {
+			if err := validPermission(perm); err != nil {
+				return nil, fmt.Errorf("authz: role %s: %v", role, err)
+			}
+			granted[Permission(perm)] = true
+		}
+		p.roles[Role(role)] = granted
+	}
+	return p, nil
+}
+
+func validPermission(perm string) error {
+	if perm == "" || strings.ContainsAny(perm, " \t\n") {
+		return fmt.Errorf("invalid permission %q", perm)
+	}
+	if i := strings.Index(perm, "*"); i >= 0 && perm != "*" && (i != len(perm)-1 || !strings.HasSuffix(perm, ".*")) {
+		return fmt.Errorf("invalid permission %q: wildcards must end the permission", perm)
+	}
+	return nil
+}
+
+// Check returns nil when one of the subject's roles grants permission
+func (p *StaticPolicy) Check(ctx context.Context, subject Subject, permission Permission) error {
+	for _, role := range subject.Roles {
+		if granted(p.roles[role], permission) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s requires %s", ErrForbidden, subjectName(subject), permission)
+}
+
+func subjectName(s Subject) string {
+	if s.ID == "" {
+		return "caller"
+	}
+	return s.ID
+}
+
+// granted checks permission against exact grants and the wildcards of each parent:
+// "transfers.create.batch" is granted by "transfers.create.*", "transfers.*" and "*".
+func granted(grants map[Permission]bool, permission Permission) bool {
+	if len(grants) == 0 {
+		return false
+	}
+	if grants[permission] || grants["*"] {
+		return true
+	}
+	name := string(permission)
+	for i := strings.LastIndex(name, "."); i > 0; i = strings.LastIndex(name, ".") {
+		name = name[:i]
+		if grants[Permission(name+".*")] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy(t *testing.T) *StaticPolicy {
+	t.Helper()
+
+	policy, err := NewStaticPolicy(PolicyConfig{
+		Roles: map[string][]string{
+			"operator": {"transfers.*", "files.read"},
+			"auditor":  {"transfers.read", "files.read"},
+			"admin":    {"*"},
+		},
+	})
+	require.NoError(t, err)
+	return policy
+}
+
+func TestStaticPolicy(t *testing.T) {
+	policy := testPolicy(t)
+	ctx := context.Background()
+
+	operator := Subject{ID: "jane", Roles: []Role{"operator"}}
+	require.NoError(t, policy.Check(ctx, operator, "transfers.create"))
+	require.NoError(t, policy.Check(ctx, operator, "transfers.create.batch"))
+	require.NoError(t, policy.Check(ctx, operator, "files.read"))
+
+	err := policy.Check(ctx, operator, "files.delete")
+	require.ErrorIs(t, err, ErrForbidden)
+	require.Equal(t, "permission denied: jane requires files.delete", err.Error())
+
+	auditor := Subject{Roles: []Role{"auditor"}}
+	require.NoError(t, policy.Check(ctx, auditor, "transfers.read"))
+	require.ErrorIs(t, policy.Check(ctx, auditor, "transfers.create"), ErrForbidden)
+	require.ErrorIs(t, policy.Check(ctx, auditor, "transfers"), ErrForbidden)
+
+	require.NoError(t, policy.Check(ctx, Subject{Roles: []Role{"unknown", "admin"}}, "files.delete"))
+	require.ErrorIs(t, policy.Check(ctx, Subject{}, "files.read"), ErrForbidden)
+}
+
+func TestNewStaticPolicy__Invalid(t *testing.T) {
+	for _, perm := range []string{"", "transfers create", "transfers*", "transfers.*.read"} {
+		_, err := NewStaticPolicy(PolicyConfig{
+			Roles: map[string][]string{"operator": {perm}},
+		})
+		require.Error(t, err, "permission %q", perm)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	ctx := context.Background()
+	require.ErrorIs(t, RequirePermission(ctx, "transfers.read"), ErrUnauthenticated)
+
+	ctx = WithSubject(ctx, testPolicy(t), Subject{ID: "jane", Roles: []Role{"auditor"}})
+	require.NoError(t, RequirePermission(ctx, "transfers.read"))
+	require.ErrorIs(t, RequirePermission(ctx, "transfers.create"), ErrForbidden)
+
+	subject, ok := SubjectFrom(ctx)
+	require.True(t, ok)
+	require.Equal(t, "jane", subject.ID)
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/transfers", Require("transfers.create", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})))
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Middleware(testPolicy(t), func(r *http.Request) (Subject, bool) {
+		role := r.Header.Get("X-Role")
+		return Subject{Roles: []Role{Role(role)}}, role != ""
+	}, mux)
+
+	serve := func(path, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, nil)
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, serve("/transfers", "operator").Code)
+	require.Equal(t, http.StatusForbidden, serve("/transfers", "auditor").Code)
+
+	w := serve("/transfers", "")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.JSONEq(t, `{"error":"authentication required"}`, w.Body.String())
+
+	require.Equal(t, http.StatusOK, serve("/ping", "").Code)
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rickar/cal"
+)
+
+// yearBankingDays has a bit set for each day of a year (by YearDay) which is a banking day
+type yearBankingDays [6]uint64
+
+var (
+	bankingDaysMu sync.RWMutex
+	bankingDays   = make(map[int]*yearBankingDays)
+
+	rulesCalendarOnce sync.Once
+	rulesCalendar     *cal.Calendar
+)
+
+// isBankingDay reports whether d is a banking day from a table of its year, which is built on
+// first use by checking every day against the holiday schedule and rules.
+func isBankingDay(d Date) bool {
+	t := d.In(time.UTC) // normalizes out of range days and months
+	year, day := t.Year(), t.YearDay()-1
+
+	bankingDaysMu.RLock()
+	table, ok := bankingDays[year]
+	bankingDaysMu.RUnlock()
+
+	if !ok {
+		table = buildBankingDays(year)
+
+		bankingDaysMu.Lock()
+		if existing, ok := bankingDays[year]; ok {
+			table = existing
+		} else {
+			bankingDays[year] = table
+		}
+		bankingDaysMu.Unlock()
+	}
+	return table[day/64]&(1<<(day%64)) != 0
+}
+
+func buildBankingDays(year int) *yearBankingDays {
+	var table yearBankingDays
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for t := start; t.Year() == year; t = t.AddDate(0, 0, 1) {
+		if computeBankingDay(t) {
+			day := t.YearDay() - 1
+			table[day/64] |= 1 << (day % 64)
+		}
+	}
+	return &table
+}
+
+// computeBankingDay checks t against the embedded holiday schedule. Dates outside of it return
+// a HolidayRangeError from the schedule, so they're checked against the US holiday rules instead.
+// CheckBankingDay returns that error rather than guessing.
+func computeBankingDay(t time.Time) bool {
+	// if date is not a weekend and not a holiday it is banking day.
+	day := t.Weekday()
+	if day == time.Saturday || day == time.Sunday {
+		return false
+	}
+	holiday, err := FederalReserveCalendar().IsHoliday(DateOf(t))
+	if err == nil {
+		return !holiday
+	}
+	return rulesBankingDay(t)
+}
+
+// rulesBankingDay checks t, a weekday, against the US holiday rules
+func rulesBankingDay(t time.Time) bool {
+	day := t.Weekday()
+
+	rulesCalendarOnce.Do(func() {
+		rulesCalendar = cal.NewCalendar()
+		cal.AddUsHolidays(rulesCalendar)
+		rulesCalendar.Observed = cal.ObservedMonday
+	})
+	// and not a holiday
+	if rulesCalendar.IsHoliday(t) {
+		return false
+	}
+	// and not a monday after a holiday
+	if day == time.Monday {
+		return !rulesCalendar.IsHoliday(t.AddDate(0, 0, -1))
+	}
+	return true
+}
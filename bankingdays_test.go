@@ -0,0 +1,42 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankingDays__Table(t *testing.T) {
+	// years on both sides of the embedded schedule's edges
+	for _, year := range []int{1985, 1989, 1990, 2021, 2060, 2061, 2070} {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		for tt := start; tt.Year() == year; tt = tt.AddDate(0, 0, 1) {
+			require.Equal(t, computeBankingDay(tt), isBankingDay(DateOf(tt)), tt.Format(DateFormat))
+		}
+	}
+}
+
+func TestBankingDays__Normalized(t *testing.T) {
+	// December 32nd 2021 is January 1st 2022, New Year's Day
+	require.False(t, isBankingDay(Date{Year: 2021, Month: time.December, Day: 32}))
+	require.True(t, isBankingDay(Date{Year: 2021, Month: time.December, Day: 31 + 3}))
+}
+
+func TestBankingDays__Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := NewDate(2100+i%3, time.July, 5)
+			require.Equal(t, computeBankingDay(d.In(time.UTC)), d.IsBankingDay())
+		}(i)
+	}
+	wg.Wait()
+}
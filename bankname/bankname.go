@@ -0,0 +1,296 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package bankname compares financial institution names, which are written many ways across
+// directories, statements and customer input ("JPMORGAN CHASE BANK, NA" and "Chase").
+//
+//	m := bankname.NewMatcher(bankname.Config{})
+//	m.Match("JPMORGAN CHASE BANK, NA", "Chase")            // true, by alias
+//	m.Match("Citadel Federal Credit Union", "Citadel FCU") // true, by abbreviation
+//	m.Canonical("BofA")                                    // "Bank of America", true
+package bankname
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is the similarity, from 0 to 1, at which names match unless configured
+const DefaultThreshold = 0.9
+
+// DefaultAliases are other names of large US institutions, keyed by their display name
+var DefaultAliases = map[string][]string{
+	"Bank of America": {"BofA", "BOA", "Bank of America NA"},
+	"Capital One":     {"Capital One NA", "Capital One Bank USA"},
+	"Citibank":        {"Citi", "Citibank NA", "Citigroup"},
+	"JPMorgan Chase":  {"Chase", "JP Morgan", "JP Morgan Chase", "Chase Bank USA"},
+	"PNC Bank":        {"PNC"},
+	"Truist":          {"BB&T", "SunTrust"},
+	"U.S. Bank":       {"US Bank", "USB", "US Bancorp"},
+	"Wells Fargo":     {"WF", "Wells Fargo Bank NA"},
+}
+
+// abbreviations are expanded so both forms of a word compare equal
+var abbreviations = map[string][]string{
+	"bk":    {"bank"},
+	"bnk":   {"bank"},
+	"cu":    {"credit", "union"},
+	"fcu":   {"federal", "credit", "union"},
+	"fed":   {"federal"},
+	"fsb":   {"federal", "savings"},
+	"intl":  {"international"},
+	"mt":    {"mount"},
+	"natl":  {"national"},
+	"sav":   {"savings"},
+	"st":    {"saint"},
+	"svgs":  {"savings"},
+	"tr":    {"trust"},
+	"&":     {"and"},
+	"amer":  {"america"},
+	"assn":  {"association"},
+	"assoc": {"association"},
+}
+
+// noise are words which don't distinguish one institution from another
+var noise = map[string]bool{
+	"the": true, "of": true, "and": true, "bank": true, "banking": true, "bancorp": true,
+	"na": true, "association": true, "company": true, "co": true, "corp": true,
+	"corporation": true, "inc": true, "ltd": true, "llc": true, "usa": true,
+	"ssb": true,
+}
+
+// Normalize reduces name to lowercase words without punctuation, abbreviations or words like
+// "bank" and "NA", so names of the same institution written differently are often equal.
+func Normalize(name string) string {
+	var words []string
+	parts := split(name)
+	for i := 0; i < len(parts); i++ {
+		word := parts[i]
+		// "national association" is noise, but "national" alone usually isn't
+		if word == "national" && i+1 < len(parts) && (parts[i+1] == "association" || parts[i+1] == "assn") {
+			i++
+			continue
+		}
+		expanded, ok := abbreviations[word]
+		if !ok {
+			expanded = []string{word}
+		}
+		for _, w := range expanded {
+			if !noise[w] {
+				words = append(words, w)
+			}
+		}
+	}
+	if len(words) == 0 {
+		return strings.Join(split(name), " ")
+	}
+	return strings.Join(words, " ")
+}
+
+// split returns the lowercase words of name. Periods are dropped so "N.A." is "na", and
+// ampersands are kept as their own word.
+func split(name string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '.' || r == '\'':
+		case r == '&':
+			flush()
+			words = append(words, "&")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// Config controls how a Matcher compares names
+type Config struct {
+	// Aliases are other names of institutions keyed by their display name, which are added to
+	// DefaultAliases
+	Aliases map[string][]string
+
+	// Threshold is the Similarity at which names match, DefaultThreshold when zero
+	Threshold float64
+}
+
+// Matcher compares institution names using an alias table and fuzzy matching
+type Matcher struct {
+	canonical map[string]string
+	threshold float64
+}
+
+// NewMatcher returns a Matcher of cfg
+func NewMatcher(cfg Config) *Matcher {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	m := &Matcher{
+		canonical: make(map[string]string),
+		threshold: cfg.Threshold,
+	}
+	for _, aliases := range []map[string][]string{DefaultAliases, cfg.Aliases} {
+		for name, others := range aliases {
+			m.canonical[Normalize(name)] = name
+			for _, other := range others {
+				m.canonical[Normalize(other)] = name
+			}
+		}
+	}
+	return m
+}
+
+// Canonical returns the display name of the institution which name is an alias of
+func (m *Matcher) Canonical(name string) (string, bool) {
+	c, ok := m.canonical[Normalize(name)]
+	return c, ok
+}
+
+// Display returns the canonical name of name when it's known, otherwise name without extra spaces
+func (m *Matcher) Display(name string) string {
+	if c, ok := m.Canonical(name); ok {
+		return c
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func (m *Matcher) key(name string) string {
+	n := Normalize(name)
+	if c, ok := m.canonical[n]; ok {
+		return Normalize(c)
+	}
+	return n
+}
+
+// Similarity returns how alike a and b are from 0 to 1. Aliases of the same institution are 1,
+// otherwise each word of one normalized name is paired with its most similar word of the other
+// by Jaro-Winkler similarity and the pairs are averaged both ways. Comparing words keeps a
+// long shared prefix like "first national" from matching banks in different cities.
+func (m *Matcher) Similarity(a, b string) float64 {
+	return similarity(m.key(a), m.key(b))
+}
+
+// Match returns true when a and b are at least the Matcher's threshold alike
+func (m *Matcher) Match(a, b string) bool {
+	return m.Similarity(a, b) >= m.threshold
+}
+
+// Best returns the candidate most alike name and its similarity, or false when none match
+func (m *Matcher) Best(name string, candidates []string) (string, float64, bool) {
+	key := m.key(name)
+	best, score := "", 0.0
+	for _, c := range candidates {
+		if s := similarity(key, m.key(c)); s > score {
+			best, score = c, s
+		}
+	}
+	if score < m.threshold {
+		return "", score, false
+	}
+	return best, score, true
+}
+
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	w1, w2 := strings.Fields(a), strings.Fields(b)
+	if len(w1) == 0 || len(w2) == 0 {
+		return 0
+	}
+	return (closest(w1, w2) + closest(w2, w1)) / 2
+}
+
+// closest averages the similarity of each word with its most similar word of others
+func closest(words, others []string) float64 {
+	total := 0.0
+	for _, w := range words {
+		best := 0.0
+		for _, o := range others {
+			if s := jaroWinkler(w, o); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+	return total / float64(len(words))
+}
+
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		if len(s1) == len(s2) {
+			return 1
+		}
+		return 0
+	}
+
+	window := maxInt(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		lo, hi := maxInt(0, i-window), minInt(len(s2), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(s1) && prefix < len(s2) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package bankname
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"JPMORGAN CHASE BANK, NA":                "jpmorgan chase",
+		"JPMorgan Chase Bank, N.A.":              "jpmorgan chase",
+		"Citadel FCU":                            "citadel federal credit union",
+		"  Citadel   Federal Credit-Union ":      "citadel federal credit union",
+		"First National Bank of Omaha":           "first national omaha",
+		"Wells Fargo Bank, National Association": "wells fargo",
+		"BB&T":                                   "bb t",
+		"The Bank":                               "the bank",
+		"":                                       "",
+	}
+	for name, expected := range cases {
+		require.Equal(t, expected, Normalize(name), name)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher(Config{
+		Aliases: map[string][]string{
+			"Citadel Credit Union": {"Citadel FCU"},
+		},
+	})
+
+	require.True(t, m.Match("JPMORGAN CHASE BANK, NA", "Chase"))
+	require.True(t, m.Match("Citibank", "CITIBANK N.A."))
+	require.True(t, m.Match("Citadel Federal Credit Union", "Citadel FCU"))
+	require.True(t, m.Match("Frist Republic Bank", "First Republic Bank"))
+	require.False(t, m.Match("Chase", "Wells Fargo"))
+	require.False(t, m.Match("First National Bank of Omaha", "First National Bank of Texas"))
+
+	require.Equal(t, 1.0, m.Similarity("BofA", "Bank of America, N.A."))
+	require.Equal(t, 0.0, m.Similarity("Chase", ""))
+
+	name, ok := m.Canonical("US BANK NA")
+	require.True(t, ok)
+	require.Equal(t, "U.S. Bank", name)
+
+	name, ok = m.Canonical("citadel fcu")
+	require.True(t, ok)
+	require.Equal(t, "Citadel Credit Union", name)
+
+	_, ok = m.Canonical("Lake Shore Savings")
+	require.False(t, ok)
+
+	require.Equal(t, "Wells Fargo", m.Display("WELLS FARGO BANK, NA"))
+	require.Equal(t, "Lake Shore Savings", m.Display("  Lake  Shore   Savings "))
+}
+
+func TestMatcher__Best(t *testing.T) {
+	m := NewMatcher(Config{})
+	candidates := []string{"WELLS FARGO BANK, NA", "JPMORGAN CHASE BANK, NA", "FIRST REPUBLIC BANK"}
+
+	best, score, ok := m.Best("Chase", candidates)
+	require.True(t, ok)
+	require.Equal(t, "JPMORGAN CHASE BANK, NA", best)
+	require.Equal(t, 1.0, score)
+
+	best, _, ok = m.Best("First Repub Bank", candidates)
+	require.True(t, ok)
+	require.Equal(t, "FIRST REPUBLIC BANK", best)
+
+	_, _, ok = m.Best("Lake Shore Savings", candidates)
+	require.False(t, ok)
+
+	// a lower threshold accepts weaker matches
+	_, _, ok = NewMatcher(Config{Threshold: 0.5}).Best("Lake Shore Savings", []string{"Lake Savings"})
+	require.True(t, ok)
+}
+
+func TestJaroWinkler(t *testing.T) {
+	require.InDelta(t, 0.961, jaroWinkler("martha", "marhta"), 0.001)
+	require.InDelta(t, 0.840, jaroWinkler("dwayne", "duane"), 0.001)
+	require.Equal(t, 1.0, jaroWinkler("", ""))
+	require.Equal(t, 0.0, jaroWinkler("abc", "xyz"))
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/benchx"
+)
+
+var (
+	benchTime    = NewTime(time.Date(2021, time.November, 1, 14, 4, 5, 0, time.UTC))
+	benchTimeRaw = []byte(`"2021-11-01T10:04:05-04:00"`)
+	benchAmount  = NewAmount(-123456, "usd")
+
+	benchSink []byte
+)
+
+func BenchmarkTime_MarshalJSON(b *testing.B) {
+	benchx.Run(b, func() {
+		benchSink, _ = benchTime.MarshalJSON()
+	})
+}
+
+func BenchmarkTime_AppendJSON(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	benchx.Run(b, func() {
+		buf = benchTime.AppendJSON(buf[:0])
+	})
+}
+
+func BenchmarkTime_UnmarshalJSON(b *testing.B) {
+	b.Run("iso8601", func(b *testing.B) {
+		var t Time
+		benchx.Run(b, func() {
+			t.UnmarshalJSON(benchTimeRaw)
+		})
+	})
+	b.Run("invalid", func(b *testing.B) {
+		var t Time
+		in := []byte(`"2021-11-01"`)
+		benchx.Run(b, func() {
+			t.UnmarshalJSON(in)
+		})
+	})
+}
+
+func BenchmarkDate_IsBankingDay(b *testing.B) {
+	d := NewDate(2021, time.November, 1)
+	benchx.Run(b, func() {
+		d.AddDays(17).IsBankingDay()
+	})
+}
+
+func BenchmarkTime_IsBankingDay(b *testing.B) {
+	b.Run("schedule", func(b *testing.B) {
+		benchx.Run(b, func() {
+			benchTime.IsBankingDay()
+		})
+	})
+	b.Run("rules", func(b *testing.B) {
+		t := NewTime(time.Date(2075, time.November, 1, 14, 4, 5, 0, time.UTC))
+		benchx.RunParallel(b, func() {
+			t.IsBankingDay()
+		})
+	})
+}
+
+func BenchmarkAmount_String(b *testing.B) {
+	benchx.Run(b, func() {
+		_ = benchAmount.String()
+	})
+}
+
+func BenchmarkCurrencyExponent(b *testing.B) {
+	benchx.Run(b, func() {
+		CurrencyExponent("JPY")
+	})
+}
+
+func TestTime_JSONAllocs(t *testing.T) {
+	var tt Time
+	benchx.Assert(t, benchx.Threshold{Allocs: 0}, func() {
+		tt.UnmarshalJSON(benchTimeRaw)
+	})
+
+	// unparsable values used to build a holiday calendar on every call
+	in := []byte(`"2021-11-01"`)
+	benchx.Assert(t, benchx.Threshold{Allocs: 3}, func() {
+		tt.UnmarshalJSON(in)
+	})
+
+	benchx.Assert(t, benchx.Threshold{Allocs: 1, Bytes: 32}, func() {
+		benchSink, _ = benchTime.MarshalJSON()
+	})
+
+	buf := make([]byte, 0, 64)
+	benchx.Assert(t, benchx.Threshold{Allocs: 0}, func() {
+		buf = benchTime.AppendJSON(buf[:0])
+	})
+}
+
+func TestBankingDay_Allocs(t *testing.T) {
+	future := NewTime(time.Date(2075, time.November, 1, 14, 4, 5, 0, time.UTC))
+	benchx.Assert(t, benchx.Threshold{Allocs: 0}, func() {
+		benchTime.IsBankingDay()
+		future.IsBankingDay()
+		NewDate(2021, time.December, 24).IsBankingDay()
+	})
+}
+
+func TestAmount_Allocs(t *testing.T) {
+	benchx.Assert(t, benchx.Threshold{Allocs: 0}, func() {
+		CurrencyExponent("JPY")
+	})
+	benchx.Assert(t, benchx.Threshold{Allocs: 4}, func() {
+		_ = benchAmount.String()
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package benchx standardizes benchmarks of hot paths and guards them against allocation
+// regressions in regular test runs.
+//
+//	func BenchmarkTime_UnmarshalJSON(b *testing.B) {
+//		in := []byte(`"2021-11-01T10:04:05Z"`)
+//		benchx.Run(b, func() {
+//			var t base.Time
+//			t.UnmarshalJSON(in)
+//		})
+//	}
+//
+//	func TestTime_UnmarshalJSONAllocs(t *testing.T) {
+//		benchx.Assert(t, benchx.Threshold{Allocs: 0}, func() { ... })
+//	}
+package benchx
+
+import (
+	"flag"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var flagSpeed = flag.Bool("benchx.speed", false, "Check the NsPerOp of benchx thresholds, which vary between machines")
+
+// Run reports allocations and calls fn b.N times, excluding any setup before Run from the
+// results.
+func Run(b *testing.B, fn func()) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn()
+	}
+}
+
+// RunParallel is Run for fn called from multiple goroutines, such as code sharing a cache
+func RunParallel(b *testing.B, fn func()) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			fn()
+		}
+	})
+}
+
+// Threshold is the most a single call may cost
+type Threshold struct {
+	// Allocs is the allowed number of heap allocations
+	Allocs float64
+
+	// Bytes is the allowed bytes allocated. Zero means it's not checked.
+	Bytes uint64
+
+	// NsPerOp is only checked with -benchx.speed, as timings depend on the machine running the
+	// tests. Zero means it's not checked.
+	NsPerOp time.Duration
+}
+
+// runs is how many times Assert calls fn when measuring allocations
+const runs = 100
+
+// Assert fails t when calling fn costs more than th. Allocations are averaged over many calls,
+// after a warm up call so lazily built state (caches, sync.Pool) isn't counted. Nothing is
+// checked with -race.
+func Assert(t testing.TB, th Threshold, fn func()) {
+	t.Helper()
+
+	if raceEnabled {
+		return
+	}
+	allocs, bytes := measure(fn)
+	if allocs > th.Allocs {
+		t.Errorf("benchx: %.1f allocs per call, expected at most %.1f", allocs, th.Allocs)
+	}
+	if th.Bytes > 0 && bytes > th.Bytes {
+		t.Errorf("benchx: %d bytes allocated per call, expected at most %d", bytes, th.Bytes)
+	}
+
+	if th.NsPerOp > 0 && *flagSpeed {
+		result := testing.Benchmark(func(b *testing.B) {
+			Run(b, fn)
+		})
+		if ns := time.Duration(result.NsPerOp()); ns > th.NsPerOp {
+			t.Errorf("benchx: %v per call, expected at most %v", ns, th.NsPerOp)
+		}
+	}
+}
+
+// measure returns the average allocations and bytes allocated by fn
+func measure(fn func()) (float64, uint64) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	fn() // warm up
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		fn()
+	}
+	runtime.ReadMemStats(&after)
+
+	allocs := float64(after.Mallocs-before.Mallocs) / runs
+	bytes := (after.TotalAlloc - before.TotalAlloc) / runs
+	return allocs, bytes
+}
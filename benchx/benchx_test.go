@@ -0,0 +1,60 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package benchx
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sink []byte
+
+func TestAssert(t *testing.T) {
+	Assert(t, Threshold{Allocs: 0}, func() {
+		strconv.Itoa(7)
+	})
+
+	Assert(t, Threshold{Allocs: 1, Bytes: 64}, func() {
+		sink = make([]byte, 64)
+	})
+}
+
+func TestAssert__Regression(t *testing.T) {
+	if raceEnabled {
+		t.Skip("-race adds allocations")
+	}
+
+	fake := &testing.T{}
+	Assert(fake, Threshold{Allocs: 0}, func() {
+		sink = make([]byte, 64)
+	})
+	require.True(t, fake.Failed())
+
+	fake = &testing.T{}
+	Assert(fake, Threshold{Allocs: 1, Bytes: 16}, func() {
+		sink = make([]byte, 64)
+	})
+	require.True(t, fake.Failed())
+}
+
+func TestMeasure(t *testing.T) {
+	if raceEnabled {
+		t.Skip("-race adds allocations")
+	}
+
+	allocs, bytes := measure(func() {
+		sink = make([]byte, 1024)
+	})
+	require.Equal(t, 1.0, allocs)
+	require.GreaterOrEqual(t, bytes, uint64(1024))
+}
+
+func BenchmarkRun(b *testing.B) {
+	Run(b, func() {
+		strconv.Itoa(12345)
+	})
+}
@@ -0,0 +1,10 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+//go:build !race
+// +build !race
+
+package benchx
+
+const raceEnabled = false
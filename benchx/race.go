@@ -0,0 +1,11 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+//go:build race
+// +build race
+
+package benchx
+
+// raceEnabled is true when built with -race, which adds allocations of its own
+const raceEnabled = true
@@ -0,0 +1,143 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package bufpool reuses byte buffers between calls to cut allocations when generating files
+// and responses.
+//
+//	buf := bufpool.GetBuffer()
+//	defer bufpool.PutBuffer(buf)
+//
+//	if err := json.NewEncoder(buf).Encode(v); err != nil {
+//		return err
+//	}
+//	w.Write(buf.Bytes())
+//
+// Byte slices are pooled in size classes, so a request for 3KB is served from the 4KB class.
+// Gets and misses (when a new buffer is allocated) are counted in bufpool_gets_total and
+// bufpool_misses_total for each pool.
+package bufpool
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	gets = stdprom.NewCounterVec(stdprom.CounterOpts{
+		Name: "bufpool_gets_total",
+		Help: "Counter of buffers taken from a pool",
+	}, []string{"pool"})
+
+	misses = stdprom.NewCounterVec(stdprom.CounterOpts{
+		Name: "bufpool_misses_total",
+		Help: "Counter of buffers allocated because a pool was empty",
+	}, []string{"pool"})
+)
+
+func init() {
+	stdprom.MustRegister(gets, misses)
+}
+
+// MaxBufferSize is the largest capacity of a Buffer which is returned to the pool. Larger
+// buffers are left for the garbage collector so one big file doesn't pin memory.
+const MaxBufferSize = 1 << 20
+
+// pool is a sync.Pool with counters bound once, as looking up labels on each call allocates
+type pool struct {
+	sync.Pool
+	gets   stdprom.Counter
+	misses stdprom.Counter
+}
+
+func newPool(name string, alloc func() interface{}) *pool {
+	p := &pool{
+		gets:   gets.WithLabelValues(name),
+		misses: misses.WithLabelValues(name),
+	}
+	p.New = func() interface{} {
+		p.misses.Inc()
+		return alloc()
+	}
+	return p
+}
+
+func (p *pool) get() interface{} {
+	p.gets.Inc()
+	return p.Get()
+}
+
+var buffers = newPool("buffer", func() interface{} {
+	return new(bytes.Buffer)
+})
+
+// GetBuffer returns an empty Buffer from the pool
+func GetBuffer() *bytes.Buffer {
+	return buffers.get().(*bytes.Buffer)
+}
+
+// PutBuffer resets buf and returns it to the pool. buf must not be used afterwards.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > MaxBufferSize {
+		return
+	}
+	buf.Reset()
+	buffers.Put(buf)
+}
+
+// sizes are the capacities of each class of pooled byte slices
+var sizes = []int{1 << 9, 1 << 12, 1 << 15, 1 << 18, 1 << 20}
+
+var classes = func() []*pool {
+	out := make([]*pool, len(sizes))
+	for i := range sizes {
+		size := sizes[i]
+		out[i] = newPool(fmt.Sprintf("bytes_%d", size), func() interface{} {
+			bs := make([]byte, size)
+			return &bs
+		})
+	}
+	return out
+}()
+
+// class returns the index of the smallest class holding n bytes, or -1 when n is too large
+func class(n int) int {
+	for i := range sizes {
+		if n <= sizes[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns a slice of length n. Its contents are whatever was last written to it. Slices
+// larger than MaxBufferSize are allocated and not pooled.
+//
+// Slices are passed by pointer so returning them with Put doesn't allocate.
+func Get(n int) *[]byte {
+	c := class(n)
+	if c < 0 {
+		bs := make([]byte, n)
+		return &bs
+	}
+	bs := classes[c].get().(*[]byte)
+	*bs = (*bs)[:n]
+	return bs
+}
+
+// Put returns bs, from Get, to the pool. bs must not be used afterwards.
+func Put(bs *[]byte) {
+	if bs == nil {
+		return
+	}
+	// only full sized slices go back, so every slice in a class can be resliced to its size
+	c := class(cap(*bs))
+	if c < 0 || cap(*bs) != sizes[c] {
+		return
+	}
+	*bs = (*bs)[:cap(*bs)]
+	classes[c].Put(bs)
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuffer(t *testing.T) {
+	buf := GetBuffer()
+	require.Equal(t, 0, buf.Len())
+	buf.WriteString("hello")
+	PutBuffer(buf)
+
+	buf = GetBuffer()
+	require.Equal(t, 0, buf.Len())
+	PutBuffer(buf)
+
+	// oversized buffers aren't kept
+	big := bytes.NewBuffer(make([]byte, 0, MaxBufferSize+1))
+	PutBuffer(big)
+	PutBuffer(nil)
+}
+
+func TestGet(t *testing.T) {
+	bs := Get(3000)
+	require.Len(t, *bs, 3000)
+	require.Equal(t, 4096, cap(*bs))
+	Put(bs)
+
+	bs = Get(10)
+	require.Len(t, *bs, 10)
+	require.Equal(t, 512, cap(*bs))
+	Put(bs)
+
+	bs = Get(MaxBufferSize + 1)
+	require.Len(t, *bs, MaxBufferSize+1)
+	Put(bs) // not pooled
+
+	// slices which weren't from Get are dropped
+	other := make([]byte, 100, 1000)
+	Put(&other)
+	Put(nil)
+}
+
+func TestClass(t *testing.T) {
+	require.Equal(t, 0, class(0))
+	require.Equal(t, 0, class(512))
+	require.Equal(t, 1, class(513))
+	require.Equal(t, 4, class(MaxBufferSize))
+	require.Equal(t, -1, class(MaxBufferSize+1))
+}
+
+func TestMetrics(t *testing.T) {
+	before := testutil.ToFloat64(gets.WithLabelValues("bytes_32768"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bs := Get(20000)
+			Put(bs)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, before+10, testutil.ToFloat64(gets.WithLabelValues("bytes_32768")))
+	require.GreaterOrEqual(t, testutil.ToFloat64(misses.WithLabelValues("bytes_32768")), 1.0)
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package calendar exports the banking calendar as an iCalendar (RFC 5545) feed so operations
+// teams can subscribe to Federal Reserve holidays and ACH cutoffs in their calendar tools.
+//
+//	ics, err := calendar.ExportICS(2021, 2022)
+//
+// admin.Server serves the current and next year's feed from 'GET /calendar.ics'.
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/settlement"
+)
+
+// Options describes the events of a feed
+type Options struct {
+	// Calendar holds the holidays, it defaults to base.FederalReserveCalendar.
+	Calendar *base.HolidayCalendar
+
+	// Cutoffs adds an event at each cutoff of every banking day. The zero value uses
+	// settlement.DefaultCutoffs.
+	Cutoffs settlement.Cutoffs
+
+	// HolidaysOnly leaves out the cutoff events
+	HolidaysOnly bool
+}
+
+// now is when feeds are generated, replaced in tests
+var now = time.Now
+
+// ExportICS returns an iCalendar feed of Federal Reserve holidays and the default ACH cutoffs
+// during years.
+func ExportICS(years ...int) ([]byte, error) {
+	return Options{}.ExportICS(years...)
+}
+
+// ExportICS returns an iCalendar feed of the holidays and cutoffs during years
+func (o Options) ExportICS(years ...int) ([]byte, error) {
+	cal := o.Calendar
+	if cal == nil {
+		cal = base.FederalReserveCalendar()
+	}
+	cutoffs := o.Cutoffs
+	if cutoffs.SameDay == 0 && cutoffs.NextDay == 0 {
+		cutoffs.SameDay, cutoffs.NextDay = settlement.DefaultCutoffs.SameDay, settlement.DefaultCutoffs.NextDay
+	}
+	loc := cutoffs.Location
+	if loc == nil {
+		var err error
+		if loc, err = time.LoadLocation("America/New_York"); err != nil {
+			return nil, fmt.Errorf("calendar: %v", err)
+		}
+	}
+	stamp := now().UTC().Format(icsTime)
+
+	var buf bytes.Buffer
+	w := &icsWriter{buf: &buf}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Moov//base calendar//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("X-WR-CALNAME:" + escape("Federal Reserve banking calendar"))
+
+	for _, year := range years {
+		holidays, err := cal.Holidays(year)
+		if err != nil {
+			return nil, fmt.Errorf("calendar: %v", err)
+		}
+		for _, h := range holidays {
+			summary := "Federal Reserve closed: " + h.Name
+			if !h.Observed.Equal(h.Date) {
+				summary += " (observed)"
+			}
+			w.line("BEGIN:VEVENT")
+			w.line("UID:holiday-" + h.Observed.String() + "@moov.io")
+			w.line("DTSTAMP:" + stamp)
+			w.line("DTSTART;VALUE=DATE:" + h.Observed.In(time.UTC).Format(icsDate))
+			w.line("DTEND;VALUE=DATE:" + h.Observed.AddDays(1).In(time.UTC).Format(icsDate))
+			w.line("SUMMARY:" + escape(summary))
+			w.line("TRANSP:TRANSPARENT")
+			w.line("END:VEVENT")
+		}
+		if o.HolidaysOnly {
+			continue
+		}
+
+		for day := base.NewDate(year, time.January, 1); day.Year == year; day = day.AddDays(1) {
+			open, err := cal.IsBankingDay(day)
+			if err != nil {
+				return nil, fmt.Errorf("calendar: %v", err)
+			}
+			if !open {
+				continue
+			}
+			w.cutoff(day, "same-day", "Same-day ACH cutoff", cutoffs.SameDay, loc, stamp)
+			w.cutoff(day, "next-day", "Next-day ACH cutoff", cutoffs.NextDay, loc, stamp)
+		}
+	}
+
+	w.line("END:VCALENDAR")
+	return buf.Bytes(), nil
+}
+
+const (
+	icsDate = "20060102"
+	icsTime = "20060102T150405Z"
+)
+
+type icsWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *icsWriter) cutoff(day base.Date, kind, summary string, cutoff time.Duration, loc *time.Location, stamp string) {
+	if cutoff <= 0 {
+		return
+	}
+	at := time.Date(day.Year, day.Month, day.Day, 0, 0, 0, int(cutoff), loc).UTC()
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + kind + "-cutoff-" + day.String() + "@moov.io")
+	w.line("DTSTAMP:" + stamp)
+	w.line("DTSTART:" + at.Format(icsTime))
+	w.line("DTEND:" + at.Add(15*time.Minute).Format(icsTime))
+	w.line("SUMMARY:" + escape(summary))
+	w.line("TRANSP:TRANSPARENT")
+	w.line("END:VEVENT")
+}
+
+// line writes a content line, folded at 75 octets as RFC 5545 requires
+func (w *icsWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		// don't split multi-byte characters
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // continuations start with a space
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Handler serves the feed with ExportICS. Years are read from a comma separated "years" query
+// parameter and default to the current and next year.
+func (o Options) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var years []int
+		if param := r.URL.Query().Get("years"); param != "" {
+			for _, y := range strings.Split(param, ",") {
+				year, err := strconv.Atoi(strings.TrimSpace(y))
+				if err != nil {
+					http.Error(w, fmt.Sprintf("invalid year %q", y), http.StatusBadRequest)
+					return
+				}
+				years = append(years, year)
+			}
+		} else {
+			year := now().Year()
+			years = []int{year, year + 1}
+		}
+
+		ics, err := o.ExportICS(years...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write(ics)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package calendar
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fixedNow(t *testing.T) {
+	t.Helper()
+	now = func() time.Time { return time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestExportICS(t *testing.T) {
+	fixedNow(t)
+
+	bs, err := ExportICS(2021)
+	require.NoError(t, err)
+	ics := string(bs)
+
+	require.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+
+	// Independence Day 2021 fell on a Sunday and was observed Monday
+	require.Contains(t, ics, "UID:holiday-2021-07-05@moov.io\r\nDTSTAMP:20210304T120000Z\r\nDTSTART;VALUE=DATE:20210705\r\nDTEND;VALUE=DATE:20210706\r\nSUMMARY:Federal Reserve closed: Independence Day (observed)\r\n")
+
+	// cutoffs are in Eastern time across DST
+	require.Contains(t, ics, "UID:same-day-cutoff-2021-03-04@moov.io\r\nDTSTAMP:20210304T120000Z\r\nDTSTART:20210304T214500Z\r\n")
+	require.Contains(t, ics, "UID:same-day-cutoff-2021-03-15@moov.io\r\nDTSTAMP:20210304T120000Z\r\nDTSTART:20210315T204500Z\r\n")
+	require.Contains(t, ics, "UID:next-day-cutoff-2021-03-15@moov.io\r\nDTSTAMP:20210304T120000Z\r\nDTSTART:20210316T000000Z\r\n")
+
+	// no cutoffs on holidays or weekends
+	require.NotContains(t, ics, "cutoff-2021-07-05")
+	require.NotContains(t, ics, "cutoff-2021-03-06")
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		require.LessOrEqual(t, len(line), 75, line)
+	}
+
+	bs, err = Options{HolidaysOnly: true}.ExportICS(2021, 2022)
+	require.NoError(t, err)
+	require.NotContains(t, string(bs), "cutoff")
+	require.Equal(t, 21, strings.Count(string(bs), "BEGIN:VEVENT"))
+
+	_, err = ExportICS(1500)
+	require.Error(t, err)
+}
+
+func TestICSWriter__Fold(t *testing.T) {
+	var buf bytes.Buffer
+	w := &icsWriter{buf: &buf}
+	w.line("SUMMARY:" + strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		require.LessOrEqual(t, len(line), 75)
+		require.True(t, utf8.ValidString(line), line)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+	require.Equal(t, "SUMMARY:"+strings.Repeat("é", 100), strings.ReplaceAll(strings.Join(lines, "\r\n"), "\r\n ", ""))
+}
+
+func TestHandler(t *testing.T) {
+	fixedNow(t)
+	handler := Options{HolidaysOnly: true}.Handler()
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/calendar.ics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), "holiday-2021-01-01")
+	require.Contains(t, w.Body.String(), "holiday-2022-01-17")
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/calendar.ics?years=2030", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "holiday-2021")
+	require.Contains(t, w.Body.String(), "holiday-2030")
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/calendar.ics?years=next", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/calendar.ics", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
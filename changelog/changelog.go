@@ -0,0 +1,223 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package changelog keeps a tamper-evident history of changes to an entity, such as each
+// status of a transfer. Entries are appended, never updated, and each one includes the hash
+// of the entry before it, so editing or removing an entry breaks the chain.
+//
+//	log := changelog.New(changelog.NewSQLStore(db, "transfer_changes"))
+//	entry, err := log.Append(ctx, "transfer:"+xfer.ID, "status", StatusChange{From: "pending", To: "processed"})
+//
+//	// later, rebuild the transfer and check nothing was altered
+//	xfer, err := changelog.Replay[Transfer](ctx, log, "transfer:"+id, Transfer{}, reducer)
+//
+// Restore reads the latest Snapshot and only the entries after it, for hot entities whose
+// full history is too long to replay on each read.
+package changelog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/jsonx"
+)
+
+// ErrConflict is returned by Append when another entry was appended at the same sequence.
+// Retry the change after reading the latest state.
+var ErrConflict = errors.New("changelog: entry already exists")
+
+// Entry is one change of an entity
+type Entry struct {
+	Entity string `json:"entity"`
+
+	// Sequence starts at 1 for each entity
+	Sequence int64 `json:"sequence"`
+
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt base.Time       `json:"createdAt"`
+
+	// PrevHash is the Hash of the entry before, empty for the first entry
+	PrevHash string `json:"prevHash"`
+
+	// Hash is the SHA-256 of the canonical JSON encoding of every other field
+	Hash string `json:"hash"`
+}
+
+// computeHash returns what e.Hash should be
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	bs, err := jsonx.EncodeCanonical(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Store saves entries
+type Store interface {
+	// Append saves entry, returning ErrConflict when its entity already has an entry at its sequence
+	Append(ctx context.Context, entry Entry) error
+
+	// Last returns the latest entry of entity, or false when there are none
+	Last(ctx context.Context, entity string) (Entry, bool, error)
+
+	// Entries returns the entries of entity after a sequence, ordered by sequence
+	Entries(ctx context.Context, entity string, after int64) ([]Entry, error)
+}
+
+// Log appends and verifies entries in a Store
+type Log struct {
+	store Store
+}
+
+// New returns a Log saving entries in store
+func New(store Store) *Log {
+	return &Log{store: store}
+}
+
+// Append adds a change of typ with data, which is encoded as JSON, to the history of entity
+func (l *Log) Append(ctx context.Context, entity, typ string, data interface{}) (Entry, error) {
+	if entity == "" || typ == "" {
+		return Entry{}, errors.New("changelog: missing entity or type")
+	}
+	bs, err := jsonx.EncodeCanonical(data)
+	if err != nil {
+		return Entry{}, fmt.Errorf("changelog: encoding %s: %w", typ, err)
+	}
+
+	last, found, err := l.store.Last(ctx, entity)
+	if err != nil {
+		return Entry{}, fmt.Errorf("changelog: reading %s: %w", entity, err)
+	}
+	entry := Entry{
+		Entity:    entity,
+		Sequence:  1,
+		Type:      typ,
+		Data:      bs,
+		CreatedAt: base.Now(),
+	}
+	if found {
+		entry.Sequence = last.Sequence + 1
+		entry.PrevHash = last.Hash
+	}
+	if entry.Hash, err = entry.computeHash(); err != nil {
+		return Entry{}, fmt.Errorf("changelog: hashing %s: %w", entity, err)
+	}
+
+	if err := l.store.Append(ctx, entry); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return Entry{}, err
+		}
+		return Entry{}, fmt.Errorf("changelog: appending to %s: %w", entity, err)
+	}
+	return entry, nil
+}
+
+// Entries returns the verified history of entity
+func (l *Log) Entries(ctx context.Context, entity string) ([]Entry, error) {
+	return l.entriesAfter(ctx, entity, 0, "")
+}
+
+// entriesAfter returns the verified entries of entity after sequence, whose hash is prevHash
+func (l *Log) entriesAfter(ctx context.Context, entity string, sequence int64, prevHash string) ([]Entry, error) {
+	entries, err := l.store.Entries(ctx, entity, sequence)
+	if err != nil {
+		return nil, fmt.Errorf("changelog: reading %s: %w", entity, err)
+	}
+	if err := verify(entries, sequence, prevHash); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// BrokenChainError is returned when entries have been altered, removed or reordered
+type BrokenChainError struct {
+	Entity   string
+	Sequence int64
+	Reason   string
+}
+
+func (e *BrokenChainError) Error() string {
+	return fmt.Sprintf("changelog: %s entry %d %s", e.Entity, e.Sequence, e.Reason)
+}
+
+// Verify checks entries are one entity's consecutive history from its first entry, with each
+// entry's hash matching its contents and linking to the one before.
+func Verify(entries []Entry) error {
+	return verify(entries, 0, "")
+}
+
+// verify checks entries continue the chain after sequence, whose hash is prevHash
+func verify(entries []Entry, sequence int64, prevHash string) error {
+	for i, e := range entries {
+		broken := func(reason string) error {
+			return &BrokenChainError{Entity: e.Entity, Sequence: e.Sequence, Reason: reason}
+		}
+		if want := sequence + int64(i+1); e.Sequence != want {
+			return broken(fmt.Sprintf("is out of sequence, expected %d", want))
+		}
+		if i == 0 {
+			if e.PrevHash != prevHash {
+				if prevHash == "" {
+					return broken("is first but has a previous hash")
+				}
+				return broken("doesn't link to the previous entry")
+			}
+		} else {
+			prev := entries[i-1]
+			if e.Entity != prev.Entity {
+				return broken("belongs to another entity than " + prev.Entity)
+			}
+			if e.PrevHash != prev.Hash {
+				return broken("doesn't link to the previous entry")
+			}
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return broken(err.Error())
+		}
+		if hash != e.Hash {
+			return broken("has been altered")
+		}
+	}
+	return nil
+}
+
+// Reducer applies entries to the state of an entity
+type Reducer[S any] interface {
+	Apply(state S, entry Entry) (S, error)
+}
+
+// ReducerFunc is a function implementing Reducer
+type ReducerFunc[S any] func(state S, entry Entry) (S, error)
+
+func (fn ReducerFunc[S]) Apply(state S, entry Entry) (S, error) {
+	return fn(state, entry)
+}
+
+// Replay verifies the history of entity and applies each entry to initial in order
+func Replay[S any](ctx context.Context, l *Log, entity string, initial S, reducer Reducer[S]) (S, error) {
+	entries, err := l.Entries(ctx, entity)
+	if err != nil {
+		return initial, err
+	}
+	return apply(entity, initial, entries, reducer)
+}
+
+func apply[S any](entity string, state S, entries []Entry, reducer Reducer[S]) (S, error) {
+	var err error
+	for _, e := range entries {
+		if state, err = reducer.Apply(state, e); err != nil {
+			return state, fmt.Errorf("changelog: applying %s entry %d: %w", entity, e.Sequence, err)
+		}
+	}
+	return state, nil
+}
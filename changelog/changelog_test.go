@@ -0,0 +1,143 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/database"
+	"github.com/moov-io/base/testtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sqliteStore(t *testing.T) *SQLStore {
+	t.Helper()
+
+	db := database.CreateTestSQLiteDB(t)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	return NewSQLStore(db.DB, "transfer_changes")
+}
+
+func stores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore(t),
+	}
+}
+
+type statusChange struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type transfer struct {
+	Status  string
+	Changes int
+}
+
+var reducer = ReducerFunc[transfer](func(state transfer, entry Entry) (transfer, error) {
+	var change statusChange
+	if err := json.Unmarshal(entry.Data, &change); err != nil {
+		return state, err
+	}
+	if change.Status == "" {
+		return state, errors.New("missing status")
+	}
+	state.Status = change.Status
+	state.Changes++
+	return state, nil
+})
+
+func TestLog(t *testing.T) {
+	clock := testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			log := New(store)
+
+			first, err := log.Append(ctx, "transfer:1", "status", statusChange{Status: "pending"})
+			require.NoError(t, err)
+			require.Equal(t, int64(1), first.Sequence)
+			require.Empty(t, first.PrevHash)
+			require.Len(t, first.Hash, 64)
+
+			clock.Add(time.Minute)
+			second, err := log.Append(ctx, "transfer:1", "status", statusChange{Status: "processed"})
+			require.NoError(t, err)
+			require.Equal(t, int64(2), second.Sequence)
+			require.Equal(t, first.Hash, second.PrevHash)
+
+			_, err = log.Append(ctx, "transfer:2", "status", statusChange{Status: "pending"})
+			require.NoError(t, err)
+
+			entries, err := log.Entries(ctx, "transfer:1")
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			require.JSONEq(t, `{"status":"processed"}`, string(entries[1].Data))
+
+			state, err := Replay[transfer](ctx, log, "transfer:1", transfer{}, reducer)
+			require.NoError(t, err)
+			require.Equal(t, transfer{Status: "processed", Changes: 2}, state)
+
+			// a concurrent append at the same sequence conflicts
+			require.ErrorIs(t, store.Append(ctx, second), ErrConflict)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	log := New(NewMemoryStore())
+	for _, status := range []string{"pending", "processed", "reversed"} {
+		_, err := log.Append(ctx, "transfer:1", "status", statusChange{Status: status})
+		require.NoError(t, err)
+	}
+	entries, err := log.Entries(ctx, "transfer:1")
+	require.NoError(t, err)
+	require.NoError(t, Verify(entries))
+
+	copied := func() []Entry { return append([]Entry(nil), entries...) }
+
+	altered := copied()
+	altered[1].Data = json.RawMessage(`{"status":"failed"}`)
+	var broken *BrokenChainError
+	require.True(t, errors.As(Verify(altered), &broken))
+	require.Equal(t, int64(2), broken.Sequence)
+	require.EqualError(t, Verify(altered), "changelog: transfer:1 entry 2 has been altered")
+
+	// rehashing an edit still breaks the link to the next entry
+	altered[1].Hash, err = altered[1].computeHash()
+	require.NoError(t, err)
+	require.EqualError(t, Verify(altered), "changelog: transfer:1 entry 3 doesn't link to the previous entry")
+
+	removed := append(copied()[:1], entries[2])
+	require.EqualError(t, Verify(removed), "changelog: transfer:1 entry 3 is out of sequence, expected 2")
+
+	// whitespace differences in stored data don't matter
+	reformatted := copied()
+	reformatted[0].Data = json.RawMessage(`{ "status": "pending" }`)
+	require.NoError(t, Verify(reformatted))
+}
+
+func TestReplay__ReducerError(t *testing.T) {
+	ctx := context.Background()
+	log := New(NewMemoryStore())
+	_, err := log.Append(ctx, "transfer:1", "status", statusChange{})
+	require.NoError(t, err)
+
+	_, err = Replay[transfer](ctx, log, "transfer:1", transfer{}, reducer)
+	require.EqualError(t, err, "changelog: applying transfer:1 entry 1: missing status")
+
+	_, err = log.Append(ctx, "", "status", statusChange{})
+	require.Error(t, err)
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package changelog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+)
+
+// DefaultSnapshotEvery is how many entries are applied before a new snapshot when
+// SnapshotConfig.Every is zero
+const DefaultSnapshotEvery = 100
+
+// Snapshot is the reduced state of an entity up to and including the entry at Sequence
+type Snapshot struct {
+	Entity   string `json:"entity"`
+	Sequence int64  `json:"sequence"`
+
+	// Hash is the Hash of the entry at Sequence, which the entries after must link to
+	Hash string `json:"hash"`
+
+	State     json.RawMessage `json:"state"`
+	CreatedAt base.Time       `json:"createdAt"`
+}
+
+// SnapshotStore saves the latest Snapshot of each entity
+type SnapshotStore interface {
+	// SaveSnapshot replaces the entity's snapshot unless one at a later sequence exists
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error
+
+	// LatestSnapshot returns the entity's snapshot, or false when there's none
+	LatestSnapshot(ctx context.Context, entity string) (Snapshot, bool, error)
+}
+
+// SnapshotConfig controls Restore
+type SnapshotConfig struct {
+	Snapshots SnapshotStore
+
+	// Every is how many entries after the latest snapshot are applied before saving another,
+	// DefaultSnapshotEvery when zero
+	Every int
+}
+
+// Restore returns the state of entity from its latest snapshot and the entries after it, so
+// entities with long histories don't need every entry read. A new snapshot is saved once
+// cfg.Every entries were applied. S must round trip through encoding/json.
+//
+// Snapshots aren't part of the hash chain, use Replay when the full history must be verified
+// such as for an audit.
+func Restore[S any](ctx context.Context, l *Log, entity string, initial S, reducer Reducer[S], cfg SnapshotConfig) (S, error) {
+	if cfg.Every <= 0 {
+		cfg.Every = DefaultSnapshotEvery
+	}
+
+	state := initial
+	var sequence int64
+	var prevHash string
+
+	snapshot, found, err := cfg.Snapshots.LatestSnapshot(ctx, entity)
+	if err != nil {
+		return initial, fmt.Errorf("changelog: reading %s snapshot: %w", entity, err)
+	}
+	if found {
+		if err := json.Unmarshal(snapshot.State, &state); err != nil {
+			return initial, fmt.Errorf("changelog: decoding %s snapshot: %w", entity, err)
+		}
+		sequence, prevHash = snapshot.Sequence, snapshot.Hash
+	}
+
+	entries, err := l.entriesAfter(ctx, entity, sequence, prevHash)
+	if err != nil {
+		return initial, err
+	}
+	if state, err = apply(entity, state, entries, reducer); err != nil {
+		return state, err
+	}
+
+	if len(entries) >= cfg.Every {
+		last := entries[len(entries)-1]
+		bs, err := json.Marshal(state)
+		if err != nil {
+			return state, fmt.Errorf("changelog: encoding %s snapshot: %w", entity, err)
+		}
+		err = cfg.Snapshots.SaveSnapshot(ctx, Snapshot{
+			Entity:    entity,
+			Sequence:  last.Sequence,
+			Hash:      last.Hash,
+			State:     bs,
+			CreatedAt: base.Now(),
+		})
+		if err != nil {
+			return state, fmt.Errorf("changelog: saving %s snapshot: %w", entity, err)
+		}
+	}
+	return state, nil
+}
+
+// MemorySnapshotStore keeps snapshots in memory, which suits tests
+type MemorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]Snapshot
+}
+
+// NewMemorySnapshotStore returns an empty MemorySnapshotStore
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+func (s *MemorySnapshotStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.snapshots[snapshot.Entity]; !ok || existing.Sequence < snapshot.Sequence {
+		s.snapshots[snapshot.Entity] = snapshot
+	}
+	return nil
+}
+
+func (s *MemorySnapshotStore) LatestSnapshot(ctx context.Context, entity string) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[entity]
+	return snapshot, ok, nil
+}
+
+// SQLSnapshotStore keeps the latest snapshot of each entity in a table created by the service's
+// migrations:
+//
+//	CREATE TABLE transfer_snapshots (
+//	    entity VARCHAR(128) NOT NULL PRIMARY KEY,
+//	    sequence BIGINT NOT NULL,
+//	    hash CHAR(64) NOT NULL,
+//	    state TEXT NOT NULL,
+//	    created_at BIGINT NOT NULL
+//	);
+//
+// created_at holds Unix nanoseconds. Queries use ? placeholders for MySQL and SQLite.
+type SQLSnapshotStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLSnapshotStore returns a SnapshotStore using table in db
+func NewSQLSnapshotStore(db *sql.DB, table string) *SQLSnapshotStore {
+	return &SQLSnapshotStore{
+		db:    db,
+		table: table,
+	}
+}
+
+func (s *SQLSnapshotStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	update := fmt.Sprintf(`UPDATE %s SET sequence = ?, hash = ?, state = ?, created_at = ? WHERE entity = ? AND sequence < ?`, s.table)
+	res, err := s.db.ExecContext(ctx, update, snapshot.Sequence, snapshot.Hash, string(snapshot.State), snapshot.CreatedAt.UnixNano(), snapshot.Entity, snapshot.Sequence)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (entity, sequence, hash, state, created_at) VALUES (?, ?, ?, ?, ?)`, s.table)
+	_, err = s.db.ExecContext(ctx, insert, snapshot.Entity, snapshot.Sequence, snapshot.Hash, string(snapshot.State), snapshot.CreatedAt.UnixNano())
+	if err != nil && database.UniqueViolation(err) {
+		// a snapshot at the same or a later sequence exists
+		return nil
+	}
+	return err
+}
+
+func (s *SQLSnapshotStore) LatestSnapshot(ctx context.Context, entity string) (Snapshot, bool, error) {
+	query := fmt.Sprintf(`SELECT sequence, hash, state, created_at FROM %s WHERE entity = ?`, s.table)
+
+	snapshot := Snapshot{Entity: entity}
+	var state string
+	var at int64
+	err := s.db.QueryRowContext(ctx, query, entity).Scan(&snapshot.Sequence, &snapshot.Hash, &state, &at)
+	if err == sql.ErrNoRows {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	snapshot.State = []byte(state)
+	snapshot.CreatedAt = base.NewTime(time.Unix(0, at))
+	return snapshot, true, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package changelog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/moov-io/base/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingStore records how many entries a Log reads
+type countingStore struct {
+	Store
+	read int
+}
+
+func (s *countingStore) Entries(ctx context.Context, entity string, after int64) ([]Entry, error) {
+	entries, err := s.Store.Entries(ctx, entity, after)
+	s.read += len(entries)
+	return entries, err
+}
+
+func sqliteSnapshots(t *testing.T) SnapshotStore {
+	t.Helper()
+
+	db := database.CreateTestSQLiteDB(t)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	return NewSQLSnapshotStore(db.DB, "transfer_snapshots")
+}
+
+func TestRestore(t *testing.T) {
+	snapshotStores := map[string]SnapshotStore{
+		"memory": NewMemorySnapshotStore(),
+		"sqlite": sqliteSnapshots(t),
+	}
+	for name, snapshots := range snapshotStores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := &countingStore{Store: NewMemoryStore()}
+			log := New(store)
+			cfg := SnapshotConfig{Snapshots: snapshots, Every: 3}
+
+			appendN := func(n int) {
+				for i := 0; i < n; i++ {
+					_, err := log.Append(ctx, "transfer:1", "status", statusChange{Status: "pending"})
+					require.NoError(t, err)
+				}
+			}
+			restore := func() transfer {
+				store.read = 0
+				state, err := Restore[transfer](ctx, log, "transfer:1", transfer{}, reducer, cfg)
+				require.NoError(t, err)
+				return state
+			}
+
+			appendN(2)
+			require.Equal(t, 2, restore().Changes)
+			_, found, err := snapshots.LatestSnapshot(ctx, "transfer:1")
+			require.NoError(t, err)
+			require.False(t, found)
+
+			appendN(2)
+			require.Equal(t, 4, restore().Changes)
+			snapshot, found, err := snapshots.LatestSnapshot(ctx, "transfer:1")
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, int64(4), snapshot.Sequence)
+
+			// only the tail is read
+			appendN(1)
+			require.Equal(t, transfer{Status: "pending", Changes: 5}, restore())
+			require.Equal(t, 1, store.read)
+
+			// a snapshot at an earlier sequence doesn't replace the latest
+			require.NoError(t, snapshots.SaveSnapshot(ctx, Snapshot{Entity: "transfer:1", Sequence: 1, Hash: "x", State: []byte(`{}`)}))
+			snapshot, _, err = snapshots.LatestSnapshot(ctx, "transfer:1")
+			require.NoError(t, err)
+			require.Equal(t, int64(4), snapshot.Sequence)
+
+			replayed, err := Replay[transfer](ctx, log, "transfer:1", transfer{}, reducer)
+			require.NoError(t, err)
+			require.Equal(t, restore(), replayed)
+		})
+	}
+}
+
+func TestRestore__BrokenTail(t *testing.T) {
+	ctx := context.Background()
+	log := New(NewMemoryStore())
+	snapshots := NewMemorySnapshotStore()
+	for i := 0; i < 2; i++ {
+		_, err := log.Append(ctx, "transfer:1", "status", statusChange{Status: "pending"})
+		require.NoError(t, err)
+	}
+
+	// the snapshot's hash doesn't match the entry it claims to follow
+	require.NoError(t, snapshots.SaveSnapshot(ctx, Snapshot{Entity: "transfer:1", Sequence: 1, Hash: "tampered", State: []byte(`{}`)}))
+
+	_, err := Restore[transfer](ctx, log, "transfer:1", transfer{}, reducer, SnapshotConfig{Snapshots: snapshots})
+	var broken *BrokenChainError
+	require.True(t, errors.As(err, &broken))
+	require.Equal(t, int64(2), broken.Sequence)
+}
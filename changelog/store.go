@@ -0,0 +1,139 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package changelog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+)
+
+// MemoryStore keeps entries in memory, which suits tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string][]Entry
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string][]Entry),
+	}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if int64(len(s.entries[entry.Entity])) >= entry.Sequence {
+		return ErrConflict
+	}
+	s.entries[entry.Entity] = append(s.entries[entry.Entity], entry)
+	return nil
+}
+
+func (s *MemoryStore) Last(ctx context.Context, entity string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[entity]
+	if len(entries) == 0 {
+		return Entry{}, false, nil
+	}
+	return entries[len(entries)-1], true, nil
+}
+
+func (s *MemoryStore) Entries(ctx context.Context, entity string, after int64) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Entry
+	for _, e := range s.entries[entity] {
+		if e.Sequence > after {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// SQLStore keeps entries in a table created by the service's migrations. The primary key makes
+// concurrent appends to an entity conflict instead of forking its history:
+//
+//	CREATE TABLE transfer_changes (
+//	    entity VARCHAR(128) NOT NULL,
+//	    sequence BIGINT NOT NULL,
+//	    change_type VARCHAR(64) NOT NULL,
+//	    data TEXT NOT NULL,
+//	    created_at BIGINT NOT NULL,
+//	    prev_hash CHAR(64) NOT NULL,
+//	    hash CHAR(64) NOT NULL,
+//	    PRIMARY KEY (entity, sequence)
+//	);
+//
+// created_at holds Unix nanoseconds. Queries use ? placeholders for MySQL and SQLite.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store using table in db
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	return &SQLStore{
+		db:    db,
+		table: table,
+	}
+}
+
+const entryColumns = `entity, sequence, change_type, data, created_at, prev_hash, hash`
+
+func (s *SQLStore) Append(ctx context.Context, entry Entry) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?)`, s.table, entryColumns)
+	_, err := s.db.ExecContext(ctx, query, entry.Entity, entry.Sequence, entry.Type, string(entry.Data), entry.CreatedAt.UnixNano(), entry.PrevHash, entry.Hash)
+	if err != nil && database.UniqueViolation(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *SQLStore) Last(ctx context.Context, entity string) (Entry, bool, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE entity = ? ORDER BY sequence DESC LIMIT 1`, entryColumns, s.table)
+	entries, err := s.query(ctx, query, entity)
+	if err != nil || len(entries) == 0 {
+		return Entry{}, false, err
+	}
+	return entries[0], true, nil
+}
+
+func (s *SQLStore) Entries(ctx context.Context, entity string, after int64) ([]Entry, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE entity = ? AND sequence > ? ORDER BY sequence`, entryColumns, s.table)
+	return s.query(ctx, query, entity, after)
+}
+
+func (s *SQLStore) query(ctx context.Context, query string, args ...interface{}) ([]Entry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Entry
+	for rows.Next() {
+		var e Entry
+		var data string
+		var at int64
+		if err := rows.Scan(&e.Entity, &e.Sequence, &e.Type, &data, &at, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		e.Data = []byte(data)
+		e.CreatedAt = base.NewTime(time.Unix(0, at))
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
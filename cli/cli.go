@@ -0,0 +1,326 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package cli builds a service's startup configuration from its defaults, a config file,
+// environment variables and flags in one call.
+//
+//	cfg := Config{HTTP: HTTP{BindAddress: ":8080"}} // defaults
+//	err := cli.Load(&cfg, cli.Options{EnvPrefix: "ACH"})
+//	if errors.Is(err, cli.ErrConfigValid) {
+//		os.Exit(0) // started with -validate-config
+//	}
+//	if err != nil {
+//		logger.Fatal().LogError(err)
+//	}
+//
+// Every field of the config struct can be set with a flag and environment variable named after
+// its path, so HTTP.BindAddress is -http.bind-address and ACH_HTTP_BIND_ADDRESS. Values are
+// applied in that order of precedence: flags, environment, the file passed with -config, then
+// defaults. Fields are skipped with a `cli:"-"` tag and described in -help with `usage:"..."`.
+//
+// The -config file is read with config.Service. The loaded config is logged with redact.Config,
+// which hides fields tagged `redact:"mask"` or `redact:"omit"` and those named like passwords,
+// secrets, tokens or keys, and validated when it implements Validate() error.
+//
+// Command runs the subcommands of operational tools, such as migrate or verify-manifest,
+// with consistent flags, usage and exit codes.
+package cli
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	baseconfig "github.com/moov-io/base/config"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/redact"
+)
+
+// ErrConfigValid is returned by Load after the config was checked with -validate-config. Callers
+// should exit successfully rather than start the service.
+var ErrConfigValid = errors.New("cli: config is valid")
+
+// Options describes where Load reads configuration from
+type Options struct {
+	// Name is shown in -help. It defaults to the program's name.
+	Name string
+
+	// Args are the command line arguments, without the program name. They default to os.Args[1:].
+	Args []string
+
+	// EnvPrefix is prepended, with an underscore, to environment variable names
+	EnvPrefix string
+
+	// ConfigFile is read when -config isn't given. Files are read with viper, so YAML, JSON
+	// and TOML are supported.
+	ConfigFile string
+
+	// Logger records the loaded config. It defaults to log.NewDefaultLogger.
+	Logger log.Logger
+
+	// LookupEnv defaults to os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+}
+
+// field is a settable value of the config struct
+type field struct {
+	flag  string
+	env   string
+	usage string
+	value reflect.Value
+}
+
+// Load fills config, a pointer to a struct, from opts and logs the result. flag.ErrHelp is
+// returned when -help was asked for.
+func Load(config interface{}, opts Options) error {
+	rv := reflect.ValueOf(config)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cli: config must be a pointer to a struct, got %T", config)
+	}
+	if opts.Name == "" {
+		opts.Name = os.Args[0]
+	}
+	if opts.Args == nil {
+		opts.Args = os.Args[1:]
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.NewDefaultLogger()
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	fields := collect(rv.Elem(), "", opts.EnvPrefix, nil)
+
+	fs := flag.NewFlagSet(opts.Name, flag.ContinueOnError)
+	configFile := fs.String("config", opts.ConfigFile, "Path of a YAML, JSON or TOML config file")
+	validateOnly := fs.Bool("validate-config", false, "Load and validate the config, then exit")
+
+	given := make(map[string]string)
+	for i := range fields {
+		f := fields[i]
+		usage := f.usage
+		if usage == "" {
+			usage = fmt.Sprintf("Sets %s", f.flag)
+		}
+		fs.Var(&flagValue{field: f, given: given}, f.flag, fmt.Sprintf("%s (env %s)", usage, f.env))
+	}
+	if err := fs.Parse(opts.Args); err != nil {
+		return err
+	}
+
+	if *configFile != "" {
+		svc := baseconfig.NewService(opts.Logger)
+		if err := svc.LoadPath(*configFile, config); err != nil {
+			return fmt.Errorf("cli: loading %s: %v", *configFile, err)
+		}
+	}
+
+	for i := range fields {
+		if value, ok := opts.LookupEnv(fields[i].env); ok {
+			if err := set(fields[i].value, value); err != nil {
+				return fmt.Errorf("cli: %s: %v", fields[i].env, err)
+			}
+		}
+	}
+	for i := range fields {
+		if value, ok := given[fields[i].flag]; ok {
+			if err := set(fields[i].value, value); err != nil {
+				return fmt.Errorf("cli: -%s: %v", fields[i].flag, err)
+			}
+		}
+	}
+
+	if v, ok := config.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("cli: invalid config: %w", err)
+		}
+	}
+
+	effective, err := json.Marshal(redact.Config(config))
+	if err != nil {
+		return fmt.Errorf("cli: %v", err)
+	}
+	opts.Logger.Info().With(log.Fields{
+		"config": log.String(string(effective)),
+	}).Log("loaded config")
+
+	if *validateOnly {
+		return ErrConfigValid
+	}
+	return nil
+}
+
+// collect returns the settable fields of v, a struct, and its nested structs
+func collect(v reflect.Value, path, env string, out []field) []field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("cli") == "-" {
+			continue
+		}
+		name := kebab(sf.Name)
+		p := name
+		if path != "" {
+			p = path + "." + name
+		}
+		e := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if env != "" {
+			e = env + "_" + e
+		}
+
+		fv := v.Field(i)
+		if settable(fv) {
+			out = append(out, field{flag: p, env: e, usage: sf.Tag.Get("usage"), value: fv})
+		} else if fv.Kind() == reflect.Struct {
+			out = collect(fv, p, e, out)
+		}
+	}
+	return out
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func settable(v reflect.Value) bool {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// set parses s into v. Slices are read as comma separated values.
+func set(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i := range items {
+			out.Index(i).SetString(items[i])
+		}
+		v.Set(out)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// flagValue records a flag's value to be applied after the config file and environment
+type flagValue struct {
+	field field
+	given map[string]string
+}
+
+func (f *flagValue) String() string {
+	if f == nil || !f.field.value.IsValid() {
+		return ""
+	}
+	if v, ok := f.field.value.Addr().Interface().(encoding.TextMarshaler); ok {
+		bs, _ := v.MarshalText()
+		return string(bs)
+	}
+	if v := f.field.value; v.Kind() == reflect.Slice {
+		// walk the slice since named types (type Codes []string) can't be asserted to []string
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("%v", f.field.value.Interface())
+}
+
+func (f *flagValue) Set(s string) error {
+	// check the value now so mistakes are reported with the flag's usage
+	tmp := reflect.New(f.field.value.Type()).Elem()
+	if err := set(tmp, s); err != nil {
+		return err
+	}
+	f.given[f.field.flag] = s
+	return nil
+}
+
+// IsBoolFlag allows -flag for bool fields
+func (f *flagValue) IsBoolFlag() bool {
+	return f.field.value.Kind() == reflect.Bool
+}
+
+// kebab converts a Go field name to lower case words separated by dashes, so BindAddress
+// becomes bind-address and HTTPPort becomes http-port.
+func kebab(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('-')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
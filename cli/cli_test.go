@@ -0,0 +1,160 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base/log"
+)
+
+type testConfig struct {
+	HTTP struct {
+		BindAddress string `usage:"Address to listen on"`
+		Timeout     time.Duration
+	}
+	Database struct {
+		Host     string
+		Password string `redact:"mask"`
+	}
+	Origins  []string
+	Workers  int
+	Debug    bool
+	APIToken string
+	Internal string `cli:"-"`
+}
+
+func (c testConfig) Validate() error {
+	if c.Workers < 0 {
+		return errors.New("workers must not be negative")
+	}
+	return nil
+}
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+http:
+  bindAddress: ":9090"
+  timeout: 5s
+database:
+  host: db.example.com
+  password: correct-horse-battery
+workers: 2
+`), 0600))
+
+	var cfg testConfig
+	cfg.HTTP.BindAddress = ":8080"
+	cfg.Workers = 1
+
+	buf, logger := log.NewBufferLogger()
+	err := Load(&cfg, Options{
+		Args:      []string{"-config", path, "-workers", "8", "-debug"},
+		EnvPrefix: "ACH",
+		Logger:    logger,
+		LookupEnv: env(map[string]string{
+			"ACH_HTTP_TIMEOUT": "30s",
+			"ACH_WORKERS":      "4",
+			"ACH_ORIGINS":      "https://a.example.com, https://b.example.com",
+			"ACH_API_TOKEN":    "tok_live_0123456789",
+			"ACH_INTERNAL":     "ignored",
+		}),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, ":9090", cfg.HTTP.BindAddress)    // file
+	require.Equal(t, 30*time.Second, cfg.HTTP.Timeout) // env over file
+	require.Equal(t, 8, cfg.Workers)                   // flag over env
+	require.Equal(t, "correct-horse-battery", cfg.Database.Password)
+	require.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.Origins)
+	require.True(t, cfg.Debug)
+	require.Empty(t, cfg.Internal)
+
+	require.Contains(t, buf.String(), "loaded config")
+	require.Contains(t, buf.String(), "db.example.com")
+	require.NotContains(t, buf.String(), "correct-horse-battery")
+	require.Equal(t, "tok_live_0123456789", cfg.APIToken)
+	require.NotContains(t, buf.String(), "tok_live")
+}
+
+type codes []string
+
+func TestLoad__NamedSlice(t *testing.T) {
+	var cfg struct {
+		Codes codes
+	}
+	cfg.Codes = codes{"R01"}
+
+	err := Load(&cfg, Options{
+		Args:      []string{"-codes", "R02, R03"},
+		LookupEnv: env(nil),
+	})
+	require.NoError(t, err)
+	require.Equal(t, codes{"R02", "R03"}, cfg.Codes)
+}
+
+func TestLoad__ValidateConfig(t *testing.T) {
+	var cfg testConfig
+	err := Load(&cfg, Options{
+		Args:      []string{"-validate-config"},
+		Logger:    log.NewNopLogger(),
+		LookupEnv: env(nil),
+	})
+	require.ErrorIs(t, err, ErrConfigValid)
+
+	err = Load(&cfg, Options{
+		Args:      []string{"-validate-config", "-workers=-1"},
+		Logger:    log.NewNopLogger(),
+		LookupEnv: env(nil),
+	})
+	require.EqualError(t, err, "cli: invalid config: workers must not be negative")
+}
+
+func TestLoad__Errors(t *testing.T) {
+	var cfg testConfig
+	opts := Options{
+		Args:      []string{},
+		Logger:    log.NewNopLogger(),
+		LookupEnv: env(map[string]string{"WORKERS": "many"}),
+	}
+
+	err := Load(&cfg, opts)
+	require.EqualError(t, err, `cli: WORKERS: strconv.ParseInt: parsing "many": invalid syntax`)
+
+	opts.LookupEnv = env(nil)
+	opts.Args = []string{"-http.timeout", "soon"}
+	require.Error(t, Load(&cfg, opts))
+
+	opts.Args = []string{"-config", filepath.Join(t.TempDir(), "missing.yml")}
+	require.ErrorContains(t, Load(&cfg, opts), "missing.yml")
+
+	opts.Args = []string{"-h"}
+	require.ErrorIs(t, Load(&cfg, opts), flag.ErrHelp)
+
+	require.Error(t, Load(cfg, opts))
+}
+
+func TestKebab(t *testing.T) {
+	require.Equal(t, "bind-address", kebab("BindAddress"))
+	require.Equal(t, "http-port", kebab("HTTPPort"))
+	require.Equal(t, "http", kebab("HTTP"))
+	require.Equal(t, "id", kebab("ID"))
+	require.Equal(t, "tls2-cert", kebab("TLS2Cert"))
+}
@@ -0,0 +1,235 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+)
+
+// Exit codes returned by Execute
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitInterrupted = 130
+)
+
+// ErrUsage is returned (or wrapped) by commands which were called incorrectly, such as with
+// missing arguments. The command's usage is printed and Execute returns ExitUsage.
+var ErrUsage = errors.New("invalid usage")
+
+// ExitError is returned by commands to exit with a specific code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Exit returns an *ExitError of code and err
+func Exit(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// Command is an operational command, such as migrate or replay-outbox, of a service's binary.
+//
+//	root := &cli.Command{
+//		Name: "ach",
+//		Subcommands: []*cli.Command{
+//			{
+//				Name:  "replay-outbox",
+//				Usage: "Publish outbox messages again",
+//				SetFlags: func(fs *flag.FlagSet) {
+//					fs.StringVar(&since, "since", "", "Only messages created after this time")
+//				},
+//				Run: func(ctx context.Context, args []string) error {
+//					return replay(ctx, since)
+//				},
+//			},
+//		},
+//	}
+//	cli.Main(root)
+type Command struct {
+	Name string
+
+	// Usage is a one line description shown in the parent's list of commands
+	Usage string
+
+	// ArgsUsage describes the arguments after the flags, such as "<manifest>"
+	ArgsUsage string
+
+	// SetFlags registers the command's flags. Flags are parsed before Run or a subcommand.
+	SetFlags func(fs *flag.FlagSet)
+
+	// Run is called with the arguments left after the flags. Commands with Subcommands may
+	// leave Run nil to require one.
+	Run func(ctx context.Context, args []string) error
+
+	Subcommands []*Command
+
+	// Output receives usage and errors. It defaults to the parent's Output or os.Stderr.
+	Output io.Writer
+
+	parent *Command
+}
+
+// Main runs the command with os.Args and exits with its exit code. ctx is cancelled on
+// SIGINT or SIGTERM so commands can stop cleanly.
+func Main(c *Command) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := c.Execute(ctx, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+// Execute parses args, runs the matching command and returns its exit code. Errors are
+// written to Output.
+func (c *Command) Execute(ctx context.Context, args []string) int {
+	cmd, err := c.execute(ctx, args)
+	out := cmd.output()
+
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK // the flag set printed usage
+	case errors.As(err, &exitErr):
+		if exitErr.Err != nil {
+			fmt.Fprintf(out, "%s: %v\n", cmd.path(), exitErr.Err)
+		}
+		return exitErr.Code
+	case errors.Is(err, ErrUsage):
+		fmt.Fprintf(out, "%s: %v\n\n", cmd.path(), err)
+		cmd.usage(nil)
+		return ExitUsage
+	case errors.Is(err, context.Canceled):
+		fmt.Fprintf(out, "%s: interrupted\n", cmd.path())
+		return ExitInterrupted
+	}
+	fmt.Fprintf(out, "%s: %v\n", cmd.path(), err)
+	return ExitFailure
+}
+
+// execute returns the command which ran (or failed to parse) along with its error
+func (c *Command) execute(ctx context.Context, args []string) (*Command, error) {
+	fs := flag.NewFlagSet(c.path(), flag.ContinueOnError)
+	fs.SetOutput(c.output())
+	fs.Usage = func() { c.usage(fs) }
+	if c.SetFlags != nil {
+		c.SetFlags(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return c, err
+		}
+		return c, Exit(ExitUsage, nil) // the flag set printed the error and usage
+	}
+
+	rest := fs.Args()
+	if len(c.Subcommands) > 0 && len(rest) > 0 {
+		if rest[0] == "help" {
+			c.usage(fs)
+			return c, flag.ErrHelp
+		}
+		for _, sub := range c.Subcommands {
+			if sub.Name == rest[0] {
+				sub.parent = c
+				return sub.execute(ctx, rest[1:])
+			}
+		}
+		if c.Run == nil {
+			return c, fmt.Errorf("%w: unknown command %q", ErrUsage, rest[0])
+		}
+	}
+	if c.Run == nil {
+		return c, fmt.Errorf("%w: missing command", ErrUsage)
+	}
+	if err := ctx.Err(); err != nil {
+		return c, err
+	}
+	return c, c.Run(ctx, rest)
+}
+
+func (c *Command) output() io.Writer {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.Output != nil {
+			return cmd.Output
+		}
+	}
+	return os.Stderr
+}
+
+// path returns the names of the command and its parents, such as "ach replay-outbox"
+func (c *Command) path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.path() + " " + c.Name
+}
+
+// usage prints how to call the command, its subcommands and flags
+func (c *Command) usage(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.NewFlagSet(c.path(), flag.ContinueOnError)
+		if c.SetFlags != nil {
+			c.SetFlags(fs)
+		}
+	}
+	out := c.output()
+
+	line := []string{c.path()}
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		line = append(line, "[flags]")
+	}
+	if len(c.Subcommands) > 0 {
+		line = append(line, "<command>")
+	}
+	if c.ArgsUsage != "" {
+		line = append(line, c.ArgsUsage)
+	}
+	fmt.Fprintf(out, "Usage: %s\n", strings.Join(line, " "))
+	if c.Usage != "" {
+		fmt.Fprintf(out, "\n%s\n", c.Usage)
+	}
+
+	if len(c.Subcommands) > 0 {
+		subs := append([]*Command(nil), c.Subcommands...)
+		sort.Slice(subs, func(i, j int) bool { return subs[i].Name < subs[j].Name })
+
+		fmt.Fprintf(out, "\nCommands:\n")
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		for _, sub := range subs {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Usage)
+		}
+		tw.Flush()
+	}
+	if hasFlags {
+		fmt.Fprintf(out, "\nFlags:\n")
+		fs.SetOutput(out)
+		fs.PrintDefaults()
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testCommand(out *strings.Builder, ran *[]string) *Command {
+	var dryRun bool
+	var since string
+	return &Command{
+		Name:   "ach",
+		Output: out,
+		SetFlags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&dryRun, "dry-run", false, "Log changes without making them")
+		},
+		Subcommands: []*Command{
+			{
+				Name:  "replay-outbox",
+				Usage: "Publish outbox messages again",
+				SetFlags: func(fs *flag.FlagSet) {
+					fs.StringVar(&since, "since", "", "Only messages created after this time")
+				},
+				Run: func(ctx context.Context, args []string) error {
+					*ran = append(*ran, "replay-outbox", since, strings.Join(args, ","))
+					if dryRun {
+						*ran = append(*ran, "dry-run")
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "verify-manifest",
+				Usage:     "Check a manifest's files",
+				ArgsUsage: "<manifest>",
+				Run: func(ctx context.Context, args []string) error {
+					if len(args) != 1 {
+						return ErrUsage
+					}
+					if args[0] == "bad.json" {
+						return Exit(3, errors.New("2 files are missing"))
+					}
+					return errors.New("boom")
+				},
+			},
+			{
+				Name: "wait",
+				Run: func(ctx context.Context, args []string) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+		},
+	}
+}
+
+func TestCommand(t *testing.T) {
+	var out strings.Builder
+	var ran []string
+	root := testCommand(&out, &ran)
+
+	code := root.Execute(context.Background(), []string{"-dry-run", "replay-outbox", "-since", "2021-11-01", "a", "b"})
+	require.Equal(t, ExitOK, code)
+	require.Equal(t, []string{"replay-outbox", "2021-11-01", "a,b", "dry-run"}, ran)
+	require.Empty(t, out.String())
+}
+
+func TestCommand__ExitCodes(t *testing.T) {
+	var out strings.Builder
+	var ran []string
+	root := testCommand(&out, &ran)
+	ctx := context.Background()
+
+	require.Equal(t, 3, root.Execute(ctx, []string{"verify-manifest", "bad.json"}))
+	require.Contains(t, out.String(), "ach verify-manifest: 2 files are missing")
+
+	out.Reset()
+	require.Equal(t, ExitFailure, root.Execute(ctx, []string{"verify-manifest", "good.json"}))
+	require.Equal(t, "ach verify-manifest: boom\n", out.String())
+
+	out.Reset()
+	require.Equal(t, ExitUsage, root.Execute(ctx, []string{"verify-manifest"}))
+	require.Contains(t, out.String(), "Usage: ach verify-manifest <manifest>")
+
+	out.Reset()
+	require.Equal(t, ExitUsage, root.Execute(ctx, []string{"migrate"}))
+	require.Contains(t, out.String(), `ach: invalid usage: unknown command "migrate"`)
+
+	out.Reset()
+	require.Equal(t, ExitUsage, root.Execute(ctx, nil))
+	require.Contains(t, out.String(), "missing command")
+
+	out.Reset()
+	require.Equal(t, ExitUsage, root.Execute(ctx, []string{"replay-outbox", "-unknown"}))
+	require.Contains(t, out.String(), "flag provided but not defined: -unknown")
+
+	cancelled, cancelFunc := context.WithCancel(ctx)
+	cancelFunc()
+	out.Reset()
+	require.Equal(t, ExitInterrupted, root.Execute(cancelled, []string{"wait"}))
+	require.Contains(t, out.String(), "ach wait: interrupted")
+}
+
+func TestCommand__Help(t *testing.T) {
+	var out strings.Builder
+	var ran []string
+	root := testCommand(&out, &ran)
+
+	require.Equal(t, ExitOK, root.Execute(context.Background(), []string{"help"}))
+	usage := out.String()
+	require.Contains(t, usage, "Usage: ach [flags] <command>")
+	require.Contains(t, usage, "  replay-outbox    Publish outbox messages again")
+	require.Contains(t, usage, "  verify-manifest  Check a manifest's files")
+	require.Contains(t, usage, "-dry-run")
+
+	out.Reset()
+	require.Equal(t, ExitOK, root.Execute(context.Background(), []string{"replay-outbox", "-h"}))
+	require.Contains(t, out.String(), "Usage: ach replay-outbox [flags]")
+	require.Contains(t, out.String(), "-since")
+	require.Empty(t, ran)
+}
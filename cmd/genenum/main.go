@@ -0,0 +1,230 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// genenum writes the methods of an enum type backed by github.com/moov-io/base/enum. The type's
+// values are the constants declared with it in the current package, in order.
+//
+// String types get a String method unless they declare one, other types (i.e. iota based) need
+// their own such as one written by stringer. Also generated are IsValid, MarshalText and UnmarshalText methods along
+// with Parse<Type> and <Type>Values functions.
+//
+// Usage:
+//
+//	//go:generate go run github.com/moov-io/base/cmd/genenum -type Status
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var (
+	flagType   = flag.String("type", "", "Enum type to generate methods for")
+	flagDir    = flag.String("dir", ".", "Directory of the package declaring the type")
+	flagOutput = flag.String("output", "", "File to write into, defaults to <type>_enum.go")
+)
+
+func main() {
+	flag.Parse()
+
+	if *flagType == "" {
+		fmt.Fprintln(os.Stderr, "ERROR: missing -type")
+		os.Exit(1)
+	}
+	output := *flagOutput
+	if output == "" {
+		output = filepath.Join(*flagDir, strings.ToLower(*flagType)+"_enum.go")
+	}
+
+	e, err := parse(*flagDir, *flagType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	bs, err := generate(e)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(output, bs, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type enum struct {
+	Package  string
+	Type     string
+	Values   []string
+	Stringer bool // generate a String method
+}
+
+// parse finds the declaration and constants of typ in the package at dir
+func parse(dir, typ string) (*enum, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && !strings.HasSuffix(fi.Name(), "_enum.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, pkg := range pkgs {
+		e := &enum{Package: name, Type: typ}
+		declared, stringer := false, false
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "String" && receiver(fn) == typ {
+					stringer = true
+				}
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+				switch gen.Tok {
+				case token.TYPE:
+					for _, spec := range gen.Specs {
+						ts := spec.(*ast.TypeSpec)
+						if ts.Name.Name == typ {
+							declared = true
+							if ident, ok := ts.Type.(*ast.Ident); ok && ident.Name == "string" {
+								e.Stringer = true
+							}
+						}
+					}
+				case token.CONST:
+					e.Values = append(e.Values, constants(gen, typ)...)
+				}
+			}
+		}
+		if !declared {
+			continue
+		}
+		if stringer {
+			e.Stringer = false
+		}
+		if len(e.Values) == 0 {
+			return nil, fmt.Errorf("no constants of type %s", typ)
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("type %s not found in %s", typ, dir)
+}
+
+// receiver returns the type name of a method's receiver, or "" for functions
+func receiver(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// constants returns the names of constants of typ declared in a const block. Specs without a type
+// or value (iota) repeat the type of the previous spec.
+func constants(decl *ast.GenDecl, typ string) []string {
+	var out []string
+	current := ""
+	for _, spec := range decl.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if ident, ok := vs.Type.(*ast.Ident); ok {
+			current = ident.Name
+		} else if vs.Type != nil || len(vs.Values) > 0 {
+			current = ""
+		}
+		if current != typ {
+			continue
+		}
+		for _, name := range vs.Names {
+			if name.Name != "_" {
+				out = append(out, name.Name)
+			}
+		}
+	}
+	return out
+}
+
+var tmpl = template.Must(template.New("enum").Parse(`// Code generated by genenum; DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"github.com/moov-io/base/enum"
+)
+
+var {{ .Set }} = enum.New(
+{{- range .Values }}
+	{{ . }},
+{{- end }}
+)
+
+// {{ .ValuesFunc }} returns each {{ .Type }} in declaration order
+func {{ .ValuesFunc }}() []{{ .Type }} {
+	return {{ .Set }}.Values()
+}
+
+// {{ .ParseFunc }} returns the {{ .Type }} matching s, ignoring case
+func {{ .ParseFunc }}(s string) ({{ .Type }}, error) {
+	return {{ .Set }}.Parse(s)
+}
+{{ if .Stringer }}
+func (v {{ .Type }}) String() string {
+	return string(v)
+}
+{{ end }}
+// IsValid returns true if v is a declared {{ .Type }}
+func (v {{ .Type }}) IsValid() bool {
+	return {{ .Set }}.IsValid(v)
+}
+
+func (v {{ .Type }}) MarshalText() ([]byte, error) {
+	return {{ .Set }}.MarshalText(v)
+}
+
+func (v *{{ .Type }}) UnmarshalText(data []byte) error {
+	return {{ .Set }}.UnmarshalText(data, v)
+}
+`))
+
+func generate(e *enum) ([]byte, error) {
+	exported := unicode.IsUpper([]rune(e.Type)[0])
+	title := strings.ToUpper(e.Type[:1]) + e.Type[1:]
+	lower := strings.ToLower(e.Type[:1]) + e.Type[1:]
+
+	parseFunc := "Parse" + title
+	if !exported {
+		parseFunc = "parse" + title
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]interface{}{
+		"Package":    e.Package,
+		"Type":       e.Type,
+		"Values":     e.Values,
+		"Stringer":   e.Stringer,
+		"Set":        lower + "Enum",
+		"ValuesFunc": e.Type + "Values",
+		"ParseFunc":  parseFunc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const source = `package transfers
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusReturned Status = "returned"
+	maxRetries            = 3
+)
+
+type speed int
+
+const (
+	speedStandard speed = iota
+	speedSameDay
+	_
+	speedInstant
+)
+
+const other = "x"
+
+type Reason string
+
+const ReasonClosed Reason = "closed"
+
+func (r Reason) String() string { return "reason " + string(r) }
+`
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "transfers.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := parse(dir, "Status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Package != "transfers" || !e.Stringer {
+		t.Errorf("unexpected enum: %#v", e)
+	}
+	if expected := []string{"StatusPending", "StatusApproved", "StatusReturned"}; !reflect.DeepEqual(e.Values, expected) {
+		t.Errorf("unexpected values: %v", e.Values)
+	}
+
+	e, err = parse(dir, "speed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Stringer {
+		t.Error("expected no String method for int enum")
+	}
+	if expected := []string{"speedStandard", "speedSameDay", "speedInstant"}; !reflect.DeepEqual(e.Values, expected) {
+		t.Errorf("unexpected values: %v", e.Values)
+	}
+
+	e, err = parse(dir, "Reason")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Stringer {
+		t.Error("expected no String method when the type declares one")
+	}
+
+	if _, err := parse(dir, "Missing"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	bs, err := generate(&enum{
+		Package:  "transfers",
+		Type:     "Status",
+		Values:   []string{"StatusPending", "StatusApproved"},
+		Stringer: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(bs)
+	for _, expected := range []string{
+		"var statusEnum = enum.New(\n\tStatusPending,\n\tStatusApproved,\n)",
+		"func StatusValues() []Status {",
+		"func ParseStatus(s string) (Status, error) {",
+		"func (v Status) String() string {",
+		"func (v *Status) UnmarshalText(data []byte) error {",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("missing %q in:\n%s", expected, out)
+		}
+	}
+
+	bs, err = generate(&enum{Package: "transfers", Type: "speed", Values: []string{"speedStandard"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(bs); strings.Contains(out, "String()") || !strings.Contains(out, "func parseSpeed(s string) (speed, error)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// genholidays writes the Federal Reserve holiday schedule embedded into github.com/moov-io/base.
+//
+// The schedule is computed from the rules published by the Federal Reserve:
+//
+//   - For holidays falling on Saturday, Federal Reserve Banks and Branches will be open the preceding Friday.
+//   - For holidays falling on Sunday, all Federal Reserve Banks and Branches will be closed the following Monday.
+//
+// The output is committed so changes to the schedule (new holidays, one-off closures) can be
+// reviewed and hand edited. See https://www.frbservices.org/about/holiday-schedules
+//
+// Usage:
+//
+//	go run ./cmd/genholidays -start 1990 -end 2060 -output holidays/federal_reserve.csv
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var (
+	flagStart  = flag.Int("start", 1990, "First year of holidays to generate")
+	flagEnd    = flag.Int("end", 2060, "Last year of holidays to generate")
+	flagOutput = flag.String("output", "", "File to write holidays into, stdout is used when empty")
+)
+
+func main() {
+	flag.Parse()
+
+	if *flagEnd < *flagStart {
+		fmt.Fprintf(os.Stderr, "ERROR: -end %d is before -start %d\n", *flagEnd, *flagStart)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *flagOutput != "" {
+		fd, err := os.Create(*flagOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		defer fd.Close()
+		w = fd
+	}
+
+	if err := write(w, *flagStart, *flagEnd); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type holiday struct {
+	name     string
+	date     time.Time
+	observed time.Time
+}
+
+func write(w io.Writer, start, end int) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"date", "observed", "name"})
+	for year := start; year <= end; year++ {
+		for _, h := range holidays(year) {
+			out.Write([]string{h.date.Format("2006-01-02"), h.observed.Format("2006-01-02"), h.name})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func holidays(year int) []holiday {
+	var out []holiday
+	add := func(name string, date time.Time) {
+		observed := date
+		if date.Weekday() == time.Sunday {
+			observed = date.AddDate(0, 0, 1)
+		}
+		out = append(out, holiday{name: name, date: date, observed: observed})
+	}
+
+	add("New Year's Day", fixed(year, time.January, 1))
+	add("Martin Luther King, Jr. Day", nth(year, time.January, time.Monday, 3))
+	add("Washington's Birthday", nth(year, time.February, time.Monday, 3))
+	add("Memorial Day", last(year, time.May, time.Monday))
+	if year >= 2022 {
+		add("Juneteenth National Independence Day", fixed(year, time.June, 19))
+	}
+	add("Independence Day", fixed(year, time.July, 4))
+	add("Labor Day", nth(year, time.September, time.Monday, 1))
+	add("Columbus Day", nth(year, time.October, time.Monday, 2))
+	add("Veterans Day", fixed(year, time.November, 11))
+	add("Thanksgiving Day", nth(year, time.November, time.Thursday, 4))
+	add("Christmas Day", fixed(year, time.December, 25))
+
+	return out
+}
+
+func fixed(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nth returns the n'th weekday of a month
+func nth(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := fixed(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// last returns the last weekday of a month
+func last(year int, month time.Month, weekday time.Weekday) time.Time {
+	end := fixed(year, month+1, 0)
+	offset := (int(end.Weekday()) - int(weekday) + 7) % 7
+	return end.AddDate(0, 0, -offset)
+}
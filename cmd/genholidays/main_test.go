@@ -0,0 +1,54 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHolidays(t *testing.T) {
+	hs := holidays(2021)
+	if len(hs) != 10 {
+		t.Fatalf("expected 10 holidays in 2021, got %d", len(hs))
+	}
+	// Independence Day 2021 was a Sunday
+	for _, h := range hs {
+		if h.name == "Independence Day" {
+			if h.observed.Day() != 5 || h.observed.Weekday() != time.Monday {
+				t.Errorf("unexpected observance: %v", h.observed)
+			}
+		}
+	}
+	if n := len(holidays(2022)); n != 11 {
+		t.Errorf("expected Juneteenth in 2022, got %d holidays", n)
+	}
+
+	if d := nth(2020, time.November, time.Thursday, 4); d.Day() != 26 {
+		t.Errorf("unexpected Thanksgiving: %v", d)
+	}
+	if d := last(2021, time.May, time.Monday); d.Day() != 31 {
+		t.Errorf("unexpected Memorial Day: %v", d)
+	}
+}
+
+// TestGenerated verifies the embedded dataset matches the generator. Regenerate it with 'go generate'
+// from the repository root, hand edits (one-off closures) need to be made here as well.
+func TestGenerated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := write(&buf, 1990, 2060); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := ioutil.ReadFile(filepath.Join("..", "..", "holidays", "federal_reserve.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), bs) {
+		t.Error("holidays/federal_reserve.csv is out of date, run 'go generate' in the repository root")
+	}
+}
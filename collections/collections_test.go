@@ -0,0 +1,52 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlices(t *testing.T) {
+	items := []int{1, 2, 3, 2, 4, 1, 5}
+
+	require.Equal(t, []string{"1", "2", "3"}, Map([]int{1, 2, 3}, strconv.Itoa))
+	require.Equal(t, []int{2, 2, 4}, Filter(items, func(i int) bool { return i%2 == 0 }))
+	require.Nil(t, Filter(items, func(i int) bool { return false }))
+	require.Equal(t, []int{1, 2, 3, 4, 5}, Unique(items))
+	require.Equal(t, []int{3, 4, 5}, Difference(items, []int{1, 2}))
+
+	groups := GroupBy(items, func(i int) bool { return i%2 == 0 })
+	require.Equal(t, []int{2, 2, 4}, groups[true])
+	require.Equal(t, []int{1, 3, 1, 5}, groups[false])
+}
+
+func TestChunk(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+
+	// appending to a chunk doesn't overwrite the next
+	_ = append(chunks[0], 9)
+	require.Equal(t, []int{3, 4}, chunks[1])
+
+	require.Nil(t, Chunk([]int{}, 3))
+	require.Panics(t, func() { Chunk([]int{1}, 0) })
+}
+
+func TestMaps(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	require.Equal(t, []string{"a", "b", "c"}, keys)
+	require.Equal(t, []string{"a", "b", "c"}, SortedKeys(m))
+
+	values := Values(m)
+	sort.Ints(values)
+	require.Equal(t, []int{1, 2, 3}, values)
+}
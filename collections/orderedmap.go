@@ -0,0 +1,179 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// OrderedMap is a map of string keys which keeps their insertion order, including through JSON
+// encoding and decoding. The zero value is an empty map ready to use.
+//
+// OrderedMap[json.RawMessage] reproduces compact JSON objects byte-for-byte. With
+// OrderedMap[any] nested objects are decoded as *OrderedMap[any] and numbers as json.Number
+// so their order and formatting survive as well.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+// NewOrderedMap returns an empty OrderedMap
+func NewOrderedMap[V any]() *OrderedMap[V] {
+	return &OrderedMap[V]{}
+}
+
+// Set stores value under key. New keys are added last, existing keys keep their position.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of key and if it exists
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	v, exists := m.values[key]
+	return v, exists
+}
+
+// Delete removes key from the map
+func (m *OrderedMap[V]) Delete(key string) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	for i := range m.keys {
+		if m.keys[i] == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns each key in insertion order
+func (m *OrderedMap[V]) Keys() []string {
+	out := make([]string, len(m.keys))
+	copy(out, m.keys)
+	return out
+}
+
+// Len returns the number of keys
+func (m *OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// MarshalJSON encodes the map as an object in insertion order. json.Marshal escapes HTML
+// characters in its output, encode with a json.Encoder and SetEscapeHTML(false) to reproduce
+// payloads containing them.
+func (m OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(key); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode adds a newline
+		buf.WriteByte(':')
+		if err := enc.Encode(m.values[key]); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the map's contents with a JSON object, keeping the order of its keys.
+// Repeated keys keep their first position and last value.
+func (m *OrderedMap[V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	*m = OrderedMap[V]{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("collections: unexpected %v in object", tok)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		var value V
+		if target, ok := any(&value).(*any); ok {
+			*target, err = decodeOrdered(raw)
+		} else {
+			err = json.Unmarshal(raw, &value)
+		}
+		if err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	return expectDelim(dec, '}')
+}
+
+// decodeOrdered decodes objects as *OrderedMap[any] and numbers as json.Number
+func decodeOrdered(raw json.RawMessage) (any, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("collections: empty value")
+	}
+	switch trimmed[0] {
+	case '{':
+		m := NewOrderedMap[any]()
+		if err := m.UnmarshalJSON(trimmed); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		out := make([]any, len(items))
+		for i := range items {
+			v, err := decodeOrdered(items[i])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var v any
+	err := dec.Decode(&v)
+	return v, err
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("collections: expected %v but found %v", delim, tok)
+	}
+	return nil
+}
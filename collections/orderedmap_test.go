@@ -0,0 +1,75 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[int]
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+	require.Equal(t, []string{"z", "a", "m"}, m.Keys())
+	require.Equal(t, 3, m.Len())
+
+	v, ok := m.Get("z")
+	require.True(t, ok)
+	require.Equal(t, 4, v)
+
+	m.Delete("a")
+	m.Delete("missing")
+	require.Equal(t, []string{"z", "m"}, m.Keys())
+	_, ok = m.Get("a")
+	require.False(t, ok)
+
+	bs, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"z":4,"m":3}`, string(bs))
+}
+
+func TestOrderedMap__RoundTrip(t *testing.T) {
+	payload := `{"txnId":"abc","amount":{"value":10.50,"currency":"USD"},"memo":"<rent> & fees","tags":[{"b":1,"a":2}],"ok":true,"next":null}`
+
+	raw := NewOrderedMap[json.RawMessage]()
+	require.NoError(t, json.Unmarshal([]byte(payload), raw))
+	require.Equal(t, []string{"txnId", "amount", "memo", "tags", "ok", "next"}, raw.Keys())
+	require.Equal(t, payload, encode(t, raw))
+
+	anything := NewOrderedMap[any]()
+	require.NoError(t, json.Unmarshal([]byte(payload), anything))
+	amount, _ := anything.Get("amount")
+	require.Equal(t, []string{"value", "currency"}, amount.(*OrderedMap[any]).Keys())
+	require.Equal(t, payload, encode(t, anything))
+
+	// nested in a struct
+	type envelope struct {
+		Body OrderedMap[string] `json:"body"`
+	}
+	var env envelope
+	require.NoError(t, json.Unmarshal([]byte(`{"body":{"b":"1","a":"2"}}`), &env))
+	bs, err := json.Marshal(env)
+	require.NoError(t, err)
+	require.Equal(t, `{"body":{"b":"1","a":"2"}}`, string(bs))
+
+	require.Error(t, json.Unmarshal([]byte(`["a"]`), anything))
+	require.Error(t, json.Unmarshal([]byte(`{"a":"b"}`), NewOrderedMap[int]()))
+}
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	require.NoError(t, enc.Encode(v))
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// PriorityQueue orders items by a less function, such as same-day entries ahead of standard
+// ones. Items of equal priority are popped in the order they were pushed. It's safe for
+// concurrent use.
+type PriorityQueue[T any] struct {
+	mu       sync.Mutex
+	items    queueItems[T]
+	seq      uint64
+	pushed   chan struct{} // closed and replaced on each Push to wake waiting Pops
+	onExpire func(T)
+}
+
+type queueItem[T any] struct {
+	value    T
+	seq      uint64
+	deadline time.Time
+}
+
+// queueItems implements heap.Interface
+type queueItems[T any] struct {
+	less  func(a, b T) bool
+	items []queueItem[T]
+}
+
+func (q *queueItems[T]) Len() int { return len(q.items) }
+
+func (q *queueItems[T]) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.less(a.value, b.value) {
+		return true
+	}
+	if q.less(b.value, a.value) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (q *queueItems[T]) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *queueItems[T]) Push(x interface{}) { q.items = append(q.items, x.(queueItem[T])) }
+
+func (q *queueItems[T]) Pop() interface{} {
+	n := len(q.items) - 1
+	item := q.items[n]
+	q.items[n] = queueItem[T]{} // release the value
+	q.items = q.items[:n]
+	return item
+}
+
+// NewPriorityQueue returns an empty queue which pops the item less reports as smallest first
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		items:  queueItems[T]{less: less},
+		pushed: make(chan struct{}),
+	}
+}
+
+// OnExpire sets fn to be called with items dropped because their deadline passed. It's called
+// from Pop without the queue locked. Set it before the queue is used.
+func (q *PriorityQueue[T]) OnExpire(fn func(T)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onExpire = fn
+}
+
+// Push adds item to the queue
+func (q *PriorityQueue[T]) Push(item T) {
+	q.PushWithDeadline(item, time.Time{})
+}
+
+// PushWithDeadline adds item to the queue until deadline. Items still queued after their
+// deadline are dropped once they reach the front of the queue rather than being popped.
+// A zero deadline never expires.
+func (q *PriorityQueue[T]) PushWithDeadline(item T, deadline time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	heap.Push(&q.items, queueItem[T]{value: item, seq: q.seq, deadline: deadline})
+
+	close(q.pushed)
+	q.pushed = make(chan struct{})
+}
+
+// Len returns the number of queued items, including any which have expired but not yet
+// been dropped.
+func (q *PriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// TryPop removes and returns the first item, or false when the queue is empty
+func (q *PriorityQueue[T]) TryPop() (T, bool) {
+	item, ok, _ := q.tryPop()
+	return item, ok
+}
+
+// Pop removes and returns the first item, waiting for one to be pushed when the queue is
+// empty. ctx's error is returned if it's done first.
+func (q *PriorityQueue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		item, ok, pushed := q.tryPop()
+		if ok {
+			return item, nil
+		}
+		select {
+		case <-pushed:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// tryPop returns the first unexpired item or, when there's none, the channel closed by the
+// next Push
+func (q *PriorityQueue[T]) tryPop() (T, bool, <-chan struct{}) {
+	var expired []T
+	q.mu.Lock()
+	onExpire := q.onExpire
+	defer func() {
+		q.mu.Unlock()
+		if onExpire != nil {
+			for i := range expired {
+				onExpire(expired[i])
+			}
+		}
+	}()
+
+	now := time.Now()
+	for q.items.Len() > 0 {
+		item := heap.Pop(&q.items).(queueItem[T])
+		if !item.deadline.IsZero() && now.After(item.deadline) {
+			expired = append(expired, item.value)
+			continue
+		}
+		return item.value, true, nil
+	}
+	var zero T
+	return zero, false, q.pushed
+}
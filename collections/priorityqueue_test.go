@@ -0,0 +1,103 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	id      string
+	sameDay bool
+}
+
+func sameDayFirst(a, b entry) bool {
+	return a.sameDay && !b.sameDay
+}
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(sameDayFirst)
+	q.Push(entry{id: "a"})
+	q.Push(entry{id: "b", sameDay: true})
+	q.Push(entry{id: "c"})
+	q.Push(entry{id: "d", sameDay: true})
+	require.Equal(t, 4, q.Len())
+
+	var order []string
+	for {
+		e, ok := q.TryPop()
+		if !ok {
+			break
+		}
+		order = append(order, e.id)
+	}
+	// same-day first, otherwise in the order pushed
+	require.Equal(t, []string{"b", "d", "a", "c"}, order)
+	require.Equal(t, 0, q.Len())
+}
+
+func TestPriorityQueue__PopWaits(t *testing.T) {
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+
+	var wg sync.WaitGroup
+	got := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := q.Pop(context.Background())
+			require.NoError(t, err)
+			got <- v
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	q.Push(2)
+	q.Push(1)
+	wg.Wait()
+	close(got)
+
+	sum := 0
+	for v := range got {
+		sum += v
+	}
+	require.Equal(t, 3, sum)
+}
+
+func TestPriorityQueue__PopCancelled(t *testing.T) {
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFunc()
+
+	_, err := q.Pop(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPriorityQueue__Deadline(t *testing.T) {
+	q := NewPriorityQueue(sameDayFirst)
+
+	var expired []string
+	q.OnExpire(func(e entry) {
+		expired = append(expired, e.id)
+	})
+
+	q.PushWithDeadline(entry{id: "late", sameDay: true}, time.Now().Add(-time.Minute))
+	q.PushWithDeadline(entry{id: "soon", sameDay: true}, time.Now().Add(time.Hour))
+	q.Push(entry{id: "standard"})
+
+	e, err := q.Pop(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "soon", e.id)
+	require.Equal(t, []string{"late"}, expired)
+
+	e, ok := q.TryPop()
+	require.True(t, ok)
+	require.Equal(t, "standard", e.id)
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Set is a collection of unique items which iterates in insertion order. It's encoded in JSON as
+// an array and in text, such as an environment variable or flag, as comma separated items.
+// config.Service loads it from either a list or a string. The zero value is an empty Set ready to use.
+type Set[T comparable] struct {
+	items []T
+	index map[T]int
+}
+
+// NewSet returns a Set of items
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{}
+	s.Add(items...)
+	return s
+}
+
+// Add inserts items which aren't already in the Set
+func (s *Set[T]) Add(items ...T) {
+	if s.index == nil {
+		s.index = make(map[T]int, len(items))
+	}
+	for _, item := range items {
+		if _, exists := s.index[item]; exists {
+			continue
+		}
+		s.index[item] = len(s.items)
+		s.items = append(s.items, item)
+	}
+}
+
+// Remove deletes item from the Set
+func (s *Set[T]) Remove(item T) {
+	idx, exists := s.index[item]
+	if !exists {
+		return
+	}
+	s.items = append(s.items[:idx], s.items[idx+1:]...)
+	delete(s.index, item)
+	for i := idx; i < len(s.items); i++ {
+		s.index[s.items[i]] = i
+	}
+}
+
+// Contains returns true if item is in the Set
+func (s *Set[T]) Contains(item T) bool {
+	if s == nil {
+		return false
+	}
+	_, exists := s.index[item]
+	return exists
+}
+
+// Len returns the number of items in the Set
+func (s *Set[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.items)
+}
+
+// Items returns each item in insertion order
+func (s *Set[T]) Items() []T {
+	if s == nil {
+		return nil
+	}
+	out := make([]T, len(s.items))
+	copy(out, s.items)
+	return out
+}
+
+// Union returns a new Set of the items in s followed by those only in other.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	out := NewSet(s.Items()...)
+	out.Add(other.Items()...)
+	return out
+}
+
+// Intersect returns a new Set of the items in both s and other, in the order of s.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	return NewSet(Filter(s.Items(), other.Contains)...)
+}
+
+// MarshalJSON encodes the Set as an array in insertion order. It has a value receiver so Sets
+// embedded by value in other structs are encoded.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	if s.items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.items)
+}
+
+// UnmarshalJSON replaces the Set's items with those of a JSON array. Duplicates are ignored.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*s = Set[T]{}
+	s.Add(items...)
+	return nil
+}
+
+// MarshalText encodes the Set as its items separated by commas
+func (s Set[T]) MarshalText() ([]byte, error) {
+	parts := make([]string, len(s.items))
+	for i := range s.items {
+		if m, ok := any(s.items[i]).(encoding.TextMarshaler); ok {
+			bs, err := m.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = string(bs)
+		} else {
+			parts[i] = fmt.Sprintf("%v", s.items[i])
+		}
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText replaces the Set's items with comma separated ones. Strings are used as-is
+// after trimming spaces, other items are read with encoding.TextUnmarshaler or as JSON.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	var items []T
+	for _, part := range strings.Split(string(text), ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		var item T
+		if u, ok := any(&item).(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(part)); err != nil {
+				return err
+			}
+		} else if rv := reflect.ValueOf(&item).Elem(); rv.Kind() == reflect.String {
+			rv.SetString(part)
+		} else if err := json.Unmarshal([]byte(part), &item); err != nil {
+			return fmt.Errorf("invalid item %q: %v", part, err)
+		}
+		items = append(items, item)
+	}
+	*s = Set[T]{}
+	s.Add(items...)
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package collections
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	var s Set[string]
+	require.False(t, s.Contains("PPD"))
+	require.Equal(t, 0, s.Len())
+
+	s.Add("PPD", "CCD", "WEB", "PPD")
+	require.Equal(t, 3, s.Len())
+	require.True(t, s.Contains("CCD"))
+	require.Equal(t, []string{"PPD", "CCD", "WEB"}, s.Items())
+
+	s.Remove("CCD")
+	s.Remove("TEL")
+	require.False(t, s.Contains("CCD"))
+	require.Equal(t, []string{"PPD", "WEB"}, s.Items())
+	s.Add("CCD")
+	require.Equal(t, []string{"PPD", "WEB", "CCD"}, s.Items())
+
+	other := NewSet("TEL", "WEB")
+	require.Equal(t, []string{"PPD", "WEB", "CCD", "TEL"}, s.Union(other).Items())
+	require.Equal(t, []string{"WEB"}, s.Intersect(other).Items())
+	require.Equal(t, 0, s.Intersect(nil).Len())
+}
+
+func TestSet__JSON(t *testing.T) {
+	type config struct {
+		RoutingNumbers Set[string] `json:"routingNumbers"`
+	}
+
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"routingNumbers":["987654320","123456780","987654320"]}`), &cfg))
+	require.Equal(t, 2, cfg.RoutingNumbers.Len())
+	require.True(t, cfg.RoutingNumbers.Contains("123456780"))
+
+	bs, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.Equal(t, `{"routingNumbers":["987654320","123456780"]}`, string(bs))
+
+	bs, err = json.Marshal(config{})
+	require.NoError(t, err)
+	require.Equal(t, `{"routingNumbers":[]}`, string(bs))
+
+	require.Error(t, json.Unmarshal([]byte(`{"routingNumbers":"123456780"}`), &cfg))
+}
+
+func TestSet__Text(t *testing.T) {
+	var codes Set[string]
+	require.NoError(t, codes.UnmarshalText([]byte("PPD, CCD,,PPD")))
+	require.Equal(t, []string{"PPD", "CCD"}, codes.Items())
+
+	bs, err := codes.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "PPD,CCD", string(bs))
+
+	var limits Set[int]
+	require.NoError(t, limits.UnmarshalText([]byte("10,20")))
+	require.Equal(t, []int{10, 20}, limits.Items())
+	require.Error(t, limits.UnmarshalText([]byte("10,ten")))
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package collections implements generic helpers for slices and maps.
+package collections
+
+// Map returns the result of fn for each item
+func Map[T, U any](items []T, fn func(T) U) []U {
+	out := make([]U, len(items))
+	for i := range items {
+		out[i] = fn(items[i])
+	}
+	return out
+}
+
+// Filter returns the items which keep returns true for, in their original order.
+func Filter[T any](items []T, keep func(T) bool) []T {
+	var out []T
+	for i := range items {
+		if keep(items[i]) {
+			out = append(out, items[i])
+		}
+	}
+	return out
+}
+
+// Unique returns items without duplicates, keeping the first occurrence of each.
+func Unique[T comparable](items []T) []T {
+	seen := make(map[T]struct{}, len(items))
+	var out []T
+	for i := range items {
+		if _, exists := seen[items[i]]; exists {
+			continue
+		}
+		seen[items[i]] = struct{}{}
+		out = append(out, items[i])
+	}
+	return out
+}
+
+// Chunk splits items into slices of at most size items. The chunks share memory with items.
+// Chunk panics if size is less than one.
+func Chunk[T any](items []T, size int) [][]T {
+	if size < 1 {
+		panic("collections: Chunk size must be positive")
+	}
+	var out [][]T
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		out = append(out, items[start:end:end])
+	}
+	return out
+}
+
+// GroupBy returns items grouped by the result of key, each group is in the original order.
+func GroupBy[T any, K comparable](items []T, key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for i := range items {
+		k := key(items[i])
+		out[k] = append(out[k], items[i])
+	}
+	return out
+}
+
+// Difference returns the items of a which are not in b
+func Difference[T comparable](a, b []T) []T {
+	exclude := make(map[T]struct{}, len(b))
+	for i := range b {
+		exclude[b[i]] = struct{}{}
+	}
+	return Filter(a, func(item T) bool {
+		_, exists := exclude[item]
+		return !exists
+	})
+}
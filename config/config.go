@@ -1,25 +1,68 @@
 package config
 
 import (
+	"encoding"
+	"encoding/json"
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/moov-io/base/log"
 
 	"github.com/markbates/pkger"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
 const APP_CONFIG = "APP_CONFIG"
 const APP_CONFIG_SECRETS = "APP_CONFIG_SECRETS"
 
+// Source is where a configuration value was loaded from
+type Source string
+
+const (
+	// SourceDefault values are from the embedded config.default.yml
+	SourceDefault Source = "default"
+
+	// SourceFile values are from the file named by APP_CONFIG
+	SourceFile Source = "file"
+
+	// SourceSecrets values are from the file named by APP_CONFIG_SECRETS
+	SourceSecrets Source = "secrets"
+)
+
 type Service struct {
-	logger log.Logger
+	logger  log.Logger
+	sources map[string]Source
 }
 
 func NewService(logger log.Logger) Service {
 	return Service{
-		logger: logger.Set("component", log.String("Service")),
+		logger:  logger.Set("component", log.String("Service")),
+		sources: make(map[string]Source),
+	}
+}
+
+// Sources returns where each loaded value came from, keyed by its lowercase dotted path such
+// as "database.mysql.password". Later files override earlier ones, so a key set in both
+// config.default.yml and APP_CONFIG is SourceFile.
+func (s *Service) Sources() map[string]Source {
+	out := make(map[string]Source, len(s.sources))
+	for k, v := range s.sources {
+		out[k] = v
+	}
+	return out
+}
+
+func (s *Service) record(v *viper.Viper, source Source) {
+	if v == nil {
+		return
+	}
+	if s.sources == nil {
+		s.sources = make(map[string]Source)
+	}
+	for _, key := range v.AllKeys() {
+		s.sources[key] = source
 	}
 }
 
@@ -28,13 +71,17 @@ func (s *Service) Load(config interface{}) error {
 		return err
 	}
 
-	if err := LoadEnvironmentFile(s.logger, APP_CONFIG, config); err != nil {
+	overrides, err := loadEnvironmentFile(s.logger, APP_CONFIG, config)
+	if err != nil {
 		return err
 	}
+	s.record(overrides, SourceFile)
 
-	if err := LoadEnvironmentFile(s.logger, APP_CONFIG_SECRETS, config); err != nil {
+	secrets, err := loadEnvironmentFile(s.logger, APP_CONFIG_SECRETS, config)
+	if err != nil {
 		return err
 	}
+	s.record(secrets, SourceSecrets)
 
 	return nil
 }
@@ -54,33 +101,97 @@ func (s *Service) LoadFile(file string, config interface{}) error {
 		return logger.LogErrorf("unable to load the defaults: %w", err).Err()
 	}
 
-	if err := deflt.Unmarshal(config); err != nil {
+	if err := deflt.Unmarshal(config, decodeHook); err != nil {
 		return logger.LogErrorf("unable to unmarshal the defaults: %w", err).Err()
 	}
+	s.record(deflt, SourceDefault)
+
+	return nil
+}
 
+// LoadPath reads the YAML, JSON or TOML file at path into config on top of what's already loaded.
+// Its values are recorded as SourceFile.
+func (s *Service) LoadPath(path string, config interface{}) error {
+	file, err := readFile(s.logger, path, config)
+	if err != nil {
+		return err
+	}
+	s.record(file, SourceFile)
 	return nil
 }
 
 func LoadEnvironmentFile(logger log.Logger, envVar string, config interface{}) error {
+	_, err := loadEnvironmentFile(logger, envVar, config)
+	return err
+}
+
+// loadEnvironmentFile returns the viper.Viper the file was read with, or nil when envVar isn't set
+func loadEnvironmentFile(logger log.Logger, envVar string, config interface{}) (*viper.Viper, error) {
 	if file, ok := os.LookupEnv(envVar); ok && strings.TrimSpace(file) != "" {
 
 		logger := logger.Set(envVar, log.String(file))
 		logger.Info().Logf("Loading %s config file", envVar)
 
-		logger = logger.Set("file", log.String(file))
-		logger.Info().Logf("loading config file")
+		return readFile(logger, file, config)
+	}
 
-		overrides := viper.New()
-		overrides.SetConfigFile(file)
+	return nil, nil
+}
 
-		if err := overrides.ReadInConfig(); err != nil {
-			return logger.LogErrorf("Failed loading the specific app config: %w", err).Err()
-		}
+func readFile(logger log.Logger, file string, config interface{}) (*viper.Viper, error) {
+	logger = logger.Set("file", log.String(file))
+	logger.Info().Logf("loading config file")
 
-		if err := overrides.Unmarshal(config); err != nil {
-			return logger.LogErrorf("Unable to unmarshal the specific app config: %w", err).Err()
-		}
+	overrides := viper.New()
+	overrides.SetConfigFile(file)
+
+	if err := overrides.ReadInConfig(); err != nil {
+		return nil, logger.LogErrorf("Failed loading the specific app config: %w", err).Err()
 	}
 
-	return nil
+	if err := overrides.Unmarshal(config, decodeHook); err != nil {
+		return nil, logger.LogErrorf("Unable to unmarshal the specific app config: %w", err).Err()
+	}
+	return overrides, nil
+}
+
+// decodeHook adds unmarshalers to viper's default hooks, so types such as collections.Set can be
+// loaded from a string with UnmarshalText or from a list with UnmarshalJSON.
+var decodeHook = viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	unmarshalerHook,
+))
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// unmarshalerHook decodes strings into structs implementing encoding.TextUnmarshaler and lists
+// into structs implementing json.Unmarshaler, which mapstructure can only decode from maps.
+func unmarshalerHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to.Kind() != reflect.Struct {
+		return data, nil
+	}
+	out := reflect.New(to)
+	ptr := out.Type()
+
+	if s, ok := data.(string); ok && ptr.Implements(textUnmarshalerType) {
+		if err := out.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return nil, err
+		}
+		return out.Elem().Interface(), nil
+	}
+	if from.Kind() == reflect.Slice && ptr.Implements(jsonUnmarshalerType) {
+		bs, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := out.Interface().(json.Unmarshaler).UnmarshalJSON(bs); err != nil {
+			return nil, err
+		}
+		return out.Elem().Interface(), nil
+	}
+	return data, nil
 }
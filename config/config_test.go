@@ -1,9 +1,12 @@
 package config_test
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/moov-io/base/collections"
 	"github.com/moov-io/base/config"
 	"github.com/moov-io/base/log"
 	"github.com/stretchr/testify/require"
@@ -37,3 +40,64 @@ func Test_Load(t *testing.T) {
 	require.Equal(t, "app", cfg.Config.App)
 	require.Equal(t, "keep secret!", cfg.Config.Secret)
 }
+
+func Test_Sources(t *testing.T) {
+	os.Setenv(config.APP_CONFIG, "../configs/config.app.yml")
+	os.Setenv(config.APP_CONFIG_SECRETS, "../configs/config.secrets.yml")
+	t.Cleanup(func() {
+		os.Unsetenv(config.APP_CONFIG)
+		os.Unsetenv(config.APP_CONFIG_SECRETS)
+	})
+
+	service := config.NewService(log.NewDefaultLogger())
+	require.NoError(t, service.Load(&GlobalConfigModel{}))
+
+	require.Equal(t, map[string]config.Source{
+		"config.default": config.SourceDefault,
+		"config.app":     config.SourceFile,
+		"config.secret":  config.SourceSecrets,
+	}, service.Sources())
+}
+
+func Test_LoadPath(t *testing.T) {
+	cfg := &GlobalConfigModel{}
+
+	service := config.NewService(log.NewDefaultLogger())
+	require.NoError(t, service.LoadPath("../configs/config.app.yml", cfg))
+	require.Equal(t, "app", cfg.Config.App)
+	require.Equal(t, map[string]config.Source{
+		"config.app": config.SourceFile,
+	}, service.Sources())
+
+	require.Error(t, service.LoadPath("../configs/missing.yml", cfg))
+}
+
+func Test_LoadSet(t *testing.T) {
+	type setConfig struct {
+		RoutingNumbers collections.Set[string] `json:"routingNumbers"`
+		SECCodes       collections.Set[string] `json:"secCodes"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+routingNumbers:
+  - "987654320"
+  - "123456780"
+  - "987654320"
+secCodes: PPD,CCD
+`), 0600))
+
+	var cfg setConfig
+	service := config.NewService(log.NewNopLogger())
+	require.NoError(t, service.LoadPath(path, &cfg))
+	require.Equal(t, []string{"987654320", "123456780"}, cfg.RoutingNumbers.Items())
+	require.Equal(t, []string{"PPD", "CCD"}, cfg.SECCodes.Items())
+
+	bs, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.Equal(t, `{"routingNumbers":["987654320","123456780"],"secCodes":["PPD","CCD"]}`, string(bs))
+
+	var decoded setConfig
+	require.NoError(t, json.Unmarshal(bs, &decoded))
+	require.Equal(t, cfg.RoutingNumbers.Items(), decoded.RoutingNumbers.Items())
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package consent records how a Receiver authorized entries, such as accepting terms on a web
+// page or agreeing over the phone, in a shape which can be audited. Records are validated
+// against the requirements of their SEC code.
+//
+//	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+//	rec := consent.Record{
+//		ID:        base.ID(),
+//		SECCode:   "WEB",
+//		Channel:   consent.Online,
+//		Subject:   customerID,
+//		TextHash:  consent.HashText(terms),
+//		IPAddress: ip,
+//		UserAgent: r.UserAgent(),
+//		CreatedAt: base.Now(),
+//	}
+//	if err := rec.Validate(); err != nil {
+//		...
+//	}
+package consent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/document"
+	"github.com/moov-io/base/sec"
+)
+
+// Channel is how consent was given
+type Channel string
+
+const (
+	// Online consent is given on a web page or in a mobile app
+	Online Channel = "online"
+
+	// Phone consent is given orally and recorded, or confirmed in writing
+	Phone Channel = "phone"
+
+	// Paper consent is a signed form
+	Paper Channel = "paper"
+
+	// SignaturePad consent is signed on a device at a branch or point of sale
+	SignaturePad Channel = "signature-pad"
+)
+
+// Record is a Receiver's authorization of entries
+type Record struct {
+	ID      string  `json:"id"`
+	SECCode string  `json:"secCode"`
+	Channel Channel `json:"channel"`
+
+	// Subject is who gave consent, such as a customer ID
+	Subject string `json:"subject"`
+
+	// TextHash is the SHA-256 of the terms shown or read to the Receiver, see HashText
+	TextHash string `json:"textHash"`
+
+	IPAddress string `json:"ipAddress,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Evidence is the stored signature, form or recording
+	Evidence *document.Reference `json:"evidence,omitempty"`
+
+	// Recurring is true when the consent covers recurring entries
+	Recurring bool `json:"recurring"`
+
+	CreatedAt base.Time  `json:"createdAt"`
+	RevokedAt *base.Time `json:"revokedAt,omitempty"`
+}
+
+// HashText returns the hex SHA-256 of the terms consented to. Line endings and surrounding
+// whitespace are normalized so the same terms hash the same on every platform.
+func HashText(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Revoke marks the Record as revoked at when it hasn't been already
+func (r *Record) Revoke(at base.Time) {
+	if r.RevokedAt == nil {
+		r.RevokedAt = &at
+	}
+}
+
+// Active reports whether the Record authorizes entries at t
+func (r Record) Active(t base.Time) bool {
+	if t.Before(r.CreatedAt.Time) {
+		return false
+	}
+	return r.RevokedAt == nil || t.Before(r.RevokedAt.Time)
+}
+
+// Validate checks the fields every Record needs and those its SEC code requires, such as an
+// IP address for WEB consent or a recording for TEL. Every problem is returned as a base.ErrorList.
+func (r Record) Validate() error {
+	var list base.ErrorList
+	if r.ID == "" {
+		list.Add(errors.New("missing id"))
+	}
+	if r.Subject == "" {
+		list.Add(errors.New("missing subject"))
+	}
+	if r.CreatedAt.IsZero() {
+		list.Add(errors.New("missing createdAt"))
+	}
+	if len(r.TextHash) != sha256.Size*2 {
+		list.Add(fmt.Errorf("invalid textHash %q", r.TextHash))
+	}
+	if r.RevokedAt != nil && r.RevokedAt.Before(r.CreatedAt.Time) {
+		list.Add(errors.New("revokedAt is before createdAt"))
+	}
+	if r.Evidence != nil {
+		if err := r.Evidence.Validate(); err != nil {
+			list.Add(err)
+		}
+	}
+
+	class, ok := sec.Lookup(r.SECCode)
+	if !ok {
+		list.Add(fmt.Errorf("unknown SEC code %q", r.SECCode))
+		return list
+	}
+	if r.Recurring && !class.Recurring {
+		list.Add(fmt.Errorf("%s entries can't be recurring", class.Code))
+	}
+
+	switch class.Authorization {
+	case sec.Online:
+		if r.Channel != Online {
+			list.Add(fmt.Errorf("%s consent must be given online", class.Code))
+		}
+		if !validIP(r.IPAddress) {
+			list.Add(fmt.Errorf("%s consent needs the Receiver's IP address", class.Code))
+		}
+	case sec.Oral:
+		if r.Channel != Phone {
+			list.Add(fmt.Errorf("%s consent must be given by phone", class.Code))
+		}
+		if r.Evidence == nil {
+			list.Add(fmt.Errorf("%s consent needs a recording or written notice", class.Code))
+		}
+	case sec.Written:
+		if r.Channel != Paper && r.Channel != SignaturePad && r.Channel != Online {
+			list.Add(fmt.Errorf("%s consent must be signed or similarly authenticated", class.Code))
+		}
+		if r.Channel != Online && r.Evidence == nil {
+			list.Add(fmt.Errorf("%s consent needs the signed form", class.Code))
+		}
+	case sec.None:
+		list.Add(fmt.Errorf("%s entries aren't authorized by consent", class.Code))
+	}
+	if list.Empty() {
+		return nil
+	}
+	return list
+}
+
+// validIP reports whether addr is an IP address, with or without a port such as the
+// "ip:port" of http.Request.RemoteAddr
+func validIP(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr) != nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package consent
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/document"
+
+	"github.com/stretchr/testify/require"
+)
+
+var created = base.NewTime(time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+func webRecord() Record {
+	return Record{
+		ID:        "c1",
+		SECCode:   "WEB",
+		Channel:   Online,
+		Subject:   "customer-1",
+		TextHash:  HashText("I authorize debits."),
+		IPAddress: "203.0.113.7",
+		Recurring: true,
+		CreatedAt: created,
+	}
+}
+
+func TestHashText(t *testing.T) {
+	require.Equal(t, HashText("line one\nline two"), HashText("  line one\r\nline two\r\n"))
+	require.NotEqual(t, HashText("a"), HashText("b"))
+}
+
+func TestRecord__Validate(t *testing.T) {
+	require.NoError(t, webRecord().Validate())
+
+	rec := webRecord()
+	rec.IPAddress = ""
+	require.EqualError(t, rec.Validate(), "WEB consent needs the Receiver's IP address")
+
+	rec = webRecord()
+	rec.IPAddress = "203.0.113.7:52144"
+	require.NoError(t, rec.Validate())
+	rec.IPAddress = "[2001:db8::1]:443"
+	require.NoError(t, rec.Validate())
+
+	rec = webRecord()
+	rec.ID = ""
+	rec.Channel = Phone
+	var list base.ErrorList
+	require.True(t, errors.As(rec.Validate(), &list))
+	require.Len(t, list, 2)
+	require.EqualError(t, list[1], "WEB consent must be given online")
+
+	rec = webRecord()
+	rec.ID = ""
+	require.EqualError(t, rec.Validate(), "missing id")
+
+	rec = webRecord()
+	rec.SECCode = "ARC"
+	require.EqualError(t, rec.Validate(), "ARC entries can't be recurring")
+
+	rec = webRecord()
+	rec.SECCode = "TEL"
+	require.EqualError(t, rec.Validate(), "TEL consent must be given by phone\n  TEL consent needs a recording or written notice")
+
+	recording, err := document.NewReference(document.Recording, strings.NewReader("audio"))
+	require.NoError(t, err)
+	rec.Channel = Phone
+	rec.Evidence = &recording
+	require.NoError(t, rec.Validate())
+
+	rec = webRecord()
+	rec.SECCode = "PPD"
+	rec.Channel = Paper
+	require.EqualError(t, rec.Validate(), "PPD consent needs the signed form")
+
+	rec.SECCode = "COR"
+	rec.Recurring = false
+	require.EqualError(t, rec.Validate(), "COR entries aren't authorized by consent")
+}
+
+func TestRecord__Revoke(t *testing.T) {
+	rec := webRecord()
+	require.True(t, rec.Active(created))
+	require.False(t, rec.Active(base.NewTime(created.Add(-time.Second))))
+
+	revoked := base.NewTime(created.Add(time.Hour))
+	rec.Revoke(revoked)
+	rec.Revoke(base.NewTime(created.Add(2 * time.Hour)))
+	require.True(t, rec.RevokedAt.Equal(revoked))
+	require.True(t, rec.Active(base.NewTime(created.Add(time.Minute))))
+	require.False(t, rec.Active(revoked))
+	require.NoError(t, rec.Validate())
+
+	bs, err := json.Marshal(rec)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `"revokedAt":"2021-03-04T13:00:00Z"`)
+
+	early := base.NewTime(created.Add(-time.Hour))
+	rec.RevokedAt = &early
+	require.EqualError(t, rec.Validate(), "revokedAt is before createdAt")
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package ctxkeys implements typed context.Context keys.
+//
+// Each Key is unique by identity, so two packages declaring a key with the same name never
+// collide, and values are read back as their type without interface{} casts.
+//
+//	var requestID = ctxkeys.New[string]("request-id")
+//
+//	ctx = requestID.Set(ctx, "abc123")
+//	id, ok := requestID.Get(ctx)
+package ctxkeys
+
+import (
+	"context"
+)
+
+// Key stores and retrieves values of type T from a context.Context.
+type Key[T any] struct {
+	name string
+}
+
+// New returns a distinct Key. The name is only used for debugging.
+func New[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// Set returns a copy of ctx with v stored under k
+func (k *Key[T]) Set(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Get returns the value stored under k and if one was found.
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	if ctx == nil {
+		var zero T
+		return zero, false
+	}
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// Value returns the value stored under k or the zero value of T.
+func (k *Key[T]) Value(ctx context.Context) T {
+	v, _ := k.Get(ctx)
+	return v
+}
+
+// String returns the name of k
+func (k *Key[T]) String() string {
+	return k.name
+}
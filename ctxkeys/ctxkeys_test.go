@@ -0,0 +1,52 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ctxkeys
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type claims struct {
+	Subject string
+}
+
+func TestKey(t *testing.T) {
+	requestID := New[string]("request-id")
+	require.Equal(t, "request-id", requestID.String())
+	require.Equal(t, "request-id", fmt.Sprintf("%v", requestID))
+
+	ctx := requestID.Set(context.Background(), "abc123")
+
+	id, ok := requestID.Get(ctx)
+	require.True(t, ok)
+	require.Equal(t, "abc123", id)
+	require.Equal(t, "abc123", requestID.Value(ctx))
+
+	// keys with the same name and type don't collide
+	other := New[string]("request-id")
+	_, ok = other.Get(ctx)
+	require.False(t, ok)
+	require.Equal(t, "", other.Value(ctx))
+
+	// plain keys from context.WithValue don't collide
+	type plainKey string
+	ctx = context.WithValue(ctx, plainKey("request-id"), "other")
+	require.Equal(t, "abc123", requestID.Value(ctx))
+}
+
+func TestKey__Struct(t *testing.T) {
+	key := New[*claims]("claims")
+
+	_, ok := key.Get(context.Background())
+	require.False(t, ok)
+	require.Nil(t, key.Value(context.Background()))
+
+	ctx := key.Set(context.Background(), &claims{Subject: "user"})
+	require.Equal(t, "user", key.Value(ctx).Subject)
+}
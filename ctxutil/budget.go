@@ -0,0 +1,102 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package ctxutil implements helpers for working with context.Context values.
+//
+// A time budget is the total time a request is allowed to take. Downstream calls are given
+// a slice of what remains so one slow dependency can't consume the whole request:
+//
+//	ctx, cancel := ctxutil.WithBudget(ctx, 5*time.Second)
+//	defer cancel()
+//
+//	dbCtx, dbCancel := ctxutil.Slice(ctx, "database", 0.60)
+//	defer dbCancel()
+//	...
+//	apiCtx, apiCancel := ctxutil.Slice(ctx, "partner-api", 1.0) // whatever is left
+//	defer apiCancel()
+package ctxutil
+
+import (
+	"context"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/moov-io/base/ctxkeys"
+)
+
+var (
+	budgetExhausted = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "context_budget_exhausted_total",
+		Help: "Count of budget slices which ran out of time before being cancelled",
+	}, []string{"name"})
+)
+
+var budgetKey = ctxkeys.New[budget]("budget")
+
+type budget struct {
+	total    time.Duration
+	deadline time.Time
+}
+
+// WithBudget returns a copy of ctx which is cancelled after total. If ctx has an earlier deadline
+// it's kept, as a budget can never extend its parent.
+func WithBudget(ctx context.Context, total time.Duration) (context.Context, context.CancelFunc) {
+	deadline := time.Now().Add(total)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	ctx = budgetKey.Set(ctx, budget{
+		total:    total,
+		deadline: deadline,
+	})
+	return context.WithDeadline(ctx, deadline)
+}
+
+// Budget returns the total budget set by WithBudget.
+func Budget(ctx context.Context) (time.Duration, bool) {
+	b, ok := budgetKey.Get(ctx)
+	return b.total, ok
+}
+
+// Remaining returns the time left until ctx's deadline. False is returned if ctx has no deadline.
+// The remaining time is never negative.
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	if left := time.Until(deadline); left > 0 {
+		return left, true
+	}
+	return 0, true
+}
+
+// Slice returns a copy of ctx with a deadline of fraction (0.0 to 1.0) of the remaining time.
+// Contexts without a deadline are returned with only a cancel func.
+//
+// The returned CancelFunc must be called. It records exhaustion metrics, labeled by name,
+// when the slice ran out of time.
+func Slice(ctx context.Context, name string, fraction float64) (context.Context, context.CancelFunc) {
+	left, ok := Remaining(ctx)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(float64(left)*fraction))
+	return ctx, func() {
+		if ctx.Err() == context.DeadlineExceeded {
+			budgetExhausted.With("name", name).Add(1)
+		}
+		cancel()
+	}
+}
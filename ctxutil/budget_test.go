@@ -0,0 +1,80 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ctxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBudget(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), time.Minute)
+	defer cancel()
+
+	total, ok := Budget(ctx)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, total)
+
+	left, ok := Remaining(ctx)
+	require.True(t, ok)
+	require.True(t, left > 59*time.Second && left <= time.Minute, "left=%v", left)
+
+	// budgets can't extend their parent
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+
+	ctx, cancel = WithBudget(parent, time.Hour)
+	defer cancel()
+
+	left, _ = Remaining(ctx)
+	require.True(t, left <= time.Second, "left=%v", left)
+}
+
+func TestRemaining(t *testing.T) {
+	_, ok := Remaining(context.Background())
+	require.False(t, ok)
+
+	_, ok = Budget(context.Background())
+	require.False(t, ok)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	left, ok := Remaining(ctx)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), left)
+}
+
+func TestSlice(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db, dbCancel := Slice(ctx, "database", 0.6)
+	defer dbCancel()
+
+	left, _ := Remaining(db)
+	require.True(t, left > 5*time.Second && left <= 6*time.Second, "left=%v", left)
+
+	all, allCancel := Slice(ctx, "partner", 2.0)
+	defer allCancel()
+
+	left, _ = Remaining(all)
+	require.True(t, left > 9*time.Second && left <= 10*time.Second, "left=%v", left)
+
+	none, noneCancel := Slice(ctx, "none", -1)
+	defer noneCancel()
+	<-none.Done()
+	require.Equal(t, context.DeadlineExceeded, none.Err())
+
+	// no deadline
+	plain, plainCancel := Slice(context.Background(), "plain", 0.5)
+	_, ok := plain.Deadline()
+	require.False(t, ok)
+	plainCancel()
+	require.Equal(t, context.Canceled, plain.Err())
+}
@@ -4,23 +4,71 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/opts"
 )
 
+// Option configures the connection pool returned from New
+type Option = opts.Option[sql.DB]
+
+// WithMaxOpenConns limits the number of open connections, see sql.DB.SetMaxOpenConns.
+func WithMaxOpenConns(n int) Option {
+	return opts.New("max-open-conns", func(db *sql.DB) error {
+		db.SetMaxOpenConns(n)
+		return nil
+	})
+}
+
+// WithMaxIdleConns limits the number of idle connections, see sql.DB.SetMaxIdleConns.
+func WithMaxIdleConns(n int) Option {
+	return opts.New("max-idle-conns", func(db *sql.DB) error {
+		db.SetMaxIdleConns(n)
+		return nil
+	})
+}
+
+// WithConnMaxLifetime closes connections older than d, see sql.DB.SetConnMaxLifetime.
+func WithConnMaxLifetime(d time.Duration) Option {
+	return opts.New("conn-max-lifetime", func(db *sql.DB) error {
+		db.SetConnMaxLifetime(d)
+		return nil
+	})
+}
+
+// WithConnMaxIdleTime closes connections idle longer than d, see sql.DB.SetConnMaxIdleTime.
+func WithConnMaxIdleTime(d time.Duration) Option {
+	return opts.New("conn-max-idle-time", func(db *sql.DB) error {
+		db.SetConnMaxIdleTime(d)
+		return nil
+	})
+}
+
 // New establishes a database connection according to the type and environmental
-// variables for that specific database.
-func New(ctx context.Context, logger log.Logger, config DatabaseConfig) (*sql.DB, error) {
+// variables for that specific database. Options are applied to the connection pool.
+func New(ctx context.Context, logger log.Logger, config DatabaseConfig, options ...Option) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
 	if config.MySQL != nil {
-		return mysqlConnection(logger, config.MySQL.User, config.MySQL.Password, config.MySQL.Address, config.DatabaseName).Connect(ctx)
+		db, err = mysqlConnection(logger, config.MySQL.User, config.MySQL.Password, config.MySQL.Address, config.DatabaseName).Connect(ctx)
 	} else if config.SQLite != nil {
-		return sqliteConnection(logger, config.SQLite.Path).Connect(ctx)
+		db, err = sqliteConnection(logger, config.SQLite.Path).Connect(ctx)
+	} else {
+		return nil, fmt.Errorf("database config not defined")
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("database config not defined")
+	if err := opts.Apply(db, options...); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
-func NewAndMigrate(ctx context.Context, logger log.Logger, config DatabaseConfig) (*sql.DB, error) {
+func NewAndMigrate(ctx context.Context, logger log.Logger, config DatabaseConfig, options ...Option) (*sql.DB, error) {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
@@ -35,7 +83,7 @@ func NewAndMigrate(ctx context.Context, logger log.Logger, config DatabaseConfig
 	}
 
 	// create DB connection for our service
-	db, err := New(ctx, logger, config)
+	db, err := New(ctx, logger, config, options...)
 	if err != nil {
 		return nil, err
 	}
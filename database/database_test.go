@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/moov-io/base/docker"
+	"github.com/moov-io/base/log"
 )
 
 func Test_NewAndMigration_SQLite(t *testing.T) {
@@ -31,6 +33,19 @@ func Test_NewAndMigration_SQLite(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_New_Options(t *testing.T) {
+	dir := t.TempDir()
+	config := DatabaseConfig{SQLite: &SQLiteConfig{
+		Path: filepath.Join(dir, "tests.db"),
+	}}
+
+	db, err := New(context.Background(), log.NewNopLogger(), config, WithMaxOpenConns(3), WithConnMaxLifetime(time.Minute))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.Equal(t, 3, db.Stats().MaxOpenConnections)
+}
+
 func Test_NewAndMigration_MySql(t *testing.T) {
 	if !docker.Enabled() {
 		t.SkipNow()
@@ -0,0 +1,156 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DateFormat is the ISO 8601 calendar date format used by Date
+	DateFormat = "2006-01-02"
+)
+
+// Date is a calendar date (year, month and day) without a time of day or location.
+//
+// Dates are used for values such as effective entry dates and settlement dates where
+// converting through a time.Time in the wrong time zone would shift the day.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the Date for year, month and day. Values outside their usual ranges
+// are normalized, so NewDate(2020, time.December, 32) is January 1st 2021.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the Date t falls on in its location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate reads a Date in the YYYY-MM-DD format.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateFormat, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// In returns midnight of the Date in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String returns the Date in the YYYY-MM-DD format.
+func (d Date) String() string {
+	return d.In(time.UTC).Format(DateFormat)
+}
+
+// Weekday returns the day of the week d falls on.
+func (d Date) Weekday() time.Weekday {
+	return d.In(time.UTC).Weekday()
+}
+
+// AddDays returns the Date n calendar days after d. n can be negative.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// DaysSince returns the number of calendar days from other until d.
+func (d Date) DaysSince(other Date) int {
+	return int(d.In(time.UTC).Sub(other.In(time.UTC)).Hours() / 24)
+}
+
+// Before reports whether d is before other.
+func (d Date) Before(other Date) bool {
+	return d.DaysSince(other) < 0
+}
+
+// After reports whether d is after other.
+func (d Date) After(other Date) bool {
+	return d.DaysSince(other) > 0
+}
+
+// Equal reports whether d and other are the same day.
+func (d Date) Equal(other Date) bool {
+	return d.DaysSince(other) == 0
+}
+
+// IsWeekend reports whether d falls on a Saturday or Sunday.
+func (d Date) IsWeekend() bool {
+	day := d.Weekday()
+	return day == time.Saturday || day == time.Sunday
+}
+
+// IsBankingDay reports whether the Federal Reserve Banks are open on d. Dates outside of the
+// embedded holiday schedule are checked against the US holiday rules, use CheckBankingDay to
+// reject them instead.
+func (d Date) IsBankingDay() bool {
+	return isBankingDay(d)
+}
+
+// CheckBankingDay reports whether the Federal Reserve Banks are open on d from the embedded
+// holiday schedule. A HolidayRangeError is returned for dates outside of it.
+func (d Date) CheckBankingDay() (bool, error) {
+	return FederalReserveCalendar().IsBankingDay(DateOf(d.In(time.UTC)))
+}
+
+// AddBankingDays returns the Date n banking days after d. Negative values of n move backwards.
+// AddBankingDays(0) returns d if it's a banking day, otherwise the next banking day.
+func (d Date) AddBankingDays(n int) Date {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	if n == 0 {
+		for !d.IsBankingDay() {
+			d = d.AddDays(1)
+		}
+		return d
+	}
+	for n > 0 {
+		d = d.AddDays(step)
+		if d.IsBankingDay() {
+			n--
+		}
+	}
+	return d
+}
+
+// MarshalJSON returns the Date as a JSON string in the YYYY-MM-DD format.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON reads a Date from a JSON string in the YYYY-MM-DD format.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	// Ignore null, like in the main JSON package.
+	if string(data) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid date %s: %v", string(data), err)
+	}
+	dd, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = dd
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	d := NewDate(2020, time.December, 32)
+	if d != (Date{2021, time.January, 1}) {
+		t.Errorf("unexpected normalized date: %v", d)
+	}
+	if v := d.String(); v != "2021-01-01" {
+		t.Errorf("got %q", v)
+	}
+	if d.IsZero() || !(Date{}).IsZero() {
+		t.Error("unexpected IsZero")
+	}
+
+	// late evening in Los Angeles is the next day in UTC
+	pacific, _ := time.LoadLocation("America/Los_Angeles")
+	when := time.Date(2020, time.March, 9, 22, 0, 0, 0, pacific)
+	if d := DateOf(when); d.Day != 9 {
+		t.Errorf("unexpected date: %v", d)
+	}
+	if d := DateOf(when.UTC()); d.Day != 10 {
+		t.Errorf("unexpected date: %v", d)
+	}
+}
+
+func TestDate__Compare(t *testing.T) {
+	a, b := NewDate(2020, time.February, 28), NewDate(2020, time.March, 1)
+	if n := b.DaysSince(a); n != 2 {
+		t.Errorf("expected 2 days, got %d", n)
+	}
+	if !a.Before(b) || a.After(b) || !b.After(a) || a.Equal(b) {
+		t.Error("unexpected comparison")
+	}
+	if !a.AddDays(2).Equal(b) || !b.AddDays(-2).Equal(a) {
+		t.Error("unexpected AddDays")
+	}
+}
+
+func TestDate__BankingDays(t *testing.T) {
+	// Friday before MLK day
+	fri := NewDate(2018, time.January, 12)
+	if !fri.IsBankingDay() || fri.IsWeekend() {
+		t.Errorf("%v should be a banking day", fri)
+	}
+	if d := fri.AddBankingDays(1); d != NewDate(2018, time.January, 16) {
+		t.Errorf("unexpected next banking day: %v", d)
+	}
+	if d := NewDate(2018, time.January, 16).AddBankingDays(-1); d != fri {
+		t.Errorf("unexpected previous banking day: %v", d)
+	}
+	if d := NewDate(2018, time.January, 13).AddBankingDays(0); d != NewDate(2018, time.January, 16) {
+		t.Errorf("unexpected banking day: %v", d)
+	}
+	if d := fri.AddBankingDays(0); d != fri {
+		t.Errorf("unexpected banking day: %v", d)
+	}
+}
+
+func TestDate__CheckBankingDay(t *testing.T) {
+	// MLK day
+	if ok, err := NewDate(2018, time.January, 15).CheckBankingDay(); ok || err != nil {
+		t.Errorf("expected holiday: %v, %v", ok, err)
+	}
+	if ok, err := NewDate(2018, time.January, 16).CheckBankingDay(); !ok || err != nil {
+		t.Errorf("expected banking day: %v, %v", ok, err)
+	}
+
+	// outside of the embedded schedule
+	d := NewDate(2099, time.January, 6)
+	if !d.IsBankingDay() {
+		t.Errorf("%v should be a banking day from the rules", d)
+	}
+	var rangeErr HolidayRangeError
+	if _, err := d.CheckBankingDay(); !errors.As(err, &rangeErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := NewTime(d.In(time.UTC)).CheckBankingDay(); !errors.As(err, &rangeErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDate__JSON(t *testing.T) {
+	bs, err := json.Marshal(NewDate(2020, time.July, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := string(bs); v != `"2020-07-04"` {
+		t.Errorf("got %s", v)
+	}
+
+	var d Date
+	if err := json.Unmarshal(bs, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d != NewDate(2020, time.July, 4) {
+		t.Errorf("got %v", d)
+	}
+	if err := json.Unmarshal([]byte(`null`), &d); err != nil || d.Day != 4 {
+		t.Errorf("unexpected null handling: %v %v", d, err)
+	}
+	if err := json.Unmarshal([]byte(`"07/04/2020"`), &d); err == nil {
+		t.Error("expected error")
+	}
+	if err := json.Unmarshal([]byte(`20200704`), &d); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package decimal implements an arbitrary-precision, fixed-point Decimal type for rates,
+// interest calculations and FX conversions which must never go through float64.
+//
+// A Decimal is an integer coefficient and a scale (the number of digits after the decimal
+// point). Addition, subtraction and multiplication are exact. Division and rounding take an
+// explicit number of places and RoundingMode.
+//
+// The zero value is 0 and Decimal values are immutable, so they are safe to copy and share.
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDivisionByZero is returned when dividing by a zero Decimal
+	ErrDivisionByZero = errors.New("decimal division by zero")
+
+	// ErrNotIntegral is returned when converting a Decimal with a fractional part into an integer
+	ErrNotIntegral = errors.New("decimal has a fractional part")
+
+	// ErrOverflow is returned when a Decimal doesn't fit into the requested type
+	ErrOverflow = errors.New("decimal overflows int64")
+
+	// Zero is the Decimal value 0
+	Zero = Decimal{}
+
+	bigTen = big.NewInt(10)
+)
+
+// maxScale bounds parsed exponents so malicious input (i.e. "1e-999999999") can't exhaust memory.
+const maxScale = 1 << 12
+
+// RoundingMode determines how a Decimal is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest neighbor, ties away from zero (2.5 -> 3, -2.5 -> -3)
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest neighbor, ties to the even neighbor (banker's rounding)
+	RoundHalfEven
+	// RoundHalfDown rounds to the nearest neighbor, ties toward zero (2.5 -> 2, -2.5 -> -2)
+	RoundHalfDown
+	// RoundDown truncates toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+)
+
+// Decimal is a fixed-point decimal number.
+type Decimal struct {
+	value *big.Int // unscaled coefficient, nil means zero
+	scale int32    // digits after the decimal point, never negative
+}
+
+// New returns the Decimal unscaled * 10^-scale, so New(12345, 2) is 123.45
+func New(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		v := new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))
+		return Decimal{value: v}
+	}
+	return Decimal{value: big.NewInt(unscaled), scale: scale}
+}
+
+// NewFromInt returns the Decimal of an integer
+func NewFromInt(i int64) Decimal {
+	return New(i, 0)
+}
+
+// Parse reads a decimal string such as "-12.3450", "+1" or "1.5e-3".
+// Trailing zeros are kept so "1.50" has a scale of 2.
+func Parse(s string) (Decimal, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+
+	exp := int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Zero, fmt.Errorf("decimal: invalid exponent in %q", orig)
+		}
+		exp, s = e, s[:i]
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return Zero, fmt.Errorf("decimal: invalid syntax %q", orig)
+	}
+	digits := intPart + fracPart
+	for i := range digits {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Zero, fmt.Errorf("decimal: invalid syntax %q", orig)
+		}
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > maxScale || scale < -maxScale {
+		return Zero, fmt.Errorf("decimal: exponent out of range in %q", orig)
+	}
+
+	value, _ := new(big.Int).SetString(sign+digits, 10)
+	if scale < 0 {
+		value.Mul(value, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParse is like Parse but panics on invalid input. It's intended for constants.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// rescale returns the coefficient of d at a larger scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale <= d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+func maxScaleOf(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := maxScaleOf(d, other)
+	return Decimal{value: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := maxScaleOf(d, other)
+	return Decimal{value: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * other. The result's scale is the sum of both scales.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale}
+}
+
+// Div returns d / other rounded to places digits after the decimal point.
+func (d Decimal) Div(other Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Zero, ErrDivisionByZero
+	}
+	if places < 0 {
+		places = 0
+	}
+	// d/other = (a * 10^-s1) / (b * 10^-s2), so the coefficient at places is a * 10^(s2+places) / (b * 10^s1)
+	num := new(big.Int).Mul(d.int(), pow10(other.scale+places))
+	den := new(big.Int).Mul(other.int(), pow10(d.scale))
+	return Decimal{value: roundQuo(num, den, mode), scale: places}, nil
+}
+
+// Round returns d with exactly places digits after the decimal point.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{value: d.rescale(places), scale: places}
+	}
+	return Decimal{value: roundQuo(d.int(), pow10(d.scale-places), mode), scale: places}
+}
+
+// roundQuo returns num/den rounded to an integer according to mode.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	negative := (num.Sign() < 0) != (den.Sign() < 0)
+
+	// compare the discarded remainder against half of the divisor
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(den))
+
+	increment := false
+	switch mode {
+	case RoundHalfUp:
+		increment = cmp >= 0
+	case RoundHalfEven:
+		increment = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+	case RoundHalfDown:
+		increment = cmp > 0
+	case RoundDown:
+		increment = false
+	case RoundUp:
+		increment = true
+	case RoundFloor:
+		increment = negative
+	case RoundCeiling:
+		increment = !negative
+	}
+	if increment {
+		if negative {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Cmp compares d and other returning -1 if d < other, 0 if equal and +1 if d > other.
+// Scale is ignored, so 1.5 and 1.50 are equal.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := maxScaleOf(d, other)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal reports whether d and other represent the same number
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Int64 returns d as an int64. An error is returned if d has a fractional part or overflows.
+func (d Decimal) Int64() (int64, error) {
+	q, r := new(big.Int).QuoRem(d.int(), pow10(d.scale), new(big.Int))
+	if r.Sign() != 0 {
+		return 0, ErrNotIntegral
+	}
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return q.Int64(), nil
+}
+
+// String returns d in plain (non-exponent) notation keeping its scale, i.e. "-0.050"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	i := len(digits) - int(d.scale)
+	return sign + digits[:i] + "." + digits[i:]
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON string so precision isn't lost by float64 decoders.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON reads a Decimal from a JSON string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	// Ignore null, like in the main JSON package.
+	if string(data) == "null" {
+		return nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("decimal: %v", err)
+		}
+		s = unquoted
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// Scan implements sql.Scanner for reading Decimal columns (i.e. DECIMAL(19,6) or TEXT)
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case []byte:
+		return d.UnmarshalText(v)
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		return d.UnmarshalText([]byte(strconv.FormatFloat(v, 'f', -1, 64)))
+	}
+	return fmt.Errorf("decimal: unable to scan %T", src)
+}
+
+// Value implements driver.Valuer by writing d as a string
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
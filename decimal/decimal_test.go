@@ -0,0 +1,178 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package decimal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		scale    int32
+	}{
+		{"0", "0", 0},
+		{"12.345", "12.345", 3},
+		{"-0.05", "-0.05", 2},
+		{"+1.50", "1.50", 2},
+		{".5", "0.5", 1},
+		{"5.", "5", 0},
+		{"1.5e-3", "0.0015", 4},
+		{"1.5E2", "150", 0},
+		{" 42 ", "42", 0},
+	}
+	for _, test := range tests {
+		d, err := Parse(test.input)
+		require.NoError(t, err, test.input)
+		require.Equal(t, test.expected, d.String(), test.input)
+		require.Equal(t, test.scale, d.Scale(), test.input)
+	}
+
+	for _, input := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1e-99999", "--1", "1,000"} {
+		_, err := Parse(input)
+		require.Error(t, err, input)
+	}
+	require.Panics(t, func() { MustParse("bad") })
+}
+
+func TestDecimal__Zero(t *testing.T) {
+	var d Decimal
+	require.True(t, d.IsZero())
+	require.Equal(t, "0", d.String())
+	require.Equal(t, "1.5", d.Add(MustParse("1.5")).String())
+	require.True(t, d.Equal(MustParse("0.000")))
+}
+
+func TestDecimal__Arithmetic(t *testing.T) {
+	a, b := MustParse("10.25"), MustParse("3.5")
+
+	require.Equal(t, "13.75", a.Add(b).String())
+	require.Equal(t, "6.75", a.Sub(b).String())
+	require.Equal(t, "-6.75", b.Sub(a).String())
+	require.Equal(t, "35.875", a.Mul(b).String())
+	require.Equal(t, "-10.25", a.Neg().String())
+	require.Equal(t, "10.25", a.Neg().Abs().String())
+
+	// operations don't modify their inputs
+	require.Equal(t, "10.25", a.String())
+	require.Equal(t, "3.5", b.String())
+
+	q, err := a.Div(b, 6, RoundHalfEven)
+	require.NoError(t, err)
+	require.Equal(t, "2.928571", q.String())
+
+	q, err = NewFromInt(1).Div(NewFromInt(3), 4, RoundHalfUp)
+	require.NoError(t, err)
+	require.Equal(t, "0.3333", q.String())
+
+	q, err = NewFromInt(-2).Div(NewFromInt(3), 2, RoundHalfUp)
+	require.NoError(t, err)
+	require.Equal(t, "-0.67", q.String())
+
+	_, err = a.Div(Zero, 2, RoundHalfUp)
+	require.Equal(t, ErrDivisionByZero, err)
+
+	require.Equal(t, "1200", New(12, -2).String())
+}
+
+func TestDecimal__Round(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.5", RoundHalfUp, "3"},
+		{"-2.5", RoundHalfUp, "-3"},
+		{"2.5", RoundHalfEven, "2"},
+		{"3.5", RoundHalfEven, "4"},
+		{"-2.5", RoundHalfEven, "-2"},
+		{"2.5", RoundHalfDown, "2"},
+		{"2.51", RoundHalfDown, "3"},
+		{"2.9", RoundDown, "2"},
+		{"-2.9", RoundDown, "-2"},
+		{"2.1", RoundUp, "3"},
+		{"-2.1", RoundUp, "-3"},
+		{"2.9", RoundFloor, "2"},
+		{"-2.1", RoundFloor, "-3"},
+		{"2.1", RoundCeiling, "3"},
+		{"-2.9", RoundCeiling, "-2"},
+		{"2.0", RoundUp, "2"},
+	}
+	for _, test := range tests {
+		out := MustParse(test.input).Round(0, test.mode)
+		require.Equal(t, test.expected, out.String(), "%s mode=%d", test.input, test.mode)
+	}
+
+	require.Equal(t, "1.2346", MustParse("1.23456").Round(4, RoundHalfUp).String())
+	require.Equal(t, "1.2000", MustParse("1.2").Round(4, RoundHalfUp).String())
+}
+
+func TestDecimal__Cmp(t *testing.T) {
+	require.Equal(t, 0, MustParse("1.5").Cmp(MustParse("1.500")))
+	require.Equal(t, -1, MustParse("1.49").Cmp(MustParse("1.5")))
+	require.Equal(t, 1, MustParse("-1").Cmp(MustParse("-1.01")))
+	require.Equal(t, -1, MustParse("-0.5").Sign())
+}
+
+func TestDecimal__Int64(t *testing.T) {
+	n, err := MustParse("1234.00").Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), n)
+
+	_, err = MustParse("1.01").Int64()
+	require.Equal(t, ErrNotIntegral, err)
+
+	_, err = MustParse("99999999999999999999").Int64()
+	require.Equal(t, ErrOverflow, err)
+}
+
+func TestDecimal__JSON(t *testing.T) {
+	type wrapper struct {
+		Rate Decimal `json:"rate"`
+	}
+	bs, err := json.Marshal(wrapper{Rate: MustParse("0.0425")})
+	require.NoError(t, err)
+	require.Equal(t, `{"rate":"0.0425"}`, string(bs))
+
+	var w wrapper
+	require.NoError(t, json.Unmarshal(bs, &w))
+	require.Equal(t, "0.0425", w.Rate.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"rate":0.0525}`), &w))
+	require.Equal(t, "0.0525", w.Rate.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"rate":null}`), &w))
+	require.Equal(t, "0.0525", w.Rate.String())
+
+	require.Error(t, json.Unmarshal([]byte(`{"rate":"abc"}`), &w))
+}
+
+func TestDecimal__SQL(t *testing.T) {
+	var d Decimal
+	require.NoError(t, d.Scan([]byte("12.50")))
+	require.Equal(t, "12.50", d.String())
+
+	require.NoError(t, d.Scan("1.1"))
+	require.Equal(t, "1.1", d.String())
+
+	require.NoError(t, d.Scan(int64(7)))
+	require.Equal(t, "7", d.String())
+
+	require.NoError(t, d.Scan(float64(0.25)))
+	require.Equal(t, "0.25", d.String())
+
+	require.NoError(t, d.Scan(nil))
+	require.True(t, d.IsZero())
+
+	require.Error(t, d.Scan(true))
+
+	v, err := MustParse("3.14").Value()
+	require.NoError(t, err)
+	require.Equal(t, "3.14", v)
+}
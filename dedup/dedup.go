@@ -0,0 +1,187 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package dedup detects files submitted more than once, such as a partner uploading the same
+// ACH file twice. Files are fingerprinted by their size, SHA-256 hash, record count and total
+// amount, and checked against recent submissions.
+//
+//	detector := dedup.New(dedup.NewSQLStore(db, "file_submissions"), dedup.Config{Window: 72 * time.Hour})
+//
+//	fp, err := dedup.NewFingerprint(bytes.NewReader(contents), len(file.Batches), total)
+//	if err := detector.Record(ctx, fileID, fp); err != nil {
+//		var dup *dedup.DuplicateFileError
+//		if errors.As(err, &dup) {
+//			// reject the upload, dup.Prior is the earlier submission
+//		}
+//	}
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DefaultWindow is how long submissions are remembered when Config.Window is zero
+const DefaultWindow = 24 * time.Hour
+
+// Fingerprint identifies the contents of a file
+type Fingerprint struct {
+	Size    int64       `json:"size"`
+	Hash    string      `json:"hash"`
+	Records int         `json:"records"`
+	Total   base.Amount `json:"total"`
+}
+
+// NewFingerprint reads r to hash it. records and total are counted by the caller while parsing
+// the file, as they depend on its format.
+func NewFingerprint(r io.Reader, records int, total base.Amount) (Fingerprint, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("dedup: reading file: %w", err)
+	}
+	return Fingerprint{
+		Size:    n,
+		Hash:    hex.EncodeToString(h.Sum(nil)),
+		Records: records,
+		Total:   base.NewAmount(total.Value, total.Currency),
+	}, nil
+}
+
+// sameContents reports whether fp has the records and total of other, which catches files
+// regenerated with a new creation time or ordering.
+func (fp Fingerprint) sameContents(other Fingerprint) bool {
+	return fp.Records == other.Records && fp.Total == other.Total
+}
+
+// Submission is a file recorded by a Detector
+type Submission struct {
+	ID          string      `json:"id"`
+	Fingerprint Fingerprint `json:"fingerprint"`
+	SubmittedAt time.Time   `json:"submittedAt"`
+}
+
+// DuplicateFileError is returned when a file matches an earlier Submission. Exact is true when
+// the hashes match, otherwise only the record counts and totals do.
+type DuplicateFileError struct {
+	Fingerprint Fingerprint
+	Prior       Submission
+	Exact       bool
+}
+
+func (e *DuplicateFileError) Error() string {
+	kind := "contents"
+	if e.Exact {
+		kind = "hash"
+	}
+	return fmt.Sprintf("duplicate file: %s matches submission %s from %s", kind, e.Prior.ID, e.Prior.SubmittedAt.UTC().Format(time.RFC3339))
+}
+
+// Store saves submissions
+type Store interface {
+	// Find returns submissions since a time whose hash, or record count and total, match fp
+	Find(ctx context.Context, fp Fingerprint, since time.Time) ([]Submission, error)
+	Save(ctx context.Context, sub Submission) error
+
+	// CheckAndSave passes check the submissions Find would return for sub's fingerprint and
+	// saves sub unless check returns an error. Submissions which could match each other are
+	// checked and saved one at a time, so two uploads of one file can't both be saved.
+	CheckAndSave(ctx context.Context, sub Submission, since time.Time, check func([]Submission) error) error
+
+	// DeleteBefore removes submissions older than before
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Config controls what a Detector considers a duplicate
+type Config struct {
+	// Window is how far back submissions are compared, DefaultWindow when zero
+	Window time.Duration
+
+	// HashOnly only matches files with the same hash. Otherwise files with the same record
+	// count and total are also duplicates.
+	HashOnly bool
+}
+
+// Detector checks files against recent submissions
+type Detector struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+// New returns a Detector keeping submissions in store
+func New(store Store, cfg Config) *Detector {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	return &Detector{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Check returns a *DuplicateFileError when fp matches a submission within the window. Exact
+// matches are preferred, followed by the most recent submission.
+func (d *Detector) Check(ctx context.Context, fp Fingerprint) error {
+	subs, err := d.store.Find(ctx, fp, d.now().Add(-d.cfg.Window))
+	if err != nil {
+		return fmt.Errorf("dedup: finding submissions: %w", err)
+	}
+	return d.duplicate(fp, subs)
+}
+
+// duplicate returns a *DuplicateFileError for the best match of fp in subs
+func (d *Detector) duplicate(fp Fingerprint, subs []Submission) error {
+	var dup *DuplicateFileError
+	for _, sub := range subs {
+		exact := sub.Fingerprint.Hash == fp.Hash
+		if !exact && (d.cfg.HashOnly || !sub.Fingerprint.sameContents(fp)) {
+			continue
+		}
+		if dup == nil || (exact && !dup.Exact) || (exact == dup.Exact && sub.SubmittedAt.After(dup.Prior.SubmittedAt)) {
+			dup = &DuplicateFileError{Fingerprint: fp, Prior: sub, Exact: exact}
+		}
+	}
+	if dup != nil {
+		return dup
+	}
+	return nil
+}
+
+// Record saves fp as submission id unless it's a duplicate, which returns a *DuplicateFileError.
+// The check and save are atomic (see Store.CheckAndSave), so only one of two uploads of a file
+// arriving at once is recorded.
+func (d *Detector) Record(ctx context.Context, id string, fp Fingerprint) error {
+	if id == "" {
+		return errors.New("dedup: missing submission ID")
+	}
+	now := d.now()
+	sub := Submission{ID: id, Fingerprint: fp, SubmittedAt: now.UTC()}
+
+	var dup error
+	err := d.store.CheckAndSave(ctx, sub, now.Add(-d.cfg.Window), func(subs []Submission) error {
+		dup = d.duplicate(fp, subs)
+		return dup
+	})
+	switch {
+	case err == nil:
+		return nil
+	case dup != nil && errors.Is(err, dup):
+		return dup
+	}
+	return fmt.Errorf("dedup: saving submission %s: %w", id, err)
+}
+
+// Prune deletes submissions which have aged out of the window.
+func (d *Detector) Prune(ctx context.Context) (int64, error) {
+	return d.store.DeleteBefore(ctx, d.now().Add(-d.cfg.Window))
+}
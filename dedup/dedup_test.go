@@ -0,0 +1,136 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dedup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sqliteStore(t *testing.T) Store {
+	t.Helper()
+
+	db := database.CreateTestSQLiteDB(t)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	return NewSQLStore(db.DB, "file_submissions")
+}
+
+func stores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore(t),
+	}
+}
+
+func fingerprint(t *testing.T, contents string, records int, total int64) Fingerprint {
+	t.Helper()
+	fp, err := NewFingerprint(strings.NewReader(contents), records, base.NewAmount(total, "usd"))
+	require.NoError(t, err)
+	return fp
+}
+
+func TestNewFingerprint(t *testing.T) {
+	fp := fingerprint(t, "hello", 1, 100)
+	require.Equal(t, int64(5), fp.Size)
+	require.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", fp.Hash)
+	require.Equal(t, base.NewAmount(100, "USD"), fp.Total)
+}
+
+func TestDetector(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			detector := New(store, Config{})
+
+			now := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+			detector.now = func() time.Time { return now }
+
+			first := fingerprint(t, "file one", 10, 5000)
+			require.NoError(t, detector.Record(ctx, "f1", first))
+
+			// same bytes
+			now = now.Add(time.Hour)
+			err := detector.Record(ctx, "f2", first)
+			var dup *DuplicateFileError
+			require.True(t, errors.As(err, &dup))
+			require.True(t, dup.Exact)
+			require.Equal(t, "f1", dup.Prior.ID)
+			require.Equal(t, first, dup.Prior.Fingerprint)
+			require.Equal(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC), dup.Prior.SubmittedAt)
+			require.Equal(t, "duplicate file: hash matches submission f1 from 2021-03-04T12:00:00Z", err.Error())
+
+			// regenerated with the same contents
+			err = detector.Record(ctx, "f3", fingerprint(t, "file one, again", 10, 5000))
+			require.True(t, errors.As(err, &dup))
+			require.False(t, dup.Exact)
+
+			// different file
+			second := fingerprint(t, "file two", 10, 7500)
+			require.NoError(t, detector.Record(ctx, "f4", second))
+
+			// outside the window
+			now = now.Add(DefaultWindow - 30*time.Minute)
+			require.NoError(t, detector.Check(ctx, first))
+			require.Error(t, detector.Check(ctx, second))
+
+			deleted, err := detector.Prune(ctx)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), deleted)
+		})
+	}
+}
+
+func TestDetector__HashOnly(t *testing.T) {
+	detector := New(NewMemoryStore(), Config{HashOnly: true, Window: time.Hour})
+	ctx := context.Background()
+
+	require.NoError(t, detector.Record(ctx, "f1", fingerprint(t, "file one", 10, 5000)))
+	require.NoError(t, detector.Record(ctx, "f2", fingerprint(t, "file one, again", 10, 5000)))
+	require.Error(t, detector.Record(ctx, "f3", fingerprint(t, "file one", 10, 5000)))
+	require.Error(t, detector.Record(ctx, "", fingerprint(t, "file three", 1, 1)))
+}
+
+func TestDetector__Concurrent(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			detector := New(store, Config{})
+			fp := fingerprint(t, "file one", 10, 5000)
+
+			// uploads of one file arriving at once are only recorded once
+			errs := make(chan error, 10)
+			for i := 0; i < 10; i++ {
+				id := fmt.Sprintf("f%d", i)
+				go func() {
+					errs <- detector.Record(context.Background(), id, fp)
+				}()
+			}
+			var recorded, duplicates int
+			for i := 0; i < 10; i++ {
+				var dup *DuplicateFileError
+				switch err := <-errs; {
+				case err == nil:
+					recorded++
+				case errors.As(err, &dup):
+					duplicates++
+				default:
+					t.Fatal(err)
+				}
+			}
+			require.Equal(t, 1, recorded)
+			require.Equal(t, 9, duplicates)
+		})
+	}
+}
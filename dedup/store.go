@@ -0,0 +1,236 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dedup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+)
+
+// MemoryStore keeps submissions in memory, which suits a single instance or tests
+type MemoryStore struct {
+	mu   sync.Mutex
+	subs []Submission
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Find(ctx context.Context, fp Fingerprint, since time.Time) ([]Submission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.find(fp, since), nil
+}
+
+func (s *MemoryStore) find(fp Fingerprint, since time.Time) []Submission {
+	var out []Submission
+	for _, sub := range s.subs {
+		if !sub.SubmittedAt.After(since) {
+			continue
+		}
+		if sub.Fingerprint.Hash == fp.Hash || sub.Fingerprint.sameContents(fp) {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+func (s *MemoryStore) Save(ctx context.Context, sub Submission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save(sub)
+}
+
+func (s *MemoryStore) CheckAndSave(ctx context.Context, sub Submission, since time.Time, check func([]Submission) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := check(s.find(sub.Fingerprint, since)); err != nil {
+		return err
+	}
+	return s.save(sub)
+}
+
+func (s *MemoryStore) save(sub Submission) error {
+	for _, existing := range s.subs {
+		if existing.ID == sub.ID {
+			return fmt.Errorf("submission %s already exists", sub.ID)
+		}
+	}
+	s.subs = append(s.subs, sub)
+	return nil
+}
+
+func (s *MemoryStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	kept := s.subs[:0]
+	for _, sub := range s.subs {
+		if sub.SubmittedAt.Before(before) {
+			deleted++
+		} else {
+			kept = append(kept, sub)
+		}
+	}
+	s.subs = kept
+	return deleted, nil
+}
+
+// SQLStore keeps submissions in tables shared by every instance of a service. The tables are
+// created by the service's migrations, where the second's primary key makes CheckAndSave atomic:
+//
+//	CREATE TABLE file_submissions (
+//	    submission_id VARCHAR(64) NOT NULL PRIMARY KEY,
+//	    size BIGINT NOT NULL,
+//	    hash CHAR(64) NOT NULL,
+//	    records INTEGER NOT NULL,
+//	    currency CHAR(3) NOT NULL,
+//	    total BIGINT NOT NULL,
+//	    submitted_at BIGINT NOT NULL
+//	);
+//	CREATE INDEX file_submissions_hash ON file_submissions (hash);
+//	CREATE INDEX file_submissions_contents ON file_submissions (records, total);
+//
+//	CREATE TABLE file_submissions_keys (
+//	    contents_key VARCHAR(128) NOT NULL PRIMARY KEY,
+//	    updated_at BIGINT NOT NULL
+//	);
+//
+// submitted_at and updated_at hold Unix nanoseconds. Queries use ? placeholders for MySQL and SQLite.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store using table and table+"_keys" in db
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	return &SQLStore{
+		db:    db,
+		table: table,
+	}
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (s *SQLStore) Find(ctx context.Context, fp Fingerprint, since time.Time) ([]Submission, error) {
+	return s.find(ctx, s.db, fp, since)
+}
+
+func (s *SQLStore) find(ctx context.Context, q querier, fp Fingerprint, since time.Time) ([]Submission, error) {
+	query := fmt.Sprintf(`SELECT submission_id, size, hash, records, currency, total, submitted_at FROM %s
+WHERE submitted_at > ? AND (hash = ? OR (records = ? AND currency = ? AND total = ?))`, s.table)
+
+	rows, err := q.QueryContext(ctx, query, since.UnixNano(), fp.Hash, fp.Records, fp.Total.Currency, fp.Total.Value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Submission
+	for rows.Next() {
+		var sub Submission
+		var currency string
+		var total, at int64
+		if err := rows.Scan(&sub.ID, &sub.Fingerprint.Size, &sub.Fingerprint.Hash, &sub.Fingerprint.Records, &currency, &total, &at); err != nil {
+			return nil, err
+		}
+		sub.Fingerprint.Total = base.NewAmount(total, currency)
+		sub.SubmittedAt = time.Unix(0, at).UTC()
+		out = append(out, sub)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) Save(ctx context.Context, sub Submission) error {
+	return s.save(ctx, s.db, sub)
+}
+
+// CheckAndSave locks the row of the fingerprint's record count and total, which exact and
+// contents matches share, before finding and saving the submission.
+func (s *SQLStore) CheckAndSave(ctx context.Context, sub Submission, since time.Time, check func([]Submission) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx, sub.Fingerprint, sub.SubmittedAt); err != nil {
+		return fmt.Errorf("locking submissions: %w", err)
+	}
+	subs, err := s.find(ctx, tx, sub.Fingerprint, since)
+	if err != nil {
+		return err
+	}
+	if err := check(subs); err != nil {
+		return err
+	}
+	if err := s.save(ctx, tx, sub); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lock updates the row of fp's contents, inserting it when it's the first, so concurrent
+// submissions of matching files wait for each other
+func (s *SQLStore) lock(ctx context.Context, tx *sql.Tx, fp Fingerprint, now time.Time) error {
+	key := fmt.Sprintf("%d:%s:%d", fp.Records, fp.Total.Currency, fp.Total.Value)
+
+	update := fmt.Sprintf(`UPDATE %s_keys SET updated_at = ? WHERE contents_key = ?`, s.table)
+	res, err := tx.ExecContext(ctx, update, now.UnixNano(), key)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s_keys (contents_key, updated_at) VALUES (?, ?)`, s.table)
+	_, err = tx.ExecContext(ctx, insert, key, now.UnixNano())
+	if err == nil || !database.UniqueViolation(err) {
+		return err
+	}
+	// another submission inserted the key first, so wait on its row instead
+	_, err = tx.ExecContext(ctx, update, now.UnixNano(), key)
+	return err
+}
+
+func (s *SQLStore) save(ctx context.Context, e execer, sub Submission) error {
+	query := fmt.Sprintf(`INSERT INTO %s (submission_id, size, hash, records, currency, total, submitted_at) VALUES (?, ?, ?, ?, ?, ?, ?)`, s.table)
+	fp := sub.Fingerprint
+	_, err := e.ExecContext(ctx, query, sub.ID, fp.Size, fp.Hash, fp.Records, fp.Total.Currency, fp.Total.Value, sub.SubmittedAt.UnixNano())
+	if err != nil && database.UniqueViolation(err) {
+		return fmt.Errorf("submission %s already exists", sub.ID)
+	}
+	return err
+}
+
+// DeleteBefore removes submissions older than before. Rows of table+"_keys" are kept as they're
+// only locked, not read.
+func (s *SQLStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE submitted_at < ?`, s.table)
+	res, err := s.db.ExecContext(ctx, query, before.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
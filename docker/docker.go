@@ -2,6 +2,17 @@
 // Use of this source code is governed by an Apache License
 // license that can be found in the LICENSE file.
 
+// Package docker starts containers for integration tests with dockertest.
+//
+//	func TestRepository(t *testing.T) {
+//		t.Parallel()
+//
+//		db := docker.MySQL(t) // a fresh database, dropped when the test finishes
+//		repo := NewRepository(db.DSN)
+//		...
+//	}
+//
+// Tests are skipped when Docker isn't installed or with -short.
 package docker
 
 import (
@@ -0,0 +1,223 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	dc "github.com/ory/dockertest/v3/docker"
+)
+
+// Container describes a container started by Start
+type Container struct {
+	// Name makes the container shared. Tests (and test binaries) starting a Container with the
+	// same Name reuse one container, which is left running for later runs until Expire passes.
+	// Containers without a Name are started for a single test and removed when it finishes.
+	Name string
+
+	Repository string
+	Tag        string
+	Env        []string
+	Cmd        []string
+
+	// Port is the container port, such as "3306/tcp", mapped to a random host port
+	Port string
+
+	// Ready is called until it returns nil, or Timeout passes, before Start returns.
+	// Containers are ready once their port accepts connections when nil.
+	Ready func(ctx context.Context, r *Resource) error
+
+	// Timeout defaults to two minutes
+	Timeout time.Duration
+
+	// Expire stops and removes the container this long after it's started, cleaning up after
+	// test binaries which exit without running their cleanup. It defaults to 10 minutes.
+	Expire time.Duration
+}
+
+// Resource is a running container
+type Resource struct {
+	// Host and Port are the host address mapped to the Container's Port
+	Host string
+	Port string
+
+	pool     *dockertest.Pool
+	resource *dockertest.Resource
+}
+
+// Addr returns Host:Port
+func (r *Resource) Addr() string {
+	return net.JoinHostPort(r.Host, r.Port)
+}
+
+// Exec runs cmd inside the container and returns its combined output. An error is returned
+// when cmd exits with a non-zero code.
+func (r *Resource) Exec(cmd ...string) (string, error) {
+	var out bytes.Buffer
+	code, err := r.resource.Exec(cmd, dockertest.ExecOptions{
+		StdOut: &out,
+		StdErr: &out,
+	})
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		return out.String(), fmt.Errorf("docker: %s exited with %d: %s", cmd[0], code, strings.TrimSpace(out.String()))
+	}
+	return out.String(), nil
+}
+
+// Logs returns the container's stdout and stderr
+func (r *Resource) Logs() string {
+	var out bytes.Buffer
+	r.pool.Client.Logs(dc.LogsOptions{
+		Container:    r.resource.Container.ID,
+		OutputStream: &out,
+		ErrorStream:  &out,
+		Stdout:       true,
+		Stderr:       true,
+	})
+	return out.String()
+}
+
+// Start runs c and waits until it's ready. Tests are skipped with -short or when Docker isn't
+// available. Containers started for a single test are removed when it finishes and their logs
+// are written to the test output if it failed.
+func Start(t testing.TB, c Container) *Resource {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("-short flag enabled")
+	}
+	if !Enabled() {
+		t.Skip("Docker not enabled")
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Minute
+	}
+	if c.Expire <= 0 {
+		c.Expire = 10 * time.Minute
+	}
+
+	if c.Name != "" {
+		r, err := startShared(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	r, err := start(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("docker: %s:%s logs\n%s", c.Repository, c.Tag, r.Logs())
+		}
+		r.pool.Purge(r.resource)
+	})
+	return r
+}
+
+type shared struct {
+	once     sync.Once
+	resource *Resource
+	err      error
+}
+
+// sharedContainers holds the *shared of each named Container started by this process
+var sharedContainers sync.Map
+
+func startShared(c Container) (*Resource, error) {
+	v, _ := sharedContainers.LoadOrStore(c.Name, &shared{})
+	s := v.(*shared)
+	s.once.Do(func() {
+		s.resource, s.err = start(c)
+	})
+	return s.resource, s.err
+}
+
+func start(c Container) (*Resource, error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("docker: %v", err)
+	}
+	pool.MaxWait = c.Timeout
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Name:         c.Name,
+		Repository:   c.Repository,
+		Tag:          c.Tag,
+		Env:          c.Env,
+		Cmd:          c.Cmd,
+		ExposedPorts: []string{c.Port},
+	}, func(hc *dc.HostConfig) {
+		hc.AutoRemove = true
+	})
+	if err != nil {
+		if c.Name == "" || !errors.Is(err, dc.ErrContainerAlreadyExists) {
+			return nil, fmt.Errorf("docker: running %s:%s: %v", c.Repository, c.Tag, err)
+		}
+		// another test binary started the shared container
+		found, ok := pool.ContainerByName(c.Name)
+		if !ok {
+			return nil, fmt.Errorf("docker: finding %s container", c.Name)
+		}
+		resource = found
+	} else {
+		resource.Expire(uint(c.Expire.Seconds()))
+	}
+
+	hostPort := resource.GetHostPort(c.Port)
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		pool.Purge(resource)
+		return nil, fmt.Errorf("docker: %s isn't mapped: %v", c.Port, err)
+	}
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	r := &Resource{
+		Host:     host,
+		Port:     port,
+		pool:     pool,
+		resource: resource,
+	}
+
+	ready := c.Ready
+	if ready == nil {
+		ready = dialReady
+	}
+	err = pool.Retry(func() error {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFunc()
+		return ready(ctx, r)
+	})
+	if err != nil {
+		logs := r.Logs()
+		pool.Purge(resource)
+		return nil, fmt.Errorf("docker: %s:%s wasn't ready after %v: %v\n%s", c.Repository, c.Tag, c.Timeout, err, logs)
+	}
+	return r, nil
+}
+
+func dialReady(ctx context.Context, r *Resource) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.Addr())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
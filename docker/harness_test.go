@@ -0,0 +1,77 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package docker
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisReady(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	responses := []string{"-LOADING Redis is loading the dataset in memory\r\n", "+PONG\r\n"}
+	go func() {
+		for i := range responses {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadString('\n')
+			conn.Write([]byte(responses[i]))
+			conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	r := &Resource{Host: host, Port: port}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+
+	require.ErrorContains(t, redisReady(ctx, r), "LOADING")
+	require.NoError(t, redisReady(ctx, r))
+}
+
+func TestMySQL(t *testing.T) {
+	t.Parallel()
+
+	first, second := MySQL(t), MySQL(t)
+	require.NotEqual(t, first.Name, second.Name)
+	require.Equal(t, first.Addr(), second.Addr())
+
+	db, err := sql.Open("mysql", first.DSN)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("create table transfers (id varchar(40) primary key)")
+	require.NoError(t, err)
+}
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	db := Postgres(t)
+	out, err := db.Exec("psql", "-U", "moov", "-d", db.Name, "-c", "select 1")
+	require.NoError(t, err)
+	require.Contains(t, out, "1 row")
+}
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+
+	r := Redis(t)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	require.NoError(t, redisReady(ctx, r))
+}
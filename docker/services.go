@@ -0,0 +1,173 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package docker
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/moov-io/base"
+)
+
+// Database is a database created for one test inside a shared container
+type Database struct {
+	*Resource
+
+	// Name is the random name of the database
+	Name string
+
+	// DSN connects to the database as the test user
+	DSN string
+}
+
+// MySQL returns a new database in a shared MySQL container. The database is dropped when t
+// finishes, so tests using it can run in parallel.
+func MySQL(t testing.TB) *Database {
+	t.Helper()
+
+	r := Start(t, Container{
+		Name:       "moov-mysql-test-container",
+		Repository: "moov/mysql-volumeless",
+		Tag:        "8.0",
+		Env: []string{
+			"MYSQL_USER=moov",
+			"MYSQL_PASSWORD=secret",
+			"MYSQL_ROOT_PASSWORD=secret",
+		},
+		Port: "3306/tcp",
+		Ready: func(ctx context.Context, r *Resource) error {
+			db, err := sql.Open("mysql", fmt.Sprintf("moov:secret@tcp(%s)/", r.Addr()))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			return db.PingContext(ctx)
+		},
+	})
+
+	root, err := sql.Open("mysql", fmt.Sprintf("root:secret@tcp(%s)/", r.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	name := "test" + base.ID()
+	if _, err := root.Exec(fmt.Sprintf("create database %s", name)); err != nil {
+		t.Fatalf("docker: creating database: %v", err)
+	}
+	if _, err := root.Exec(fmt.Sprintf("grant all on %s.* to 'moov'@'%%'", name)); err != nil {
+		t.Fatalf("docker: granting database: %v", err)
+	}
+	t.Cleanup(func() {
+		root, err := sql.Open("mysql", fmt.Sprintf("root:secret@tcp(%s)/", r.Addr()))
+		if err != nil {
+			return
+		}
+		defer root.Close()
+		root.Exec(fmt.Sprintf("drop database %s", name))
+	})
+
+	return &Database{
+		Resource: r,
+		Name:     name,
+		DSN:      fmt.Sprintf("moov:secret@tcp(%s)/%s?parseTime=true", r.Addr(), name),
+	}
+}
+
+// Postgres returns a new database in a shared Postgres container. The database is dropped when
+// t finishes, so tests using it can run in parallel.
+func Postgres(t testing.TB) *Database {
+	t.Helper()
+
+	r := Start(t, Container{
+		Name:       "moov-postgres-test-container",
+		Repository: "postgres",
+		Tag:        "14-alpine",
+		Env: []string{
+			"POSTGRES_USER=moov",
+			"POSTGRES_PASSWORD=secret",
+		},
+		Port: "5432/tcp",
+		Ready: func(ctx context.Context, r *Resource) error {
+			// the server restarts once after running its init scripts, so wait for the host port too
+			if _, err := r.Exec("pg_isready", "-U", "moov", "-h", "127.0.0.1"); err != nil {
+				return err
+			}
+			return dialReady(ctx, r)
+		},
+	})
+
+	name := "test" + base.ID()
+	if _, err := r.Exec("psql", "-U", "moov", "-c", fmt.Sprintf("create database %s", name)); err != nil {
+		t.Fatalf("docker: creating database: %v", err)
+	}
+	t.Cleanup(func() {
+		r.Exec("psql", "-U", "moov", "-c", fmt.Sprintf("drop database if exists %s with (force)", name))
+	})
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword("moov", "secret"),
+		Host:     r.Addr(),
+		Path:     "/" + name,
+		RawQuery: "sslmode=disable",
+	}
+	return &Database{
+		Resource: r,
+		Name:     name,
+		DSN:      dsn.String(),
+	}
+}
+
+// Redis starts a redis:7-alpine container for t, which is removed when t finishes.
+func Redis(t testing.TB) *Resource {
+	t.Helper()
+	return RedisCompatible(t, "redis", "7-alpine")
+}
+
+// RedisCompatible starts a container of a Redis compatible image, such as eqalpha/keydb,
+// for t. It's removed when t finishes.
+func RedisCompatible(t testing.TB, repository, tag string) *Resource {
+	t.Helper()
+	return Start(t, Container{
+		Repository: repository,
+		Tag:        tag,
+		Port:       "6379/tcp",
+		Ready:      redisReady,
+	})
+}
+
+// redisReady sends PING and expects PONG, as the server refuses commands while loading
+func redisReady(ctx context.Context, r *Resource) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.Addr())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "+PONG") {
+		return fmt.Errorf("unexpected PING response: %q", strings.TrimSpace(line))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package document links stored files, such as a signed authorization or a recorded phone call,
+// to the records they support. A Reference holds the SHA-256 hash and size of the file so it
+// can be verified later against what storage returns.
+//
+//	ref, err := document.NewReference(document.Authorization, bytes.NewReader(pdf))
+//	// store pdf under ref.ID and save ref alongside the transfer
+//
+//	err = ref.VerifyStored(ctx, storage)
+package document
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moov-io/base"
+)
+
+// Type is what a document is
+type Type string
+
+const (
+	// Authorization is evidence a Receiver authorized entries, such as a signed form
+	Authorization Type = "authorization"
+
+	// Recording is an audio recording, such as an oral TEL authorization
+	Recording Type = "recording"
+
+	// Statement is an account or settlement statement
+	Statement Type = "statement"
+)
+
+// Reference identifies the contents of a stored document
+type Reference struct {
+	ID        string    `json:"id"`
+	Type      Type      `json:"type"`
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	CreatedAt base.Time `json:"createdAt"`
+}
+
+// NewReference reads r to return a Reference of typ with a new ID
+func NewReference(typ Type, r io.Reader) (Reference, error) {
+	if typ == "" {
+		return Reference{}, errors.New("document: missing type")
+	}
+	sum, size, err := hash(r)
+	if err != nil {
+		return Reference{}, err
+	}
+	return Reference{
+		ID:        base.ID(),
+		Type:      typ,
+		SHA256:    sum,
+		Size:      size,
+		CreatedAt: base.Now(),
+	}, nil
+}
+
+func hash(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, fmt.Errorf("document: reading contents: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// Validate checks each field of the Reference is set and the hash is 64 lowercase hex characters
+func (ref Reference) Validate() error {
+	switch {
+	case ref.ID == "":
+		return errors.New("document: missing id")
+	case ref.Type == "":
+		return errors.New("document: missing type")
+	case ref.Size < 0:
+		return errors.New("document: negative size")
+	case ref.CreatedAt.IsZero():
+		return errors.New("document: missing createdAt")
+	}
+	if len(ref.SHA256) != sha256.Size*2 {
+		return fmt.Errorf("document: invalid sha256 %q", ref.SHA256)
+	}
+	for _, c := range ref.SHA256 {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Errorf("document: invalid sha256 %q", ref.SHA256)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON reads a Reference and validates it
+func (ref *Reference) UnmarshalJSON(data []byte) error {
+	type reference Reference
+	var out reference
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	if err := Reference(out).Validate(); err != nil {
+		return err
+	}
+	*ref = Reference(out)
+	return nil
+}
+
+// MismatchError is returned when contents don't match a Reference
+type MismatchError struct {
+	ID string
+
+	WantSHA256, GotSHA256 string
+	WantSize, GotSize     int64
+}
+
+func (e *MismatchError) Error() string {
+	if e.WantSize != e.GotSize {
+		return fmt.Sprintf("document %s is %d bytes, expected %d", e.ID, e.GotSize, e.WantSize)
+	}
+	return fmt.Sprintf("document %s has sha256 %s, expected %s", e.ID, e.GotSHA256, e.WantSHA256)
+}
+
+// Verify reads r and returns a *MismatchError when its size or hash differ from ref
+func (ref Reference) Verify(r io.Reader) error {
+	sum, size, err := hash(r)
+	if err != nil {
+		return err
+	}
+	if sum != ref.SHA256 || size != ref.Size {
+		return &MismatchError{
+			ID:         ref.ID,
+			WantSHA256: ref.SHA256,
+			GotSHA256:  sum,
+			WantSize:   ref.Size,
+			GotSize:    size,
+		}
+	}
+	return nil
+}
+
+// Storage reads stored documents by their ID
+type Storage interface {
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+}
+
+// VerifyStored reads the document of ref from storage and verifies it
+func (ref Reference) VerifyStored(ctx context.Context, storage Storage) error {
+	rc, err := storage.Open(ctx, ref.ID)
+	if err != nil {
+		return fmt.Errorf("document: opening %s: %w", ref.ID, err)
+	}
+	defer rc.Close()
+	return ref.Verify(rc)
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package document
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/testtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	bs, ok := s[id]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(bs)), nil
+}
+
+func TestReference(t *testing.T) {
+	testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	ref, err := NewReference(Authorization, strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.NoError(t, ref.Validate())
+	require.Len(t, ref.ID, 40)
+	require.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", ref.SHA256)
+	require.Equal(t, int64(5), ref.Size)
+
+	bs, err := json.Marshal(ref)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "`+ref.ID+`",
+		"type": "authorization",
+		"sha256": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"size": 5,
+		"createdAt": "2021-03-04T12:00:00Z"
+	}`, string(bs))
+
+	var out Reference
+	require.NoError(t, json.Unmarshal(bs, &out))
+	require.Equal(t, ref.SHA256, out.SHA256)
+	require.True(t, ref.CreatedAt.Equal(out.CreatedAt))
+
+	require.Error(t, json.Unmarshal([]byte(`{"id":"a","type":"authorization","sha256":"ABC","size":5,"createdAt":"2021-03-04T12:00:00Z"}`), &out))
+	require.Error(t, json.Unmarshal([]byte(`{"type":"authorization"}`), &out))
+
+	_, err = NewReference("", strings.NewReader("hello"))
+	require.Error(t, err)
+}
+
+func TestReference__Verify(t *testing.T) {
+	ref, err := NewReference(Recording, strings.NewReader("hello"))
+	require.NoError(t, err)
+
+	require.NoError(t, ref.Verify(strings.NewReader("hello")))
+
+	err = ref.Verify(strings.NewReader("jello"))
+	var mismatch *MismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Contains(t, err.Error(), "has sha256 ")
+
+	err = ref.Verify(strings.NewReader("hello!"))
+	require.EqualError(t, err, "document "+ref.ID+" is 6 bytes, expected 5")
+
+	storage := memoryStorage{ref.ID: []byte("hello")}
+	require.NoError(t, ref.VerifyStored(context.Background(), storage))
+
+	storage[ref.ID] = []byte("tampered")
+	require.True(t, errors.As(ref.VerifyStored(context.Background(), storage), &mismatch))
+
+	delete(storage, ref.ID)
+	require.ErrorIs(t, ref.VerifyStored(context.Background(), storage), os.ErrNotExist)
+}
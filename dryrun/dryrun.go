@@ -0,0 +1,135 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package dryrun marks a context as a rehearsal so side effects (database writes, file uploads,
+// webhooks) are logged instead of performed.
+//
+//	ctx = dryrun.WithDryRun(ctx)
+//
+//	err := dryrun.Do(ctx, logger, "upload file", log.Fields{
+//		"file": log.String(name),
+//	}, func(ctx context.Context) error {
+//		return agent.Upload(ctx, name, contents)
+//	})
+//
+// Reads should still happen so the rehearsal finds the same data a real run would.
+package dryrun
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/moov-io/base/ctxkeys"
+	"github.com/moov-io/base/log"
+)
+
+var dryRunKey = ctxkeys.New[bool]("dry-run")
+
+// WithDryRun returns a context which is a dry run
+func WithDryRun(ctx context.Context) context.Context {
+	return dryRunKey.Set(ctx, true)
+}
+
+// IsDryRun reports whether ctx is a dry run
+func IsDryRun(ctx context.Context) bool {
+	return dryRunKey.Value(ctx)
+}
+
+// Do calls fn unless ctx is a dry run, when action and fields are logged instead and nil is
+// returned.
+func Do(ctx context.Context, logger log.Logger, action string, fields log.Fields, fn func(ctx context.Context) error) error {
+	_, err := Run(ctx, logger, action, fields, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Run is Do for operations returning a value. The zero value of T is returned in a dry run.
+func Run[T any](ctx context.Context, logger log.Logger, action string, fields log.Fields, fn func(ctx context.Context) (T, error)) (T, error) {
+	if !IsDryRun(ctx) {
+		return fn(ctx)
+	}
+	logged := log.Fields{
+		"dry_run": log.Bool(true),
+	}
+	for k, v := range fields {
+		logged[k] = v
+	}
+	logger.Info().With(logged).Logf("dry run: skipped %s", action)
+
+	var zero T
+	return zero, nil
+}
+
+// Execer is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Exec runs query on db unless ctx is a dry run, when the query is logged and a result with
+// no affected rows is returned.
+func Exec(ctx context.Context, logger log.Logger, db Execer, query string, args ...interface{}) (sql.Result, error) {
+	result, err := Run(ctx, logger, "database write", log.Fields{
+		"query": log.String(strings.Join(strings.Fields(query), " ")),
+	}, func(ctx context.Context) (sql.Result, error) {
+		return db.ExecContext(ctx, query, args...)
+	})
+	if result == nil && err == nil {
+		result = noResult{}
+	}
+	return result, err
+}
+
+type noResult struct{}
+
+func (noResult) LastInsertId() (int64, error) { return 0, nil }
+func (noResult) RowsAffected() (int64, error) { return 0, nil }
+
+// Transport wraps next so requests which change state (anything but GET, HEAD and OPTIONS)
+// made with a dry run context are logged and answered with 202 Accepted instead of being sent.
+// next defaults to http.DefaultTransport.
+func Transport(logger log.Logger, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{logger: logger, next: next}
+}
+
+type transport struct {
+	logger log.Logger
+	next   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+	if !IsDryRun(req.Context()) {
+		return t.next.RoundTrip(req)
+	}
+
+	t.logger.Info().With(log.Fields{
+		"dry_run": log.Bool(true),
+		"method":  log.String(req.Method),
+		"url":     log.String(req.URL.Redacted()),
+	}).Log("dry run: skipped HTTP request")
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		Status:     "202 Accepted",
+		StatusCode: http.StatusAccepted,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"X-Dry-Run": []string{"true"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dryrun
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base/log"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	require.False(t, IsDryRun(ctx))
+	require.True(t, IsDryRun(WithDryRun(ctx)))
+}
+
+func TestDo(t *testing.T) {
+	buf, logger := log.NewBufferLogger()
+
+	called := false
+	upload := func(ctx context.Context) error {
+		called = true
+		return nil
+	}
+	fields := log.Fields{"file": log.String("20211101.ach")}
+
+	require.NoError(t, Do(context.Background(), logger, "upload file", fields, upload))
+	require.True(t, called)
+	require.Empty(t, buf.String())
+
+	called = false
+	require.NoError(t, Do(WithDryRun(context.Background()), logger, "upload file", fields, upload))
+	require.False(t, called)
+	require.Contains(t, buf.String(), "dry run: skipped upload file")
+	require.Contains(t, buf.String(), "20211101.ach")
+}
+
+func TestRun(t *testing.T) {
+	logger := log.NewNopLogger()
+	fn := func(ctx context.Context) (int, error) { return 7, nil }
+
+	n, err := Run(context.Background(), logger, "count", nil, fn)
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+
+	n, err = Run(WithDryRun(context.Background()), logger, "count", nil, fn)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestExec(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err = db.Exec("create table transfers (id text)")
+	require.NoError(t, err)
+
+	buf, logger := log.NewBufferLogger()
+	result, err := Exec(WithDryRun(ctx), logger, db, "insert into transfers\n  (id) values (?)", "a")
+	require.NoError(t, err)
+	rows, _ := result.RowsAffected()
+	require.Equal(t, int64(0), rows)
+	require.Contains(t, buf.String(), "insert into transfers (id) values (?)")
+
+	result, err = Exec(ctx, logger, db, "insert into transfers (id) values (?)", "b")
+	require.NoError(t, err)
+	rows, _ = result.RowsAffected()
+	require.Equal(t, int64(1), rows)
+
+	var count int
+	require.NoError(t, db.QueryRow("select count(*) from transfers").Scan(&count))
+	require.Equal(t, 1, count)
+}
+
+func TestTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method)
+	}))
+	defer server.Close()
+
+	buf, logger := log.NewBufferLogger()
+	client := &http.Client{Transport: Transport(logger, nil)}
+	ctx := WithDryRun(context.Background())
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", server.URL+"/webhooks", strings.NewReader(`{}`))
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, "true", resp.Header.Get("X-Dry-Run"))
+	require.Contains(t, buf.String(), "/webhooks")
+
+	// reads are still sent
+	req, _ = http.NewRequestWithContext(ctx, "GET", server.URL+"/transfers", nil)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	req, _ = http.NewRequest("POST", server.URL+"/webhooks", strings.NewReader(`{}`))
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, []string{"GET", "POST"}, received)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package enum implements parsing, validation and text (JSON) marshaling for enumerated types.
+// The methods are usually generated with cmd/genenum:
+//
+//	//go:generate go run github.com/moov-io/base/cmd/genenum -type Status
+//	type Status string
+//
+//	const (
+//		StatusPending  Status = "pending"
+//		StatusApproved Status = "approved"
+//		StatusReturned Status = "returned"
+//	)
+//
+// Status then has String, IsValid, MarshalText and UnmarshalText methods along with
+// ParseStatus and StatusValues functions backed by an enum.Set.
+package enum
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Value is implemented by enum types. Their String form is used for parsing and marshaling.
+type Value interface {
+	comparable
+	fmt.Stringer
+}
+
+// InvalidError is returned for strings which aren't one of an enum's values
+type InvalidError struct {
+	Type  string
+	Value string
+	Valid []string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid %s %q, must be one of %s", e.Type, e.Value, strings.Join(e.Valid, ", "))
+}
+
+// Set holds the values of an enum type
+type Set[T Value] struct {
+	name   string
+	values []T
+	lookup map[string]T
+}
+
+// New returns a Set of values in the order given
+func New[T Value](values ...T) *Set[T] {
+	var zero T
+	s := &Set[T]{
+		name:   reflect.TypeOf(zero).Name(),
+		values: values,
+		lookup: make(map[string]T, len(values)),
+	}
+	for _, v := range values {
+		s.lookup[strings.ToLower(v.String())] = v
+	}
+	return s
+}
+
+// Values returns each value in declaration order
+func (s *Set[T]) Values() []T {
+	out := make([]T, len(s.values))
+	copy(out, s.values)
+	return out
+}
+
+// IsValid returns true if v is one of the values
+func (s *Set[T]) IsValid(v T) bool {
+	for i := range s.values {
+		if s.values[i] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse returns the value whose String matches str, ignoring case and surrounding whitespace.
+func (s *Set[T]) Parse(str string) (T, error) {
+	if v, exists := s.lookup[strings.ToLower(strings.TrimSpace(str))]; exists {
+		return v, nil
+	}
+	var zero T
+	return zero, s.invalid(str)
+}
+
+// MarshalText returns the String of v, or an error for invalid values. The zero value is
+// encoded as "" when it isn't one of the values, so structs with an unset enum can be marshaled.
+func (s *Set[T]) MarshalText(v T) ([]byte, error) {
+	var zero T
+	if v == zero && !s.IsValid(v) {
+		return []byte{}, nil
+	}
+	if !s.IsValid(v) {
+		return nil, s.invalid(v.String())
+	}
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses data into v. Empty data is read as the zero value when it isn't one of
+// the values, matching MarshalText.
+func (s *Set[T]) UnmarshalText(data []byte, v *T) error {
+	var zero T
+	if len(data) == 0 && !s.IsValid(zero) {
+		*v = zero
+		return nil
+	}
+	parsed, err := s.Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+func (s *Set[T]) invalid(str string) error {
+	valid := make([]string, len(s.values))
+	for i := range s.values {
+		valid[i] = s.values[i].String()
+	}
+	return &InvalidError{Type: s.name, Value: str, Valid: valid}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type status string
+
+const (
+	statusPending  status = "pending"
+	statusApproved status = "approved"
+	statusReturned status = "returned"
+)
+
+var statuses = New(statusPending, statusApproved, statusReturned)
+
+func (s status) String() string                { return string(s) }
+func (s status) MarshalText() ([]byte, error)  { return statuses.MarshalText(s) }
+func (s *status) UnmarshalText(b []byte) error { return statuses.UnmarshalText(b, s) }
+
+type speed int
+
+const (
+	speedStandard speed = iota
+	speedSameDay
+)
+
+func (s speed) String() string {
+	return [...]string{"standard", "same-day"}[s]
+}
+
+func TestSet(t *testing.T) {
+	require.Equal(t, []status{statusPending, statusApproved, statusReturned}, statuses.Values())
+	require.True(t, statuses.IsValid(statusApproved))
+	require.False(t, statuses.IsValid(status("voided")))
+
+	s, err := statuses.Parse(" Approved ")
+	require.NoError(t, err)
+	require.Equal(t, statusApproved, s)
+
+	_, err = statuses.Parse("voided")
+	var invalid *InvalidError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, `invalid status "voided", must be one of pending, approved, returned`, err.Error())
+
+	speeds := New(speedStandard, speedSameDay)
+	sp, err := speeds.Parse("SAME-DAY")
+	require.NoError(t, err)
+	require.Equal(t, speedSameDay, sp)
+}
+
+func TestSet__JSON(t *testing.T) {
+	type transfer struct {
+		Status status `json:"status"`
+	}
+	var xfer transfer
+	require.NoError(t, json.Unmarshal([]byte(`{"status":"RETURNED"}`), &xfer))
+	require.Equal(t, statusReturned, xfer.Status)
+
+	bs, err := json.Marshal(xfer)
+	require.NoError(t, err)
+	require.Equal(t, `{"status":"returned"}`, string(bs))
+
+	require.Error(t, json.Unmarshal([]byte(`{"status":"voided"}`), &xfer))
+	_, err = json.Marshal(transfer{Status: "voided"})
+	require.Error(t, err)
+
+	bs, err = json.Marshal(transfer{})
+	require.NoError(t, err)
+	require.Equal(t, `{"status":""}`, string(bs))
+	require.NoError(t, json.Unmarshal(bs, &xfer))
+	require.Equal(t, status(""), xfer.Status)
+
+	// speedStandard is the zero value, so it's marshaled by name and "" isn't accepted
+	speeds := New(speedStandard, speedSameDay)
+	bs, err = speeds.MarshalText(speedStandard)
+	require.NoError(t, err)
+	require.Equal(t, "standard", string(bs))
+	var sp speed
+	require.Error(t, speeds.UnmarshalText(nil, &sp))
+}
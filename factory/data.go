@@ -0,0 +1,42 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package factory
+
+var firstNames = []string{
+	"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth",
+	"William", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Carlos", "Maria", "Wei", "Mei",
+	"Ahmed", "Fatima", "Hiroshi", "Yuki", "Kwame", "Amara", "Raj", "Priya", "Olga", "Ivan",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+	"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+	"Lee", "Nguyen", "Kim", "Patel", "Chen", "Okafor", "Tanaka", "Ivanova", "Haddad", "Cohen",
+}
+
+var streets = []string{
+	"Main St", "Oak Ave", "Maple Dr", "Cedar Ln", "Park Blvd", "Pine St", "Elm St", "Washington Ave",
+	"Lakeview Rd", "Hillcrest Dr", "Sunset Blvd", "River Rd", "Church St", "Highland Ave", "Mill Rd",
+}
+
+// cities holds the first postal code of each city's range
+var cities = []struct {
+	name  string
+	state string
+	zip   int
+}{
+	{"New York", "NY", 10001},
+	{"Los Angeles", "CA", 90001},
+	{"Chicago", "IL", 60601},
+	{"Houston", "TX", 77001},
+	{"Phoenix", "AZ", 85001},
+	{"Philadelphia", "PA", 19102},
+	{"Denver", "CO", 80202},
+	{"Seattle", "WA", 98101},
+	{"Atlanta", "GA", 30303},
+	{"Des Moines", "IA", 50309},
+	{"Boston", "MA", 2108},
+	{"Miami", "FL", 33101},
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package factory generates realistic but fake customers, bank accounts, cards, amounts and
+// times for tests. Values are read from randx, so seeding it with randxtest.Seed makes a test
+// generate the same values on every run.
+//
+//	func TestTransfer(t *testing.T) {
+//		randxtest.Seed(t, 7)
+//
+//		customer := factory.NewCustomer()
+//		account := factory.NewBankAccount()
+//		account.HolderName = customer.FirstName + " " + customer.LastName
+//		amount := factory.Amount(100, 500000, "USD") // $1.00 to $5,000.00
+//		...
+//	}
+//
+// Unlike proptest, which looks for edge cases, these values look like production data.
+package factory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/proptest"
+	"github.com/moov-io/base/randx"
+)
+
+// Customer is a fake person
+type Customer struct {
+	ID        string
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	BirthDate base.Date
+	Address   Address
+}
+
+// Address is a fake US mailing address
+type Address struct {
+	Line1      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// NewCustomer returns a fake adult customer with a unique ID and email. IDs look like base.ID but
+// are read from randx so seeded tests repeat them.
+func NewCustomer() Customer {
+	first, last := pick(firstNames), pick(lastNames)
+	id := randx.String(40, "0123456789abcdef")
+	city := pick(cities)
+	return Customer{
+		ID:        id,
+		FirstName: first,
+		LastName:  last,
+		Email:     fmt.Sprintf("%s.%s.%s@example.com", strings.ToLower(first), strings.ToLower(last), id[:6]),
+		Phone:     fmt.Sprintf("+1%d555%04d", 200+randx.Intn(800), randx.Intn(10000)),
+		BirthDate: base.NewDate(1940+randx.Intn(60), time.Month(1+randx.Intn(12)), 1+randx.Intn(28)),
+		Address: Address{
+			Line1:      fmt.Sprintf("%d %s", 1+randx.Intn(9999), pick(streets)),
+			City:       city.name,
+			State:      city.state,
+			PostalCode: fmt.Sprintf("%05d", city.zip+randx.Intn(100)),
+			Country:    "US",
+		},
+	}
+}
+
+// AccountType is the kind of a BankAccount
+type AccountType string
+
+const (
+	Checking AccountType = "checking"
+	Savings  AccountType = "savings"
+)
+
+// BankAccount is a fake US bank account
+type BankAccount struct {
+	HolderName    string
+	RoutingNumber string
+	AccountNumber string
+	Type          AccountType
+}
+
+// NewBankAccount returns a fake account with a valid routing number
+func NewBankAccount() BankAccount {
+	acct := BankAccount{
+		HolderName:    pick(firstNames) + " " + pick(lastNames),
+		RoutingNumber: RoutingNumber(),
+		AccountNumber: digits(8 + randx.Intn(5)),
+		Type:          Checking,
+	}
+	if randx.Intn(4) == 0 {
+		acct.Type = Savings
+	}
+	return acct
+}
+
+// RoutingNumber returns a nine digit ABA routing number with a Federal Reserve prefix and a
+// valid check digit
+func RoutingNumber() string {
+	prefix := fmt.Sprintf("%02d", 1+randx.Intn(12))
+	number := prefix + digits(6)
+	return number + fmt.Sprint(proptest.CheckDigit(number))
+}
+
+// Card is a fake payment card
+type Card struct {
+	Brand    string
+	Number   string
+	ExpMonth time.Month
+	ExpYear  int
+	CVV      string
+}
+
+var cardBrands = []struct {
+	name     string
+	prefixes []string
+	length   int
+}{
+	{"visa", []string{"4"}, 16},
+	{"mastercard", []string{"51", "52", "53", "54", "55"}, 16},
+	{"amex", []string{"34", "37"}, 15},
+	{"discover", []string{"6011"}, 16},
+}
+
+// NewCard returns a fake card with a Luhn valid number expiring in the next few years
+func NewCard() Card {
+	brand := cardBrands[randx.Intn(len(cardBrands))]
+	prefix := brand.prefixes[randx.Intn(len(brand.prefixes))]
+	number := prefix + digits(brand.length-len(prefix)-1)
+	number += fmt.Sprint(LuhnCheckDigit(number))
+
+	cvv := 3
+	if brand.name == "amex" {
+		cvv = 4
+	}
+	return Card{
+		Brand:    brand.name,
+		Number:   number,
+		ExpMonth: time.Month(1 + randx.Intn(12)),
+		ExpYear:  base.Clock.Now().Year() + 1 + randx.Intn(5),
+		CVV:      digits(cvv),
+	}
+}
+
+// LuhnCheckDigit returns the digit which makes number followed by it pass the Luhn check
+func LuhnCheckDigit(number string) int {
+	sum := 0
+	double := true
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// Amount returns an amount of currency from min to max minor units, inclusive
+func Amount(min, max int64, currency string) base.Amount {
+	if max < min {
+		min, max = max, min
+	}
+	return base.NewAmount(min+randx.Int63n(max-min+1), currency)
+}
+
+// BankingDay returns a banking day from after through within calendar days after it, or the
+// next banking day when there are none
+func BankingDay(after base.Date, within int) base.Date {
+	var days []base.Date
+	for i := 1; i <= within; i++ {
+		if d := after.AddDays(i); d.IsBankingDay() {
+			days = append(days, d)
+		}
+	}
+	if len(days) == 0 {
+		return after.AddBankingDays(1)
+	}
+	return pick(days)
+}
+
+// BankingTime returns a time during business hours, 9am to 5pm Eastern, on a banking day
+// from after through within calendar days after it
+func BankingTime(after base.Date, within int) base.Time {
+	day := BankingDay(after, within)
+	at := day.In(eastern).Add(9*time.Hour + time.Duration(randx.Int63n(int64(8*time.Hour/time.Second)))*time.Second)
+	return base.NewTime(at)
+}
+
+var eastern = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(fmt.Sprintf("factory: %v", err))
+	}
+	return loc
+}()
+
+func digits(n int) string {
+	return randx.String(n, "0123456789")
+}
+
+func pick[T any](options []T) T {
+	return options[randx.Intn(len(options))]
+}
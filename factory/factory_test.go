@@ -0,0 +1,94 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/proptest"
+	"github.com/moov-io/base/randx/randxtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func luhnValid(number string) bool {
+	return LuhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+}
+
+func TestSeeded(t *testing.T) {
+	randxtest.Seed(t, 7)
+	first := []interface{}{NewCustomer(), NewBankAccount(), NewCard()}
+
+	randxtest.Seed(t, 7)
+	second := []interface{}{NewCustomer(), NewBankAccount(), NewCard()}
+
+	require.Equal(t, first, second)
+}
+
+func TestNewCustomer(t *testing.T) {
+	a, b := NewCustomer(), NewCustomer()
+	require.NotEqual(t, a.ID, b.ID)
+	require.NotEqual(t, a.Email, b.Email)
+	require.Regexp(t, regexp.MustCompile(`^[a-z]+\.[a-z]+\.[0-9a-f]{6}@example\.com$`), a.Email)
+	require.Regexp(t, regexp.MustCompile(`^\+1[2-9]\d{2}555\d{4}$`), a.Phone)
+	require.Regexp(t, regexp.MustCompile(`^\d{5}$`), a.Address.PostalCode)
+	require.True(t, a.BirthDate.Year >= 1940 && a.BirthDate.Year < 2000)
+}
+
+func TestNewBankAccount(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		acct := NewBankAccount()
+		require.Len(t, acct.RoutingNumber, 9)
+		require.Equal(t, proptest.CheckDigit(acct.RoutingNumber), int(acct.RoutingNumber[8]-'0'))
+		require.Regexp(t, regexp.MustCompile(`^\d{8,12}$`), acct.AccountNumber)
+		require.Contains(t, []AccountType{Checking, Savings}, acct.Type)
+	}
+}
+
+func TestNewCard(t *testing.T) {
+	require.Equal(t, 3, LuhnCheckDigit("7992739871")) // 79927398713
+	for i := 0; i < 100; i++ {
+		card := NewCard()
+		require.True(t, luhnValid(card.Number), card.Number)
+		if card.Brand == "amex" {
+			require.Len(t, card.Number, 15)
+			require.Len(t, card.CVV, 4)
+		} else {
+			require.Len(t, card.Number, 16)
+			require.Len(t, card.CVV, 3)
+		}
+		require.Greater(t, card.ExpYear, base.Clock.Now().Year())
+	}
+}
+
+func TestAmount(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a := Amount(100, 200, "usd")
+		require.Equal(t, "USD", a.Currency)
+		require.True(t, a.Value >= 100 && a.Value <= 200)
+	}
+	require.Equal(t, base.NewAmount(5, "USD"), Amount(5, 5, "USD"))
+}
+
+func TestBankingTime(t *testing.T) {
+	friday := base.NewDate(2021, time.December, 31) // New Year's Eve, a banking day
+	for i := 0; i < 100; i++ {
+		day := BankingDay(friday, 10)
+		require.True(t, day.IsBankingDay(), day.String())
+		require.True(t, day.After(friday))
+		require.True(t, day.DaysSince(friday) <= 10)
+
+		at := BankingTime(friday, 10)
+		require.True(t, at.IsBankingDay())
+		hour := at.In(eastern).Hour()
+		require.True(t, hour >= 9 && hour < 17, at.String())
+	}
+
+	// no banking days within two days of a Friday
+	require.Equal(t, base.NewDate(2021, time.March, 8), BankingDay(base.NewDate(2021, time.March, 5), 2))
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package faultinject adds latency, errors and partial writes at named points in production
+// code for game days which test retries, timeouts and circuit breakers. Points are no-ops,
+// costing one atomic load, until faults are enabled with Configure.
+//
+//	func (c *Client) Upload(ctx context.Context, name string, r io.Reader) error {
+//		if err := faultinject.Inject(ctx, "sftp.upload"); err != nil {
+//			return err
+//		}
+//		w := faultinject.Writer(ctx, "sftp.upload", remote)
+//		...
+//	}
+//
+//	faultinject.Configure(faultinject.Config{
+//		Enabled: true,
+//		Faults: []faultinject.Fault{
+//			{Point: "sftp.upload", Probability: 0.2, Latency: 3 * time.Second},
+//		},
+//	})
+//
+// Faults can also be added to one request's context with WithFaults, which only has an effect
+// while faults are enabled.
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/moov-io/base/ctxkeys"
+	"github.com/moov-io/base/randx"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	injectedFaults = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "faultinject_injected_total",
+		Help: "Counter of faults injected at each point",
+	}, []string{"point", "fault"})
+)
+
+// ErrInjected is wrapped by every error returned from Inject
+var ErrInjected = errors.New("injected fault")
+
+// Fault is injected at Point. Faults with "*" as their Point apply to every point.
+type Fault struct {
+	Point string
+
+	// Probability is the chance the fault applies on each call, from 0 to 1. It defaults to 1.
+	Probability float64
+
+	// Latency is slept before returning
+	Latency time.Duration
+
+	// Error is returned from Inject when set
+	Error string
+
+	// PartialWrite is how many bytes the Writer accepts before failing with io.ErrShortWrite
+	PartialWrite int
+}
+
+func (f Fault) validate() error {
+	if f.Point == "" {
+		return errors.New("faultinject: fault without a point")
+	}
+	if f.Probability < 0 || f.Probability > 1 {
+		return fmt.Errorf("faultinject: %s probability %v must be between 0 and 1", f.Point, f.Probability)
+	}
+	if f.Latency < 0 || f.PartialWrite < 0 {
+		return fmt.Errorf("faultinject: %s latency and partial write can't be negative", f.Point)
+	}
+	return nil
+}
+
+func (f Fault) matches(point string) bool {
+	return f.Point == "*" || f.Point == point
+}
+
+func (f Fault) triggered() bool {
+	if f.Probability == 0 || f.Probability >= 1 {
+		return true
+	}
+	return randx.Int63n(1_000_000) < int64(f.Probability*1_000_000)
+}
+
+// Config is the set of faults to inject
+type Config struct {
+	Enabled bool
+	Faults  []Fault
+}
+
+var (
+	enabled int32
+
+	mu     sync.RWMutex
+	faults []Fault
+)
+
+var contextFaults = ctxkeys.New[[]Fault]("faultinject")
+
+// Configure replaces the injected faults. Disabled configs turn every point back into a no-op.
+func Configure(cfg Config) error {
+	for i := range cfg.Faults {
+		if err := cfg.Faults[i].validate(); err != nil {
+			return err
+		}
+	}
+	mu.Lock()
+	faults = append([]Fault(nil), cfg.Faults...)
+	mu.Unlock()
+
+	if cfg.Enabled {
+		atomic.StoreInt32(&enabled, 1)
+	} else {
+		atomic.StoreInt32(&enabled, 0)
+	}
+	return nil
+}
+
+// Enabled reports whether faults are injected
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// WithFaults returns a copy of ctx which also injects faults. They're ignored unless faults
+// are enabled with Configure.
+func WithFaults(ctx context.Context, add ...Fault) (context.Context, error) {
+	for i := range add {
+		if err := add[i].validate(); err != nil {
+			return ctx, err
+		}
+	}
+	existing, _ := contextFaults.Get(ctx)
+	return contextFaults.Set(ctx, append(append([]Fault(nil), existing...), add...)), nil
+}
+
+// active returns the triggered faults of point, those of the context first
+func active(ctx context.Context, point string) []Fault {
+	var out []Fault
+	if fs, ok := contextFaults.Get(ctx); ok {
+		for _, f := range fs {
+			if f.matches(point) && f.triggered() {
+				out = append(out, f)
+			}
+		}
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, f := range faults {
+		if f.matches(point) && f.triggered() {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// InjectedError is returned from Inject for faults with an Error
+type InjectedError struct {
+	Point   string
+	Message string
+}
+
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("%s: %s (injected)", e.Point, e.Message)
+}
+
+func (e *InjectedError) Unwrap() error {
+	return ErrInjected
+}
+
+// Inject applies the faults of point, sleeping for their latency and returning the first
+// error. The sleep ends early with ctx.Err() when ctx is done.
+func Inject(ctx context.Context, point string) error {
+	if !Enabled() {
+		return nil
+	}
+	for _, f := range active(ctx, point) {
+		if f.Latency > 0 {
+			injectedFaults.With("point", point, "fault", "latency").Add(1)
+
+			timer := time.NewTimer(f.Latency)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if f.Error != "" {
+			injectedFaults.With("point", point, "fault", "error").Add(1)
+			return &InjectedError{Point: point, Message: f.Error}
+		}
+	}
+	return nil
+}
+
+// Writer returns w, or when a fault of point has a PartialWrite a writer which fails with
+// io.ErrShortWrite once that many bytes are written through it
+func Writer(ctx context.Context, point string, w io.Writer) io.Writer {
+	if !Enabled() {
+		return w
+	}
+	for _, f := range active(ctx, point) {
+		if f.PartialWrite > 0 {
+			injectedFaults.With("point", point, "fault", "partial_write").Add(1)
+			return &partialWriter{w: w, remaining: f.PartialWrite}
+		}
+	}
+	return w
+}
+
+type partialWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (p *partialWriter) Write(b []byte) (int, error) {
+	if len(b) <= p.remaining {
+		n, err := p.w.Write(b)
+		p.remaining -= n
+		return n, err
+	}
+	n, err := p.w.Write(b[:p.remaining])
+	p.remaining -= n
+	if err != nil {
+		return n, err
+	}
+	return n, io.ErrShortWrite
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package faultinject
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/randx/randxtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func configure(t *testing.T, cfg Config) {
+	t.Helper()
+	require.NoError(t, Configure(cfg))
+	t.Cleanup(func() {
+		Configure(Config{})
+	})
+}
+
+func TestInject(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, Inject(ctx, "sftp.upload"))
+
+	configure(t, Config{
+		Enabled: true,
+		Faults: []Fault{
+			{Point: "sftp.upload", Latency: 20 * time.Millisecond},
+			{Point: "sftp.upload", Error: "connection reset"},
+		},
+	})
+
+	start := time.Now()
+	err := Inject(ctx, "sftp.upload")
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	require.ErrorIs(t, err, ErrInjected)
+	require.EqualError(t, err, "sftp.upload: connection reset (injected)")
+
+	require.NoError(t, Inject(ctx, "sftp.list"))
+
+	// latency stops with the context
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, Inject(ctx, "sftp.upload"), context.DeadlineExceeded)
+
+	// disabling turns points back into no-ops
+	require.NoError(t, Configure(Config{Faults: []Fault{{Point: "*", Error: "boom"}}}))
+	require.False(t, Enabled())
+	require.NoError(t, Inject(context.Background(), "sftp.upload"))
+}
+
+func TestInject__Probability(t *testing.T) {
+	randxtest.Seed(t, 1)
+	configure(t, Config{
+		Enabled: true,
+		Faults:  []Fault{{Point: "*", Probability: 0.25, Error: "flaky"}},
+	})
+
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if Inject(context.Background(), "db.query") != nil {
+			failed++
+		}
+	}
+	require.InDelta(t, 250, failed, 50)
+}
+
+func TestWithFaults(t *testing.T) {
+	ctx, err := WithFaults(context.Background(), Fault{Point: "webhook.send", Error: "timeout"})
+	require.NoError(t, err)
+
+	// ignored until enabled
+	require.NoError(t, Inject(ctx, "webhook.send"))
+
+	configure(t, Config{Enabled: true})
+	require.ErrorIs(t, Inject(ctx, "webhook.send"), ErrInjected)
+	require.NoError(t, Inject(context.Background(), "webhook.send"))
+
+	ctx, err = WithFaults(ctx, Fault{Point: "db.query", Error: "deadlock"})
+	require.NoError(t, err)
+	require.ErrorContains(t, Inject(ctx, "db.query"), "deadlock")
+	require.ErrorContains(t, Inject(ctx, "webhook.send"), "timeout")
+
+	_, err = WithFaults(ctx, Fault{Point: "x", Probability: 2})
+	require.ErrorContains(t, err, "x probability 2 must be between 0 and 1")
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	require.Same(t, &buf, Writer(context.Background(), "file.write", &buf))
+
+	configure(t, Config{
+		Enabled: true,
+		Faults:  []Fault{{Point: "file.write", PartialWrite: 5}},
+	})
+
+	w := Writer(context.Background(), "file.write", &buf)
+	n, err := w.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = w.Write([]byte("defgh"))
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	require.Equal(t, 2, n)
+
+	_, err = io.WriteString(w, "more")
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	require.Equal(t, "abcde", buf.String())
+}
+
+func TestConfigure__Errors(t *testing.T) {
+	err := Configure(Config{Enabled: true, Faults: []Fault{{Error: "x"}}})
+	require.ErrorContains(t, err, "fault without a point")
+
+	err = Configure(Config{Enabled: true, Faults: []Fault{{Point: "a", Latency: -time.Second}}})
+	require.ErrorContains(t, err, "can't be negative")
+	require.False(t, Enabled())
+	require.False(t, errors.Is(err, ErrInjected))
+}
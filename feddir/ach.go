@@ -0,0 +1,86 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package feddir
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/moov-io/base"
+)
+
+// ACHLineWidth is the length of each FedACH directory record
+const ACHLineWidth = 155
+
+// ACHParticipant is a record of the FedACH participant directory
+type ACHParticipant struct {
+	RoutingNumber string `json:"routingNumber"`
+
+	// OfficeCode is "O" for a main office and "B" for a branch
+	OfficeCode string `json:"officeCode"`
+
+	// ServicingFRBNumber is the routing number of the Federal Reserve Bank serving the institution
+	ServicingFRBNumber string `json:"servicingFRBNumber"`
+
+	// RecordTypeCode is "0" when the institution is the Federal Reserve Bank, "1" when it
+	// receives entries at this routing number and "2" when they're sent to NewRoutingNumber
+	RecordTypeCode string `json:"recordTypeCode"`
+
+	ChangeDate       base.Date `json:"changeDate"`
+	NewRoutingNumber string    `json:"newRoutingNumber,omitempty"`
+
+	CustomerName string `json:"customerName"`
+	Address      string `json:"address"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	PostalCode   string `json:"postalCode"`
+	Phone        string `json:"phone"`
+
+	// StatusCode is "1" for a receiver
+	StatusCode string `json:"statusCode"`
+
+	// DataViewCode is "1" when the record hasn't changed
+	DataViewCode string `json:"dataViewCode"`
+}
+
+// ParseACH reads a FedACH directory. Short lines are padded, as some copies have their
+// trailing spaces removed.
+func ParseACH(r io.Reader) ([]ACHParticipant, error) {
+	var out []ACHParticipant
+	err := parseLines(r, ACHLineWidth, func(n int, line string) error {
+		var p ACHParticipant
+		var err error
+		if p.RoutingNumber, err = routingNumber(n, "routing number", field(line, 1, 9)); err != nil {
+			return err
+		}
+		p.OfficeCode = field(line, 10, 10)
+		p.ServicingFRBNumber = field(line, 11, 19)
+		p.RecordTypeCode = field(line, 20, 20)
+		if p.ChangeDate, err = date(n, "change date", "010206", field(line, 21, 26)); err != nil {
+			return err
+		}
+		if newRTN := field(line, 27, 35); newRTN != "" && newRTN != "000000000" {
+			if p.NewRoutingNumber, err = routingNumber(n, "new routing number", newRTN); err != nil {
+				return err
+			}
+		}
+		p.CustomerName = field(line, 36, 71)
+		p.Address = field(line, 72, 107)
+		p.City = field(line, 108, 127)
+		p.State = field(line, 128, 129)
+		p.PostalCode = field(line, 130, 134)
+		if ext := field(line, 135, 138); ext != "" && ext != "0000" {
+			p.PostalCode += "-" + ext
+		}
+		if phone := field(line, 139, 148); phone != "" {
+			p.Phone = fmt.Sprintf("%s-%s-%s", line[138:141], line[141:144], line[144:148])
+		}
+		p.StatusCode = field(line, 149, 149)
+		p.DataViewCode = field(line, 150, 150)
+		out = append(out, p)
+		return nil
+	})
+	return out, err
+}
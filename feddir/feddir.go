@@ -0,0 +1,132 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package feddir reads the Federal Reserve's FedACH and Fedwire participant directories, the
+// fixed-width files listing each routing number's institution, and indexes them by routing number.
+//
+//	f, err := os.Open("FedACHdir.txt")
+//	participants, err := feddir.ParseACH(f)
+//	dir := feddir.NewDirectory(participants, nil)
+//
+//	if p, ok := dir.ACH("231380104"); ok {
+//		fmt.Println(p.CustomerName)
+//	}
+//
+// The directories are licensed from the Federal Reserve Banks and aren't included.
+package feddir
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// ParseError describes a line of a directory which couldn't be read
+type ParseError struct {
+	Line  int
+	Field string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseLines calls fn with each non-empty line of r padded with spaces to width
+func parseLines(r io.Reader, width int, fn func(n int, line string) error) error {
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) > width {
+			return &ParseError{Line: n, Err: fmt.Errorf("is %d characters, expected %d", len(line), width)}
+		}
+		if err := fn(n, line+strings.Repeat(" ", width-len(line))); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// field returns the trimmed text from the 1-based positions start through end
+func field(line string, start, end int) string {
+	return strings.TrimSpace(line[start-1 : end])
+}
+
+func routingNumber(n int, name, value string) (string, error) {
+	if len(value) != 9 || strings.Trim(value, "0123456789") != "" {
+		return "", &ParseError{Line: n, Field: name, Err: fmt.Errorf("invalid routing number %q", value)}
+	}
+	return value, nil
+}
+
+func date(n int, name, layout, value string) (base.Date, error) {
+	if strings.Trim(value, "0 ") == "" {
+		return base.Date{}, nil
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return base.Date{}, &ParseError{Line: n, Field: name, Err: fmt.Errorf("invalid date %q", value)}
+	}
+	return base.DateOf(t), nil
+}
+
+// Directory indexes participants by routing number
+type Directory struct {
+	ach  map[string]ACHParticipant
+	wire map[string]WireParticipant
+}
+
+// NewDirectory returns a Directory of ach and wire participants. Either can be nil.
+func NewDirectory(ach []ACHParticipant, wire []WireParticipant) *Directory {
+	d := &Directory{
+		ach:  make(map[string]ACHParticipant, len(ach)),
+		wire: make(map[string]WireParticipant, len(wire)),
+	}
+	for _, p := range ach {
+		d.ach[p.RoutingNumber] = p
+	}
+	for _, p := range wire {
+		d.wire[p.RoutingNumber] = p
+	}
+	return d
+}
+
+// ACH returns the FedACH participant of routingNumber
+func (d *Directory) ACH(routingNumber string) (ACHParticipant, bool) {
+	p, ok := d.ach[strings.TrimSpace(routingNumber)]
+	return p, ok
+}
+
+// Wire returns the Fedwire participant of routingNumber
+func (d *Directory) Wire(routingNumber string) (WireParticipant, bool) {
+	p, ok := d.wire[strings.TrimSpace(routingNumber)]
+	return p, ok
+}
+
+// Name returns the institution name of routingNumber from either directory, preferring FedACH
+func (d *Directory) Name(routingNumber string) (string, bool) {
+	if p, ok := d.ACH(routingNumber); ok {
+		return p.CustomerName, true
+	}
+	if p, ok := d.Wire(routingNumber); ok {
+		return p.CustomerName, true
+	}
+	return "", false
+}
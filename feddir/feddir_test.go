@@ -0,0 +1,143 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package feddir
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+func achLine(fields ...string) string {
+	widths := []int{9, 1, 9, 1, 6, 9, 36, 36, 20, 2, 5, 4, 3, 3, 4, 1, 1, 5}
+	var sb strings.Builder
+	for i, w := range widths {
+		v := ""
+		if i < len(fields) {
+			v = fields[i]
+		}
+		sb.WriteString(v + strings.Repeat(" ", w-len(v)))
+	}
+	return sb.String()
+}
+
+func wireLine(fields ...string) string {
+	widths := []int{9, 18, 36, 2, 25, 1, 1, 1, 8}
+	var sb strings.Builder
+	for i, w := range widths {
+		sb.WriteString(fields[i] + strings.Repeat(" ", w-len(fields[i])))
+	}
+	return sb.String()
+}
+
+func TestParseACH(t *testing.T) {
+	lines := []string{
+		achLine("231380104", "O", "031000040", "1", "020316", "000000000", "CITADEL FEDERAL CREDIT UNION", "520 EAGLEVIEW BLVD", "EXTON", "PA", "19341", "0000", "610", "380", "2400", "1", "1"),
+		achLine("011000015", "O", "011000015", "2", "122019", "011000028", "FEDERAL RESERVE BANK", "1000 PEACHTREE ST N.E.", "ATLANTA", "GA", "30309", "4470", "877", "372", "2457", "1", "1"),
+		"",
+	}
+	participants, err := ParseACH(strings.NewReader(strings.Join(lines, "\r\n")))
+	require.NoError(t, err)
+	require.Len(t, participants, 2)
+
+	p := participants[0]
+	require.Equal(t, "231380104", p.RoutingNumber)
+	require.Equal(t, "O", p.OfficeCode)
+	require.Equal(t, "031000040", p.ServicingFRBNumber)
+	require.Equal(t, base.NewDate(2016, time.February, 3), p.ChangeDate)
+	require.Empty(t, p.NewRoutingNumber)
+	require.Equal(t, "CITADEL FEDERAL CREDIT UNION", p.CustomerName)
+	require.Equal(t, "520 EAGLEVIEW BLVD", p.Address)
+	require.Equal(t, "EXTON", p.City)
+	require.Equal(t, "PA", p.State)
+	require.Equal(t, "19341", p.PostalCode)
+	require.Equal(t, "610-380-2400", p.Phone)
+
+	p = participants[1]
+	require.Equal(t, "2", p.RecordTypeCode)
+	require.Equal(t, "011000028", p.NewRoutingNumber)
+	require.Equal(t, "30309-4470", p.PostalCode)
+
+	// trailing spaces removed
+	participants, err = ParseACH(strings.NewReader(strings.TrimRight(lines[0], " ") + "\n"))
+	require.NoError(t, err)
+	require.Equal(t, "EXTON", participants[0].City)
+
+	_, err = ParseACH(strings.NewReader(lines[0] + "\n" + achLine("23138010X")))
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 2, perr.Line)
+	require.Equal(t, "routing number", perr.Field)
+
+	_, err = ParseACH(strings.NewReader(achLine("231380104", "O", "031000040", "1", "133016")))
+	require.ErrorContains(t, err, "line 1: change date: invalid date")
+
+	_, err = ParseACH(strings.NewReader(lines[0] + "extra"))
+	require.ErrorContains(t, err, "is 160 characters, expected 155")
+}
+
+func TestParseWire(t *testing.T) {
+	lines := []string{
+		wireLine("011000015", "FRB BOS", "FEDERAL RESERVE BANK OF BOSTON", "MA", "BOSTON", "Y", " ", "Y", "20020716"),
+		wireLine("021000021", "JPMCHASE", "JPMORGAN CHASE BANK, NA", "NY", "NEW YORK", "Y", "S", "N", "00000000"),
+	}
+	participants, err := ParseWire(strings.NewReader(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+	require.Len(t, participants, 2)
+
+	p := participants[0]
+	require.Equal(t, "FRB BOS", p.TelegraphicName)
+	require.Equal(t, "FEDERAL RESERVE BANK OF BOSTON", p.CustomerName)
+	require.Equal(t, "MA", p.State)
+	require.Equal(t, "BOSTON", p.City)
+	require.True(t, p.FundsTransfer)
+	require.False(t, p.SettlementOnly)
+	require.True(t, p.SecuritiesTransfer)
+	require.Equal(t, base.NewDate(2002, time.July, 16), p.RevisionDate)
+
+	p = participants[1]
+	require.True(t, p.SettlementOnly)
+	require.False(t, p.SecuritiesTransfer)
+	require.True(t, p.RevisionDate.IsZero())
+}
+
+func TestDirectory(t *testing.T) {
+	ach, err := ParseACH(strings.NewReader(achLine("231380104", "O", "031000040", "1", "020316", "", "CITADEL FEDERAL CREDIT UNION")))
+	require.NoError(t, err)
+	wire, err := ParseWire(strings.NewReader(strings.Join([]string{
+		wireLine("231380104", "CITADEL FCU", "CITADEL FCU", "PA", "EXTON", "Y", " ", "N", "20200101"),
+		wireLine("021000021", "JPMCHASE", "JPMORGAN CHASE BANK, NA", "NY", "NEW YORK", "Y", " ", "Y", "20200101"),
+	}, "\n")))
+	require.NoError(t, err)
+
+	dir := NewDirectory(ach, wire)
+
+	p, ok := dir.ACH(" 231380104 ")
+	require.True(t, ok)
+	require.Equal(t, "CITADEL FEDERAL CREDIT UNION", p.CustomerName)
+
+	_, ok = dir.ACH("021000021")
+	require.False(t, ok)
+
+	w, ok := dir.Wire("021000021")
+	require.True(t, ok)
+	require.Equal(t, "JPMCHASE", w.TelegraphicName)
+
+	name, ok := dir.Name("231380104")
+	require.True(t, ok)
+	require.Equal(t, "CITADEL FEDERAL CREDIT UNION", name)
+
+	name, ok = dir.Name("021000021")
+	require.True(t, ok)
+	require.Equal(t, "JPMORGAN CHASE BANK, NA", name)
+
+	_, ok = NewDirectory(nil, nil).Name("021000021")
+	require.False(t, ok)
+}
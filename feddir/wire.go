@@ -0,0 +1,60 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package feddir
+
+import (
+	"io"
+
+	"github.com/moov-io/base"
+)
+
+// WireLineWidth is the length of each Fedwire directory record
+const WireLineWidth = 101
+
+// WireParticipant is a record of the Fedwire Funds Service participant directory
+type WireParticipant struct {
+	RoutingNumber   string `json:"routingNumber"`
+	TelegraphicName string `json:"telegraphicName"`
+	CustomerName    string `json:"customerName"`
+	State           string `json:"state"`
+	City            string `json:"city"`
+
+	// FundsTransfer is true when the institution can send and receive funds transfers
+	FundsTransfer bool `json:"fundsTransfer"`
+
+	// SettlementOnly is true when the institution only settles, without sending or receiving transfers
+	SettlementOnly bool `json:"settlementOnly"`
+
+	// SecuritiesTransfer is true when the institution can transfer book-entry securities
+	SecuritiesTransfer bool `json:"securitiesTransfer"`
+
+	RevisionDate base.Date `json:"revisionDate"`
+}
+
+// ParseWire reads a Fedwire directory. Short lines are padded, as some copies have their
+// trailing spaces removed.
+func ParseWire(r io.Reader) ([]WireParticipant, error) {
+	var out []WireParticipant
+	err := parseLines(r, WireLineWidth, func(n int, line string) error {
+		var p WireParticipant
+		var err error
+		if p.RoutingNumber, err = routingNumber(n, "routing number", field(line, 1, 9)); err != nil {
+			return err
+		}
+		p.TelegraphicName = field(line, 10, 27)
+		p.CustomerName = field(line, 28, 63)
+		p.State = field(line, 64, 65)
+		p.City = field(line, 66, 90)
+		p.FundsTransfer = field(line, 91, 91) == "Y"
+		p.SettlementOnly = field(line, 92, 92) == "S"
+		p.SecuritiesTransfer = field(line, 93, 93) == "Y"
+		if p.RevisionDate, err = date(n, "revision date", "20060102", field(line, 94, 101)); err != nil {
+			return err
+		}
+		out = append(out, p)
+		return nil
+	})
+	return out, err
+}
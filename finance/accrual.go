@@ -0,0 +1,96 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package finance implements calculations for deposit and lending products such as interest accrual.
+package finance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/decimal"
+)
+
+var (
+	// ErrInvalidPeriod is returned when an accrual period ends before it starts
+	ErrInvalidPeriod = errors.New("accrual period ends before it starts")
+)
+
+// Convention is a day count convention which determines how many days of interest
+// accrue over a period and how many days make up a year.
+type Convention string
+
+const (
+	// Actual360 counts the actual calendar days over a 360 day year
+	Actual360 Convention = "ACT/360"
+
+	// Actual365 counts the actual calendar days over a 365 day year
+	Actual365 Convention = "ACT/365"
+
+	// Thirty360 treats every month as 30 days over a 360 day year (30/360 US bond basis)
+	Thirty360 Convention = "30/360"
+)
+
+// Validate returns an error if c is not a known Convention
+func (c Convention) Validate() error {
+	switch c {
+	case Actual360, Actual365, Thirty360:
+		return nil
+	}
+	return fmt.Errorf("unknown day count convention %q", string(c))
+}
+
+// DayCount returns the number of days of interest between from and to under c.
+func (c Convention) DayCount(from, to base.Date) int {
+	if c != Thirty360 {
+		return to.DaysSince(from)
+	}
+
+	d1, d2 := from.Day, to.Day
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return 360*(to.Year-from.Year) + 30*int(to.Month-from.Month) + (d2 - d1)
+}
+
+// YearDays returns the number of days in a year under c.
+func (c Convention) YearDays() int {
+	if c == Actual365 {
+		return 365
+	}
+	return 360
+}
+
+// Accrue returns the simple interest earned on principal at an annual rate (i.e. 0.0425 for 4.25%)
+// between from and to under the dayCount convention.
+//
+// Funds can only move on banking days, so from and to are moved forward to the following banking day
+// when they fall on a weekend or holiday. The result is rounded half-even to the currency's minor units.
+func Accrue(principal base.Amount, rate decimal.Decimal, dayCount Convention, from, to base.Date) (base.Amount, error) {
+	if err := dayCount.Validate(); err != nil {
+		return base.Amount{}, err
+	}
+
+	from, to = from.AddBankingDays(0), to.AddBankingDays(0)
+	if to.Before(from) {
+		return base.Amount{}, ErrInvalidPeriod
+	}
+
+	days := decimal.NewFromInt(int64(dayCount.DayCount(from, to)))
+	interest := decimal.NewFromInt(principal.Value).Mul(rate).Mul(days)
+
+	interest, err := interest.Div(decimal.NewFromInt(int64(dayCount.YearDays())), 0, decimal.RoundHalfEven)
+	if err != nil {
+		return base.Amount{}, err
+	}
+	value, err := interest.Int64()
+	if err != nil {
+		return base.Amount{}, fmt.Errorf("accrued interest on %s: %v", principal, err)
+	}
+	return base.NewAmount(value, principal.Currency), nil
+}
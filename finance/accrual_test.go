@@ -0,0 +1,82 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/decimal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvention__DayCount(t *testing.T) {
+	tests := []struct {
+		convention Convention
+		from, to   base.Date
+		expected   int
+	}{
+		{Actual360, base.NewDate(2020, time.January, 31), base.NewDate(2020, time.March, 2), 31},
+		{Actual365, base.NewDate(2020, time.January, 1), base.NewDate(2021, time.January, 1), 366},
+		{Thirty360, base.NewDate(2020, time.January, 31), base.NewDate(2020, time.March, 2), 32},
+		{Thirty360, base.NewDate(2020, time.January, 30), base.NewDate(2020, time.March, 31), 60},
+		{Thirty360, base.NewDate(2020, time.January, 15), base.NewDate(2021, time.January, 15), 360},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.convention.DayCount(test.from, test.to), "%s %v -> %v", test.convention, test.from, test.to)
+	}
+}
+
+func TestAccrue(t *testing.T) {
+	principal := base.NewAmount(1000000, "USD") // $10,000.00
+	rate := decimal.MustParse("0.05")
+
+	// 2020-06-01 (Monday) to 2020-07-01 (Wednesday) is 30 days
+	from, to := base.NewDate(2020, time.June, 1), base.NewDate(2020, time.July, 1)
+
+	interest, err := Accrue(principal, rate, Actual360, from, to)
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(4167, "USD"), interest) // 41.666...
+
+	interest, err = Accrue(principal, rate, Actual365, from, to)
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(4110, "USD"), interest) // 41.095...
+
+	interest, err = Accrue(principal, rate, Thirty360, from, to)
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(4167, "USD"), interest)
+
+	interest, err = Accrue(principal, rate, Actual360, from, from)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), interest.Value)
+}
+
+func TestAccrue__bankingDays(t *testing.T) {
+	principal := base.NewAmount(3600000, "USD")
+	rate := decimal.MustParse("0.10") // $10.00 per day on ACT/360
+
+	// Wednesday 2020-11-11 is Veterans Day, so a period ending on the holiday accrues through Thursday.
+	interest, err := Accrue(principal, rate, Actual360, base.NewDate(2020, time.November, 10), base.NewDate(2020, time.November, 11))
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(2000, "USD"), interest)
+
+	// A period ending on Saturday accrues through Monday
+	interest, err = Accrue(principal, rate, Actual360, base.NewDate(2020, time.November, 12), base.NewDate(2020, time.November, 14))
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(4000, "USD"), interest)
+}
+
+func TestAccrue__errors(t *testing.T) {
+	principal := base.NewAmount(100, "USD")
+	from, to := base.NewDate(2020, time.June, 1), base.NewDate(2020, time.July, 1)
+
+	_, err := Accrue(principal, decimal.MustParse("0.01"), Convention("ACT/ACT"), from, to)
+	require.Error(t, err)
+
+	_, err = Accrue(principal, decimal.MustParse("0.01"), Actual360, to, from)
+	require.Equal(t, ErrInvalidPeriod, err)
+}
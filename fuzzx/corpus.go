@@ -0,0 +1,92 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package fuzzx helps file parsers use native Go fuzzing. Example files seed the corpus and a
+// Mutator derives variants which keep the fixed-width record structure, so the fuzzer reaches
+// field validation instead of stopping at the first malformed line.
+//
+//	var layout = fuzzx.Layout{
+//		RecordLength: 94,
+//		Records: map[byte][]fuzzx.Field{
+//			'1': {{Name: "immediateDestination", Start: 3, Width: 10, Kind: fuzzx.Numeric}, ...},
+//			'6': {{Name: "amount", Start: 29, Width: 10, Kind: fuzzx.Numeric}, ...},
+//		},
+//	}
+//
+//	func FuzzReader(f *testing.F) {
+//		corpus := fuzzx.AddCorpus(f, "testdata")
+//		fuzzx.AddMutations(f, fuzzx.NewMutator(layout, 1), corpus, 10)
+//		fuzzx.Fuzz(f, func(data []byte) error {
+//			_, err := NewReader(bytes.NewReader(data)).Read()
+//			return err
+//		})
+//	}
+package fuzzx
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// LoadCorpus returns the contents of each file in dir, sorted by name. Subdirectories are skipped.
+func LoadCorpus(dir string) ([][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var out [][]byte
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		bs, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, bs)
+	}
+	return out, nil
+}
+
+// AddCorpus adds each file of dirs to the seed corpus of f and returns their contents.
+func AddCorpus(f *testing.F, dirs ...string) [][]byte {
+	f.Helper()
+
+	var out [][]byte
+	for _, dir := range dirs {
+		corpus, err := LoadCorpus(dir)
+		if err != nil {
+			f.Fatalf("fuzzx: %v", err)
+		}
+		for i := range corpus {
+			f.Add(corpus[i])
+		}
+		out = append(out, corpus...)
+	}
+	return out
+}
+
+// AddMutations adds n variants of each corpus entry from m to the seed corpus of f. Go's fuzzer
+// has no hook for custom mutators, so structure-aware mutations are seeded instead.
+func AddMutations(f *testing.F, m *Mutator, corpus [][]byte, n int) {
+	f.Helper()
+
+	for i := range corpus {
+		for j := 0; j < n; j++ {
+			f.Add(m.Mutate(corpus[i]))
+		}
+	}
+}
+
+// Fuzz runs parse against fuzzed inputs. Errors are expected for malformed input and ignored,
+// panics and hangs are reported by the fuzzer.
+func Fuzz(f *testing.F, parse func(data []byte) error) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parse(data)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package fuzzx
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// layout of testdata/batch.txt
+var layout = Layout{
+	RecordLength: 21,
+	Records: map[byte][]Field{
+		'6': {
+			{Name: "name", Start: 1, Width: 10, Kind: Alphanumeric},
+			{Name: "amount", Start: 11, Width: 10, Kind: Numeric},
+		},
+	},
+	Default: []Field{
+		{Name: "label", Start: 1, Width: 10, Kind: Alphanumeric},
+		{Name: "number", Start: 11, Width: 10, Kind: Numeric},
+	},
+}
+
+// parse is a small fixed-width parser which sums entry amounts
+func parse(data []byte) (int, error) {
+	total := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if len(line) != 21 {
+			return 0, errors.New("invalid record length")
+		}
+		n, err := strconv.Atoi(line[11:])
+		if err != nil {
+			return 0, err
+		}
+		if line[0] == '6' {
+			total += n
+		}
+	}
+	return total, nil
+}
+
+func TestLoadCorpus(t *testing.T) {
+	corpus, err := LoadCorpus("testdata")
+	require.NoError(t, err)
+	require.Len(t, corpus, 1)
+
+	total, err := parse(corpus[0])
+	require.NoError(t, err)
+	require.Equal(t, 12599, total)
+
+	_, err = LoadCorpus("missing")
+	require.Error(t, err)
+}
+
+func TestMutator(t *testing.T) {
+	corpus, _ := LoadCorpus("testdata")
+	m := NewMutator(layout, 1)
+
+	changed, sameLength := 0, 0
+	for i := 0; i < 200; i++ {
+		out := m.Mutate(corpus[0])
+		if !bytes.Equal(out, corpus[0]) {
+			changed++
+		}
+		if len(out) == len(corpus[0]) {
+			sameLength++
+		}
+	}
+	// most mutations replace a field in place
+	require.Greater(t, changed, 150)
+	require.Greater(t, sameLength, 120)
+
+	// deterministic for a seed
+	require.Equal(t, NewMutator(layout, 7).Mutate(corpus[0]), NewMutator(layout, 7).Mutate(corpus[0]))
+
+	// without line breaks
+	flat := bytes.ReplaceAll(corpus[0], []byte("\n"), nil)
+	records, sep := layout.split(flat)
+	require.Len(t, records, 4)
+	require.Nil(t, sep)
+}
+
+func TestMinimize(t *testing.T) {
+	input := []byte("1HEADER    0000000001\n6ALICE     00000A2500\n6BOB       0000000099\n9TRAILER   0000012599")
+	fails := func(data []byte) bool {
+		_, err := parse(data)
+		return err != nil && strings.Contains(err.Error(), `"00000A2500"`)
+	}
+	require.True(t, fails(input))
+
+	out := Minimize(layout, input, fails)
+	require.True(t, fails(out))
+	require.Equal(t, "           00000A2500", string(out))
+}
+
+func FuzzParse(f *testing.F) {
+	corpus := AddCorpus(f, "testdata")
+	AddMutations(f, NewMutator(layout, 1), corpus, 20)
+	Fuzz(f, func(data []byte) error {
+		_, err := parse(data)
+		return err
+	})
+}
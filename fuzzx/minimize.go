@@ -0,0 +1,48 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package fuzzx
+
+import (
+	"bytes"
+)
+
+// Minimize returns a smaller input for which fails still returns true, to turn a crash found by
+// the fuzzer into a readable test case. Whole records are removed first, then runs of characters
+// within the remaining records are blanked to spaces so fields keep their positions.
+//
+// fails must return true for input.
+func Minimize(layout Layout, input []byte, fails func([]byte) bool) []byte {
+	records, sep := layout.split(input)
+	join := func(rs [][]byte) []byte { return bytes.Join(rs, sep) }
+
+	// remove chunks of records, halving the chunk size each pass (ddmin)
+	for chunk := len(records) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(records) && len(records) > 1; {
+			candidate := append(append([][]byte(nil), records[:start]...), records[start+chunk:]...)
+			if len(candidate) > 0 && fails(join(candidate)) {
+				records = candidate
+				continue
+			}
+			start += chunk
+		}
+	}
+
+	// blank runs of characters within each record
+	for i := range records {
+		for chunk := len(records[i]) / 2; chunk >= 1; chunk /= 2 {
+			for start := 0; start+chunk <= len(records[i]); start += chunk {
+				original := append([]byte(nil), records[i][start:start+chunk]...)
+				if bytes.Count(original, []byte(" ")) == len(original) {
+					continue
+				}
+				copy(records[i][start:start+chunk], bytes.Repeat([]byte(" "), chunk))
+				if !fails(join(records)) {
+					copy(records[i][start:start+chunk], original)
+				}
+			}
+		}
+	}
+	return join(records)
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package fuzzx
+
+import (
+	"bytes"
+	"math/rand"
+)
+
+// Kind is the type of characters a field holds
+type Kind int
+
+const (
+	Alphanumeric Kind = iota
+	Numeric
+)
+
+// Field is a column of a fixed-width record. Start is zero based.
+type Field struct {
+	Name  string
+	Start int
+	Width int
+	Kind  Kind
+}
+
+// Layout describes the fields of a file's records. Records are lines of RecordLength characters
+// whose fields are chosen by their first character, i.e. the record type code of NACHA files.
+type Layout struct {
+	RecordLength int
+
+	Records map[byte][]Field
+
+	// Default are the fields of records without an entry in Records
+	Default []Field
+}
+
+func (l Layout) fields(record []byte) []Field {
+	if len(record) > 0 {
+		if fields, exists := l.Records[record[0]]; exists {
+			return fields
+		}
+	}
+	return l.Default
+}
+
+// Mutator derives variants of fixed-width files. Most mutations replace a single field with an
+// edge case, the rest add, remove or reorder records or change a record's length.
+type Mutator struct {
+	layout Layout
+	rand   *rand.Rand
+}
+
+// NewMutator returns a Mutator with deterministic mutations for seed
+func NewMutator(layout Layout, seed int64) *Mutator {
+	return &Mutator{layout: layout, rand: rand.New(rand.NewSource(seed))}
+}
+
+// Mutate returns a variant of data, which is unchanged.
+func (m *Mutator) Mutate(data []byte) []byte {
+	records, sep := m.layout.split(data)
+	if len(records) == 0 {
+		return append([]byte(nil), data...)
+	}
+
+	if m.rand.Intn(5) == 0 {
+		records = m.mutateStructure(records)
+	} else {
+		idx := m.rand.Intn(len(records))
+		records[idx] = m.mutateField(records[idx])
+	}
+	return bytes.Join(records, sep)
+}
+
+func (m *Mutator) mutateStructure(records [][]byte) [][]byte {
+	idx := m.rand.Intn(len(records))
+	switch m.rand.Intn(5) {
+	case 0: // drop a record
+		return append(records[:idx], records[idx+1:]...)
+	case 1: // duplicate a record
+		dup := append([]byte(nil), records[idx]...)
+		return append(records[:idx+1], append([][]byte{dup}, records[idx+1:]...)...)
+	case 2: // swap two records
+		other := m.rand.Intn(len(records))
+		records[idx], records[other] = records[other], records[idx]
+	case 3: // truncate a record
+		if n := len(records[idx]); n > 0 {
+			records[idx] = records[idx][:m.rand.Intn(n)]
+		}
+	default: // lengthen a record
+		records[idx] = append(records[idx], bytes.Repeat([]byte(" "), 1+m.rand.Intn(10))...)
+	}
+	return records
+}
+
+func (m *Mutator) mutateField(record []byte) []byte {
+	fields := m.layout.fields(record)
+	if len(fields) == 0 {
+		// flip a random character
+		if len(record) > 0 {
+			record[m.rand.Intn(len(record))] = byte(m.rand.Intn(256))
+		}
+		return record
+	}
+
+	field := fields[m.rand.Intn(len(fields))]
+	if field.Start >= len(record) {
+		return record
+	}
+	end := field.Start + field.Width
+	if end > len(record) {
+		end = len(record)
+	}
+	value := m.value(field.Kind, end-field.Start)
+	copy(record[field.Start:end], value)
+	return record
+}
+
+var alphanumericEdges = [][]byte{
+	[]byte(" "), []byte("lowercase"), []byte("\x00"), []byte("\t"), []byte("é"), []byte("*"),
+	[]byte("\""), []byte(","), []byte("9"),
+}
+
+// value returns width bytes of an edge case for a field of kind
+func (m *Mutator) value(kind Kind, width int) []byte {
+	out := bytes.Repeat([]byte(" "), width)
+	switch kind {
+	case Numeric:
+		switch m.rand.Intn(6) {
+		case 0: // all nines, the maximum value
+			copy(out, bytes.Repeat([]byte("9"), width))
+		case 1: // zero
+			copy(out, bytes.Repeat([]byte("0"), width))
+		case 2: // blank
+		case 3: // sign or decimal point
+			copy(out, bytes.Repeat([]byte("0"), width))
+			out[m.rand.Intn(width)] = "-+.,"[m.rand.Intn(4)]
+		case 4: // letter
+			copy(out, bytes.Repeat([]byte("0"), width))
+			out[m.rand.Intn(width)] = byte('A' + m.rand.Intn(26))
+		default:
+			for i := range out {
+				out[i] = byte('0' + m.rand.Intn(10))
+			}
+		}
+	default:
+		switch m.rand.Intn(3) {
+		case 0: // blank
+		case 1:
+			edge := alphanumericEdges[m.rand.Intn(len(alphanumericEdges))]
+			copy(out[m.rand.Intn(width):], edge)
+		default:
+			for i := range out {
+				out[i] = byte(' ' + m.rand.Intn(95))
+			}
+		}
+	}
+	return out
+}
+
+// split returns copies of the records of data and their separator. Files without line breaks
+// are split every RecordLength characters.
+func (l Layout) split(data []byte) ([][]byte, []byte) {
+	var out [][]byte
+	if !bytes.Contains(data, []byte("\n")) && l.RecordLength > 0 {
+		for start := 0; start < len(data); start += l.RecordLength {
+			end := start + l.RecordLength
+			if end > len(data) {
+				end = len(data)
+			}
+			out = append(out, append([]byte(nil), data[start:end]...))
+		}
+		return out, nil
+	}
+
+	sep := []byte("\n")
+	if bytes.Contains(data, []byte("\r\n")) {
+		sep = []byte("\r\n")
+	}
+	for _, line := range bytes.Split(data, sep) {
+		out = append(out, append([]byte(nil), line...))
+	}
+	return out, sep
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package golden compares test output against files in testdata/. Run tests with
+// -golden.update to rewrite the files from the current output. The flag is namespaced so it
+// doesn't collide with an -update flag the package under test defines.
+//
+//	func TestWriter(t *testing.T) {
+//		var buf bytes.Buffer
+//		writeFile(&buf, batch)
+//		golden.Assert(t, "ppd-debit.ach", buf.Bytes(), golden.ScrubTimestamps())
+//	}
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var flagUpdate = flag.Bool("golden.update", false, "Rewrite golden files in testdata/ from test output")
+
+// Dir is where golden files are read from, relative to the test's package.
+var Dir = "testdata"
+
+// Scrubber normalizes values which differ between runs, such as timestamps and generated IDs.
+// Scrubbers are applied to output before it's compared or written.
+type Scrubber func([]byte) []byte
+
+// ScrubRegexp replaces each match of re
+func ScrubRegexp(re *regexp.Regexp, replacement string) Scrubber {
+	return func(bs []byte) []byte {
+		return re.ReplaceAll(bs, []byte(replacement))
+	}
+}
+
+var (
+	timestamps = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+	moovIDs    = regexp.MustCompile(`\b[0-9a-f]{40}\b`)
+	uuids      = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+)
+
+// ScrubTimestamps replaces RFC 3339 timestamps with "2006-01-02T15:04:05Z"
+func ScrubTimestamps() Scrubber {
+	return ScrubRegexp(timestamps, "2006-01-02T15:04:05Z")
+}
+
+// ScrubIDs replaces IDs from base.ID and UUIDs with "<id>"
+func ScrubIDs() Scrubber {
+	ids, us := ScrubRegexp(moovIDs, "<id>"), ScrubRegexp(uuids, "<id>")
+	return func(bs []byte) []byte {
+		return us(ids(bs))
+	}
+}
+
+// Assert fails the test when got, after scrubbing, differs from the golden file name.
+func Assert(t testing.TB, name string, got []byte, scrubbers ...Scrubber) {
+	t.Helper()
+
+	for _, scrub := range scrubbers {
+		got = scrub(got)
+	}
+	path := filepath.Join(Dir, name)
+
+	if *flagUpdate {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden: %v (create it with -golden.update)", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("golden: %s differs (update it with -golden.update)\n%s", path, Diff(string(want), string(got)))
+	}
+}
+
+// AssertJSON compares the indented JSON encoding of v against the golden file name.
+func AssertJSON(t testing.TB, name string, v interface{}, scrubbers ...Scrubber) {
+	t.Helper()
+
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("golden: encoding %T: %v", v, err)
+	}
+	Assert(t, name, append(bs, '\n'), scrubbers...)
+}
+
+// Diff returns the lines removed from want ("-") and added in got ("+"), with unchanged lines
+// around each change for context.
+func Diff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	// longest common subsequence of lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	const context = 2
+	var out strings.Builder
+	skipped := false
+	for k := range lines {
+		near := false
+		for c := k - context; c <= k+context; c++ {
+			if c >= 0 && c < len(lines) && lines[c].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			skipped = true
+			continue
+		}
+		if skipped && out.Len() > 0 {
+			out.WriteString("...\n")
+		}
+		skipped = false
+		fmt.Fprintf(&out, "%c %s\n", lines[k].op, lines[k].text)
+	}
+	return out.String()
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package golden
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, format)
+}
+
+func TestAssert(t *testing.T) {
+	Assert(t, "transfer.txt", []byte("id: 0123456789abcdef0123456789abcdef01234567\ncreated: 2021-03-04T10:11:12.345Z\n"), ScrubIDs(), ScrubTimestamps())
+
+	AssertJSON(t, "transfer.json", map[string]interface{}{
+		"id":      "7d676c65-eccd-4809-8ff2-38a0d5e35eb6",
+		"created": "2021-03-04T10:11:12-05:00",
+		"amount":  100,
+	}, ScrubIDs(), ScrubTimestamps())
+
+	fake := &fakeT{TB: t}
+	Assert(fake, "transfer.txt", []byte("id: <id>\ncreated: now\n"))
+	require.Len(t, fake.errors, 1)
+}
+
+func TestAssert__Update(t *testing.T) {
+	dir := Dir
+	Dir = t.TempDir()
+	*flagUpdate = true
+	t.Cleanup(func() {
+		Dir = dir
+		*flagUpdate = false
+	})
+
+	Assert(t, filepath.Join("nested", "out.txt"), []byte("written\n"))
+
+	*flagUpdate = false
+	Assert(t, filepath.Join("nested", "out.txt"), []byte("written\n"))
+}
+
+func TestDiff(t *testing.T) {
+	want := strings.Join([]string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, "\n")
+	got := strings.Join([]string{"a", "b", "c", "D", "e", "f", "g", "h", "i", "j", "k"}, "\n")
+
+	expected := "  b\n  c\n- d\n+ D\n  e\n  f\n...\n  i\n  j\n+ k\n"
+	require.Equal(t, expected, Diff(want, got))
+	require.Equal(t, "", Diff("same", "same"))
+}
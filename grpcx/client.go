@@ -0,0 +1,125 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ClientConfig configures a connection from Dial
+type ClientConfig struct {
+	// TLS enables TLS when set. Add Certificates for mutual TLS. Connections are insecure
+	// (plaintext) when nil, which is only suitable inside a service mesh.
+	TLS *tls.Config
+
+	Retry RetryPolicy
+
+	// KeepaliveTime is how often idle connections are pinged. It defaults to 2 minutes, which
+	// is above the servers' minimum from NewServer.
+	KeepaliveTime time.Duration
+
+	// KeepaliveTimeout is how long to wait for a ping before closing. It defaults to 20 seconds.
+	KeepaliveTimeout time.Duration
+
+	// Options are appended to the dial options set by Dial
+	Options []grpc.DialOption
+}
+
+// RetryPolicy describes how failed calls are retried by the gRPC client
+type RetryPolicy struct {
+	// MaxAttempts includes the original call. It defaults to 3, one disables retries.
+	MaxAttempts int
+
+	// InitialBackoff defaults to 100ms and MaxBackoff to 2s
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Codes are the status codes which are retried. It defaults to Unavailable.
+	Codes []codes.Code
+}
+
+// Dial connects to target with logging metadata propagation, client metrics, keepalives and retries.
+func Dial(ctx context.Context, target string, cfg ClientConfig) (*grpc.ClientConn, error) {
+	if cfg.KeepaliveTime <= 0 {
+		cfg.KeepaliveTime = 2 * time.Minute
+	}
+	if cfg.KeepaliveTimeout <= 0 {
+		cfg.KeepaliveTimeout = 20 * time.Second
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		creds = credentials.NewTLS(cfg.TLS)
+	}
+
+	serviceConfig, err := cfg.Retry.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(unaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(streamClientInterceptor()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	}
+	opts = append(opts, cfg.Options...)
+
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpcx: dialing %s: %v", target, err)
+	}
+	return conn, nil
+}
+
+// serviceConfig returns the JSON service config applying p to every method
+func (p RetryPolicy) serviceConfig() (string, error) {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 2 * time.Second
+	}
+	if len(p.Codes) == 0 {
+		p.Codes = []codes.Code{codes.Unavailable}
+	}
+	if p.MaxAttempts == 1 {
+		return `{}`, nil
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"methodConfig": []interface{}{
+			map[string]interface{}{
+				"name": []interface{}{map[string]interface{}{}},
+				"retryPolicy": map[string]interface{}{
+					"maxAttempts":          p.MaxAttempts,
+					"initialBackoff":       fmt.Sprintf("%.3fs", p.InitialBackoff.Seconds()),
+					"maxBackoff":           fmt.Sprintf("%.3fs", p.MaxBackoff.Seconds()),
+					"backoffMultiplier":    2,
+					"retryableStatusCodes": p.Codes,
+				},
+			},
+		},
+	})
+	return string(bs), err
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/moov-io/base/log"
+)
+
+// testService is registered without generated code
+type testService struct {
+	calls    int32
+	failures int32
+
+	outgoing metadata.MD
+}
+
+func (s *testService) handle(ctx context.Context, method string) error {
+	switch method {
+	case "Panic":
+		panic("something broke")
+	case "Flaky":
+		if atomic.AddInt32(&s.calls, 1) <= s.failures {
+			return status.Error(codes.Unavailable, "try again")
+		}
+	case "Forward":
+		s.outgoing, _ = metadata.FromOutgoingContext(outgoing(ctx))
+	}
+	return nil
+}
+
+func unaryHandler(method string) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return new(emptypb.Empty), srv.(*testService).handle(ctx, method)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Test/" + method}, handler)
+		},
+	}
+}
+
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Test",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		unaryHandler("Panic"),
+		unaryHandler("Flaky"),
+		unaryHandler("Forward"),
+	},
+}
+
+func setup(t *testing.T, logger log.Logger, svc *testService, cfg ClientConfig) (*grpc.ClientConn, func(healthpb.HealthCheckResponse_ServingStatus)) {
+	t.Helper()
+
+	server, healthServer := NewServer(ServerConfig{Logger: logger})
+	server.RegisterService(&testServiceDesc, svc)
+
+	ln := bufconn.Listen(1 << 20)
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	cfg.Options = append(cfg.Options, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return ln.DialContext(ctx)
+	}))
+	conn, err := Dial(context.Background(), "passthrough:///bufnet", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, func(s healthpb.HealthCheckResponse_ServingStatus) {
+		healthServer.SetServingStatus("", s)
+	}
+}
+
+func TestServer__Health(t *testing.T) {
+	conn, setStatus := setup(t, nil, &testService{}, ClientConfig{})
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	setStatus(healthpb.HealthCheckResponse_SERVING)
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestServer__RecoveryAndLogging(t *testing.T) {
+	buf, logger := log.NewBufferLogger()
+	conn, _ := setup(t, logger, &testService{}, ClientConfig{})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"x-request-id", "request-1",
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	)
+	err := conn.Invoke(ctx, "/test.Test/Panic", &emptypb.Empty{}, &emptypb.Empty{})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, "internal error", status.Convert(err).Message())
+
+	out := buf.String()
+	require.Contains(t, out, "recovered from panic: something broke")
+	require.Contains(t, out, "method=/test.Test/Panic")
+	require.Contains(t, out, "code=Internal")
+	require.Contains(t, out, "requestID=request-1")
+	require.Contains(t, out, "traceID=4bf92f3577b34da6a3ce929d0e0e4736")
+	require.Contains(t, out, "level=error")
+}
+
+func TestClient__Retry(t *testing.T) {
+	svc := &testService{failures: 2}
+	conn, _ := setup(t, nil, svc, ClientConfig{})
+
+	err := conn.Invoke(context.Background(), "/test.Test/Flaky", &emptypb.Empty{}, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&svc.calls))
+
+	// retries disabled
+	svc = &testService{failures: 1}
+	conn, _ = setup(t, nil, svc, ClientConfig{Retry: RetryPolicy{MaxAttempts: 1}})
+	err = conn.Invoke(context.Background(), "/test.Test/Flaky", &emptypb.Empty{}, &emptypb.Empty{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestClient__Propagation(t *testing.T) {
+	svc := &testService{}
+	conn, _ := setup(t, nil, svc, ClientConfig{})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "request-2")
+	require.NoError(t, conn.Invoke(ctx, "/test.Test/Forward", &emptypb.Empty{}, &emptypb.Empty{}))
+	require.Equal(t, []string{"request-2"}, svc.outgoing.Get("x-request-id"))
+	require.Empty(t, svc.outgoing.Get("traceparent"))
+}
+
+func TestRetryPolicy__serviceConfig(t *testing.T) {
+	cfg, err := RetryPolicy{}.serviceConfig()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":3,"initialBackoff":"0.100s","maxBackoff":"2.000s","backoffMultiplier":2,"retryableStatusCodes":[14]}}]}`, cfg)
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package grpcx
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/moov-io/base/ctxkeys"
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+)
+
+var (
+	serverHandlingSeconds = kitprom.NewHistogramFrom(stdprom.HistogramOpts{
+		Name: "grpc_server_handling_seconds",
+		Help: "Histogram of gRPC server call durations",
+	}, []string{"method", "code"})
+
+	clientHandlingSeconds = kitprom.NewHistogramFrom(stdprom.HistogramOpts{
+		Name: "grpc_client_handling_seconds",
+		Help: "Histogram of gRPC client call durations",
+	}, []string{"method", "code"})
+)
+
+const (
+	requestIDHeader   = "x-request-id"
+	traceparentHeader = "traceparent"
+)
+
+// callInfo holds the IDs of a call for logging and propagation to downstream calls
+type callInfo struct {
+	requestID   string
+	traceparent string
+}
+
+var callInfoKey = ctxkeys.New[callInfo]("grpcx-call")
+
+// RequestID returns the X-Request-Id sent by the caller of a server RPC
+func RequestID(ctx context.Context) string {
+	info, _ := callInfoKey.Get(ctx)
+	return info.requestID
+}
+
+// TraceID returns the trace ID of the traceparent sent by the caller of a server RPC
+func TraceID(ctx context.Context) string {
+	info, _ := callInfoKey.Get(ctx)
+	return moovhttp.ParseTraceID(info.traceparent)
+}
+
+func withCallInfo(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info := callInfo{
+		requestID:   first(md.Get(requestIDHeader)),
+		traceparent: first(md.Get(traceparentHeader)),
+	}
+	if info.requestID == "" && info.traceparent == "" {
+		return ctx
+	}
+	return callInfoKey.Set(ctx, info)
+}
+
+func first(values []string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func unaryServerMetadata() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withCallInfo(ctx), req)
+	}
+}
+
+func streamServerMetadata() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: withCallInfo(ss.Context())})
+	}
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// serverFault returns true for codes which are the server's fault rather than the caller's
+func serverFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unimplemented, codes.Unavailable:
+		return true
+	}
+	return false
+}
+
+func logCall(logger log.Logger, ctx context.Context, method string, start time.Time, err error) {
+	if logger == nil {
+		return
+	}
+	code := status.Code(err)
+	fields := log.Fields{
+		"method":   log.String(method),
+		"code":     log.String(code.String()),
+		"duration": log.TimeDuration(time.Since(start)),
+	}
+	if requestID := RequestID(ctx); requestID != "" {
+		fields["requestID"] = log.String(requestID)
+	}
+	if traceID := TraceID(ctx); traceID != "" {
+		fields["traceID"] = log.String(traceID)
+	}
+	if err != nil {
+		fields["error"] = log.String(status.Convert(err).Message())
+	}
+	if serverFault(code) {
+		logger.Error().With(fields).Send()
+	} else {
+		logger.Info().With(fields).Send()
+	}
+}
+
+func unaryServerLogging(logger log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(logger, ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func streamServerLogging(logger log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func unaryServerMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		serverHandlingSeconds.With("method", info.FullMethod, "code", status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+func streamServerMetrics() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		serverHandlingSeconds.With("method", info.FullMethod, "code", status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// recovered logs a handler's panic and returns a generic error, so panic values (which can hold
+// request data) aren't sent to callers
+func recovered(logger log.Logger, method string, r interface{}) error {
+	if logger != nil {
+		logger.Error().With(log.Fields{
+			"method": log.String(method),
+			"stack":  log.String(string(debug.Stack())),
+		}).Logf("recovered from panic: %v", r)
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+func unaryServerRecovery(logger log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func streamServerRecovery(logger log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// outgoing copies the request and trace IDs of a server RPC onto calls made while handling it
+func outgoing(ctx context.Context) context.Context {
+	info, ok := callInfoKey.Get(ctx)
+	if !ok {
+		return ctx
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if info.requestID != "" && len(md.Get(requestIDHeader)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDHeader, info.requestID)
+	}
+	if info.traceparent != "" && len(md.Get(traceparentHeader)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, traceparentHeader, info.traceparent)
+	}
+	return ctx
+}
+
+func unaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(outgoing(ctx), method, req, reply, cc, opts...)
+		clientHandlingSeconds.With("method", method, "code", status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func streamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
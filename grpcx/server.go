@@ -0,0 +1,115 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package grpcx implements helpers for running gRPC servers and clients with the same operational
+// baseline (logging, metrics, panic recovery and health checks) as our HTTP services.
+//
+//	server, healthServer := grpcx.NewServer(grpcx.ServerConfig{
+//		Logger: logger,
+//	})
+//	pb.RegisterTransfersServer(server, svc)
+//
+//	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+//	err := server.Serve(listener)
+package grpcx
+
+import (
+	"crypto/tls"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/moov-io/base/log"
+)
+
+// ServerConfig configures a gRPC server from NewServer
+type ServerConfig struct {
+	// Logger records each RPC and recovered panics. Nothing is logged when nil.
+	Logger log.Logger
+
+	// TLS enables TLS when set. Set ClientAuth and ClientCAs for mutual TLS.
+	TLS *tls.Config
+
+	// Keepalive controls how connections are kept alive. Zero values use defaults suited to
+	// long lived connections between internal services.
+	Keepalive ServerKeepalive
+
+	// Options are appended to the server options set by NewServer
+	Options []grpc.ServerOption
+}
+
+// ServerKeepalive describes how a server pings clients and limits their pings
+type ServerKeepalive struct {
+	// MaxConnectionIdle closes connections idle this long. It defaults to 15 minutes.
+	MaxConnectionIdle time.Duration
+
+	// Time is how often idle clients are pinged. It defaults to 2 minutes.
+	Time time.Duration
+
+	// Timeout is how long to wait for a ping response before closing. It defaults to 20 seconds.
+	Timeout time.Duration
+
+	// MinTime is the shortest interval clients may ping. It defaults to 30 seconds, clients
+	// pinging faster are disconnected.
+	MinTime time.Duration
+}
+
+// NewServer returns a *grpc.Server with recovery, logging, metrics and trace propagation
+// interceptors. The standard health service is registered and returned with its status
+// set to NOT_SERVING until the caller marks the server ready.
+func NewServer(cfg ServerConfig) (*grpc.Server, *health.Server) {
+	ka := cfg.Keepalive
+	if ka.MaxConnectionIdle <= 0 {
+		ka.MaxConnectionIdle = 15 * time.Minute
+	}
+	if ka.Time <= 0 {
+		ka.Time = 2 * time.Minute
+	}
+	if ka.Timeout <= 0 {
+		ka.Timeout = 20 * time.Second
+	}
+	if ka.MinTime <= 0 {
+		ka.MinTime = 30 * time.Second
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			unaryServerMetadata(),
+			unaryServerLogging(cfg.Logger),
+			unaryServerMetrics(),
+			unaryServerRecovery(cfg.Logger),
+		),
+		grpc.ChainStreamInterceptor(
+			streamServerMetadata(),
+			streamServerLogging(cfg.Logger),
+			streamServerMetrics(),
+			streamServerRecovery(cfg.Logger),
+		),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: ka.MaxConnectionIdle,
+			Time:              ka.Time,
+			Timeout:           ka.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             ka.MinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+	if cfg.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLS)))
+	}
+	opts = append(opts, cfg.Options...)
+
+	server := grpc.NewServer(opts...)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	return server, healthServer
+}
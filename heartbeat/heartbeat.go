@@ -0,0 +1,186 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package heartbeat reports the completion of critical scheduled jobs to an external monitor
+// (such as Dead Man's Snitch or Healthchecks.io) which alerts when check-ins stop, and raises
+// its own alert when a banking day job hasn't succeeded by its deadline.
+//
+//	hb := heartbeat.New("ach-cutoff", heartbeat.Config{
+//		URL:     "https://nosnch.in/abc123",
+//		FailURL: "https://hc-ping.com/abc123/fail",
+//	})
+//	go hb.WatchBankingDays(ctx, 18*time.Hour, eastern) // alert when it hasn't run by 6pm
+//
+//	for tick := range ticker.C {
+//		hb.Run(ctx, uploadFiles)
+//	}
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/jobs"
+	"github.com/moov-io/base/log"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	missedDeadlines = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "heartbeat_missed_deadlines_total",
+		Help: "Counter of banking days a job didn't succeed by its deadline",
+	}, []string{"name"})
+
+	pingFailures = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "heartbeat_ping_failures_total",
+		Help: "Counter of check-ins which couldn't be sent to the monitor",
+	}, []string{"name"})
+)
+
+// Config describes where check-ins are sent
+type Config struct {
+	// URL is requested after each successful run
+	URL string
+
+	// FailURL, when set, is requested when a run fails or misses its deadline so the monitor
+	// alerts immediately rather than once check-ins are overdue
+	FailURL string
+
+	// Client sends check-ins. It defaults to a client with a 10 second timeout.
+	Client *http.Client
+
+	Logger log.Logger
+}
+
+// Heartbeat checks in with a monitor for one job
+type Heartbeat struct {
+	name   string
+	cfg    Config
+	logger log.Logger
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+// New returns a Heartbeat for the job called name
+func New(name string, cfg Config) *Heartbeat {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	return &Heartbeat{
+		name:   name,
+		cfg:    cfg,
+		logger: logger.Set("heartbeat", log.String(name)),
+	}
+}
+
+// Run calls job and checks in with the monitor when it succeeds, or reports the failure to
+// FailURL. job's error is returned, check-in errors are only logged so a monitor outage
+// doesn't fail the job.
+func (h *Heartbeat) Run(ctx context.Context, job func(ctx context.Context) error) error {
+	if err := job(ctx); err != nil {
+		h.Fail(ctx, err)
+		return err
+	}
+	h.Ping(ctx)
+	return nil
+}
+
+// Ping records a success and checks in with URL
+func (h *Heartbeat) Ping(ctx context.Context) error {
+	h.mu.Lock()
+	h.lastSuccess = base.Now().Time
+	h.mu.Unlock()
+
+	return h.send(ctx, h.cfg.URL)
+}
+
+// Fail reports reason to FailURL
+func (h *Heartbeat) Fail(ctx context.Context, reason error) error {
+	h.logger.Error().LogErrorf("job failed: %v", reason)
+	return h.send(ctx, h.cfg.FailURL)
+}
+
+// LastSuccess returns when Ping was last called, or the zero time
+func (h *Heartbeat) LastSuccess() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastSuccess
+}
+
+func (h *Heartbeat) send(ctx context.Context, url string) error {
+	if url == "" {
+		return nil
+	}
+	err := h.request(ctx, url)
+	if err != nil {
+		pingFailures.With("name", h.name).Add(1)
+		h.logger.Warn().LogErrorf("check-in failed: %v", err)
+	}
+	return err
+}
+
+func (h *Heartbeat) request(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("check-in returned %s", resp.Status)
+	}
+	return nil
+}
+
+// WatchBankingDays checks at deadline, an offset from midnight in loc, on each banking day
+// that the job has succeeded since the start of that day, reporting a missed deadline to
+// FailURL when it hasn't. It blocks until ctx is cancelled.
+func (h *Heartbeat) WatchBankingDays(ctx context.Context, deadline time.Duration, loc *time.Location) error {
+	ticker := jobs.BankingDayTickerIn(deadline, nil, loc)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case tick, ok := <-ticker.C:
+			if !ok {
+				return ticker.Err()
+			}
+			h.check(ctx, tick.Time, loc)
+		}
+	}
+}
+
+// check reports a missed deadline when the job hasn't succeeded on the day of at
+func (h *Heartbeat) check(ctx context.Context, at time.Time, loc *time.Location) bool {
+	day := base.DateOf(at.In(loc))
+	if last := h.LastSuccess(); !last.IsZero() && base.DateOf(last.In(loc)) == day {
+		return true
+	}
+	missedDeadlines.With("name", h.name).Add(1)
+	h.logger.Error().With(log.Fields{
+		"day": log.String(day.String()),
+	}).Logf("job didn't succeed by %s", at.In(loc).Format("15:04 MST"))
+
+	h.send(ctx, h.cfg.FailURL)
+	return false
+}
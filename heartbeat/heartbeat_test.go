@@ -0,0 +1,106 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/testtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+type monitor struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	paths  []string
+	status int
+}
+
+func newMonitor(t *testing.T) *monitor {
+	m := &monitor{status: http.StatusOK}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.paths = append(m.paths, r.URL.Path)
+		w.WriteHeader(m.status)
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *monitor) requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.paths...)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	testtime.Freeze(t, time.Date(2021, time.March, 4, 17, 0, 0, 0, time.UTC))
+	m := newMonitor(t)
+
+	hb := New("cutoff", Config{URL: m.URL + "/abc", FailURL: m.URL + "/abc/fail"})
+	require.True(t, hb.LastSuccess().IsZero())
+
+	require.NoError(t, hb.Run(ctx, func(context.Context) error { return nil }))
+	require.Equal(t, time.Date(2021, time.March, 4, 17, 0, 0, 0, time.UTC), hb.LastSuccess().UTC())
+
+	failed := errors.New("upload failed")
+	require.ErrorIs(t, hb.Run(ctx, func(context.Context) error { return failed }), failed)
+
+	require.Equal(t, []string{"/abc", "/abc/fail"}, m.requests())
+}
+
+func TestRun__MonitorDown(t *testing.T) {
+	m := newMonitor(t)
+	m.status = http.StatusServiceUnavailable
+
+	buf, logger := log.NewBufferLogger()
+	hb := New("cutoff", Config{URL: m.URL, Logger: logger})
+
+	// the job still succeeds
+	require.NoError(t, hb.Run(context.Background(), func(context.Context) error { return nil }))
+	require.ErrorContains(t, hb.Ping(context.Background()), "check-in returned 503 Service Unavailable")
+	require.Contains(t, buf.String(), "check-in failed")
+
+	// without a FailURL failures are only logged
+	require.NoError(t, hb.Fail(context.Background(), errors.New("oops")))
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	eastern, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	clock := testtime.Freeze(t, time.Date(2021, time.March, 4, 17, 0, 0, 0, eastern))
+	m := newMonitor(t)
+
+	buf, logger := log.NewBufferLogger()
+	hb := New("cutoff", Config{URL: m.URL + "/ok", FailURL: m.URL + "/fail", Logger: logger})
+
+	deadline := time.Date(2021, time.March, 4, 18, 0, 0, 0, eastern)
+	require.False(t, hb.check(ctx, deadline, eastern))
+	require.Contains(t, buf.String(), "job didn't succeed by 18:00 EST")
+
+	require.NoError(t, hb.Ping(ctx))
+	require.True(t, hb.check(ctx, deadline, eastern))
+
+	// yesterday's success doesn't count
+	clock.Change(clock.Now().Add(24 * time.Hour))
+	require.False(t, hb.check(ctx, deadline.Add(24*time.Hour), eastern))
+
+	require.Equal(t, []string{"/fail", "/ok", "/fail"}, m.requests())
+	require.Equal(t, 2, strings.Count(buf.String(), "job didn't succeed"))
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+//go:generate go run ./cmd/genholidays -start 1990 -end 2060 -output holidays/federal_reserve.csv
+
+//go:embed holidays/federal_reserve.csv
+var federalReserveHolidays []byte
+
+// Holiday is a day the Federal Reserve Banks are closed.
+type Holiday struct {
+	Name string `json:"name"`
+
+	// Date is the calendar date of the holiday
+	Date Date `json:"date"`
+
+	// Observed is the date Federal Reserve Banks are closed. Holidays falling on a Sunday are
+	// observed the following Monday. Saturday holidays are not moved.
+	Observed Date `json:"observed"`
+}
+
+// HolidayRangeError is returned when a date falls outside the range of a HolidayCalendar.
+type HolidayRangeError struct {
+	Date        Date
+	First, Last Date
+}
+
+func (e HolidayRangeError) Error() string {
+	return fmt.Sprintf("%s is outside of supported holidays %s to %s", e.Date, e.First, e.Last)
+}
+
+// Calendar reports which days Federal Reserve Banks are open.
+type Calendar interface {
+	IsBankingDay(d Date) (bool, error)
+}
+
+// HolidayCalendar is a Calendar backed by a fixed schedule of holidays. Dates outside the
+// years covered by the schedule return a HolidayRangeError rather than being guessed at.
+type HolidayCalendar struct {
+	first, last Date
+
+	observed map[Date]Holiday
+	years    map[int][]Holiday
+}
+
+var _ Calendar = (*HolidayCalendar)(nil)
+
+var (
+	federalReserveOnce     sync.Once
+	federalReserveCalendar *HolidayCalendar
+)
+
+// FederalReserveCalendar returns the HolidayCalendar of the Federal Reserve holiday schedule embedded
+// into this package. The schedule is generated with ./cmd/genholidays and covers 1990 through 2060.
+func FederalReserveCalendar() *HolidayCalendar {
+	federalReserveOnce.Do(func() {
+		cal, err := NewHolidayCalendar(bytes.NewReader(federalReserveHolidays))
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded holiday schedule: %v", err))
+		}
+		federalReserveCalendar = cal
+	})
+	return federalReserveCalendar
+}
+
+// NewHolidayCalendar reads a CSV schedule of holidays with 'date,observed,name' columns and a header row.
+// The calendar covers every year with at least one holiday.
+func NewHolidayCalendar(r io.Reader) (*HolidayCalendar, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("no holidays found")
+	}
+
+	cal := &HolidayCalendar{
+		observed: make(map[Date]Holiday),
+		years:    make(map[int][]Holiday),
+	}
+	for i, row := range rows[1:] {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 columns, found %d", i+2, len(row))
+		}
+		h := Holiday{Name: row[2]}
+		if h.Date, err = ParseDate(row[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		if h.Observed, err = ParseDate(row[1]); err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		cal.observed[h.Observed] = h
+		cal.years[h.Date.Year] = append(cal.years[h.Date.Year], h)
+
+		if cal.first.IsZero() || h.Date.Year < cal.first.Year {
+			cal.first = NewDate(h.Date.Year, 1, 1)
+		}
+		if h.Date.Year > cal.last.Year {
+			cal.last = NewDate(h.Date.Year, 12, 31)
+		}
+	}
+	for year := range cal.years {
+		hs := cal.years[year]
+		sort.Slice(hs, func(i, j int) bool { return hs[i].Date.Before(hs[j].Date) })
+	}
+	return cal, nil
+}
+
+// Range returns the first and last dates covered by the calendar.
+func (c *HolidayCalendar) Range() (first, last Date) {
+	return c.first, c.last
+}
+
+func (c *HolidayCalendar) checkRange(d Date) error {
+	if d.Before(c.first) || d.After(c.last) {
+		return HolidayRangeError{Date: d, First: c.first, Last: c.last}
+	}
+	return nil
+}
+
+// Holidays returns the holidays of a year in date order.
+func (c *HolidayCalendar) Holidays(year int) ([]Holiday, error) {
+	if err := c.checkRange(NewDate(year, 1, 1)); err != nil {
+		return nil, err
+	}
+	out := make([]Holiday, len(c.years[year]))
+	copy(out, c.years[year])
+	return out, nil
+}
+
+// IsHoliday reports whether Federal Reserve Banks are closed on d for a holiday.
+func (c *HolidayCalendar) IsHoliday(d Date) (bool, error) {
+	if err := c.checkRange(d); err != nil {
+		return false, err
+	}
+	_, ok := c.observed[d]
+	return ok, nil
+}
+
+// IsBankingDay reports whether d is neither a weekend nor a holiday.
+func (c *HolidayCalendar) IsBankingDay(d Date) (bool, error) {
+	holiday, err := c.IsHoliday(d)
+	if err != nil {
+		return false, err
+	}
+	return !holiday && !d.IsWeekend(), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFederalReserveCalendar(t *testing.T) {
+	cal := FederalReserveCalendar()
+
+	first, last := cal.Range()
+	if first != NewDate(1990, time.January, 1) || last != NewDate(2060, time.December, 31) {
+		t.Errorf("unexpected range: %v to %v", first, last)
+	}
+
+	hs, err := cal.Holidays(2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hs) != 11 {
+		t.Fatalf("expected 11 holidays, got %d", len(hs))
+	}
+	if hs[0].Name != "New Year's Day" || hs[10].Name != "Christmas Day" {
+		t.Errorf("unexpected order: %v", hs)
+	}
+	// Juneteenth 2022 fell on a Sunday
+	if hs[4].Date != NewDate(2022, time.June, 19) || hs[4].Observed != NewDate(2022, time.June, 20) {
+		t.Errorf("unexpected Juneteenth: %#v", hs[4])
+	}
+}
+
+func TestFederalReserveCalendar__IsBankingDay(t *testing.T) {
+	cal := FederalReserveCalendar()
+
+	tests := []struct {
+		date     Date
+		expected bool
+	}{
+		{NewDate(2021, time.July, 5), false},  // Independence Day observed
+		{NewDate(2020, time.July, 3), true},   // Saturday holidays are not moved
+		{NewDate(2022, time.June, 20), false}, // Juneteenth observed
+		{NewDate(2021, time.June, 18), true},  // Juneteenth wasn't observed until 2022
+		{NewDate(2020, time.November, 14), false},
+		{NewDate(2020, time.November, 16), true},
+	}
+	for _, test := range tests {
+		ok, err := cal.IsBankingDay(test.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.expected {
+			t.Errorf("%v: expected %v", test.date, test.expected)
+		}
+		if v := test.date.IsBankingDay(); v != test.expected {
+			t.Errorf("Date.IsBankingDay %v: expected %v", test.date, test.expected)
+		}
+	}
+}
+
+func TestFederalReserveCalendar__outOfRange(t *testing.T) {
+	cal := FederalReserveCalendar()
+
+	_, err := cal.IsBankingDay(NewDate(1989, time.December, 31))
+	var rangeErr HolidayRangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(err.Error(), "1989-12-31 is outside of supported holidays") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := cal.Holidays(2061); err == nil {
+		t.Error("expected error")
+	}
+
+	// Time falls back to rules outside of the schedule
+	if NewTime(time.Date(2061, time.December, 26, 12, 0, 0, 0, time.UTC)).IsBankingDay() {
+		t.Error("expected Christmas observed on Monday 2061-12-26")
+	}
+	if (Time{Time: time.Date(1985, time.December, 25, 12, 0, 0, 0, time.UTC)}).IsBankingDay() {
+		t.Error("expected Christmas 1985")
+	}
+}
+
+func TestNewHolidayCalendar(t *testing.T) {
+	cal, err := NewHolidayCalendar(strings.NewReader("date,observed,name\n2020-12-25,2020-12-25,Christmas Day\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first, last := cal.Range(); first.Year != 2020 || last.Year != 2020 {
+		t.Errorf("unexpected range: %v to %v", first, last)
+	}
+
+	for _, input := range []string{
+		"",
+		"date,observed,name\n",
+		"date,observed,name\n2020-12-25,Christmas Day\n",
+		"date,observed,name\n12/25/2020,2020-12-25,Christmas Day\n",
+		"date,observed,name\n2020-12-25,12/25/2020,Christmas Day\n",
+	} {
+		if _, err := NewHolidayCalendar(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error from %q", input)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/moov-io/base/opts"
+)
+
+const defaultClientTimeout = 30 * time.Second
+
+// ClientConfig configures an *http.Client from NewClient. It's designed to be loaded through the
+// config package so deployments can be configured without environment variables.
+type ClientConfig struct {
+	// Timeout limits the time of each request. It defaults to 30 seconds.
+	Timeout time.Duration
+
+	// DNSCache caches host lookups when set, see DNSCache.
+	DNSCache *DNSCacheConfig
+
+	Proxy ProxyConfig
+}
+
+// ProxyConfig routes requests through (corporate) proxies.
+//
+//	Proxy:
+//	  URL: "http://proxy.corp.example:3128"
+//	  Username: "svc-ach"
+//	  Password: "..."
+//	  NoProxy: "localhost,10.0.0.0/8,.svc.cluster.local"
+//	  Rules:
+//	    - Hosts: [".bank.com"]
+//	      URL: "http://bank-proxy.corp.example:8080"
+type ProxyConfig struct {
+	// URL is the proxy used for hosts without a matching rule. When empty along with Rules,
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY are read from the environment.
+	URL string
+
+	// Username and Password are sent to each proxy which doesn't set them in its URL.
+	Username string
+	Password string
+
+	// NoProxy lists hosts which are connected to directly, in the format of NO_PROXY
+	// (comma separated hostnames, domain suffixes, IP addresses and CIDR ranges).
+	NoProxy string
+
+	// Rules route specific hosts through another proxy. The first matching rule is used.
+	Rules []ProxyRule
+}
+
+// ProxyRule sends requests for Hosts through URL. Hosts are matched exactly unless they start with
+// "." or "*." which matches the domain and any subdomain. An empty URL connects directly.
+type ProxyRule struct {
+	Hosts []string
+	URL   string
+}
+
+// ClientOption customizes the *http.Client returned from NewClient
+type ClientOption = opts.Option[http.Client]
+
+// WithTransport replaces the configured transport, which ignores cfg.Proxy and cfg.DNSCache.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return opts.New("transport", func(c *http.Client) error {
+		c.Transport = rt
+		return nil
+	})
+}
+
+// WithRoundTripper wraps the client's transport, i.e. to add headers or record metrics. It may be
+// given multiple times, the last wrapper given runs first.
+func WithRoundTripper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return opts.New("round-tripper", func(c *http.Client) error {
+		c.Transport = wrap(c.Transport)
+		return nil
+	})
+}
+
+// WithCheckRedirect sets the policy for following redirects, see http.Client.CheckRedirect.
+func WithCheckRedirect(fn func(req *http.Request, via []*http.Request) error) ClientOption {
+	return opts.New("check-redirect", func(c *http.Client) error {
+		c.CheckRedirect = fn
+		return nil
+	}).ConflictsWith("without-redirects")
+}
+
+// WithoutRedirects returns redirect responses to the caller instead of following them.
+func WithoutRedirects() ClientOption {
+	return opts.New("without-redirects", func(c *http.Client) error {
+		c.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}).ConflictsWith("check-redirect")
+}
+
+// NewClient returns an *http.Client configured from cfg and then options.
+func NewClient(cfg ClientConfig, options ...ClientOption) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxy, err := cfg.Proxy.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+
+	if cfg.DNSCache != nil {
+		transport.DialContext = NewDNSCache(*cfg.DNSCache).DialContext
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultClientTimeout
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+	if err := opts.Apply(client, options...); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+type proxyRoute struct {
+	hosts []string
+	proxy func(*url.URL) (*url.URL, error)
+}
+
+func (cfg ProxyConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if cfg.URL == "" && len(cfg.Rules) == 0 {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	fallback, err := cfg.route(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]proxyRoute, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		if len(rule.Hosts) == 0 {
+			return nil, fmt.Errorf("proxy rule %d has no hosts", i)
+		}
+		routes[i].hosts = rule.Hosts
+		routes[i].proxy, err = cfg.route(rule.URL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+		for i := range routes {
+			for _, pattern := range routes[i].hosts {
+				if matchHost(pattern, host) {
+					return routes[i].proxy(req.URL)
+				}
+			}
+		}
+		return fallback(req.URL)
+	}, nil
+}
+
+// route returns a proxy func for proxyURL which honors NoProxy
+func (cfg ProxyConfig) route(proxyURL string) (func(*url.URL) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return func(*url.URL) (*url.URL, error) { return nil, nil }, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", proxyURL)
+	}
+	if u.User == nil && cfg.Username != "" {
+		u.User = url.UserPassword(cfg.Username, cfg.Password)
+	}
+	conf := &httpproxy.Config{
+		HTTPProxy:  u.String(),
+		HTTPSProxy: u.String(),
+		NoProxy:    cfg.NoProxy,
+	}
+	return conf.ProxyFunc(), nil
+}
+
+func matchHost(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimPrefix(pattern, "*"))
+	host = strings.ToLower(host)
+	if strings.HasPrefix(pattern, ".") {
+		return host == pattern[1:] || strings.HasSuffix(host, pattern)
+	}
+	return host == pattern
+}
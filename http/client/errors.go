@@ -0,0 +1,200 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package client implements helpers for services calling other Moov services over HTTP.
+//
+// CheckResponse turns non-2xx responses into an *Error decoded from the response body, which
+// callers can inspect with errors.As:
+//
+//	resp, err := http.DefaultClient.Do(req)
+//	if err != nil {
+//		return err
+//	}
+//	defer resp.Body.Close()
+//
+//	if err := client.CheckResponse(resp); err != nil {
+//		var apiErr *client.Error
+//		if errors.As(err, &apiErr) && apiErr.Code == "account_not_found" {
+//			...
+//		}
+//		return err
+//	}
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxErrorBodySize is how much of a response body is read when decoding an Error
+	maxErrorBodySize = 1 << 20
+
+	// maxMessageLength limits how much of a non-JSON body is kept as an Error's Message
+	maxMessageLength = 512
+)
+
+// Error is a non-2xx response from another service.
+//
+// Bodies written by github.com/moov-io/base/http.Problem, RFC 7807 problem details and
+// coded errors ({"code": "...", "message": "..."}) are decoded.
+type Error struct {
+	StatusCode int
+
+	// Code is a machine readable identifier of the error, when the server sent one
+	Code string
+
+	// Message is a human readable description of the error. For responses without a recognized
+	// JSON body this is the (truncated) body.
+	Message string
+
+	// Details lists each issue when the server rejected several parts of a request
+	Details []string
+
+	// RFC 7807 fields
+	Type     string
+	Title    string
+	Instance string
+
+	RequestID string
+	TraceID   string
+	Timestamp time.Time
+}
+
+func (e *Error) Error() string {
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("unexpected status %d", e.StatusCode))
+	if e.Code != "" {
+		buf.WriteString(fmt.Sprintf(" (%s)", e.Code))
+	}
+	if msg := e.message(); msg != "" {
+		buf.WriteString(": " + msg)
+	}
+	if e.RequestID != "" {
+		buf.WriteString(fmt.Sprintf(" [requestID=%s]", e.RequestID))
+	}
+	return buf.String()
+}
+
+func (e *Error) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Title
+}
+
+// Temporary returns true for statuses where retrying the request may succeed.
+func (e *Error) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// StatusCode returns the HTTP status code of an *Error found in err's chain, or zero if there isn't one.
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// CheckResponse returns nil for 2xx responses. Otherwise the body is read and decoded into an *Error.
+// The caller is still responsible for closing resp.Body.
+func CheckResponse(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("nil http.Response")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return DecodeError(resp)
+}
+
+// DecodeError reads resp.Body into an *Error regardless of the response status.
+func DecodeError(resp *http.Response) *Error {
+	out := &Error{
+		StatusCode: resp.StatusCode,
+	}
+	if resp.Body == nil {
+		return out
+	}
+	bs, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if !decodeBody(bs, out) {
+		msg := string(bytes.TrimSpace(bs))
+		if len(msg) > maxMessageLength {
+			msg = msg[:maxMessageLength]
+		}
+		out.Message = msg
+	}
+	if out.RequestID == "" {
+		out.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	return out
+}
+
+type errorBody struct {
+	// {"error": "..."} or {"error": {"code": "...", "message": "..."}}
+	Error json.RawMessage `json:"error"`
+
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details"`
+
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+
+	RequestID string    `json:"requestId"`
+	TraceID   string    `json:"traceId"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type codedError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func decodeBody(bs []byte, out *Error) bool {
+	var body errorBody
+	if err := json.Unmarshal(bs, &body); err != nil {
+		return false
+	}
+
+	out.Code = body.Code
+	out.Message = body.Message
+	if body.Detail != "" {
+		out.Message = body.Detail
+	}
+	if len(body.Error) > 0 {
+		var msg string
+		var coded codedError
+		if err := json.Unmarshal(body.Error, &msg); err == nil {
+			out.Message = msg
+		} else if err := json.Unmarshal(body.Error, &coded); err == nil {
+			out.Code = coded.Code
+			out.Message = coded.Message
+		}
+	}
+
+	out.Details = body.Details
+	out.Type = body.Type
+	out.Title = body.Title
+	out.Instance = body.Instance
+	out.RequestID = body.RequestID
+	out.TraceID = body.TraceID
+	out.Timestamp = body.Timestamp
+
+	return out.Code != "" || out.Message != "" || out.Title != ""
+}
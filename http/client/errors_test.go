@@ -0,0 +1,96 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+)
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestCheckResponse__Problem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		moovhttp.Problem(moovhttp.Wrap(log.NewNopLogger(), nil, w, r), errors.New("invalid routing number"))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("X-Request-Id", "request-1")
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	err = fmt.Errorf("creating transfer: %w", CheckResponse(resp))
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Equal(t, "invalid routing number", apiErr.Message)
+	require.Equal(t, "request-1", apiErr.RequestID)
+	require.False(t, apiErr.Timestamp.IsZero())
+	require.False(t, apiErr.Temporary())
+
+	require.Equal(t, http.StatusBadRequest, StatusCode(err))
+	require.Equal(t, "creating transfer: unexpected status 400: invalid routing number [requestID=request-1]", err.Error())
+}
+
+func TestCheckResponse__Formats(t *testing.T) {
+	err := CheckResponse(response(http.StatusNotFound, `{"type":"https://moov.io/errors/not-found","title":"Not Found","status":404,"detail":"account 123 not found","instance":"/accounts/123"}`))
+	apiErr := err.(*Error)
+	require.Equal(t, "https://moov.io/errors/not-found", apiErr.Type)
+	require.Equal(t, "Not Found", apiErr.Title)
+	require.Equal(t, "account 123 not found", apiErr.Message)
+	require.Equal(t, "/accounts/123", apiErr.Instance)
+
+	apiErr = CheckResponse(response(http.StatusBadRequest, `{"error":"request body: invalid","details":["field \"amount\": required","field \"name\": required"]}`)).(*Error)
+	require.Equal(t, "request body: invalid", apiErr.Message)
+	require.Len(t, apiErr.Details, 2)
+
+	err = CheckResponse(response(http.StatusConflict, `{"code":"duplicate_file","message":"file already uploaded"}`))
+	require.Equal(t, "unexpected status 409 (duplicate_file): file already uploaded", err.Error())
+
+	err = CheckResponse(response(http.StatusUnprocessableEntity, `{"error":{"code":"invalid_amount","message":"amount must be positive"}}`))
+	apiErr = err.(*Error)
+	require.Equal(t, "invalid_amount", apiErr.Code)
+	require.Equal(t, "amount must be positive", apiErr.Message)
+
+	resp := response(http.StatusServiceUnavailable, "upstream connect error\n")
+	resp.Header.Set("X-Request-Id", "request-2")
+	apiErr = CheckResponse(resp).(*Error)
+	require.Equal(t, "upstream connect error", apiErr.Message)
+	require.Equal(t, "request-2", apiErr.RequestID)
+	require.True(t, apiErr.Temporary())
+
+	apiErr = CheckResponse(response(http.StatusBadGateway, strings.Repeat("a", 2000))).(*Error)
+	require.Len(t, apiErr.Message, maxMessageLength)
+
+	apiErr = CheckResponse(response(http.StatusInternalServerError, `{}`)).(*Error)
+	require.Equal(t, "{}", apiErr.Message)
+	require.Equal(t, "unexpected status 500: {}", apiErr.Error())
+}
+
+func TestCheckResponse__OK(t *testing.T) {
+	require.NoError(t, CheckResponse(response(http.StatusOK, "")))
+	require.NoError(t, CheckResponse(response(http.StatusNoContent, "")))
+	require.Error(t, CheckResponse(nil))
+	require.Equal(t, 0, StatusCode(errors.New("other")))
+}
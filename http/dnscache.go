@@ -0,0 +1,129 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultDNSCacheTTL = time.Minute
+
+// DNSCacheConfig configures a DNSCache
+type DNSCacheConfig struct {
+	// TTL is how long resolved addresses are cached, regardless of the record's TTL.
+	// It defaults to one minute.
+	TTL time.Duration
+
+	// MaxStale is how long after expiring the last known good addresses of a host are used when
+	// lookups fail. Zero disables serving stale addresses.
+	MaxStale time.Duration
+}
+
+// DNSCache resolves and caches host addresses for dialing. When the resolver fails (i.e. during
+// a brief outage) the last known good addresses can be used so transmissions near a cutoff
+// aren't lost.
+//
+// Set DialContext on an http.Transport to use the cache:
+//
+//	cache := moovhttp.NewDNSCache(moovhttp.DNSCacheConfig{TTL: time.Minute, MaxStale: time.Hour})
+//	transport := &http.Transport{DialContext: cache.DialContext}
+type DNSCache struct {
+	cfg DNSCacheConfig
+
+	lookup func(ctx context.Context, host string) ([]string, error)
+	dialer *net.Dialer
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// NewDNSCache returns an empty DNSCache using the default resolver
+func NewDNSCache(cfg DNSCacheConfig) *DNSCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultDNSCacheTTL
+	}
+	return &DNSCache{
+		cfg:    cfg,
+		lookup: net.DefaultResolver.LookupHost,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// LookupHost returns the addresses of host. Cached addresses are returned until they expire.
+func (c *DNSCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	entry, found := c.entries[host]
+	c.mu.Unlock()
+
+	if found && now.Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no addresses found for %s", host)
+	}
+	if err != nil {
+		if found && now.Before(entry.expires.Add(c.cfg.MaxStale)) {
+			return entry.addrs, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{
+		addrs:   addrs,
+		expires: now.Add(c.cfg.TTL),
+	}
+	c.mu.Unlock()
+
+	return addrs, nil
+}
+
+// DialContext connects to addr after resolving its host through the cache. Each address is tried
+// in order until one connects.
+func (c *DNSCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return c.dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := c.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for i := range addrs {
+		conn, err := c.dialer.DialContext(ctx, network, net.JoinHostPort(addrs[i], port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
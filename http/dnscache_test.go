@@ -0,0 +1,117 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDNSCache(t *testing.T) {
+	now := time.Date(2020, time.December, 1, 16, 0, 0, 0, time.UTC)
+	var lookups int
+	var lookupErr error
+
+	cache := NewDNSCache(DNSCacheConfig{TTL: time.Minute, MaxStale: time.Hour})
+	cache.now = func() time.Time { return now }
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups++
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return []string{"10.1.2.3"}, nil
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		addrs, err := cache.LookupHost(ctx, "sftp.bank.com")
+		if err != nil || len(addrs) != 1 {
+			t.Fatalf("addrs=%v error=%v", addrs, err)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected one lookup, got %d", lookups)
+	}
+
+	// expired entries are resolved again
+	now = now.Add(2 * time.Minute)
+	cache.LookupHost(ctx, "sftp.bank.com")
+	if lookups != 2 {
+		t.Errorf("expected another lookup, got %d", lookups)
+	}
+
+	// resolver failures fall back to the last known good addresses
+	lookupErr = errors.New("i/o timeout")
+	now = now.Add(30 * time.Minute)
+	addrs, err := cache.LookupHost(ctx, "sftp.bank.com")
+	if err != nil || addrs[0] != "10.1.2.3" {
+		t.Errorf("addrs=%v error=%v", addrs, err)
+	}
+
+	// until they're too stale
+	now = now.Add(2 * time.Hour)
+	if _, err := cache.LookupHost(ctx, "sftp.bank.com"); err == nil || err.Error() != "i/o timeout" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// unknown hosts return the resolver's error
+	if _, err := cache.LookupHost(ctx, "other.bank.com"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDNSCache__Defaults(t *testing.T) {
+	cache := NewDNSCache(DNSCacheConfig{})
+	if cache.cfg.TTL != defaultDNSCacheTTL {
+		t.Errorf("unexpected TTL: %v", cache.cfg.TTL)
+	}
+
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		return nil, nil
+	}
+	if _, err := cache.LookupHost(context.Background(), "empty.bank.com"); err == nil || !strings.Contains(err.Error(), "no addresses") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDNSCache__DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PONG"))
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+
+	cache := NewDNSCache(DNSCacheConfig{})
+	cache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		// the first address refuses connections
+		return []string{"127.0.0.2", "127.0.0.1"}, nil
+	}
+	client := &http.Client{
+		Transport: &http.Transport{DialContext: cache.DialContext},
+	}
+
+	resp, err := client.Get("http://partner.bank.com:" + port + "/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("bogus HTTP status: %d", resp.StatusCode)
+	}
+
+	// IP addresses are dialed directly
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package openapi implements middleware which validates HTTP requests against an OpenAPI 3 document.
+//
+//	//go:embed openapi.yaml
+//	var spec []byte
+//
+//	validator, err := openapi.NewValidator(spec)
+//	if err != nil {
+//		return err
+//	}
+//	router.Use(validator.Middleware)
+//
+// Requests with invalid parameters or bodies are rejected with a 400 Problem response listing each
+// issue, so handlers can assume well-formed input.
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"github.com/moov-io/base"
+	moovhttp "github.com/moov-io/base/http"
+)
+
+// Validator checks requests against the operations of an OpenAPI 3 document
+type Validator struct {
+	router routers.Router
+}
+
+// NewValidator parses an OpenAPI 3 document (as YAML or JSON) and returns a Validator of its operations.
+//
+// Only the paths of the document's servers are used when matching requests, so the same document
+// works regardless of the hostname a service is running under.
+func NewValidator(spec []byte) (*Validator, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: loading document: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %v", err)
+	}
+
+	for i := range doc.Servers {
+		if u, err := url.Parse(doc.Servers[i].URL); err == nil {
+			doc.Servers[i].URL = u.Path
+		}
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %v", err)
+	}
+	return &Validator{
+		router: router,
+	}, nil
+}
+
+// Middleware validates each request before calling next. Requests which don't match an operation
+// of the document are passed to next without validation.
+//
+// Security requirements aren't checked as authentication is handled elsewhere.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Validate(r); err != nil {
+			moovhttp.Problem(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Validate checks r against the matching operation of the document. A nil error is returned for
+// requests without an operation. Otherwise any issues are returned as a base.ErrorList.
+//
+// The request body is read and replaced, so it can be read again after Validate.
+func (v *Validator) Validate(r *http.Request) error {
+	route, pathParams, err := v.router.FindRoute(r)
+	if err != nil {
+		return nil
+	}
+
+	err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	})
+	if err == nil {
+		return nil
+	}
+
+	var problems base.ErrorList
+	collect(&problems, "", err)
+	return problems
+}
+
+// collect flattens the errors kin-openapi returns into one error per invalid parameter or field
+func collect(problems *base.ErrorList, prefix string, err error) {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for i := range e {
+			collect(problems, prefix, e[i])
+		}
+
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			prefix = fmt.Sprintf("%s parameter %q", e.Parameter.In, e.Parameter.Name)
+		case e.RequestBody != nil:
+			prefix = "request body"
+		}
+		if e.Err == nil {
+			problems.Add(issue(prefix, e.Reason))
+		} else {
+			collect(problems, prefix, e.Err)
+		}
+
+	case *openapi3filter.ParseError:
+		problems.Add(issue(prefix, e.Error()))
+
+	case *openapi3.SchemaError:
+		if pointer := e.JSONPointer(); len(pointer) > 0 {
+			prefix = strings.TrimSpace(fmt.Sprintf("%s field %q", prefix, strings.Join(pointer, ".")))
+		}
+		problems.Add(issue(prefix, e.Reason))
+
+	default:
+		problems.Add(issue(prefix, err.Error()))
+	}
+}
+
+func issue(prefix, reason string) error {
+	if prefix == "" {
+		return errors.New(reason)
+	}
+	return fmt.Errorf("%s: %s", prefix, reason)
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(t *testing.T) *mux.Router {
+	t.Helper()
+
+	spec, err := os.ReadFile("testdata/transfers.yaml")
+	require.NoError(t, err)
+
+	validator, err := NewValidator(spec)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(validator.Middleware)
+	router.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// bodies are readable after validation
+		bs, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write(bs)
+	})
+	return router
+}
+
+type problem struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details"`
+}
+
+func do(t *testing.T, router *mux.Router, req *http.Request) (*httptest.ResponseRecorder, problem) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	w.Flush()
+
+	var out problem
+	if w.Code != http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	}
+	return w, out
+}
+
+func TestValidator(t *testing.T) {
+	router := setupRouter(t)
+
+	body := `{"amount":{"value":100,"currency":"USD"},"description":"payroll"}`
+	req := httptest.NewRequest("POST", "/v1/transfers", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Idempotency-Key", "key")
+
+	w, _ := do(t, router, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, body, w.Body.String())
+
+	w, _ = do(t, router, httptest.NewRequest("GET", "/v1/transfers/abcd1234", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// routes outside the document are passed through
+	w, _ = do(t, router, httptest.NewRequest("GET", "/ping", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestValidator__Invalid(t *testing.T) {
+	router := setupRouter(t)
+
+	req := httptest.NewRequest("POST", "/v1/transfers", strings.NewReader(`{"amount":{"value":0,"currency":"EUR"},"description":"a long description"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w, problem := do(t, router, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, []string{
+		`header parameter "X-Idempotency-Key": value is required but missing`,
+		`request body field "amount.currency": value is not one of the allowed values ["USD"]`,
+		`request body field "amount.value": number must be at least 1`,
+		`request body field "description": maximum string length is 10`,
+	}, problem.Details)
+
+	w, problem = do(t, router, httptest.NewRequest("GET", "/v1/transfers?count=500", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, []string{`query parameter "count": number must be at most 200`}, problem.Details)
+
+	w, problem = do(t, router, httptest.NewRequest("GET", "/v1/transfers/nope", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, problem.Details, 1)
+	require.Contains(t, problem.Details[0], `path parameter "transferID"`)
+}
+
+func TestNewValidator__Invalid(t *testing.T) {
+	_, err := NewValidator([]byte("not: [openapi"))
+	require.Error(t, err)
+
+	_, err = NewValidator([]byte(`{"openapi":"3.0.2","info":{"title":"x"},"paths":{}}`))
+	require.Error(t, err)
+}
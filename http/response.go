@@ -29,7 +29,8 @@ type ResponseWriter struct {
 	request *http.Request
 	metric  metrics.Histogram
 
-	headersWritten bool // set on WriteHeader
+	headersWritten bool  // set on WriteHeader
+	err            error // set by Problem
 
 	log log.Logger
 }
@@ -58,14 +59,24 @@ func (w *ResponseWriter) WriteHeader(code int) {
 		w.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
 	}
 
-	if requestID := GetRequestID(w.request); requestID != "" && w.log != nil {
-		w.log.With(log.Fields{
-			"method":    log.String(w.request.Method),
-			"path":      log.String(w.request.URL.Path),
-			"status":    log.Int(code),
-			"duration":  log.TimeDuration(diff),
-			"requestID": log.String(requestID),
-		}).Send()
+	requestID, traceID := GetRequestID(w.request), GetTraceID(w.request)
+	if (requestID != "" || traceID != "") && w.log != nil {
+		fields := log.Fields{
+			"method":   log.String(w.request.Method),
+			"path":     log.String(w.request.URL.Path),
+			"status":   log.Int(code),
+			"duration": log.TimeDuration(diff),
+		}
+		if requestID != "" {
+			fields["requestID"] = log.String(requestID)
+		}
+		if traceID != "" {
+			fields["traceID"] = log.String(traceID)
+		}
+		if w.err != nil {
+			fields["error"] = log.String(w.err.Error())
+		}
+		w.log.With(fields).Send()
 	}
 }
 
@@ -5,7 +5,9 @@
 package http
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -13,8 +15,11 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/bufpool"
 	"github.com/moov-io/base/strx"
 )
 
@@ -24,15 +29,45 @@ const (
 
 // Problem writes err to w while also setting the HTTP status code, content-type and marshaling
 // err as the response body.
+//
+// The response includes a timestamp and, when w was returned from Wrap or EnsureHeaders, the request's
+// X-Request-Id and trace ID. The same IDs and err are added to the log line written for the request.
+//
+// Each error of a base.ErrorList is also listed in the response's "details".
 func Problem(w http.ResponseWriter, err error) {
 	if err == nil {
 		return
 	}
-	w.WriteHeader(http.StatusBadRequest)
+	body := problem{
+		Error:     err.Error(),
+		Timestamp: time.Now().UTC(),
+	}
+	var list base.ErrorList
+	if errors.As(err, &list) {
+		for i := range list {
+			body.Details = append(body.Details, list[i].Error())
+		}
+	}
+	if ww, ok := w.(*ResponseWriter); ok && ww != nil {
+		ww.err = err
+		body.RequestID = GetRequestID(ww.request)
+		body.TraceID = GetTraceID(ww.request)
+	}
+	buf := bufpool.GetBuffer()
+	defer bufpool.PutBuffer(buf)
+	json.NewEncoder(buf).Encode(body)
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-	})
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(buf.Bytes())
+}
+
+type problem struct {
+	Error     string    `json:"error"`
+	Details   []string  `json:"details,omitempty"`
+	RequestID string    `json:"requestId,omitempty"`
+	TraceID   string    `json:"traceId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // InternalError writes err to w while also setting the HTTP status code, content-type and marshaling
@@ -110,6 +145,26 @@ func GetRequestID(r *http.Request) string {
 	return r.Header.Get("X-Request-Id")
 }
 
+// GetTraceID returns the trace ID from a W3C traceparent header, or an empty string when the
+// header is missing or malformed.
+func GetTraceID(r *http.Request) string {
+	return ParseTraceID(r.Header.Get("Traceparent"))
+}
+
+// ParseTraceID returns the trace ID of a W3C traceparent value, or an empty string when it's malformed.
+//
+// Docs: https://www.w3.org/TR/trace-context/#traceparent-header
+func ParseTraceID(traceparent string) string {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 {
+		return ""
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	return parts[1]
+}
+
 // GetUserID returns the Moov userId from HTTP headers
 func GetUserID(r *http.Request) string {
 	return strx.Or(r.Header.Get("X-User"), r.Header.Get("X-User-Id"))
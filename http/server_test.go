@@ -14,6 +14,9 @@ import (
 	"unicode/utf8"
 
 	"github.com/gorilla/mux"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/log"
 )
 
 func truncate(s string) string {
@@ -92,6 +95,19 @@ func TestHTTP__Problem(t *testing.T) {
 		t.Errorf("got %q", response.Error)
 	}
 
+	// error lists are returned as details
+	w = httptest.NewRecorder()
+	Problem(w, base.ErrorList{errors.New("problem A"), errors.New("problem B")})
+	w.Flush()
+
+	var problems problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problems); err != nil {
+		t.Error(err)
+	}
+	if len(problems.Details) != 2 || problems.Details[1] != "problem B" {
+		t.Errorf("got %#v", problems)
+	}
+
 	// nil error, respond http.StatusOK
 	w = httptest.NewRecorder()
 	Problem(w, nil)
@@ -125,6 +141,58 @@ func TestHTTP__GetRequestID(t *testing.T) {
 	}
 }
 
+func TestHTTP__ProblemWrapped(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("x-request-id", "request-1")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	buf, logger := log.NewBufferLogger()
+	w := httptest.NewRecorder()
+	Problem(Wrap(logger, nil, w, req), errors.New("problem Z"))
+	w.Flush()
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got %d", w.Code)
+	}
+	if v := w.Result().Header.Get("Content-Type"); !strings.Contains(v, "application/json") {
+		t.Errorf("got %s", v)
+	}
+
+	var response problem
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error != "problem Z" || response.RequestID != "request-1" || response.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected response: %#v", response)
+	}
+	if response.Timestamp.IsZero() {
+		t.Error("expected timestamp")
+	}
+
+	for _, kv := range []string{"requestID=request-1", "traceID=4bf92f3577b34da6a3ce929d0e0e4736", `error="problem Z"`, "status=400"} {
+		if !strings.Contains(buf.String(), kv) {
+			t.Errorf("missing %s in log: %s", kv, buf.String())
+		}
+	}
+}
+
+func TestHTTP__GetTraceID(t *testing.T) {
+	cases := map[string]string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01": "",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01": "",
+		"00-4bf92f35-00f067aa0ba902b7-01":                         "",
+		"":                                                        "",
+	}
+	for header, expected := range cases {
+		r := httptest.NewRequest("GET", "/ping", nil)
+		r.Header.Set("traceparent", header)
+		if traceID := GetTraceID(r); traceID != expected {
+			t.Errorf("%q: got %q", header, traceID)
+		}
+	}
+}
+
 func TestHTTP__GetUserID(t *testing.T) {
 	r := httptest.NewRequest("GET", "/ping", nil)
 	r.Header.Set("x-user-id", "userID")
@@ -0,0 +1,222 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var shedRequests = kitprom.NewCounterFrom(stdprom.CounterOpts{
+	Name: "http_shed_requests_total",
+	Help: "Counter of HTTP requests rejected while the server was saturated",
+}, []string{"priority"})
+
+// Priority orders requests for shedding. Low priority requests are rejected first.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+
+	// PriorityCritical requests are never shed, such as submitting files before a cutoff
+	PriorityCritical
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityCritical:
+		return "critical"
+	}
+	return "normal"
+}
+
+// ParsePriority reads "low", "normal" or "critical"
+func ParsePriority(s string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low":
+		return PriorityLow, true
+	case "normal":
+		return PriorityNormal, true
+	case "critical":
+		return PriorityCritical, true
+	}
+	return PriorityNormal, false
+}
+
+// ShedRule sets the Priority of requests whose path starts with PathPrefix. Method matches
+// every method when empty.
+type ShedRule struct {
+	Method     string
+	PathPrefix string
+	Priority   Priority
+}
+
+// ShedConfig describes when Shed rejects requests
+type ShedConfig struct {
+	// MaxInFlight is how many requests are served at once before normal priority requests
+	// are rejected. Zero means unlimited.
+	MaxInFlight int
+
+	// SaturatedInFlight is how many requests are served at once before low priority requests
+	// are rejected. It defaults to three quarters of MaxInFlight.
+	SaturatedInFlight int
+
+	// TargetLatency marks the server saturated once the moving average of response times
+	// exceeds it. The average decays while requests are shed, so low priority requests are
+	// let through again after a while without other traffic. Zero only considers requests in flight.
+	TargetLatency time.Duration
+
+	// Rules are checked in order and the first match sets a request's Priority. Requests
+	// without a match are PriorityNormal.
+	Rules []ShedRule
+
+	// PriorityHeader, when set, is read with ParsePriority and overrides Rules. Only set it
+	// when callers are trusted, such as behind an internal gateway.
+	PriorityHeader string
+
+	// RetryAfter is sent to rejected requests. It defaults to 1 second.
+	RetryAfter time.Duration
+}
+
+// Shed returns an http.Handler which rejects requests with 503 Service Unavailable while next
+// is saturated. Low priority requests are rejected once SaturatedInFlight or TargetLatency is
+// exceeded and normal priority requests at MaxInFlight. Critical requests are always served.
+func Shed(cfg ShedConfig, next http.Handler) http.Handler {
+	if cfg.SaturatedInFlight <= 0 {
+		cfg.SaturatedInFlight = cfg.MaxInFlight * 3 / 4
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Second
+	}
+	return &shedder{
+		cfg:  cfg,
+		next: next,
+		now:  time.Now,
+	}
+}
+
+type shedder struct {
+	cfg  ShedConfig
+	next http.Handler
+
+	inFlight int64 // accessed atomically
+
+	mu         sync.Mutex
+	latency    float64 // moving average, in seconds
+	observedAt time.Time
+	now        func() time.Time
+}
+
+const (
+	// latencyWeight is how much each response moves the average latency
+	latencyWeight = 0.1
+
+	// latencyDecay is how long the average latency takes to fall by about two thirds without
+	// responses, such as while every request is shed
+	latencyDecay = 5 * time.Second
+)
+
+func (s *shedder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	priority := s.priority(r)
+
+	inFlight := atomic.AddInt64(&s.inFlight, 1)
+	defer atomic.AddInt64(&s.inFlight, -1)
+
+	if s.reject(priority, inFlight) {
+		shedRequests.With("priority", priority.String()).Add(1)
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(s.cfg.RetryAfter.Seconds()))))
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "server is overloaded",
+		})
+		return
+	}
+
+	start := time.Now()
+	s.next.ServeHTTP(w, r)
+	s.observe(time.Since(start))
+}
+
+func (s *shedder) priority(r *http.Request) Priority {
+	if s.cfg.PriorityHeader != "" {
+		if p, ok := ParsePriority(r.Header.Get(s.cfg.PriorityHeader)); ok {
+			return p
+		}
+	}
+	for _, rule := range s.cfg.Rules {
+		if rule.Method != "" && !strings.EqualFold(rule.Method, r.Method) {
+			continue
+		}
+		if strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+			return rule.Priority
+		}
+	}
+	return PriorityNormal
+}
+
+// reject reports whether a request of priority is shed with inFlight requests being served,
+// including itself.
+func (s *shedder) reject(priority Priority, inFlight int64) bool {
+	switch priority {
+	case PriorityCritical:
+		return false
+	case PriorityLow:
+		return s.saturated(inFlight)
+	}
+	return s.cfg.MaxInFlight > 0 && inFlight > int64(s.cfg.MaxInFlight)
+}
+
+func (s *shedder) saturated(inFlight int64) bool {
+	if s.cfg.SaturatedInFlight > 0 && inFlight > int64(s.cfg.SaturatedInFlight) {
+		return true
+	}
+	if s.cfg.TargetLatency > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.averageLatency(s.now()) > s.cfg.TargetLatency.Seconds()
+	}
+	return false
+}
+
+func (s *shedder) observe(took time.Duration) {
+	if s.cfg.TargetLatency <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.latency == 0 {
+		s.latency = took.Seconds()
+	} else {
+		s.latency = s.averageLatency(now)
+		s.latency += latencyWeight * (took.Seconds() - s.latency)
+	}
+	s.observedAt = now
+}
+
+// averageLatency returns the moving average decayed by the time since the last response.
+// s.mu must be held.
+func (s *shedder) averageLatency(now time.Time) float64 {
+	elapsed := now.Sub(s.observedAt)
+	if elapsed <= 0 {
+		return s.latency
+	}
+	return s.latency * math.Exp(-elapsed.Seconds()/latencyDecay.Seconds())
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShed__InFlight(t *testing.T) {
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	handler := Shed(ShedConfig{
+		MaxInFlight:       2,
+		SaturatedInFlight: 1,
+		Rules: []ShedRule{
+			{PathPrefix: "/reports", Priority: PriorityLow},
+			{Method: "POST", PathPrefix: "/files", Priority: PriorityCritical},
+		},
+		PriorityHeader: "X-Priority",
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") == "yes" {
+			started.Done()
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, target string, header string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, nil)
+		if header != "" {
+			req.Header.Set("X-Priority", header)
+		}
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// nothing in flight
+	if got := serve("GET", "/reports", "").Code; got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+
+	// fill the server with two requests
+	var done sync.WaitGroup
+	started.Add(2)
+	done.Add(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer done.Done()
+			serve("GET", "/transfers?block=yes", "critical")
+		}()
+	}
+	started.Wait()
+
+	w := serve("GET", "/reports", "")
+	if got := w.Code; got != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected %v", got, http.StatusServiceUnavailable)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("got %v, expected %v", got, "1")
+	}
+
+	if got := serve("GET", "/transfers", "").Code; got != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected %v", got, http.StatusServiceUnavailable)
+	}
+	if got := serve("GET", "/files", "").Code; got != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected %v", got, http.StatusServiceUnavailable)
+	}
+	if got := serve("POST", "/files", "").Code; got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+	if got := serve("GET", "/reports", "critical").Code; got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+
+	close(release)
+	done.Wait()
+
+	if got := serve("GET", "/reports", "").Code; got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+}
+
+func TestShed__Latency(t *testing.T) {
+	delay := 20 * time.Millisecond
+	handler := Shed(ShedConfig{
+		TargetLatency: 5 * time.Millisecond,
+		Rules: []ShedRule{
+			{PathPrefix: "/reports", Priority: PriorityLow},
+		},
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(target string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		return w.Code
+	}
+
+	if got := serve("/reports"); got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+	if got := serve("/reports"); got != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected %v", got, http.StatusServiceUnavailable)
+	}
+	if got := serve("/transfers"); got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+
+	// recover once responses are fast again
+	delay = 0
+	for i := 0; i < 50; i++ {
+		serve("/transfers")
+	}
+	if got := serve("/reports"); got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+}
+
+func TestShed__LatencyDecay(t *testing.T) {
+	now := time.Now()
+	handler := Shed(ShedConfig{
+		TargetLatency: 5 * time.Millisecond,
+		Rules: []ShedRule{
+			{PathPrefix: "/reports", Priority: PriorityLow},
+		},
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	handler.(*shedder).now = func() time.Time { return now }
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/reports", nil))
+		return w.Code
+	}
+
+	if got := serve(); got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+	if got := serve(); got != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected %v", got, http.StatusServiceUnavailable)
+	}
+
+	// only low priority requests are arriving, so the average falls with time
+	now = now.Add(30 * time.Second)
+	if got := serve(); got != http.StatusOK {
+		t.Errorf("got %v, expected %v", got, http.StatusOK)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	p, ok := ParsePriority(" Critical")
+	if !ok {
+		t.Error("expected priority to parse")
+	}
+	if p != PriorityCritical {
+		t.Errorf("got %v, expected %v", p, PriorityCritical)
+	}
+
+	p, ok = ParsePriority("urgent")
+	if ok {
+		t.Error("expected unknown priority")
+	}
+	if p != PriorityNormal {
+		t.Errorf("got %v, expected %v", p, PriorityNormal)
+	}
+	if got := PriorityLow.String(); got != "low" {
+		t.Errorf("got %v, expected %v", got, "low")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/moov-io/base/throttle"
+)
+
+// StreamOptions configures ServeFileStream
+type StreamOptions struct {
+	// Filename is sent in the Content-Disposition header and used to detect the Content-Type
+	// by extension when ContentType is empty.
+	Filename string
+
+	// Inline sets a Content-Disposition of inline rather than attachment
+	Inline bool
+
+	ContentType string
+
+	// ModTime is used for Last-Modified and If-Modified-Since / If-Range handling
+	ModTime time.Time
+
+	// BytesPerSecond limits how fast the response body is written. Zero means unlimited.
+	BytesPerSecond int64
+}
+
+// ServeFileStream writes content as the response to r without loading it into memory.
+// Range and conditional requests are handled by http.ServeContent.
+func ServeFileStream(w http.ResponseWriter, r *http.Request, content io.ReadSeeker, opts StreamOptions) {
+	if opts.ContentType != "" {
+		w.Header().Set("Content-Type", opts.ContentType)
+	}
+	if opts.Filename != "" {
+		disposition := "attachment"
+		if opts.Inline {
+			disposition = "inline"
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{
+			"filename": opts.Filename,
+		}))
+	}
+	if opts.BytesPerSecond > 0 {
+		// refill in tenths of a second so large buffers are spread out, starting empty so the
+		// first chunk is paced too
+		burst := int(opts.BytesPerSecond / 10)
+		bucket := throttle.NewTokenBucket(throttle.Rate(opts.BytesPerSecond), burst)
+		bucket.AllowN(bucket.Burst())
+
+		w = &throttledWriter{
+			ResponseWriter: w,
+			body:           throttle.NewWriter(r.Context(), w, bucket),
+		}
+	}
+	http.ServeContent(w, r, opts.Filename, opts.ModTime, content)
+}
+
+// throttledWriter writes the response body through a throttle.Writer
+type throttledWriter struct {
+	http.ResponseWriter
+	body io.Writer
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	return w.body.Write(p)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeFileStream(t *testing.T) {
+	contents := strings.Repeat("0123456789", 100)
+	modTime := time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)
+
+	serve := func(req *http.Request, opts StreamOptions) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ServeFileStream(w, req, strings.NewReader(contents), opts)
+		w.Flush()
+		return w
+	}
+
+	w := serve(httptest.NewRequest("GET", "/reports/1", nil), StreamOptions{
+		Filename: "report.csv",
+		ModTime:  modTime,
+	})
+	if w.Code != http.StatusOK || w.Body.String() != contents {
+		t.Errorf("unexpected response: %d", w.Code)
+	}
+	if v := w.Header().Get("Content-Disposition"); v != `attachment; filename=report.csv` {
+		t.Errorf("unexpected Content-Disposition: %s", v)
+	}
+	if v := w.Header().Get("Content-Type"); v != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected Content-Type: %s", v)
+	}
+	if v := w.Header().Get("Last-Modified"); v != "Tue, 01 Dec 2020 10:00:00 GMT" {
+		t.Errorf("unexpected Last-Modified: %s", v)
+	}
+
+	// Range request
+	req := httptest.NewRequest("GET", "/reports/1", nil)
+	req.Header.Set("Range", "bytes=10-19")
+	w = serve(req, StreamOptions{Filename: "résumé.pdf", Inline: true, ContentType: "application/pdf"})
+	if w.Code != http.StatusPartialContent || w.Body.String() != "0123456789" {
+		t.Errorf("unexpected response: %d %q", w.Code, w.Body.String())
+	}
+	if v := w.Header().Get("Content-Range"); v != "bytes 10-19/1000" {
+		t.Errorf("unexpected Content-Range: %s", v)
+	}
+	if v := w.Header().Get("Content-Disposition"); v != `inline; filename*=utf-8''r%C3%A9sum%C3%A9.pdf` {
+		t.Errorf("unexpected Content-Disposition: %s", v)
+	}
+	if v := w.Header().Get("Content-Type"); v != "application/pdf" {
+		t.Errorf("unexpected Content-Type: %s", v)
+	}
+
+	// not modified
+	req = httptest.NewRequest("GET", "/reports/1", nil)
+	req.Header.Set("If-Modified-Since", modTime.Format(http.TimeFormat))
+	w = serve(req, StreamOptions{ModTime: modTime})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("unexpected response: %d", w.Code)
+	}
+}
+
+func TestServeFileStream__RateLimit(t *testing.T) {
+	contents := bytes.Repeat([]byte("a"), 2000)
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	ServeFileStream(w, httptest.NewRequest("GET", "/", nil), bytes.NewReader(contents), StreamOptions{
+		BytesPerSecond: 10000,
+	})
+	if diff := time.Since(start); diff < 150*time.Millisecond {
+		t.Errorf("response was too fast: %v", diff)
+	}
+	if w.Body.Len() != len(contents) {
+		t.Errorf("unexpected body length: %d", w.Body.Len())
+	}
+}
+
+func TestServeFileStream__Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	ServeFileStream(w, req, bytes.NewReader(bytes.Repeat([]byte("a"), 2000)), StreamOptions{
+		BytesPerSecond: 100,
+	})
+	if w.Body.Len() >= 2000 {
+		t.Errorf("expected a partial body: %d", w.Body.Len())
+	}
+}
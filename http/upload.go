@@ -0,0 +1,173 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	defaultUploadField   = "file"
+	defaultUploadMaxSize = 32 << 20 // 32MB
+)
+
+var (
+	// ErrNoFileUpload is returned when a multipart request has no part for the upload field
+	ErrNoFileUpload = errors.New("no file upload found")
+
+	// ErrUploadTooLarge is returned when an uploaded file is over UploadOptions.MaxSize
+	ErrUploadTooLarge = errors.New("file upload too large")
+)
+
+// UploadOptions configures ReadFileUpload
+type UploadOptions struct {
+	// FieldName is the form field of the file part. It defaults to "file".
+	FieldName string
+
+	// MaxSize is the largest file accepted in bytes. It defaults to 32MB.
+	MaxSize int64
+
+	// AllowedContentTypes limits uploads to files whose sniffed media type (i.e. "text/plain")
+	// is in the list. An empty list accepts any file.
+	AllowedContentTypes []string
+
+	// Writer receives the file's contents. When nil the file is written to a temporary file
+	// in TempDir (or the OS default) which the caller should Remove.
+	Writer  io.Writer
+	TempDir string
+}
+
+// FileUpload describes a file read by ReadFileUpload
+type FileUpload struct {
+	// Filename is the name given by the client, it should not be trusted as a path.
+	Filename string
+
+	// ContentType is sniffed from the file's contents by http.DetectContentType
+	ContentType string
+
+	Size   int64
+	SHA256 string // hex encoded
+
+	// Path is the temporary file holding the upload when UploadOptions.Writer was nil
+	Path string
+}
+
+// Remove deletes the temporary file of an upload, if one was created
+func (u *FileUpload) Remove() error {
+	if u == nil || u.Path == "" {
+		return nil
+	}
+	return os.Remove(u.Path)
+}
+
+// ReadFileUpload streams the file part of a multipart/form-data request without buffering it in memory.
+// The file's size and contents are checked against opts as it's read. For rejected files nothing is kept
+// on disk, although a provided Writer may have received part of the file.
+func ReadFileUpload(r *http.Request, opts UploadOptions) (*FileUpload, error) {
+	if opts.FieldName == "" {
+		opts.FieldName = defaultUploadField
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = defaultUploadMaxSize
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("reading file upload: %v", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, ErrNoFileUpload
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading file upload: %v", err)
+		}
+		if part.FormName() != opts.FieldName || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		upload, err := readPart(part, opts)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		upload.Filename = part.FileName()
+		return upload, nil
+	}
+}
+
+func readPart(part io.Reader, opts UploadOptions) (*FileUpload, error) {
+	buf := bufio.NewReaderSize(io.LimitReader(part, opts.MaxSize+1), 512)
+	sniff, _ := buf.Peek(512)
+
+	upload := &FileUpload{
+		ContentType: http.DetectContentType(sniff),
+	}
+	if !allowedContentType(upload.ContentType, opts.AllowedContentTypes) {
+		return nil, fmt.Errorf("file upload has unsupported content type %s", upload.ContentType)
+	}
+
+	w := opts.Writer
+	var fd *os.File
+	if w == nil {
+		var err error
+		fd, err = os.CreateTemp(opts.TempDir, "upload-*")
+		if err != nil {
+			return nil, fmt.Errorf("creating temp file for upload: %v", err)
+		}
+		upload.Path = fd.Name()
+		w = fd
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, hash), buf)
+	if err == nil && n > opts.MaxSize {
+		err = ErrUploadTooLarge
+	}
+	// close the temp file before it's removed, a failed Close can mean the file wasn't fully written
+	if fd != nil {
+		if cerr := fd.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		upload.Remove()
+		if err == ErrUploadTooLarge {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading file upload: %v", err)
+	}
+
+	upload.Size = n
+	upload.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return upload, nil
+}
+
+func allowedContentType(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for i := range allowed {
+		if strings.EqualFold(mediaType, allowed[i]) {
+			return true
+		}
+	}
+	return false
+}
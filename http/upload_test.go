@@ -0,0 +1,126 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func uploadRequest(t *testing.T, field, filename string, contents []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("description", "payroll")
+	part, err := w.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(contents)
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/files", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestReadFileUpload(t *testing.T) {
+	contents := []byte(strings.Repeat("101 076401251 0764012511807291511A094101\n", 100))
+	req := uploadRequest(t, "file", "ppd-debit.ach", contents)
+
+	upload, err := ReadFileUpload(req, UploadOptions{
+		AllowedContentTypes: []string{"text/plain"},
+		TempDir:             t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upload.Remove()
+
+	if upload.Filename != "ppd-debit.ach" || upload.Size != int64(len(contents)) {
+		t.Errorf("unexpected upload: %#v", upload)
+	}
+	if upload.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected content type: %s", upload.ContentType)
+	}
+	sum := sha256.Sum256(contents)
+	if upload.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected checksum: %s", upload.SHA256)
+	}
+	bs, err := os.ReadFile(upload.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, contents) {
+		t.Error("temp file doesn't match upload")
+	}
+
+	if err := upload.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(upload.Path); !os.IsNotExist(err) {
+		t.Errorf("expected temp file to be removed: %v", err)
+	}
+}
+
+func TestReadFileUpload__Writer(t *testing.T) {
+	req := uploadRequest(t, "statement", "statement.pdf", []byte("%PDF-1.4 ..."))
+
+	var buf bytes.Buffer
+	upload, err := ReadFileUpload(req, UploadOptions{
+		FieldName:           "statement",
+		AllowedContentTypes: []string{"application/pdf"},
+		Writer:              &buf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.Path != "" || upload.ContentType != "application/pdf" || buf.String() != "%PDF-1.4 ..." {
+		t.Errorf("unexpected upload: %#v", upload)
+	}
+}
+
+func TestReadFileUpload__Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	// too large
+	req := uploadRequest(t, "file", "big.txt", bytes.Repeat([]byte("a"), 1025))
+	if _, err := ReadFileUpload(req, UploadOptions{MaxSize: 1024, TempDir: dir}); err != ErrUploadTooLarge {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// content type
+	req = uploadRequest(t, "file", "image.png", []byte("\x89PNG\x0D\x0A\x1A\x0A"))
+	_, err := ReadFileUpload(req, UploadOptions{AllowedContentTypes: []string{"text/plain"}, TempDir: dir})
+	if err == nil || !strings.Contains(err.Error(), "image/png") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// missing field
+	req = uploadRequest(t, "other", "file.txt", []byte("data"))
+	if _, err := ReadFileUpload(req, UploadOptions{TempDir: dir}); err != ErrNoFileUpload {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// not multipart
+	req = httptest.NewRequest("POST", "/files", strings.NewReader("data"))
+	if _, err := ReadFileUpload(req, UploadOptions{TempDir: dir}); err == nil {
+		t.Error("expected error")
+	}
+
+	// nothing should be left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("found %d leftover files", len(entries))
+	}
+}
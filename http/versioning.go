@@ -0,0 +1,175 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base/ctxkeys"
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/version"
+)
+
+const defaultVersionHeader = "X-Api-Version"
+
+var (
+	apiVersionKey = ctxkeys.New[version.Version]("api-version")
+
+	versionPrefix = regexp.MustCompile(`^/v(\d+(?:\.\d+)?)(/|$)`)
+)
+
+// VersionConfig describes how Versioned reads the API version of requests
+type VersionConfig struct {
+	// Header holds the requested version, it defaults to X-Api-Version.
+	Header string
+
+	// PathPrefix reads the version from a "/v2" or "/v2.1" prefix, which is removed before
+	// calling the handler. The header takes precedence when both are given.
+	PathPrefix bool
+
+	// Default is used for requests without a version. Requests must specify one when it's empty.
+	Default string
+
+	// Logger records requests made against deprecated versions when set
+	Logger log.Logger
+}
+
+// APIVersion is a Handler serving a version of an API
+type APIVersion struct {
+	Version string
+	Handler http.Handler
+
+	// Sunset is when the version will be removed. Responses are marked deprecated with
+	// Deprecation and Sunset (RFC 8594) headers when it's set.
+	Sunset time.Time
+
+	// Link is documentation about the deprecation, such as a migration guide.
+	Link string
+}
+
+type registeredVersion struct {
+	APIVersion
+	version version.Version
+}
+
+// Versioned returns an http.Handler which routes requests to the newest of versions that doesn't
+// exceed the requested version within the same major version. A request for 2.3 is served by 2.1
+// when 2.0 and 2.1 are registered. The selected version is returned from GetAPIVersion and the
+// version header of the response.
+//
+// Requests for versions without a handler are rejected with a 400 response.
+func Versioned(cfg VersionConfig, versions ...APIVersion) (http.Handler, error) {
+	if cfg.Header == "" {
+		cfg.Header = defaultVersionHeader
+	}
+	registered := make([]registeredVersion, len(versions))
+	for i := range versions {
+		v, err := version.Parse(versions[i].Version)
+		if err != nil {
+			return nil, err
+		}
+		if versions[i].Handler == nil {
+			return nil, fmt.Errorf("missing handler for API version %s", versions[i].Version)
+		}
+		registered[i] = registeredVersion{APIVersion: versions[i], version: v}
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[j].version.LessThan(registered[i].version)
+	})
+
+	var fallback *version.Version
+	if cfg.Default != "" {
+		v, err := version.Parse(cfg.Default)
+		if err != nil {
+			return nil, err
+		}
+		fallback = &v
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested, r, err := requestedVersion(cfg, r)
+		if err != nil {
+			Problem(w, err)
+			return
+		}
+		if requested == nil {
+			if fallback == nil {
+				Problem(w, fmt.Errorf("missing %s header", cfg.Header))
+				return
+			}
+			requested = fallback
+		}
+
+		for i := range registered {
+			v := registered[i]
+			if v.version.Major != requested.Major || requested.LessThan(v.version) {
+				continue
+			}
+			w.Header().Set(cfg.Header, v.Version)
+			if !v.Sunset.IsZero() {
+				deprecated(cfg.Logger, w, r, v.APIVersion)
+			}
+			r = r.WithContext(apiVersionKey.Set(r.Context(), v.version))
+			v.Handler.ServeHTTP(w, r)
+			return
+		}
+		Problem(w, fmt.Errorf("unsupported API version %s", requested))
+	}), nil
+}
+
+// requestedVersion reads the version from the header or path prefix. The request is returned
+// without the prefix.
+func requestedVersion(cfg VersionConfig, r *http.Request) (*version.Version, *http.Request, error) {
+	var raw string
+	if cfg.PathPrefix {
+		if m := versionPrefix.FindStringSubmatch(r.URL.Path); m != nil {
+			raw = m[1]
+
+			u := *r.URL
+			u.Path = "/" + strings.TrimPrefix(r.URL.Path[len(m[0]):], "/")
+			u.RawPath = ""
+			r2 := r.Clone(r.Context())
+			r2.URL = &u
+			r = r2
+		}
+	}
+	if header := strings.TrimSpace(r.Header.Get(cfg.Header)); header != "" {
+		raw = header
+	}
+	if raw == "" {
+		return nil, r, nil
+	}
+	v, err := version.Parse(raw)
+	if err != nil {
+		return nil, r, fmt.Errorf("invalid API version %q", raw)
+	}
+	return &v, r, nil
+}
+
+func deprecated(logger log.Logger, w http.ResponseWriter, r *http.Request, v APIVersion) {
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+	if v.Link != "" {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, v.Link))
+	}
+	if logger != nil {
+		logger.Warn().With(log.Fields{
+			"apiVersion": log.String(v.Version),
+			"sunset":     log.Time(v.Sunset),
+			"path":       log.String(r.URL.Path),
+			"requestID":  log.String(GetRequestID(r)),
+		}).Logf("request made against deprecated API version %s", v.Version)
+	}
+}
+
+// GetAPIVersion returns the version selected by Versioned for the request
+func GetAPIVersion(r *http.Request) (version.Version, bool) {
+	return apiVersionKey.Get(r.Context())
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+)
+
+func versionEcho(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, _ := GetAPIVersion(r)
+		fmt.Fprintf(w, "%s %s %s", name, v, r.URL.Path)
+	})
+}
+
+func TestVersioned(t *testing.T) {
+	buf, logger := log.NewBufferLogger()
+	sunset := time.Date(2021, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	handler, err := Versioned(VersionConfig{PathPrefix: true, Default: "1", Logger: logger},
+		APIVersion{Version: "1.0", Handler: versionEcho("v1"), Sunset: sunset, Link: "https://docs.example.com/v2-migration"},
+		APIVersion{Version: "2.0", Handler: versionEcho("v2.0")},
+		APIVersion{Version: "2.1", Handler: versionEcho("v2.1")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		path, header string
+		status       int
+		body         string
+	}{
+		{"/transfers", "", http.StatusOK, "v1 1.0.0 /transfers"},
+		{"/v2/transfers", "", http.StatusOK, "v2.0 2.0.0 /transfers"},
+		{"/v2.3/transfers", "", http.StatusOK, "v2.1 2.1.0 /transfers"},
+		{"/v2/transfers", "2.1", http.StatusOK, "v2.1 2.1.0 /transfers"},
+		{"/transfers", "1.4", http.StatusOK, "v1 1.0.0 /transfers"},
+		{"/v2", "", http.StatusOK, "v2.0 2.0.0 /"},
+		{"/transfers", "3", http.StatusBadRequest, "unsupported API version 3.0.0"},
+		{"/transfers", "latest", http.StatusBadRequest, `invalid API version \"latest\"`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set("X-Api-Version", tc.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tc.status || !strings.Contains(w.Body.String(), tc.body) {
+			t.Errorf("%s (%q): got %d %s", tc.path, tc.header, w.Code, w.Body.String())
+		}
+	}
+
+	// deprecated version
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/v1/transfers", nil))
+	if v := w.Header().Get("X-Api-Version"); v != "1.0" {
+		t.Errorf("unexpected version header: %q", v)
+	}
+	if v := w.Header().Get("Deprecation"); v != "true" {
+		t.Errorf("unexpected Deprecation: %q", v)
+	}
+	if v := w.Header().Get("Sunset"); v != "Wed, 30 Jun 2021 00:00:00 GMT" {
+		t.Errorf("unexpected Sunset: %q", v)
+	}
+	if v := w.Header().Get("Link"); v != `<https://docs.example.com/v2-migration>; rel="deprecation"` {
+		t.Errorf("unexpected Link: %q", v)
+	}
+	if !strings.Contains(buf.String(), "deprecated API version 1.0") {
+		t.Errorf("unexpected logs: %s", buf.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/v2/transfers", nil))
+	if v := w.Header().Get("Sunset"); v != "" {
+		t.Errorf("unexpected Sunset: %q", v)
+	}
+}
+
+func TestVersioned__Required(t *testing.T) {
+	handler, err := Versioned(VersionConfig{Header: "Api-Version"}, APIVersion{Version: "1", Handler: versionEcho("v1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/v1/transfers", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "missing Api-Version header") {
+		t.Errorf("got %d %s", w.Code, w.Body.String())
+	}
+
+	if _, err := Versioned(VersionConfig{}, APIVersion{Version: "one", Handler: versionEcho("v1")}); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := Versioned(VersionConfig{}, APIVersion{Version: "1"}); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolWorkers = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "jobs_pool_workers",
+		Help: "Gauge of running workers in a pool",
+	}, []string{"pool"})
+
+	poolQueueDepth = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "jobs_pool_queue_depth",
+		Help: "Gauge of jobs waiting for a worker",
+	}, []string{"pool"})
+)
+
+// ErrPoolClosed is returned from Submit after Close
+var ErrPoolClosed = errors.New("jobs: pool closed")
+
+// Job is work run by a Pool. ctx is the context given to Submit.
+type Job func(ctx context.Context)
+
+// PoolConfig describes how a Pool sizes itself
+type PoolConfig struct {
+	// Name labels the pool's metrics
+	Name string
+
+	// MinWorkers are always running. It defaults to 1. MaxWorkers defaults to MinWorkers,
+	// which disables scaling.
+	MinWorkers int
+	MaxWorkers int
+
+	// QueueSize is how many jobs wait for a worker before Submit blocks. It defaults to
+	// 10 times MaxWorkers.
+	QueueSize int
+
+	// Interval is how often the pool is resized. It defaults to one second.
+	Interval time.Duration
+
+	// Workers are added when more jobs are waiting than QueueDepth per worker (default 1) or
+	// jobs waited longer than QueueLatency on average (zero doesn't check) since the last check.
+	QueueDepth   int
+	QueueLatency time.Duration
+
+	// ScaleUpCooldown and ScaleDownCooldown are the least time between resizes in each
+	// direction. They default to Interval and one minute, so bursts are answered quickly
+	// but workers aren't stopped until the burst has passed.
+	ScaleUpCooldown   time.Duration
+	ScaleDownCooldown time.Duration
+}
+
+// Pool runs jobs on a number of workers between PoolConfig.MinWorkers and MaxWorkers, growing
+// while jobs are queued and shrinking once workers sit idle.
+type Pool struct {
+	cfg   PoolConfig
+	queue chan queued
+	stop  chan struct{} // a worker exits for each value
+	done  chan struct{}
+
+	workers    sync.WaitGroup
+	scaler     sync.WaitGroup
+	submitting sync.WaitGroup
+
+	mu       sync.Mutex
+	size     int
+	busy     int
+	waited   time.Duration // total queue time of jobs started since the last check
+	started  int
+	lastUp   time.Time
+	lastDown time.Time
+	closed   bool
+}
+
+type queued struct {
+	ctx context.Context
+	job Job
+	at  time.Time
+}
+
+// NewPool starts a Pool with MinWorkers running
+func NewPool(cfg PoolConfig) (*Pool, error) {
+	if cfg.MinWorkers <= 0 {
+		cfg.MinWorkers = 1
+	}
+	if cfg.MaxWorkers <= 0 {
+		cfg.MaxWorkers = cfg.MinWorkers
+	}
+	if cfg.MaxWorkers < cfg.MinWorkers {
+		return nil, errors.New("jobs: MaxWorkers is less than MinWorkers")
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10 * cfg.MaxWorkers
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.QueueDepth <= 0 {
+		cfg.QueueDepth = 1
+	}
+	if cfg.ScaleUpCooldown <= 0 {
+		cfg.ScaleUpCooldown = cfg.Interval
+	}
+	if cfg.ScaleDownCooldown <= 0 {
+		cfg.ScaleDownCooldown = time.Minute
+	}
+
+	p := &Pool{
+		cfg:   cfg,
+		queue: make(chan queued, cfg.QueueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	p.mu.Lock()
+	p.resize(cfg.MinWorkers)
+	p.mu.Unlock()
+
+	if cfg.MaxWorkers > cfg.MinWorkers {
+		p.scaler.Add(1)
+		go p.autoscale()
+	}
+	return p, nil
+}
+
+// Submit queues job, blocking while the queue is full. ctx is passed to job and stops
+// Submit from waiting.
+func (p *Pool) Submit(ctx context.Context, job Job) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	p.submitting.Add(1)
+	p.mu.Unlock()
+	defer p.submitting.Done()
+
+	select {
+	case p.queue <- queued{ctx: ctx, job: job, at: time.Now()}:
+		poolQueueDepth.With("pool", p.cfg.Name).Set(float64(len(p.queue)))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return ErrPoolClosed
+	}
+}
+
+// Size returns the number of running workers
+func (p *Pool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// Close stops accepting jobs and waits for queued jobs to finish
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.done)
+	p.mu.Unlock()
+
+	p.scaler.Wait()
+	p.submitting.Wait()
+	close(p.queue)
+	p.workers.Wait()
+	poolWorkers.With("pool", p.cfg.Name).Set(0)
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for {
+		select {
+		case q, ok := <-p.queue:
+			if !ok {
+				return
+			}
+			p.mu.Lock()
+			p.busy++
+			p.started++
+			p.waited += time.Since(q.at)
+			p.mu.Unlock()
+
+			q.job(q.ctx)
+
+			p.mu.Lock()
+			p.busy--
+			p.mu.Unlock()
+
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// resize starts or stops workers until size are running. p.mu must be held.
+func (p *Pool) resize(size int) {
+	for p.size < size {
+		p.size++
+		p.workers.Add(1)
+		go p.work()
+	}
+	for p.size > size {
+		p.size--
+		go func() {
+			select {
+			case p.stop <- struct{}{}:
+			case <-p.done:
+			}
+		}()
+	}
+	poolWorkers.With("pool", p.cfg.Name).Set(float64(p.size))
+}
+
+func (p *Pool) autoscale() {
+	defer p.scaler.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			p.scale(now)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// scale grows the pool by half (at least one worker) when jobs are backing up and shrinks it
+// by one worker when the queue is empty and workers are idle.
+func (p *Pool) scale(now time.Time) {
+	depth := len(p.queue)
+	poolQueueDepth.With("pool", p.cfg.Name).Set(float64(depth))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var latency time.Duration
+	if p.started > 0 {
+		latency = p.waited / time.Duration(p.started)
+	}
+	p.waited, p.started = 0, 0
+
+	behind := depth > p.size*p.cfg.QueueDepth || (p.cfg.QueueLatency > 0 && latency > p.cfg.QueueLatency)
+	switch {
+	case behind && p.size < p.cfg.MaxWorkers:
+		if now.Sub(p.lastUp) < p.cfg.ScaleUpCooldown {
+			return
+		}
+		grow := p.size / 2
+		if grow < 1 {
+			grow = 1
+		}
+		size := p.size + grow
+		if size > p.cfg.MaxWorkers {
+			size = p.cfg.MaxWorkers
+		}
+		p.resize(size)
+		p.lastUp = now
+
+	case depth == 0 && p.busy < p.size && p.size > p.cfg.MinWorkers:
+		if now.Sub(p.lastUp) < p.cfg.ScaleDownCooldown || now.Sub(p.lastDown) < p.cfg.ScaleDownCooldown {
+			return
+		}
+		p.resize(p.size - 1)
+		p.lastDown = now
+	}
+}
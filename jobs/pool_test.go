@@ -0,0 +1,162 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	p, err := NewPool(PoolConfig{MinWorkers: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, p.Size())
+
+	var ran int32
+	for i := 0; i < 50; i++ {
+		err := p.Submit(context.Background(), func(ctx context.Context) {
+			atomic.AddInt32(&ran, 1)
+		})
+		require.NoError(t, err)
+	}
+	p.Close()
+	require.Equal(t, int32(50), atomic.LoadInt32(&ran))
+
+	require.ErrorIs(t, p.Submit(context.Background(), func(ctx context.Context) {}), ErrPoolClosed)
+	p.Close()
+}
+
+func TestPool__Invalid(t *testing.T) {
+	_, err := NewPool(PoolConfig{MinWorkers: 4, MaxWorkers: 2})
+	require.Error(t, err)
+}
+
+func TestPool__SubmitCancelled(t *testing.T) {
+	p, err := NewPool(PoolConfig{QueueSize: 1})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	block := func(ctx context.Context) { <-release }
+	require.NoError(t, p.Submit(context.Background(), block))
+
+	// the worker may not have taken the first job yet, so fill the queue
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFunc()
+	var err2 error
+	for err2 == nil {
+		err2 = p.Submit(ctx, block)
+	}
+	require.ErrorIs(t, err2, context.DeadlineExceeded)
+
+	close(release)
+	p.Close()
+}
+
+// waitFor polls cond, as workers pick up jobs asynchronously
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	require.Eventually(t, cond, time.Second, time.Millisecond)
+}
+
+func TestPool__Scale(t *testing.T) {
+	p, err := NewPool(PoolConfig{
+		MinWorkers:        1,
+		MaxWorkers:        4,
+		Interval:          time.Hour, // scale is called by the test
+		ScaleUpCooldown:   time.Second,
+		ScaleDownCooldown: time.Minute,
+	})
+	require.NoError(t, err)
+	defer p.Close()
+
+	release := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+			<-release
+		}))
+	}
+
+	start := time.Now()
+	p.scale(start)
+	require.Equal(t, 2, p.Size())
+
+	p.scale(start.Add(500 * time.Millisecond)) // cooling down
+	require.Equal(t, 2, p.Size())
+
+	p.scale(start.Add(2 * time.Second))
+	require.Equal(t, 3, p.Size())
+	p.scale(start.Add(4 * time.Second))
+	require.Equal(t, 4, p.Size())
+	p.scale(start.Add(6 * time.Second)) // at MaxWorkers
+	require.Equal(t, 4, p.Size())
+
+	close(release)
+	waitFor(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.queue) == 0 && p.busy == 0
+	})
+
+	p.scale(start.Add(30 * time.Second)) // too soon after growing
+	require.Equal(t, 4, p.Size())
+	p.scale(start.Add(2 * time.Minute))
+	require.Equal(t, 3, p.Size())
+	p.scale(start.Add(2*time.Minute + time.Second))
+	require.Equal(t, 3, p.Size())
+	p.scale(start.Add(4 * time.Minute))
+	p.scale(start.Add(6 * time.Minute))
+	p.scale(start.Add(8 * time.Minute)) // at MinWorkers
+	require.Equal(t, 1, p.Size())
+
+	// stopped workers exit
+	var ran int32
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		atomic.AddInt32(&ran, 1)
+	}))
+	waitFor(t, func() bool { return atomic.LoadInt32(&ran) == 1 })
+}
+
+func TestPool__ScaleLatency(t *testing.T) {
+	p, err := NewPool(PoolConfig{
+		MinWorkers:   1,
+		MaxWorkers:   2,
+		Interval:     time.Hour,
+		QueueLatency: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer p.Close()
+
+	// the queue is empty but jobs waited too long to start
+	p.mu.Lock()
+	p.started, p.waited = 2, 500*time.Millisecond
+	p.mu.Unlock()
+
+	p.scale(time.Now())
+	require.Equal(t, 2, p.Size())
+}
+
+func TestPool__Autoscale(t *testing.T) {
+	p, err := NewPool(PoolConfig{
+		MinWorkers: 1,
+		MaxWorkers: 3,
+		Interval:   5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	for i := 0; i < 6; i++ {
+		require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+			<-release
+		}))
+	}
+	waitFor(t, func() bool { return p.Size() == 3 })
+
+	close(release)
+	p.Close()
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package jobs implements helpers for scheduling recurring work such as end-of-day jobs and
+// running it on pools of workers which scale with the queue.
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/stime"
+)
+
+// maxSearchDays bounds how far ahead a Ticker looks for the next banking day.
+const maxSearchDays = 31
+
+// Ticker delivers a tick once per banking day at a wall clock time. Ticks are dropped if the
+// receiver falls behind, like a time.Ticker.
+type Ticker struct {
+	// C delivers the scheduled time of each tick. It's closed after Stop or when the calendar
+	// returns an error (see Err).
+	C <-chan base.Time
+
+	c        chan base.Time
+	at       time.Duration
+	calendar base.Calendar
+	loc      *time.Location
+
+	clock stime.TimeService
+	after func(time.Duration) <-chan time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// BankingDayTicker returns a Ticker which fires at the given wall clock offset from midnight
+// in the local time zone (i.e. 17h30m for 5:30pm) on every banking day of calendar.
+//
+// Weekends and holidays are skipped. The wall clock time is kept across DST changes, so a 5:30pm
+// job stays at 5:30pm on days which are 23 or 25 hours long.
+//
+// base.FederalReserveCalendar is used when calendar is nil.
+func BankingDayTicker(at time.Duration, calendar base.Calendar) *Ticker {
+	return BankingDayTickerIn(at, calendar, time.Local)
+}
+
+// BankingDayTickerIn is like BankingDayTicker but the wall clock time is read in loc.
+func BankingDayTickerIn(at time.Duration, calendar base.Calendar, loc *time.Location) *Ticker {
+	t := newTicker(at, calendar, loc, stime.NewSystemTimeService(), time.After)
+	go t.run()
+	return t
+}
+
+func newTicker(at time.Duration, calendar base.Calendar, loc *time.Location, clock stime.TimeService, after func(time.Duration) <-chan time.Time) *Ticker {
+	if calendar == nil {
+		calendar = base.FederalReserveCalendar()
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	c := make(chan base.Time, 1)
+	return &Ticker{
+		C:        c,
+		c:        c,
+		at:       at,
+		calendar: calendar,
+		loc:      loc,
+		clock:    clock,
+		after:    after,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Stop turns off the Ticker and closes C. It's safe to call multiple times.
+func (t *Ticker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+// Err returns the error which stopped the Ticker, if any.
+func (t *Ticker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Ticker) run() {
+	defer close(t.c)
+	for {
+		now := t.clock.Now()
+		next, err := t.next(now)
+		if err != nil {
+			t.mu.Lock()
+			t.err = err
+			t.mu.Unlock()
+			return
+		}
+
+		select {
+		case <-t.stop:
+			return
+		case <-t.after(next.Sub(now)):
+		}
+
+		select {
+		case t.c <- base.NewTime(next):
+		default:
+		}
+	}
+}
+
+// next returns the first scheduled time after now which is on a banking day.
+func (t *Ticker) next(now time.Time) (time.Time, error) {
+	local := now.In(t.loc)
+	day := base.DateOf(local)
+	for i := 0; i < maxSearchDays; i++ {
+		fire := time.Date(day.Year, day.Month, day.Day, 0, 0, 0, int(t.at), t.loc)
+		if fire.After(now) {
+			ok, err := t.calendar.IsBankingDay(day)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if ok {
+				return fire, nil
+			}
+		}
+		day = day.AddDays(1)
+	}
+	return time.Time{}, fmt.Errorf("no banking day found within %d days of %s", maxSearchDays, base.DateOf(local))
+}
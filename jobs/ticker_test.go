@@ -0,0 +1,110 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/stime"
+
+	"github.com/stretchr/testify/require"
+)
+
+var eastern, _ = time.LoadLocation("America/New_York")
+
+func TestTicker__next(t *testing.T) {
+	ticker := newTicker(17*time.Hour+30*time.Minute, nil, eastern, nil, nil)
+
+	tests := []struct {
+		now, expected time.Time
+	}{
+		// Tuesday morning fires that evening
+		{time.Date(2020, time.November, 10, 9, 0, 0, 0, eastern), time.Date(2020, time.November, 10, 17, 30, 0, 0, eastern)},
+		// Tuesday evening skips Veterans Day
+		{time.Date(2020, time.November, 10, 17, 30, 0, 0, eastern), time.Date(2020, time.November, 12, 17, 30, 0, 0, eastern)},
+		// Friday evening skips the weekend
+		{time.Date(2020, time.November, 13, 20, 0, 0, 0, eastern), time.Date(2020, time.November, 16, 17, 30, 0, 0, eastern)},
+		// Friday before clocks fall back keeps the wall clock time on Monday
+		{time.Date(2020, time.October, 30, 18, 0, 0, 0, eastern), time.Date(2020, time.November, 2, 17, 30, 0, 0, eastern)},
+		// Friday before clocks spring forward
+		{time.Date(2021, time.March, 12, 18, 0, 0, 0, eastern), time.Date(2021, time.March, 15, 17, 30, 0, 0, eastern)},
+	}
+	for _, test := range tests {
+		next, err := ticker.next(test.now)
+		require.NoError(t, err)
+		require.True(t, test.expected.Equal(next), "now=%v expected=%v got=%v", test.now, test.expected, next)
+	}
+}
+
+type closedCalendar struct{}
+
+func (closedCalendar) IsBankingDay(d base.Date) (bool, error) {
+	return false, nil
+}
+
+type brokenCalendar struct{}
+
+func (brokenCalendar) IsBankingDay(d base.Date) (bool, error) {
+	return false, errors.New("bad calendar")
+}
+
+func TestTicker__nextErrors(t *testing.T) {
+	now := time.Date(2020, time.November, 10, 9, 0, 0, 0, eastern)
+
+	_, err := newTicker(time.Hour, closedCalendar{}, eastern, nil, nil).next(now)
+	require.Error(t, err)
+
+	_, err = newTicker(time.Hour, brokenCalendar{}, eastern, nil, nil).next(now)
+	require.EqualError(t, err, "bad calendar")
+}
+
+func TestTicker__run(t *testing.T) {
+	clock := stime.NewStaticTimeService()
+	clock.Change(time.Date(2020, time.November, 10, 9, 0, 0, 0, eastern))
+
+	waits := make(chan time.Duration, 10)
+	fire := make(chan time.Time)
+	after := func(d time.Duration) <-chan time.Time {
+		waits <- d
+		clock.Add(d)
+		return fire
+	}
+
+	ticker := newTicker(17*time.Hour, nil, eastern, clock, after)
+	go ticker.run()
+
+	require.Equal(t, 8*time.Hour, <-waits)
+	fire <- time.Now()
+	tick := <-ticker.C
+	require.Equal(t, base.NewDate(2020, time.November, 10), base.DateOf(tick.In(eastern)))
+
+	// Veterans Day is skipped
+	require.Equal(t, 48*time.Hour, <-waits)
+	ticker.Stop()
+	ticker.Stop()
+
+	_, ok := <-ticker.C
+	require.False(t, ok)
+	require.NoError(t, ticker.Err())
+}
+
+func TestTicker__runErr(t *testing.T) {
+	ticker := newTicker(time.Hour, brokenCalendar{}, eastern, stime.NewSystemTimeService(), time.After)
+	go ticker.run()
+
+	_, ok := <-ticker.C
+	require.False(t, ok)
+	require.Error(t, ticker.Err())
+}
+
+func TestBankingDayTicker(t *testing.T) {
+	ticker := BankingDayTicker(time.Hour, nil)
+	ticker.Stop()
+	for range ticker.C {
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// EncodeCanonical returns a deterministic encoding of v for signing or hashing. Object keys are
+// sorted, insignificant whitespace is removed and HTML characters are not escaped. Numbers are
+// kept as encoding/json writes them. Use Canonicalize for RFC 8785 output shared with other
+// languages.
+func EncodeCanonical(v interface{}) ([]byte, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseValue(bs)
+	if err != nil {
+		return nil, err
+	}
+	return encodeValue(value)
+}
+
+func writeCanonical(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		return writeString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, v[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeString(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("jsonx: unexpected %T", value)
+	}
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // Encode adds a newline
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package jsonx implements strict JSON decoding for request bodies, canonical encoding for signatures
+// and newline delimited JSON streams for bulk imports and exports.
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxBodySize is the limit of DecodeStrict
+const DefaultMaxBodySize = 1 << 20 // 1MB
+
+// TooLargeError is returned when a body is over the size limit
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("body is larger than %d bytes", e.Limit)
+}
+
+// DecodeError describes why a body couldn't be decoded. Path is the dotted path of the
+// field ("body.amount.value") when the error is specific to one.
+type DecodeError struct {
+	Path    string
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	return e.Path + " " + e.Message
+}
+
+// DecodeStrict decodes a single JSON value from r into v, which is limited to DefaultMaxBodySize.
+// See DecodeStrictLimit.
+func DecodeStrict(r io.Reader, v interface{}) error {
+	return DecodeStrictLimit(r, v, DefaultMaxBodySize)
+}
+
+// DecodeStrictLimit decodes a single JSON value from r into v. Unknown fields and trailing data
+// are rejected. Bodies over limit bytes return a *TooLargeError and invalid bodies return
+// a *DecodeError such as "body.amount must be a string".
+func DecodeStrictLimit(r io.Reader, v interface{}, limit int64) error {
+	lr := &limitedReader{r: r, left: limit, limit: limit}
+
+	dec := json.NewDecoder(lr)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+
+	// only whitespace may follow the value
+	var extra json.RawMessage
+	if err := dec.Decode(&extra); err != io.EOF {
+		var tooLarge *TooLargeError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return &DecodeError{Path: "body", Message: "must contain a single JSON value"}
+	}
+	return nil
+}
+
+func decodeError(err error) error {
+	var tooLarge *TooLargeError
+	if errors.As(err, &tooLarge) {
+		return tooLarge
+	}
+	if err == io.EOF {
+		return &DecodeError{Path: "body", Message: "must not be empty"}
+	}
+	if err == io.ErrUnexpectedEOF {
+		return &DecodeError{Path: "body", Message: "is not valid JSON: unexpected end of input"}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &DecodeError{
+			Path:    "body",
+			Message: fmt.Sprintf("is not valid JSON: %s at offset %d", strings.TrimPrefix(syntaxErr.Error(), "json: "), syntaxErr.Offset),
+		}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		path := "body"
+		if typeErr.Field != "" {
+			path += "." + typeErr.Field
+		}
+		return &DecodeError{Path: path, Message: "must be " + describe(typeErr.Type)}
+	}
+
+	// encoding/json has no type for unknown fields
+	if msg := err.Error(); strings.HasPrefix(msg, `json: unknown field "`) {
+		field := strings.TrimSuffix(strings.TrimPrefix(msg, `json: unknown field "`), `"`)
+		return &DecodeError{Path: "body." + field, Message: "is not a known field"}
+	}
+
+	return &DecodeError{Path: "body", Message: strings.TrimPrefix(err.Error(), "json: ")}
+}
+
+// describe returns how a JSON value of t is written
+func describe(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return fmt.Sprintf("a %s", t)
+}
+
+// limitedReader returns a *TooLargeError once more than limit bytes are read
+type limitedReader struct {
+	r     io.Reader
+	left  int64
+	limit int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, &TooLargeError{Limit: l.limit}
+	}
+	// read one byte past the limit to know if there's more
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, &TooLargeError{Limit: l.limit}
+	}
+	return n, err
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/moov-io/base/bufpool"
+)
+
+// Canonicalize returns data in the JSON Canonicalization Scheme (RFC 8785), which other
+// languages implement too, so signatures and hashes computed here verify elsewhere.
+//
+// Unlike EncodeCanonical numbers are rewritten as IEEE 754 doubles the way JavaScript prints
+// them, so integers beyond 2^53 lose precision. Duplicate object keys and invalid UTF-8 are
+// rejected.
+func Canonicalize(data []byte) ([]byte, error) {
+	buf := bufpool.GetBuffer()
+	defer bufpool.PutBuffer(buf)
+
+	if err := CanonicalizeStream(buf, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// CanonicalizeStream reads one JSON value from r and writes its RFC 8785 form to w. Arrays are
+// written as their elements are read, so memory is bounded by the largest object rather than
+// the whole payload. Objects are held until their last member is read as keys must be sorted.
+func CanonicalizeStream(w io.Writer, r io.Reader) error {
+	dec := json.NewDecoder(&utf8Checker{r: r})
+	dec.UseNumber()
+
+	bw := bufio.NewWriter(w)
+	if err := canonicalize(bw, dec); err != nil {
+		return fmt.Errorf("jsonx: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("jsonx: data after the JSON value")
+	}
+	return bw.Flush()
+}
+
+var errInvalidUTF8 = errors.New("invalid UTF-8")
+
+// utf8Checker fails reads of invalid UTF-8, which encoding/json would replace with U+FFFD
+// and so change what's signed
+type utf8Checker struct {
+	r       io.Reader
+	partial []byte
+}
+
+func (c *utf8Checker) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	data := append(c.partial, p[:n]...)
+	end := len(data)
+	if err == nil {
+		// an incomplete sequence at the end may be completed by the next read
+		for i := len(data) - 1; i >= 0 && i > len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					end = i
+				}
+				break
+			}
+		}
+	}
+	if !utf8.Valid(data[:end]) {
+		return 0, errInvalidUTF8
+	}
+	c.partial = append(c.partial[:0], data[end:]...)
+	return n, err
+}
+
+func canonicalize(w *bufio.Writer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	switch v := tok.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		s, err := formatNumber(v)
+		if err != nil {
+			return err
+		}
+		w.WriteString(s)
+	case string:
+		writeJCSString(w, v)
+	case json.Delim:
+		if v == '[' {
+			return canonicalizeArray(w, dec)
+		}
+		return canonicalizeObject(w, dec)
+	}
+	return nil
+}
+
+func canonicalizeArray(w *bufio.Writer, dec *json.Decoder) error {
+	w.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		if err := canonicalize(w, dec); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // ]
+		return err
+	}
+	w.WriteByte(']')
+	return nil
+}
+
+type member struct {
+	key   string
+	order []uint16
+	value []byte
+}
+
+func canonicalizeObject(w *bufio.Writer, dec *json.Decoder) error {
+	var members []member
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		if seen[key] {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		seen[key] = true
+
+		var value bytes.Buffer
+		vw := bufio.NewWriter(&value)
+		if err := canonicalize(vw, dec); err != nil {
+			return err
+		}
+		vw.Flush()
+		members = append(members, member{key: key, order: utf16.Encode([]rune(key)), value: value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil { // }
+		return err
+	}
+
+	// keys are sorted by their UTF-16 code units, as JavaScript compares strings
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i].order, members[j].order
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	w.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		writeJCSString(w, m.key)
+		w.WriteByte(':')
+		w.Write(m.value)
+	}
+	w.WriteByte('}')
+	return nil
+}
+
+// writeJCSString escapes only quotes, backslashes and control characters
+func writeJCSString(w *bufio.Writer, s string) {
+	w.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			w.WriteString(`\"`)
+		case '\\':
+			w.WriteString(`\\`)
+		case '\b':
+			w.WriteString(`\b`)
+		case '\f':
+			w.WriteString(`\f`)
+		case '\n':
+			w.WriteString(`\n`)
+		case '\r':
+			w.WriteString(`\r`)
+		case '\t':
+			w.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(w, `\u%04x`, r)
+			} else {
+				w.WriteRune(r)
+			}
+		}
+	}
+	w.WriteByte('"')
+}
+
+// formatNumber writes n like JavaScript's Number.prototype.toString (ECMA-262 7.1.12.1)
+func formatNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("number %s can't be represented as a double", n)
+	}
+	if f == 0 {
+		return "0", nil // including -0
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	// the shortest digits which round trip and their exponent, "d.ddde±x"
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(e, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+
+	k, pos := len(digits), x+1 // pos is where the decimal point goes
+	switch {
+	case k <= pos && pos <= 21:
+		return sign + digits + strings.Repeat("0", pos-k), nil
+	case 0 < pos && pos <= 21:
+		return sign + digits[:pos] + "." + digits[pos:], nil
+	case -6 < pos && pos <= 0:
+		return sign + "0." + strings.Repeat("0", -pos) + digits, nil
+	}
+	out := digits[:1]
+	if k > 1 {
+		out += "." + digits[1:]
+	}
+	if x > 0 {
+		return sign + out + "e+" + strconv.Itoa(x), nil
+	}
+	return sign + out + "e" + strconv.Itoa(x), nil
+}
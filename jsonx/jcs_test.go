@@ -0,0 +1,107 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// RFC 8785 section 3.2.2
+	input := `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+	out, err := Canonicalize([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(out))
+
+	// RFC 8785 section 3.2.3, keys are sorted by their UTF-16 code units
+	input = `{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7}`
+	out, err = Canonicalize([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}", string(out))
+
+	// nested objects and no HTML escaping
+	out, err = Canonicalize([]byte(`{"b": {"z": [], "a": {}}, "a": "<&>\u2028"}`))
+	require.NoError(t, err)
+	require.Equal(t, "{\"a\":\"<&>\u2028\",\"b\":{\"a\":{},\"z\":[]}}", string(out))
+}
+
+func TestCanonicalize__Numbers(t *testing.T) {
+	// RFC 8785 appendix B
+	cases := map[string]string{
+		"0":                      "0",
+		"-0":                     "0",
+		"5e-324":                 "5e-324",
+		"-5e-324":                "-5e-324",
+		"1.7976931348623157e308": "1.7976931348623157e+308",
+		"9007199254740992":       "9007199254740992",
+		"-9007199254740992":      "-9007199254740992",
+		"295147905179352830000":  "295147905179352830000",
+		"9.999999999999997e22":   "9.999999999999997e+22",
+		"1e23":                   "1e+23",
+		"1.0000000000000001e21":  "1.0000000000000001e+21",
+		"1e21":                   "1e+21",
+		"999999999999999700000":  "999999999999999700000",
+		"0.000001":               "0.000001",
+		"1e-7":                   "1e-7",
+		"0.1":                    "0.1",
+		"1.5e-8":                 "1.5e-8",
+		"123456.789":             "123456.789",
+		"100":                    "100",
+		"-1.25":                  "-1.25",
+	}
+	for in, expected := range cases {
+		out, err := Canonicalize([]byte(in))
+		require.NoError(t, err, in)
+		require.Equal(t, expected, string(out), in)
+	}
+
+	_, err := Canonicalize([]byte(`1e400`))
+	require.ErrorContains(t, err, "number 1e400 can't be represented as a double")
+}
+
+func TestCanonicalize__Errors(t *testing.T) {
+	cases := map[string]string{
+		`{"a": 1, "a": 2}`: `duplicate key "a"`,
+		`[1, 2`:            "unexpected end of JSON input",
+		`{"a": 1} {}`:      "data after the JSON value",
+		``:                 "unexpected EOF",
+		"\"\xff\"":         "invalid UTF-8",
+	}
+	for in, msg := range cases {
+		_, err := Canonicalize([]byte(in))
+		require.ErrorContains(t, err, msg, in)
+	}
+}
+
+func TestCanonicalizeStream(t *testing.T) {
+	var records []string
+	for i := 0; i < 1000; i++ {
+		records = append(records, `{"name": "café ☕", "id": `+strings.Repeat("1", 1+i%5)+`}`)
+	}
+	input := "[" + strings.Join(records, ",") + "]"
+
+	// one byte at a time splits multi-byte characters between reads
+	var buf bytes.Buffer
+	require.NoError(t, CanonicalizeStream(&buf, iotest.OneByteReader(strings.NewReader(input))))
+	require.True(t, strings.HasPrefix(buf.String(), `[{"id":1,"name":"café ☕"},{"id":11,`))
+
+	expected, err := Canonicalize([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 1000)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type amount struct {
+	Currency string `json:"currency"`
+	Value    int    `json:"value"`
+}
+
+type transfer struct {
+	Description string `json:"description"`
+	Amount      amount `json:"amount"`
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var xfer transfer
+	err := DecodeStrict(strings.NewReader(`{"description":"rent","amount":{"currency":"USD","value":1250}}  `), &xfer)
+	require.NoError(t, err)
+	require.Equal(t, "USD", xfer.Amount.Currency)
+	require.Equal(t, 1250, xfer.Amount.Value)
+}
+
+func TestDecodeStrict__Errors(t *testing.T) {
+	cases := map[string]string{
+		``:                                  "body must not be empty",
+		`{"description":`:                   "body is not valid JSON: unexpected end of input",
+		`{"description": 12}`:               "body.description must be a string",
+		`{"amount": {"value": "12"}}`:       "body.amount.value must be an integer",
+		`{"amount": []}`:                    "body.amount must be an object",
+		`{"memo": "hi"}`:                    "body.memo is not a known field",
+		`{"description": "a"} {"extra": 1}`: "body must contain a single JSON value",
+		`{"description": "a"}}`:             "body must contain a single JSON value",
+	}
+	for body, expected := range cases {
+		var xfer transfer
+		err := DecodeStrict(strings.NewReader(body), &xfer)
+
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr), "body %q: %v", body, err)
+		require.Equal(t, expected, err.Error(), "body %q", body)
+	}
+
+	var xfer transfer
+	err := DecodeStrict(strings.NewReader(`{"description" 1}`), &xfer)
+	require.Contains(t, err.Error(), "body is not valid JSON: invalid character '1' after object key at offset")
+}
+
+func TestDecodeStrict__TooLarge(t *testing.T) {
+	body := `{"description":"` + strings.Repeat("a", 100) + `"}`
+
+	var xfer transfer
+	err := DecodeStrictLimit(strings.NewReader(body), &xfer, 50)
+
+	var tooLarge *TooLargeError
+	require.True(t, errors.As(err, &tooLarge), "%v", err)
+	require.Equal(t, int64(50), tooLarge.Limit)
+	require.Equal(t, "body is larger than 50 bytes", err.Error())
+
+	// exactly at the limit
+	require.NoError(t, DecodeStrictLimit(strings.NewReader(body), &xfer, int64(len(body))))
+
+	// trailing whitespace over the limit
+	err = DecodeStrictLimit(strings.NewReader(body+strings.Repeat(" ", 10)), &xfer, int64(len(body)))
+	require.True(t, errors.As(err, &tooLarge), "%v", err)
+}
+
+func TestEncodeCanonical(t *testing.T) {
+	type payload struct {
+		Zeta  string                 `json:"zeta"`
+		Alpha int                    `json:"alpha"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	bs, err := EncodeCanonical(payload{
+		Zeta:  "<b>&",
+		Alpha: 10,
+		Meta: map[string]interface{}{
+			"y": []interface{}{1.5, nil, true},
+			"b": "é",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"alpha":10,"meta":{"b":"é","y":[1.5,null,true]},"zeta":"<b>&"}`, string(bs))
+
+	// same output regardless of field order
+	other, err := EncodeCanonical(map[string]interface{}{
+		"meta":  map[string]interface{}{"b": "é", "y": []interface{}{1.5, nil, true}},
+		"zeta":  "<b>&",
+		"alpha": 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(bs), string(other))
+
+	_, err = EncodeCanonical(func() {})
+	require.Error(t, err)
+}
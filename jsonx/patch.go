@@ -0,0 +1,421 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/moov-io/base/bufpool"
+)
+
+var (
+	// ErrNotPatchable is returned (within a *PatchError) for changes to paths which aren't allowed
+	ErrNotPatchable = errors.New("is not patchable")
+)
+
+// PatchError is an error applying a patch to the value at Path, a JSON pointer (RFC 6901).
+type PatchError struct {
+	Path string
+	Err  error
+}
+
+func (e *PatchError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "document"
+	}
+	return fmt.Sprintf("%s %v", path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// Paths are the JSON pointers a patch may change. A "*" segment matches any single key or index
+// and each path also allows everything beneath it.
+//
+//	jsonx.Paths{"/description", "/metadata", "/addresses/*/line2"}
+type Paths []string
+
+// Allows returns true when pointer is, or is beneath, one of the paths
+func (p Paths) Allows(pointer string) bool {
+	tokens := splitPointer(pointer)
+	for i := range p {
+		pattern := splitPointer(p[i])
+		if len(pattern) > len(tokens) {
+			continue
+		}
+		matched := true
+		for j := range pattern {
+			if pattern[j] != "*" && pattern[j] != tokens[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMergePatch applies a JSON merge patch (RFC 7396) to doc and returns the result, encoded
+// with EncodeCanonical's rules. Every value the patch changes must be allowed. Decode the result
+// with DecodeStrict so resource validation runs on the patched value.
+func ApplyMergePatch(doc, patch []byte, allowed Paths) ([]byte, error) {
+	target, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	result, err := mergePatch(target, p, "", allowed)
+	if err != nil {
+		return nil, err
+	}
+	return encodeValue(result)
+}
+
+func mergePatch(target, patch interface{}, path string, allowed Paths) (interface{}, error) {
+	obj, ok := patch.(map[string]interface{})
+	if !ok {
+		if !allowed.Allows(path) {
+			return nil, &PatchError{Path: path, Err: ErrNotPatchable}
+		}
+		return patch, nil
+	}
+
+	existing, ok := target.(map[string]interface{})
+	if !ok {
+		// an object replaces a scalar or array as a whole, or is added at a missing key
+		if !allowed.Allows(path) {
+			return nil, &PatchError{Path: path, Err: ErrNotPatchable}
+		}
+		existing = make(map[string]interface{})
+	}
+	for key, value := range obj {
+		childPath := path + "/" + escapeToken(key)
+		if value == nil {
+			if _, exists := existing[key]; exists {
+				if !allowed.Allows(childPath) {
+					return nil, &PatchError{Path: childPath, Err: ErrNotPatchable}
+				}
+				delete(existing, key)
+			}
+			continue
+		}
+		merged, err := mergePatch(existing[key], value, childPath, allowed)
+		if err != nil {
+			return nil, err
+		}
+		existing[key] = merged
+	}
+	return existing, nil
+}
+
+// operation is one step of a JSON Patch
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies a JSON Patch (RFC 6902) to doc and returns the result, encoded with
+// EncodeCanonical's rules. The path of each add, remove, replace, move and copy operation, and
+// the from of moves and copies, must be allowed. Nothing is returned unless every operation succeeds.
+func ApplyJSONPatch(doc, patch []byte, allowed Paths) ([]byte, error) {
+	target, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, decodeError(err)
+	}
+
+	for i := range ops {
+		target, err = applyOperation(target, ops[i], allowed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return encodeValue(target)
+}
+
+func applyOperation(doc interface{}, op operation, allowed Paths) (interface{}, error) {
+	if !strings.HasPrefix(op.Path, "/") && op.Path != "" {
+		return nil, &PatchError{Path: op.Path, Err: errors.New("is not a JSON pointer")}
+	}
+	if op.Op != "test" && !allowed.Allows(op.Path) {
+		return nil, &PatchError{Path: op.Path, Err: ErrNotPatchable}
+	}
+	path := splitPointer(op.Path)
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, &PatchError{Path: op.Path, Err: fmt.Errorf("%s is missing a value", op.Op)}
+		}
+		value, err := parseValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			return addValue(doc, op.Path, path, value)
+		case "replace":
+			return replaceValue(doc, op.Path, path, value)
+		}
+		current, err := getValue(doc, op.Path, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, &PatchError{Path: op.Path, Err: errors.New("does not match the tested value")}
+		}
+		return doc, nil
+
+	case "remove":
+		return removeValue(doc, op.Path, path)
+
+	case "move", "copy":
+		if !strings.HasPrefix(op.From, "/") && op.From != "" {
+			return nil, &PatchError{Path: op.From, Err: errors.New("is not a JSON pointer")}
+		}
+		if !allowed.Allows(op.From) {
+			return nil, &PatchError{Path: op.From, Err: ErrNotPatchable}
+		}
+		from := splitPointer(op.From)
+		value, err := getValue(doc, op.From, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return addValue(doc, op.Path, path, deepCopy(value))
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, &PatchError{Path: op.Path, Err: errors.New("cannot be moved into itself")}
+		}
+		doc, err = removeValue(doc, op.From, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, path, value)
+	}
+	return nil, &PatchError{Path: op.Path, Err: fmt.Errorf("has an unknown op %q", op.Op)}
+}
+
+func addValue(doc interface{}, pointer string, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, pointer, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			if token == "-" {
+				return append(p, value), nil
+			}
+			idx, err := index(token, len(p)+1)
+			if err != nil {
+				return nil, &PatchError{Path: pointer, Err: err}
+			}
+			p = append(p, nil)
+			copy(p[idx+1:], p[idx:])
+			p[idx] = value
+			return p, nil
+		}
+		return nil, &PatchError{Path: pointer, Err: errors.New("has no parent object or array")}
+	})
+}
+
+func removeValue(doc interface{}, pointer string, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, &PatchError{Path: pointer, Err: errors.New("cannot be removed")}
+	}
+	return update(doc, pointer, path, func(parent interface{}, token string) (interface{}, error) {
+		if _, err := child(parent, pointer, token); err != nil {
+			return nil, err
+		}
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			idx, _ := index(token, len(p))
+			return append(p[:idx], p[idx+1:]...), nil
+		}
+		return parent, nil
+	})
+}
+
+func replaceValue(doc interface{}, pointer string, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, pointer, path, func(parent interface{}, token string) (interface{}, error) {
+		if _, err := child(parent, pointer, token); err != nil {
+			return nil, err
+		}
+		return setChild(parent, token, value), nil
+	})
+}
+
+func getValue(doc interface{}, pointer string, path []string) (interface{}, error) {
+	for _, token := range path {
+		var err error
+		doc, err = child(doc, pointer, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// update walks to the parent of path's last token and replaces it with the result of fn
+func update(node interface{}, pointer string, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+	next, err := child(node, pointer, path[0])
+	if err != nil {
+		return nil, err
+	}
+	next, err = update(next, pointer, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return setChild(node, path[0], next), nil
+}
+
+func child(node interface{}, pointer, token string) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if value, exists := n[token]; exists {
+			return value, nil
+		}
+	case []interface{}:
+		idx, err := index(token, len(n))
+		if err != nil {
+			return nil, &PatchError{Path: pointer, Err: err}
+		}
+		return n[idx], nil
+	}
+	return nil, &PatchError{Path: pointer, Err: errors.New("does not exist")}
+}
+
+// setChild replaces an existing value of node
+func setChild(node interface{}, token string, value interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		n[token] = value
+	case []interface{}:
+		idx, _ := index(token, len(n))
+		n[idx] = value
+	}
+	return node
+}
+
+// index parses an array index which must be less than max
+func index(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("has an invalid index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("has an invalid index %q", token)
+	}
+	if idx >= max {
+		return 0, fmt.Errorf("index %d is out of range", idx)
+	}
+	return idx, nil
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k := range v {
+			out[k] = deepCopy(v[k])
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = deepCopy(v[i])
+		}
+		return out
+	}
+	return value
+}
+
+func parseDocument(doc []byte) (interface{}, error) {
+	value, err := parseValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonx: invalid document: %v", err)
+	}
+	return value, nil
+}
+
+func parsePatch(patch []byte) (interface{}, error) {
+	value, err := parseValue(patch)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return value, nil
+}
+
+// errTrailingData is returned by parseValue when more than whitespace follows the value
+var errTrailingData = errors.New("must contain a single JSON value")
+
+func parseValue(bs []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errTrailingData
+	}
+	return value, nil
+}
+
+func encodeValue(value interface{}) ([]byte, error) {
+	buf := bufpool.GetBuffer()
+	defer bufpool.PutBuffer(buf)
+
+	if err := writeCanonical(buf, value); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+	return tokens
+}
+
+func escapeToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
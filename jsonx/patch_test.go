@@ -0,0 +1,112 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const customer = `{"id":"c1","name":"Jane","status":"active","metadata":{"source":"web"},"addresses":[{"line1":"1 Main St","line2":""}]}`
+
+var customerPaths = Paths{"/name", "/metadata", "/addresses/*/line2"}
+
+func TestPaths(t *testing.T) {
+	require.True(t, customerPaths.Allows("/name"))
+	require.True(t, customerPaths.Allows("/metadata/source"))
+	require.True(t, customerPaths.Allows("/addresses/3/line2"))
+	require.False(t, customerPaths.Allows("/addresses/3/line1"))
+	require.False(t, customerPaths.Allows("/status"))
+	require.False(t, customerPaths.Allows(""))
+
+	require.True(t, Paths{"/a~1b"}.Allows("/a~1b/c"))
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	out, err := ApplyMergePatch([]byte(customer), []byte(`{"name":"Janet","metadata":{"source":null,"campaign":"fall"}}`), customerPaths)
+	require.NoError(t, err)
+	require.Equal(t, `{"addresses":[{"line1":"1 Main St","line2":""}],"id":"c1","metadata":{"campaign":"fall"},"name":"Janet","status":"active"}`, string(out))
+
+	// removing a missing key is a no-op
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"nickname":null}`), customerPaths)
+	require.NoError(t, err)
+}
+
+func TestApplyMergePatch__NotAllowed(t *testing.T) {
+	_, err := ApplyMergePatch([]byte(customer), []byte(`{"name":"Janet","status":"closed"}`), customerPaths)
+	require.True(t, errors.Is(err, ErrNotPatchable))
+	require.Equal(t, "/status is not patchable", err.Error())
+
+	// arrays are replaced as a whole
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"addresses":[]}`), customerPaths)
+	require.True(t, errors.Is(err, ErrNotPatchable))
+
+	_, err = ApplyMergePatch([]byte(customer), []byte(`"replaced"`), customerPaths)
+	require.Equal(t, "document is not patchable", err.Error())
+
+	// objects can't be added at keys which aren't allowed, even when empty
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"internal":{}}`), customerPaths)
+	require.Equal(t, "/internal is not patchable", err.Error())
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"internal":{"flag":null}}`), customerPaths)
+	require.Equal(t, "/internal is not patchable", err.Error())
+
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"name":`), customerPaths)
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+
+	_, err = ApplyMergePatch([]byte(customer), []byte(`{"name":"Janet"} {"status":"closed"}`), customerPaths)
+	require.Equal(t, "body must contain a single JSON value", err.Error())
+
+	_, err = ApplyMergePatch([]byte(customer+`x`), []byte(`{"name":"Janet"}`), customerPaths)
+	require.Error(t, err)
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	patch := `[
+		{"op":"test","path":"/status","value":"active"},
+		{"op":"replace","path":"/name","value":"Janet"},
+		{"op":"add","path":"/metadata/tags","value":["a"]},
+		{"op":"add","path":"/metadata/tags/0","value":"z"},
+		{"op":"add","path":"/metadata/tags/-","value":"b"},
+		{"op":"copy","from":"/metadata/source","path":"/metadata/origin"},
+		{"op":"move","from":"/metadata/source","path":"/metadata/channel"},
+		{"op":"remove","path":"/metadata/tags/1"},
+		{"op":"replace","path":"/addresses/0/line2","value":"Apt 2"}
+	]`
+	out, err := ApplyJSONPatch([]byte(customer), []byte(patch), customerPaths)
+	require.NoError(t, err)
+	require.Equal(t, `{"addresses":[{"line1":"1 Main St","line2":"Apt 2"}],"id":"c1","metadata":{"channel":"web","origin":"web","tags":["z","b"]},"name":"Janet","status":"active"}`, string(out))
+}
+
+func TestApplyJSONPatch__Errors(t *testing.T) {
+	cases := map[string]string{
+		`[{"op":"replace","path":"/status","value":"closed"}]`:       "/status is not patchable",
+		`[{"op":"move","from":"/status","path":"/name"}]`:            "/status is not patchable",
+		`[{"op":"copy","from":"/status","path":"/metadata/status"}]`: "/status is not patchable",
+		`[{"op":"copy","from":"status","path":"/metadata/status"}]`:  "status is not a JSON pointer",
+		`[{"op":"test","path":"/status","value":"closed"}]`:          "/status does not match the tested value",
+		`[{"op":"replace","path":"/metadata/missing","value":1}]`:    "/metadata/missing does not exist",
+		`[{"op":"remove","path":"/addresses/2/line2"}]`:              "/addresses/2/line2 index 2 is out of range",
+		`[{"op":"add","path":"/addresses/01/line2","value":"x"}]`:    `/addresses/01/line2 has an invalid index "01"`,
+		`[{"op":"add","path":"/name"}]`:                              "/name add is missing a value",
+		`[{"op":"merge","path":"/name"}]`:                            `/name has an unknown op "merge"`,
+		`[{"op":"add","path":"name","value":1}]`:                     "name is not a JSON pointer",
+		`[{"op":"move","from":"/metadata","path":"/metadata/x"}]`:    "/metadata/x cannot be moved into itself",
+	}
+	for patch, expected := range cases {
+		_, err := ApplyJSONPatch([]byte(customer), []byte(patch), customerPaths)
+		if expected == "" {
+			require.NoError(t, err, patch)
+			continue
+		}
+		require.Error(t, err, patch)
+		require.Equal(t, expected, err.Error(), patch)
+	}
+
+	_, err := ApplyJSONPatch([]byte(customer), []byte(`{"op":"add"}`), customerPaths)
+	require.Equal(t, "body must be an array", err.Error())
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/moov-io/base/bufpool"
+)
+
+// StreamConfig describes how newline delimited JSON (NDJSON) is read or written
+type StreamConfig struct {
+	// MaxRecordSize limits each line. It defaults to DefaultMaxBodySize.
+	MaxRecordSize int
+
+	// MaxErrors is how many records may fail and be skipped before the stream is stopped.
+	// Zero stops at the first failure.
+	MaxErrors int
+
+	// AllowUnknownFields accepts records with fields their type doesn't have, which are
+	// rejected by default like DecodeStrict.
+	AllowUnknownFields bool
+}
+
+// StreamStats counts the records of a stream
+type StreamStats struct {
+	// Records is how many records were read (or written) successfully
+	Records int
+
+	// Errors holds each record which failed and was skipped
+	Errors []RecordError
+}
+
+// RecordError is the failure of one record in a stream
+type RecordError struct {
+	// Line is the 1-based line of the record. Records from StreamEncode are numbered in the
+	// order they were received.
+	Line int
+	Err  error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ErrTooManyErrors is returned when more records fail than StreamConfig.MaxErrors
+var ErrTooManyErrors = errors.New("jsonx: too many failed records")
+
+// addError records err and returns ErrTooManyErrors once the limit is passed
+func (s *StreamStats) addError(cfg StreamConfig, line int, err error) error {
+	s.Errors = append(s.Errors, RecordError{Line: line, Err: err})
+	if len(s.Errors) > cfg.MaxErrors {
+		return fmt.Errorf("%w: %v", ErrTooManyErrors, s.Errors[len(s.Errors)-1])
+	}
+	return nil
+}
+
+// StreamDecode reads one JSON value per line from r and calls fn with each. Blank lines are
+// skipped. Lines which can't be decoded, or which fn returns an error for, are recorded in the
+// returned stats until more than cfg.MaxErrors fail.
+//
+// fn is called before the next line is read, so slow consumers (such as database writes) hold
+// back reading the body rather than buffering it. An error is returned when the stream stopped
+// before reaching the end of r.
+func StreamDecode[T any](ctx context.Context, r io.Reader, cfg StreamConfig, fn func(T) error) (StreamStats, error) {
+	if cfg.MaxRecordSize <= 0 {
+		cfg.MaxRecordSize = DefaultMaxBodySize
+	}
+
+	var stats StreamStats
+
+	// the max token size is the larger of the buffer's capacity and limit
+	limit := cfg.MaxRecordSize + 1 // room for a trailing \r
+	size := 4096
+	if size > limit {
+		size = limit
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, size), limit)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var record T
+		err := decodeRecord(data, &record, cfg)
+		if err == nil {
+			err = fn(record)
+		}
+		if err != nil {
+			if err := stats.addError(cfg, line, err); err != nil {
+				return stats, err
+			}
+			continue
+		}
+		stats.Records++
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = &TooLargeError{Limit: int64(cfg.MaxRecordSize)}
+		}
+		return stats, RecordError{Line: line + 1, Err: err}
+	}
+	return stats, nil
+}
+
+func decodeRecord(data []byte, v interface{}, cfg StreamConfig) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if !cfg.AllowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if dec.More() {
+		return &DecodeError{Path: "body", Message: "must contain a single JSON value"}
+	}
+	return nil
+}
+
+// streamFlushSize is how much StreamEncode buffers before writing, even while records are waiting
+const streamFlushSize = 32 * 1024
+
+// StreamEncode writes each record received from records to w as a line of JSON until records
+// is closed. Records which can't be encoded are skipped and recorded in the returned stats
+// until more than cfg.MaxErrors fail.
+//
+// Output is buffered while more records are waiting and flushed (including an http.Flusher)
+// whenever the producer falls behind, so clients receive records as they're produced. A
+// slow writer blocks StreamEncode, which leaves producers blocked on sending.
+func StreamEncode[T any](ctx context.Context, w io.Writer, cfg StreamConfig, records <-chan T) (StreamStats, error) {
+	var stats StreamStats
+
+	buf := bufpool.GetBuffer()
+	defer bufpool.PutBuffer(buf)
+	enc := json.NewEncoder(buf)
+
+	flush := func() error {
+		if buf.Len() > 0 {
+			_, err := w.Write(buf.Bytes())
+			buf.Reset()
+			if err != nil {
+				return err
+			}
+		}
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			return f.Flush()
+		case interface{ Flush() }:
+			f.Flush()
+		}
+		return nil
+	}
+
+	received := 0
+	for {
+		var record T
+		var ok bool
+		select {
+		case record, ok = <-records:
+		case <-ctx.Done():
+			flush()
+			return stats, ctx.Err()
+		}
+		if !ok {
+			return stats, flush()
+		}
+		received++
+
+		// Encode writes nothing when a record fails
+		if err := enc.Encode(record); err != nil {
+			if err := stats.addError(cfg, received, err); err != nil {
+				flush()
+				return stats, err
+			}
+			continue
+		}
+		stats.Records++
+
+		if len(records) == 0 || buf.Len() >= streamFlushSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jsonx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamDecode(t *testing.T) {
+	body := strings.Join([]string{
+		`{"description":"rent","amount":{"currency":"USD","value":1200}}`,
+		``,
+		`{"description":"coffee","amount":{"currency":"USD","value":"4"}}`,
+		`{"description":"refund","amount":{"currency":"USD","value":-300}}`,
+		`{"description":"lunch","extra":true}`,
+	}, "\r\n")
+
+	var got []transfer
+	stats, err := StreamDecode(context.Background(), strings.NewReader(body), StreamConfig{MaxErrors: 5}, func(t transfer) error {
+		if t.Amount.Value < 0 {
+			return errors.New("negative amount")
+		}
+		got = append(got, t)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Records)
+	require.Len(t, got, 1)
+	require.Equal(t, "rent", got[0].Description)
+
+	require.Len(t, stats.Errors, 3)
+	require.Equal(t, "line 3: body.amount.value must be an integer", stats.Errors[0].Error())
+	require.Equal(t, "line 4: negative amount", stats.Errors[1].Error())
+	require.Equal(t, "line 5: body.extra is not a known field", stats.Errors[2].Error())
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, stats.Errors[0], &decodeErr)
+}
+
+func TestStreamDecode__MaxErrors(t *testing.T) {
+	body := "{}\nnot json\n{}\n[]\n{}\n"
+
+	stats, err := StreamDecode(context.Background(), strings.NewReader(body), StreamConfig{MaxErrors: 1}, func(t transfer) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrTooManyErrors)
+	require.Equal(t, 2, stats.Records)
+	require.Len(t, stats.Errors, 2)
+	require.Equal(t, 4, stats.Errors[1].Line)
+}
+
+func TestStreamDecode__TooLarge(t *testing.T) {
+	body := `{"description":"` + strings.Repeat("a", 100) + `"}` + "\n"
+
+	_, err := StreamDecode(context.Background(), strings.NewReader(body), StreamConfig{MaxRecordSize: 64}, func(t transfer) error {
+		return nil
+	})
+	var tooLarge *TooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	require.Equal(t, "line 1: body is larger than 64 bytes", err.Error())
+}
+
+func TestStreamDecode__Cancelled(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	stats, err := StreamDecode(ctx, strings.NewReader("{}\n{}\n{}\n"), StreamConfig{}, func(t transfer) error {
+		cancelFunc()
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, stats.Records)
+}
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes++
+}
+
+func TestStreamEncode(t *testing.T) {
+	records := make(chan interface{}, 3)
+	records <- transfer{Description: "rent", Amount: amount{"USD", 1200}}
+	records <- math.Inf(1) // can't be encoded
+	records <- map[string]string{"description": "coffee"}
+	close(records)
+
+	var buf flushRecorder
+	stats, err := StreamEncode(context.Background(), &buf, StreamConfig{MaxErrors: 1}, records)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Records)
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, 2, stats.Errors[0].Line)
+
+	expected := `{"description":"rent","amount":{"currency":"USD","value":1200}}` + "\n" + `{"description":"coffee"}` + "\n"
+	require.Equal(t, expected, buf.String())
+	require.Equal(t, 2, buf.flushes) // after the last buffered record and when closed
+}
+
+func TestStreamEncode__Cancelled(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	records := make(chan transfer)
+	go func() {
+		records <- transfer{Description: "rent"}
+		cancelFunc()
+	}()
+
+	var buf bytes.Buffer
+	stats, err := StreamEncode(ctx, &buf, StreamConfig{}, records)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, stats.Records)
+	require.Contains(t, buf.String(), `"rent"`)
+}
@@ -0,0 +1,226 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jwtx
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/testtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ecKey(t *testing.T, id string) Key {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return Key{ID: id, Signer: k}
+}
+
+func rsaKey(t *testing.T, id string) Key {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return Key{ID: id, Signer: k}
+}
+
+func TestMint(t *testing.T) {
+	testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	for _, key := range []Key{ecKey(t, "ec"), rsaKey(t, "rsa")} {
+		signer, err := NewSigner(SignerConfig{Issuer: "paygate", Key: key})
+		require.NoError(t, err)
+
+		token, err := signer.Mint("paygate", "ledger")
+		require.NoError(t, err)
+
+		verifier := NewVerifier(VerifierConfig{Keys: signer.PublicKeys(), Audience: "ledger", Issuers: []string{"paygate"}})
+		claims, err := verifier.Verify(token)
+		require.NoError(t, err, key.ID)
+		require.Equal(t, "paygate", claims.Subject)
+		require.Equal(t, Audience{"ledger"}, claims.Audience)
+		require.Equal(t, time.Date(2021, time.March, 4, 12, 5, 0, 0, time.UTC).Unix(), claims.ExpiresAt)
+		require.Len(t, claims.ID, 40)
+
+		h, _, _, err := split(token)
+		require.NoError(t, err)
+		require.Equal(t, key.ID, h.KeyID)
+		require.Equal(t, "JWT", h.Type)
+	}
+}
+
+func TestVerify__Times(t *testing.T) {
+	clock := testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	signer, err := NewSigner(SignerConfig{Key: ecKey(t, "a"), TTL: time.Minute})
+	require.NoError(t, err)
+	verifier := NewVerifier(VerifierConfig{Keys: signer.PublicKeys()})
+
+	token, err := signer.Mint("svc")
+	require.NoError(t, err)
+
+	// within the leeway of expiring
+	clock.Change(clock.Now().Add(80 * time.Second))
+	_, err = verifier.Verify(token)
+	require.NoError(t, err)
+
+	clock.Change(clock.Now().Add(20 * time.Second))
+	_, err = verifier.Verify(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.ErrorContains(t, err, "expired")
+
+	// a signer whose clock is ahead
+	future, err := signer.Sign(Claims{NotBefore: clock.Now().Add(time.Minute).Unix(), ExpiresAt: clock.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	_, err = verifier.Verify(future)
+	require.ErrorContains(t, err, "not valid yet")
+
+	_, err = NewVerifier(VerifierConfig{Keys: signer.PublicKeys(), Leeway: 2 * time.Minute}).Verify(future)
+	require.NoError(t, err)
+
+	forever, err := signer.Sign(Claims{Subject: "svc"})
+	require.NoError(t, err)
+	_, err = verifier.Verify(forever)
+	require.ErrorContains(t, err, "no expiration")
+}
+
+func TestVerify__Errors(t *testing.T) {
+	signer, err := NewSigner(SignerConfig{Issuer: "paygate", Key: ecKey(t, "a")})
+	require.NoError(t, err)
+	token, err := signer.Mint("paygate", "ledger", "billing")
+	require.NoError(t, err)
+
+	keys := signer.PublicKeys()
+
+	_, err = NewVerifier(VerifierConfig{Keys: keys, Audience: "ach"}).Verify(token)
+	require.ErrorContains(t, err, "not for audience ach")
+
+	_, err = NewVerifier(VerifierConfig{Keys: keys, Issuers: []string{"other"}}).Verify(token)
+	require.ErrorContains(t, err, `untrusted issuer "paygate"`)
+
+	_, err = NewVerifier(VerifierConfig{Keys: map[string]crypto.PublicKey{"a": ecKey(t, "a").Signer.Public()}}).Verify(token)
+	require.ErrorContains(t, err, "bad signature")
+
+	_, err = NewVerifier(VerifierConfig{}).Verify(token)
+	require.ErrorContains(t, err, `unknown key "a"`)
+
+	verifier := NewVerifier(VerifierConfig{Keys: keys})
+	parts := strings.Split(token, ".")
+
+	// claims can't be changed
+	other, err := signer.Mint("billing", "ledger")
+	require.NoError(t, err)
+	tampered := parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2]
+	_, err = verifier.Verify(tampered)
+	require.ErrorContains(t, err, "bad signature")
+
+	// nor the algorithm
+	none, _ := encodeSegment(header{Algorithm: "none", KeyID: "a"})
+	_, err = verifier.Verify(none + "." + parts[1] + ".")
+	require.ErrorContains(t, err, `unsupported algorithm "none"`)
+
+	_, err = verifier.Verify("abc")
+	require.ErrorContains(t, err, "invalid token: malformed")
+}
+
+func TestRotate(t *testing.T) {
+	signer, err := NewSigner(SignerConfig{Key: ecKey(t, "2021-01")})
+	require.NoError(t, err)
+	old, err := signer.Mint("svc")
+	require.NoError(t, err)
+
+	require.ErrorContains(t, signer.Rotate(ecKey(t, "2021-01")), "rotated key needs a new ID")
+	require.NoError(t, signer.Rotate(rsaKey(t, "2021-02")))
+
+	current, err := signer.Mint("svc")
+	require.NoError(t, err)
+	h, _, _, _ := split(current)
+	require.Equal(t, "2021-02", h.KeyID)
+	require.Equal(t, RS256, h.Algorithm)
+
+	verifier := NewVerifier(VerifierConfig{Keys: signer.PublicKeys()})
+	_, err = verifier.Verify(old)
+	require.NoError(t, err)
+	_, err = verifier.Verify(current)
+	require.NoError(t, err)
+
+	// the oldest key is dropped after a second rotation
+	require.NoError(t, signer.Rotate(ecKey(t, "2021-03")))
+	_, err = NewVerifier(VerifierConfig{Keys: signer.PublicKeys()}).Verify(old)
+	require.ErrorContains(t, err, `unknown key "2021-01"`)
+}
+
+func TestLoadKey(t *testing.T) {
+	dir := t.TempDir()
+
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(ec)
+	require.NoError(t, err)
+	write := func(name, typ string, der []byte) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+	}
+	write("ec.pem", "EC PRIVATE KEY", der)
+
+	rk, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKCS8PrivateKey(rk)
+	require.NoError(t, err)
+	write("rsa.pem", "PRIVATE KEY", der)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	write("small.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(small))
+
+	ctx := context.Background()
+	secrets := SecretsDir(dir)
+
+	key, err := LoadKey(ctx, secrets, "ec.pem", "ec-1")
+	require.NoError(t, err)
+	alg, _ := key.Algorithm()
+	require.Equal(t, ES256, alg)
+	require.Equal(t, "ec-1", key.ID)
+
+	key, err = LoadKey(ctx, secrets, "rsa.pem", "rsa-1")
+	require.NoError(t, err)
+	alg, _ = key.Algorithm()
+	require.Equal(t, RS256, alg)
+
+	_, err = LoadKey(ctx, secrets, "small.pem", "small")
+	require.ErrorContains(t, err, "is 1024 bits, at least 2048 are required")
+
+	_, err = LoadKey(ctx, secrets, "missing.pem", "x")
+	require.ErrorContains(t, err, "jwtx: loading missing.pem")
+
+	_, err = LoadKey(ctx, secrets, "../ec.pem", "x")
+	require.ErrorContains(t, err, "invalid secret name")
+
+	_, err = ParseKey("x", []byte("not pem"))
+	require.ErrorContains(t, err, "no PEM block found")
+}
+
+func TestAudience(t *testing.T) {
+	var claims Claims
+	require.NoError(t, json.Unmarshal([]byte(`{"aud": "ledger"}`), &claims))
+	require.Equal(t, Audience{"ledger"}, claims.Audience)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"aud": ["ledger", "billing"]}`), &claims))
+	require.True(t, claims.Audience.Contains("billing"))
+
+	require.Error(t, json.Unmarshal([]byte(`{"aud": 12}`), &claims))
+}
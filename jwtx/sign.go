@@ -0,0 +1,245 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jwtx
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DefaultTTL is how long minted tokens are valid unless SignerConfig.TTL is set
+const DefaultTTL = 5 * time.Minute
+
+// Key is a private signing key and the ID verifiers find its public key by
+type Key struct {
+	ID     string
+	Signer crypto.Signer
+}
+
+// Algorithm returns RS256 for RSA keys and ES256 for P-256 keys
+func (k Key) Algorithm() (string, error) {
+	switch key := k.Signer.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return "", fmt.Errorf("jwtx: RSA key %s is %d bits, at least 2048 are required", k.ID, key.N.BitLen())
+		}
+		return RS256, nil
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("jwtx: ECDSA key %s isn't P-256", k.ID)
+		}
+		return ES256, nil
+	}
+	return "", fmt.Errorf("jwtx: unsupported key type %T", k.Signer)
+}
+
+// ParseKey reads a PEM encoded RSA or P-256 private key in PKCS #1, PKCS #8 or SEC 1 form
+func ParseKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("jwtx: no PEM block found")
+	}
+	var signer interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		signer, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		signer, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		signer, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("jwtx: unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("jwtx: %w", err)
+	}
+	cs, ok := signer.(crypto.Signer)
+	if !ok {
+		return Key{}, fmt.Errorf("jwtx: unsupported key type %T", signer)
+	}
+	key := Key{ID: id, Signer: cs}
+	if _, err := key.Algorithm(); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// Secrets returns the secret called name, such as from a secrets manager or mounted volume
+type Secrets interface {
+	Secret(ctx context.Context, name string) ([]byte, error)
+}
+
+// SecretsDir reads secrets from files in a directory, as Kubernetes mounts them
+type SecretsDir string
+
+// Secret returns the contents of the file called name
+func (d SecretsDir) Secret(ctx context.Context, name string) ([]byte, error) {
+	if name != filepath.Base(name) {
+		return nil, fmt.Errorf("jwtx: invalid secret name %q", name)
+	}
+	return os.ReadFile(filepath.Join(string(d), name))
+}
+
+// LoadKey reads the PEM encoded private key called name from secrets
+func LoadKey(ctx context.Context, secrets Secrets, name, id string) (Key, error) {
+	data, err := secrets.Secret(ctx, name)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwtx: loading %s: %w", name, err)
+	}
+	return ParseKey(id, data)
+}
+
+// SignerConfig describes the tokens a Signer mints
+type SignerConfig struct {
+	Issuer string
+	Key    Key
+
+	// TTL is how long tokens are valid, DefaultTTL when zero
+	TTL time.Duration
+}
+
+// Signer mints tokens with its current key. It's safe for concurrent use.
+type Signer struct {
+	issuer string
+	ttl    time.Duration
+
+	mu       sync.RWMutex
+	key      Key
+	alg      string
+	previous *Key
+}
+
+// NewSigner returns a Signer of cfg
+func NewSigner(cfg SignerConfig) (*Signer, error) {
+	alg, err := cfg.Key.Algorithm()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Key.ID == "" {
+		return nil, errors.New("jwtx: key has no ID")
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	return &Signer{issuer: cfg.Issuer, ttl: cfg.TTL, key: cfg.Key, alg: alg}, nil
+}
+
+// Rotate signs new tokens with key. The replaced key is still returned from PublicKeys so
+// tokens it signed verify until they expire.
+func (s *Signer) Rotate(key Key) error {
+	alg, err := key.Algorithm()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key.ID == "" || key.ID == s.key.ID {
+		return fmt.Errorf("jwtx: rotated key needs a new ID, got %q", key.ID)
+	}
+	previous := s.key
+	s.previous = &previous
+	s.key, s.alg = key, alg
+	return nil
+}
+
+// PublicKeys returns the public keys of the current and previous key by their IDs
+func (s *Signer) PublicKeys() map[string]crypto.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := map[string]crypto.PublicKey{s.key.ID: s.key.Signer.Public()}
+	if s.previous != nil {
+		out[s.previous.ID] = s.previous.Signer.Public()
+	}
+	return out
+}
+
+// Mint returns a token for subject which is valid for the signer's TTL from now for each of
+// audience
+func (s *Signer) Mint(subject string, audience ...string) (string, error) {
+	now := base.Now()
+	return s.Sign(Claims{
+		Issuer:    s.issuer,
+		Subject:   subject,
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+		ID:        base.ID(),
+	})
+}
+
+// Sign returns a token of claims, signed with the current key
+func (s *Signer) Sign(claims Claims) (string, error) {
+	s.mu.RLock()
+	key, alg := s.key, s.alg
+	s.mu.RUnlock()
+
+	h, err := encodeSegment(header{Algorithm: alg, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	c, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	input := h + "." + c
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.Signer.(type) {
+	case *ecdsa.PrivateKey:
+		r, ss, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return "", err
+		}
+		// JWS uses the fixed width R || S form rather than ASN.1
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		ss.FillBytes(sig[32:])
+	default:
+		sig, err = key.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return "", err
+		}
+	}
+	return input + "." + encoding.EncodeToString(sig), nil
+}
+
+// verifySignature checks sig of input with pub for alg
+func verifySignature(alg string, pub crypto.PublicKey, input string, sig []byte) bool {
+	digest := sha256.Sum256([]byte(input))
+	switch alg {
+	case RS256:
+		key, ok := pub.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	case ES256:
+		key, ok := pub.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(key, digest[:], r, s)
+	}
+	return false
+}
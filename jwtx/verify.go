@@ -0,0 +1,96 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package jwtx
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DefaultLeeway is how far the clocks of services may disagree unless VerifierConfig.Leeway is set
+const DefaultLeeway = 30 * time.Second
+
+// VerifierConfig describes which tokens a Verifier accepts
+type VerifierConfig struct {
+	// Keys are the public keys of trusted signers by their ID
+	Keys map[string]crypto.PublicKey
+
+	// Audience must be one of the token's audiences when set
+	Audience string
+
+	// Issuers, when set, are the only issuers accepted
+	Issuers []string
+
+	// Leeway is added to expiration and subtracted from not before times, DefaultLeeway when zero
+	Leeway time.Duration
+}
+
+// Verifier checks tokens minted by a Signer
+type Verifier struct {
+	cfg VerifierConfig
+}
+
+// NewVerifier returns a Verifier of cfg
+func NewVerifier(cfg VerifierConfig) *Verifier {
+	if cfg.Leeway <= 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+	return &Verifier{cfg: cfg}
+}
+
+// Verify checks the signature, times, audience and issuer of token and returns its claims.
+// Tokens must name their key and expire. Errors wrap ErrInvalidToken.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	h, input, sig, err := split(strings.TrimSpace(token))
+	if err != nil {
+		return Claims{}, err
+	}
+	if h.Algorithm != RS256 && h.Algorithm != ES256 {
+		return Claims{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, h.Algorithm)
+	}
+	pub, ok := v.cfg.Keys[h.KeyID]
+	if !ok {
+		return Claims{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, h.KeyID)
+	}
+	if !verifySignature(h.Algorithm, pub, input, sig) {
+		return Claims{}, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims Claims
+	payload := input[strings.IndexByte(input, '.')+1:]
+	bs, err := encoding.DecodeString(payload)
+	if err != nil || json.Unmarshal(bs, &claims) != nil {
+		return Claims{}, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+
+	now := base.Now()
+	switch {
+	case claims.ExpiresAt == 0:
+		return Claims{}, fmt.Errorf("%w: no expiration", ErrInvalidToken)
+	case now.Add(-v.cfg.Leeway).Unix() >= claims.ExpiresAt:
+		return Claims{}, fmt.Errorf("%w: expired", ErrInvalidToken)
+	case claims.NotBefore != 0 && now.Add(v.cfg.Leeway).Unix() < claims.NotBefore:
+		return Claims{}, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	case v.cfg.Audience != "" && !claims.Audience.Contains(v.cfg.Audience):
+		return Claims{}, fmt.Errorf("%w: not for audience %s", ErrInvalidToken, v.cfg.Audience)
+	case len(v.cfg.Issuers) > 0 && !contains(v.cfg.Issuers, claims.Issuer):
+		return Claims{}, fmt.Errorf("%w: untrusted issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	return claims, nil
+}
+
+func contains(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+	return false
+}
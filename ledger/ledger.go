@@ -0,0 +1,165 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package ledger implements double-entry bookkeeping types for services which keep
+// an internal ledger.
+//
+// An Entry is a journal entry made of Postings. Every Entry must be balanced: for each
+// currency the sum of debits equals the sum of credits.
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/moov-io/base"
+)
+
+var (
+	// ErrTooFewPostings is returned when a transaction doesn't have both sides of an entry
+	ErrTooFewPostings = errors.New("transaction requires at least two postings")
+)
+
+// Direction is which side of an account a Posting is applied to.
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// Validate returns an error if d is not Debit or Credit
+func (d Direction) Validate() error {
+	switch d {
+	case Debit, Credit:
+		return nil
+	}
+	return fmt.Errorf("unknown direction %q", string(d))
+}
+
+// UnmarshalJSON parses a case-insensitive Direction and rejects unknown values
+func (d *Direction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	dir := Direction(strings.ToLower(strings.TrimSpace(s)))
+	if err := dir.Validate(); err != nil {
+		return err
+	}
+	*d = dir
+	return nil
+}
+
+// Posting is one leg of an Entry which moves Amount into or out of Account.
+type Posting struct {
+	Account   string      `json:"account"`
+	Direction Direction   `json:"direction"`
+	Amount    base.Amount `json:"amount"`
+}
+
+// Validate checks the Posting has an account, direction and positive amount.
+func (p Posting) Validate() error {
+	if strings.TrimSpace(p.Account) == "" {
+		return errors.New("missing account")
+	}
+	if err := p.Direction.Validate(); err != nil {
+		return fmt.Errorf("account %s: %v", p.Account, err)
+	}
+	if p.Amount.Currency == "" {
+		return fmt.Errorf("account %s: missing currency", p.Account)
+	}
+	if p.Amount.Value <= 0 {
+		return fmt.Errorf("account %s: amount must be positive, got %s", p.Account, p.Amount)
+	}
+	return nil
+}
+
+// Entry is a journal entry recorded in the ledger.
+type Entry struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description,omitempty"`
+	PostedAt    base.Time `json:"postedAt"`
+	Postings    []Posting `json:"postings"`
+}
+
+// Validate checks the Entry's postings with ValidateTransaction.
+func (e Entry) Validate() error {
+	return ValidateTransaction(e.Postings...)
+}
+
+// UnbalancedError is returned when the debits and credits of a currency don't match.
+type UnbalancedError struct {
+	Currency string
+	Debits   base.Amount
+	Credits  base.Amount
+}
+
+func (e UnbalancedError) Error() string {
+	return fmt.Sprintf("unbalanced %s postings: debits=%s credits=%s", e.Currency, e.Debits, e.Credits)
+}
+
+// ValidateTransaction checks every posting is valid and that for each currency the
+// sum of debits equals the sum of credits.
+//
+// All problems found are returned as a base.ErrorList.
+func ValidateTransaction(postings ...Posting) error {
+	var el base.ErrorList
+	if len(postings) < 2 {
+		el.Add(ErrTooFewPostings)
+	}
+
+	debits, credits := make(map[string]int64), make(map[string]int64)
+	for i := range postings {
+		if err := postings[i].Validate(); err != nil {
+			el.Add(fmt.Errorf("posting %d: %v", i, err))
+			continue
+		}
+		totals := debits
+		if postings[i].Direction == Credit {
+			totals = credits
+		}
+		cur := postings[i].Amount.Currency
+		if totals[cur] > math.MaxInt64-postings[i].Amount.Value {
+			el.Add(fmt.Errorf("posting %d: %s total overflows", i, cur))
+			continue
+		}
+		totals[cur] += postings[i].Amount.Value
+	}
+
+	for _, cur := range currencies(debits, credits) {
+		if debits[cur] != credits[cur] {
+			el.Add(UnbalancedError{
+				Currency: cur,
+				Debits:   base.NewAmount(debits[cur], cur),
+				Credits:  base.NewAmount(credits[cur], cur),
+			})
+		}
+	}
+
+	if el.Empty() {
+		return nil
+	}
+	return el
+}
+
+// currencies returns the sorted set of currencies seen so errors are deterministic
+func currencies(totals ...map[string]int64) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for i := range totals {
+		for cur := range totals[i] {
+			if !seen[cur] {
+				seen[cur] = true
+				out = append(out, cur)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
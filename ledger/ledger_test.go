@@ -0,0 +1,105 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ledger
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+func usd(v int64) base.Amount {
+	return base.NewAmount(v, "USD")
+}
+
+func TestValidateTransaction(t *testing.T) {
+	err := ValidateTransaction(
+		Posting{Account: "cash", Direction: Debit, Amount: usd(1000)},
+		Posting{Account: "fees", Direction: Credit, Amount: usd(25)},
+		Posting{Account: "customer", Direction: Credit, Amount: usd(975)},
+	)
+	require.NoError(t, err)
+
+	// balanced per currency
+	err = ValidateTransaction(
+		Posting{Account: "cash", Direction: Debit, Amount: usd(1000)},
+		Posting{Account: "customer", Direction: Credit, Amount: usd(1000)},
+		Posting{Account: "fx", Direction: Debit, Amount: base.NewAmount(500, "JPY")},
+		Posting{Account: "customer-jpy", Direction: Credit, Amount: base.NewAmount(500, "JPY")},
+	)
+	require.NoError(t, err)
+}
+
+func TestValidateTransaction__unbalanced(t *testing.T) {
+	err := ValidateTransaction(
+		Posting{Account: "cash", Direction: Debit, Amount: usd(1000)},
+		Posting{Account: "customer", Direction: Credit, Amount: usd(999)},
+		Posting{Account: "fx", Direction: Credit, Amount: base.NewAmount(500, "JPY")},
+	)
+	require.Error(t, err)
+	require.True(t, base.Has(err, UnbalancedError{}))
+
+	el, ok := err.(base.ErrorList)
+	require.True(t, ok)
+	require.Len(t, el, 2)
+	require.Equal(t, "JPY", el[0].(UnbalancedError).Currency)
+	require.Equal(t, UnbalancedError{Currency: "USD", Debits: usd(1000), Credits: usd(999)}, el[1])
+}
+
+func TestValidateTransaction__invalid(t *testing.T) {
+	require.Error(t, ValidateTransaction())
+	require.Error(t, ValidateTransaction(Posting{Account: "cash", Direction: Debit, Amount: usd(1)}))
+
+	err := ValidateTransaction(
+		Posting{Account: "", Direction: Debit, Amount: usd(1)},
+		Posting{Account: "cash", Direction: "sideways", Amount: usd(1)},
+		Posting{Account: "cash", Direction: Credit, Amount: usd(-1)},
+		Posting{Account: "cash", Direction: Credit, Amount: base.Amount{Value: 1}},
+	)
+	el, ok := err.(base.ErrorList)
+	require.True(t, ok)
+	require.Len(t, el, 4)
+
+	err = ValidateTransaction(
+		Posting{Account: "a", Direction: Debit, Amount: usd(math.MaxInt64)},
+		Posting{Account: "b", Direction: Debit, Amount: usd(1)},
+		Posting{Account: "c", Direction: Credit, Amount: usd(math.MaxInt64)},
+	)
+	require.Contains(t, err.Error(), "overflows")
+}
+
+func TestEntry__JSON(t *testing.T) {
+	entry := Entry{
+		ID:          base.ID(),
+		Description: "transfer",
+		PostedAt:    base.NewTime(time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)),
+		Postings: []Posting{
+			{Account: "cash", Direction: Debit, Amount: usd(1000)},
+			{Account: "customer", Direction: Credit, Amount: usd(1000)},
+		},
+	}
+	require.NoError(t, entry.Validate())
+
+	bs, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `"direction":"debit"`)
+	require.Contains(t, string(bs), `"postedAt":"2020-12-01T10:00:00Z"`)
+
+	var out Entry
+	require.NoError(t, json.Unmarshal(bs, &out))
+	require.Equal(t, entry.Postings, out.Postings)
+	require.True(t, entry.PostedAt.Equal(out.PostedAt))
+	require.NoError(t, out.Validate())
+
+	var dir Direction
+	require.NoError(t, json.Unmarshal([]byte(`"CREDIT"`), &dir))
+	require.Equal(t, Credit, dir)
+	require.Error(t, json.Unmarshal([]byte(`"sideways"`), &dir))
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package limits checks amounts against per-transaction, daily and monthly caps of each currency,
+// such as the origination limits of a customer.
+//
+//	Limits:
+//	  USD:
+//	    PerTransaction: { Value: 2500000, Currency: USD }
+//	    Daily: { Value: 10000000, Currency: USD }
+//
+//	decision, err := cfg.Limits.Evaluate(transfer.Amount, limits.Usage{Daily: sentToday})
+//	if err == nil {
+//		err = decision.Err()
+//	}
+//
+// Currencies without limits are rejected so new currencies are denied until they're configured.
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/base"
+)
+
+// Period names a limit
+type Period string
+
+const (
+	PerTransaction Period = "per-transaction"
+	Daily          Period = "daily"
+	Monthly        Period = "monthly"
+)
+
+var (
+	// ErrNoLimits is returned for currencies without limits
+	ErrNoLimits = errors.New("no limits for currency")
+
+	// ErrCurrencyMismatch is returned when an amount and usage are in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Caps are the limits of one currency. Caps left out (with a zero Value) are unlimited.
+type Caps struct {
+	PerTransaction base.Amount `json:"perTransaction"`
+	Daily          base.Amount `json:"daily"`
+	Monthly        base.Amount `json:"monthly"`
+}
+
+// Limits are the Caps of each currency by ISO 4217 code
+type Limits map[string]Caps
+
+// Usage is how much was already spent in the current day and month, in the evaluated currency
+type Usage struct {
+	Daily   base.Amount `json:"daily"`
+	Monthly base.Amount `json:"monthly"`
+}
+
+// ExceededError describes a limit an amount would pass
+type ExceededError struct {
+	Period Period
+	Limit  base.Amount
+
+	// Total is the amount plus the usage of Period
+	Total base.Amount
+}
+
+func (e *ExceededError) Error() string {
+	over := base.NewAmount(e.Total.Value-e.Limit.Value, e.Limit.Currency)
+	return fmt.Sprintf("%s limit of %s exceeded by %s", e.Period, e.Limit, over)
+}
+
+// Decision is the result of Evaluate
+type Decision struct {
+	Allowed bool
+
+	// Exceeded lists every limit the amount would pass
+	Exceeded []*ExceededError
+
+	// Remaining is how much could still be spent today and this month after the amount. Values
+	// are negative once a limit is exceeded and zero for unlimited periods.
+	Remaining Usage
+}
+
+// Err returns the first exceeded limit as an *ExceededError, or nil when the amount is allowed
+func (d Decision) Err() error {
+	var list base.ErrorList
+	for _, e := range d.Exceeded {
+		list.Add(e)
+	}
+	return list.Err()
+}
+
+// Evaluate decides whether amount may be spent given usage. An error is returned for currencies
+// without limits and usage in another currency.
+func (l Limits) Evaluate(amount base.Amount, usage Usage) (Decision, error) {
+	currency := strings.ToUpper(amount.Currency)
+	caps, ok := l.caps(currency)
+	if !ok {
+		return Decision{}, fmt.Errorf("%w %s", ErrNoLimits, currency)
+	}
+	for _, used := range []base.Amount{usage.Daily, usage.Monthly} {
+		if used.Value != 0 && !strings.EqualFold(used.Currency, currency) {
+			return Decision{}, fmt.Errorf("%w: usage in %s for %s amount", ErrCurrencyMismatch, used.Currency, currency)
+		}
+	}
+
+	decision := Decision{
+		Remaining: Usage{
+			Daily:   base.NewAmount(0, currency),
+			Monthly: base.NewAmount(0, currency),
+		},
+	}
+	check := func(period Period, limit base.Amount, used int64) int64 {
+		if limit.Value == 0 {
+			return 0
+		}
+		total := used + amount.Value
+		if total > limit.Value {
+			decision.Exceeded = append(decision.Exceeded, &ExceededError{
+				Period: period,
+				Limit:  limit,
+				Total:  base.NewAmount(total, currency),
+			})
+		}
+		return limit.Value - total
+	}
+	check(PerTransaction, caps.PerTransaction, 0)
+	decision.Remaining.Daily.Value = check(Daily, caps.Daily, usage.Daily.Value)
+	decision.Remaining.Monthly.Value = check(Monthly, caps.Monthly, usage.Monthly.Value)
+
+	decision.Allowed = len(decision.Exceeded) == 0
+	return decision, nil
+}
+
+// caps returns the Caps of currency. Config loaders such as viper lowercase map keys so
+// they're matched without case.
+func (l Limits) caps(currency string) (Caps, bool) {
+	if caps, ok := l[currency]; ok {
+		return caps, true
+	}
+	for code, caps := range l {
+		if strings.EqualFold(code, currency) {
+			return caps, true
+		}
+	}
+	return Caps{}, false
+}
+
+// Validate checks each cap is positive and in the currency it's listed under
+func (l Limits) Validate() error {
+	var list base.ErrorList
+	for currency, caps := range l {
+		if len(currency) != 3 {
+			list.Add(fmt.Errorf("limits: invalid currency %q", currency))
+			continue
+		}
+		for _, c := range []struct {
+			period Period
+			amount base.Amount
+		}{
+			{PerTransaction, caps.PerTransaction},
+			{Daily, caps.Daily},
+			{Monthly, caps.Monthly},
+		} {
+			if c.amount.Value == 0 {
+				continue
+			}
+			if c.amount.Value < 0 {
+				list.Add(fmt.Errorf("limits: %s %s limit must be positive", currency, c.period))
+			}
+			if !strings.EqualFold(c.amount.Currency, currency) {
+				list.Add(fmt.Errorf("limits: %s %s limit is in %s", currency, c.period, c.amount.Currency))
+			}
+		}
+	}
+	return list.Err()
+}
+
+// UnmarshalJSON reads Limits keyed by currency code and validates them
+func (l *Limits) UnmarshalJSON(data []byte) error {
+	var raw map[string]Caps
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := make(Limits, len(raw))
+	for currency, caps := range raw {
+		currency = strings.ToUpper(currency)
+		caps.PerTransaction.Currency = strings.ToUpper(caps.PerTransaction.Currency)
+		caps.Daily.Currency = strings.ToUpper(caps.Daily.Currency)
+		caps.Monthly.Currency = strings.ToUpper(caps.Monthly.Currency)
+		out[currency] = caps
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*l = out
+	return nil
+}
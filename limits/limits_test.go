@@ -0,0 +1,103 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/base"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+var testLimits = Limits{
+	"USD": {
+		PerTransaction: base.NewAmount(250000, "USD"),
+		Daily:          base.NewAmount(1000000, "USD"),
+		Monthly:        base.NewAmount(5000000, "USD"),
+	},
+	"EUR": {
+		Daily: base.NewAmount(100000, "EUR"),
+	},
+}
+
+func TestEvaluate(t *testing.T) {
+	decision, err := testLimits.Evaluate(base.NewAmount(100000, "USD"), Usage{
+		Daily:   base.NewAmount(200000, "USD"),
+		Monthly: base.NewAmount(1000000, "USD"),
+	})
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.NoError(t, decision.Err())
+	require.Equal(t, base.NewAmount(700000, "USD"), decision.Remaining.Daily)
+	require.Equal(t, base.NewAmount(3900000, "USD"), decision.Remaining.Monthly)
+
+	// over the transaction and daily limits
+	decision, err = testLimits.Evaluate(base.NewAmount(300000, "usd"), Usage{
+		Daily: base.NewAmount(800000, "USD"),
+	})
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Len(t, decision.Exceeded, 2)
+	require.Equal(t, PerTransaction, decision.Exceeded[0].Period)
+	require.Equal(t, Daily, decision.Exceeded[1].Period)
+	require.Equal(t, base.NewAmount(-100000, "USD"), decision.Remaining.Daily)
+
+	err = decision.Err()
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, "daily limit of USD 10000.00 exceeded by USD 1000.00", decision.Exceeded[1].Error())
+
+	// no monthly limit
+	decision, err = testLimits.Evaluate(base.NewAmount(100000, "EUR"), Usage{})
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, base.NewAmount(0, "EUR"), decision.Remaining.Monthly)
+
+	_, err = testLimits.Evaluate(base.NewAmount(100, "GBP"), Usage{})
+	require.ErrorIs(t, err, ErrNoLimits)
+
+	_, err = testLimits.Evaluate(base.NewAmount(100, "USD"), Usage{Daily: base.NewAmount(1, "EUR")})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestLimits__JSON(t *testing.T) {
+	var l Limits
+	err := json.Unmarshal([]byte(`{"usd": {"daily": {"value": 1000, "currency": "usd"}}}`), &l)
+	require.NoError(t, err)
+	require.Equal(t, base.NewAmount(1000, "USD"), l["USD"].Daily)
+
+	err = json.Unmarshal([]byte(`{"USD": {"daily": {"value": 1000, "currency": "EUR"}}}`), &l)
+	require.ErrorContains(t, err, "USD daily limit is in EUR")
+
+	err = json.Unmarshal([]byte(`{"USD": {"monthly": {"value": -1, "currency": "USD"}}}`), &l)
+	require.ErrorContains(t, err, "USD monthly limit must be positive")
+}
+
+func TestLimits__Config(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(`
+Limits:
+  USD:
+    PerTransaction: { Value: 2500, Currency: USD }
+    Daily: { Value: 10000, Currency: USD }
+`)))
+
+	var cfg struct {
+		Limits Limits
+	}
+	require.NoError(t, v.Unmarshal(&cfg))
+	require.NoError(t, cfg.Limits.Validate())
+
+	decision, err := cfg.Limits.Evaluate(base.NewAmount(3000, "USD"), Usage{})
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, PerTransaction, decision.Exceeded[0].Period)
+}
@@ -73,6 +73,10 @@ func (l *logger) With(ctxs ...Context) Logger {
 	}
 }
 
+func (l *logger) Debug() Logger {
+	return l.With(Debug)
+}
+
 func (l *logger) Info() Logger {
 	return l.With(Info)
 }
@@ -90,6 +94,12 @@ func (l *logger) Fatal() Logger {
 }
 
 func (l *logger) Log(msg string) {
+	if level, ok := l.ctx["level"]; ok {
+		if name, ok := level.getValue().(string); ok && !Level(name).Enabled() {
+			return
+		}
+	}
+
 	orig := []string{
 		"ts", time.Now().UTC().Format(time.RFC3339),
 	}
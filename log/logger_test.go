@@ -163,6 +163,58 @@ func Test_Info(t *testing.T) {
 	a.Contains(buffer.String(), "level=info")
 }
 
+func Test_Debug(t *testing.T) {
+	a, buffer, log := Setup(t)
+
+	log.Debug().Logf("dropped")
+	a.Empty(buffer.String())
+
+	prev, err := lib.SetLevel(lib.Debug)
+	a.NoError(err)
+	a.Equal(lib.Info, prev)
+	defer lib.SetLevel(prev)
+
+	log.Debug().Logf("message")
+	a.Contains(buffer.String(), "level=debug")
+}
+
+func Test_SetLevel(t *testing.T) {
+	a, buffer, log := Setup(t)
+
+	a.Equal(lib.Info, lib.CurrentLevel())
+
+	prev, err := lib.SetLevel(lib.Error)
+	a.NoError(err)
+	defer lib.SetLevel(prev)
+	a.Equal(lib.Error, lib.CurrentLevel())
+
+	log.Info().Logf("dropped")
+	log.Warn().Logf("dropped")
+	a.Empty(buffer.String())
+
+	log.Error().Logf("error message")
+	log.Fatal().Logf("fatal message")
+	log.With(lib.Level("custom")).Logf("custom message")
+	a.Contains(buffer.String(), "error message")
+	a.Contains(buffer.String(), "fatal message")
+	a.Contains(buffer.String(), "custom message")
+
+	_, err = lib.SetLevel(lib.Level("verbose"))
+	a.Error(err)
+	a.Equal(lib.Error, lib.CurrentLevel())
+}
+
+func Test_ParseLevel(t *testing.T) {
+	a := assert.New(t)
+
+	l, err := lib.ParseLevel(" DEBUG ")
+	a.NoError(err)
+	a.Equal(lib.Debug, l)
+
+	_, err = lib.ParseLevel("verbose")
+	a.Error(err)
+}
+
 func Test_Error(t *testing.T) {
 	a, buffer, log := Setup(t)
 
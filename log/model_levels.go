@@ -1,8 +1,17 @@
 package log
 
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
 // Level just wraps a string to be able to add Context specific to log levels
 type Level string
 
+// Debug sets level=debug in the log output. Debug logs are dropped unless enabled with SetLevel.
+const Debug = Level("debug")
+
 // Info is sets level=info in the log output
 const Info = Level("info")
 
@@ -21,3 +30,53 @@ func (l Level) Context() map[string]Valuer {
 		"level": String(string(l)),
 	}
 }
+
+var levelRanks = map[Level]int32{
+	Debug: 0,
+	Info:  1,
+	Warn:  2,
+	Error: 3,
+	Fatal: 4,
+}
+
+// minimumRank is the rank of the lowest Level written by every Logger
+var minimumRank = levelRanks[Info]
+
+// ParseLevel returns the Level matching a case-insensitive name (i.e. "DEBUG")
+func ParseLevel(name string) (Level, error) {
+	l := Level(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := levelRanks[l]; !ok {
+		return "", fmt.Errorf("unknown log level %q", name)
+	}
+	return l, nil
+}
+
+// SetLevel changes the lowest Level written by every Logger and returns the previous Level.
+// Logs below the level are dropped. Info is the default.
+func SetLevel(l Level) (Level, error) {
+	rank, ok := levelRanks[l]
+	if !ok {
+		return "", fmt.Errorf("unknown log level %q", string(l))
+	}
+	return levelOf(atomic.SwapInt32(&minimumRank, rank)), nil
+}
+
+// CurrentLevel returns the lowest Level written by every Logger
+func CurrentLevel() Level {
+	return levelOf(atomic.LoadInt32(&minimumRank))
+}
+
+// Enabled reports whether logs of Level l are written. Unknown levels are always written.
+func (l Level) Enabled() bool {
+	rank, ok := levelRanks[l]
+	return !ok || rank >= atomic.LoadInt32(&minimumRank)
+}
+
+func levelOf(rank int32) Level {
+	for l, r := range levelRanks {
+		if r == rank {
+			return l
+		}
+	}
+	return Info
+}
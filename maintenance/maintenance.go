@@ -0,0 +1,261 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package maintenance rejects requests with 503 Service Unavailable while a service is in
+// maintenance, such as during a database cutover. Maintenance is turned on from an admin
+// endpoint or scheduled for a window of time.
+//
+//	mode := maintenance.New(maintenance.Config{
+//		Allow: []string{"/ping", "/health"},
+//	})
+//	adminServer.AddHandler("/maintenance", mode.Handler())
+//
+//	handler := mode.Middleware(router)
+//
+// Operators toggle it with:
+//
+//	curl -XPUT localhost:9090/maintenance -d '{"enabled":true,"message":"Database upgrade"}'
+//	curl -XPUT localhost:9090/maintenance -d '{"windows":[{"start":"2021-11-06T02:00:00Z","end":"2021-11-06T04:00:00Z"}]}'
+package maintenance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var rejectedRequests = kitprom.NewCounterFrom(stdprom.CounterOpts{
+	Name: "http_maintenance_rejected_requests_total",
+	Help: "Counter of HTTP requests rejected during maintenance",
+}, []string{"method"})
+
+// DefaultMessage is returned to rejected requests when none was given
+const DefaultMessage = "service is down for maintenance"
+
+// Config describes how requests are rejected during maintenance
+type Config struct {
+	// Allow are paths served during maintenance, such as health checks. Each matches the path
+	// itself and paths beneath it, so "/ping" allows "/ping/db" but not "/pingdom".
+	Allow []string
+
+	// RetryAfter is sent to clients when maintenance was enabled without an end. It defaults
+	// to 5 minutes. Clients are told the end of the window during scheduled maintenance.
+	RetryAfter time.Duration
+}
+
+// Window is a scheduled period of maintenance
+type Window struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Mode tracks whether a service is in maintenance. It's safe for concurrent use.
+type Mode struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	enabled bool
+	message string
+	windows []Window
+}
+
+// New returns a Mode which isn't in maintenance
+func New(cfg Config) *Mode {
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = 5 * time.Minute
+	}
+	return &Mode{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Enable starts maintenance until Disable is called. message is returned to rejected requests.
+func (m *Mode) Enable(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = true
+	m.message = message
+}
+
+// Disable ends maintenance started with Enable. Scheduled windows are kept.
+func (m *Mode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.enabled = false
+	m.message = ""
+}
+
+// Schedule replaces the scheduled windows. Windows which have ended are dropped.
+func (m *Mode) Schedule(windows ...Window) error {
+	for i := range windows {
+		if windows[i].Start.IsZero() || !windows[i].End.After(windows[i].Start) {
+			return fmt.Errorf("maintenance: window %d must end after it starts", i)
+		}
+	}
+	windows = append([]Window(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.windows = windows
+	m.prune(m.now())
+	return nil
+}
+
+// prune drops windows which have ended. m.mu must be held.
+func (m *Mode) prune(now time.Time) {
+	kept := m.windows[:0]
+	for i := range m.windows {
+		if m.windows[i].End.After(now) {
+			kept = append(kept, m.windows[i])
+		}
+	}
+	m.windows = kept
+}
+
+// Active reports whether the service is in maintenance, along with the message for clients
+// and how long until it's expected to end.
+func (m *Mode) Active() (bool, string, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.prune(now)
+
+	if m.enabled {
+		return true, messageOr(m.message), m.cfg.RetryAfter
+	}
+	for i := range m.windows {
+		w := m.windows[i]
+		if !now.Before(w.Start) && now.Before(w.End) {
+			return true, messageOr(w.Message), w.End.Sub(now)
+		}
+	}
+	return false, "", 0
+}
+
+func messageOr(message string) string {
+	if message == "" {
+		return DefaultMessage
+	}
+	return message
+}
+
+func (m *Mode) allowed(path string) bool {
+	for _, prefix := range m.cfg.Allow {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/' {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware rejects requests during maintenance with 503 and a Retry-After header, except
+// for paths under Config.Allow.
+func (m *Mode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		active, message, retryAfter := m.Active()
+		if !active || m.allowed(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rejectedRequests.With("method", r.Method).Add(1)
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": message,
+		})
+	})
+}
+
+type statusRequest struct {
+	Enabled *bool     `json:"enabled"`
+	Message string    `json:"message"`
+	Windows *[]Window `json:"windows"`
+}
+
+type statusResponse struct {
+	Active  bool     `json:"active"`
+	Enabled bool     `json:"enabled"`
+	Message string   `json:"message,omitempty"`
+	Windows []Window `json:"windows"`
+}
+
+// Handler serves 'GET' and 'PUT' of the maintenance status for an admin server. PUT requests
+// may set "enabled" (with an optional "message") and replace the scheduled "windows".
+func (m *Mode) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req statusRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, fmt.Errorf("invalid request: %v", err))
+				return
+			}
+			if req.Enabled == nil && req.Windows == nil {
+				writeError(w, errors.New("enabled or windows is required"))
+				return
+			}
+			if req.Windows != nil {
+				if err := m.Schedule(*req.Windows...); err != nil {
+					writeError(w, err)
+					return
+				}
+			}
+			if req.Enabled != nil {
+				if *req.Enabled {
+					m.Enable(req.Message)
+				} else {
+					m.Disable()
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		active, _, _ := m.Active()
+		m.mu.Lock()
+		resp := statusResponse{
+			Active:  active,
+			Enabled: m.enabled,
+			Message: m.message,
+			Windows: append([]Window{}, m.windows...),
+		}
+		m.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package maintenance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func serve(mode *Mode, method, path string) *httptest.ResponseRecorder {
+	handler := mode.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMode__Enable(t *testing.T) {
+	mode := New(Config{Allow: []string{"/ping"}})
+	require.Equal(t, http.StatusOK, serve(mode, "GET", "/transfers").Code)
+
+	mode.Enable("database upgrade")
+	w := serve(mode, "POST", "/transfers")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Equal(t, "300", w.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"error":"database upgrade"}`, w.Body.String())
+
+	require.Equal(t, http.StatusOK, serve(mode, "GET", "/ping").Code)
+	require.Equal(t, http.StatusOK, serve(mode, "GET", "/ping/db").Code)
+	require.Equal(t, http.StatusServiceUnavailable, serve(mode, "GET", "/pingdom").Code)
+
+	mode.Disable()
+	require.Equal(t, http.StatusOK, serve(mode, "GET", "/transfers").Code)
+}
+
+func TestMode__Schedule(t *testing.T) {
+	now := time.Date(2021, time.November, 6, 1, 0, 0, 0, time.UTC)
+	mode := New(Config{})
+	mode.now = func() time.Time { return now }
+
+	require.Error(t, mode.Schedule(Window{Start: now, End: now}))
+	require.NoError(t, mode.Schedule(
+		Window{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+		Window{Start: now.Add(time.Hour), End: now.Add(90 * time.Minute)},
+	))
+	require.Len(t, mode.windows, 1)
+
+	active, _, _ := mode.Active()
+	require.False(t, active)
+
+	now = now.Add(time.Hour + time.Minute)
+	active, message, retryAfter := mode.Active()
+	require.True(t, active)
+	require.Equal(t, DefaultMessage, message)
+	require.Equal(t, 29*time.Minute, retryAfter)
+
+	w := serve(mode, "GET", "/transfers")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Equal(t, "1740", w.Header().Get("Retry-After"))
+
+	now = now.Add(time.Hour)
+	active, _, _ = mode.Active()
+	require.False(t, active)
+	require.Empty(t, mode.windows)
+}
+
+func TestMode__Handler(t *testing.T) {
+	mode := New(Config{})
+	handler := mode.Handler()
+
+	call := func(method, body string) (*httptest.ResponseRecorder, statusResponse) {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(method, "/maintenance", strings.NewReader(body)))
+
+		var resp statusResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		}
+		return w, resp
+	}
+
+	w, resp := call("GET", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.False(t, resp.Active)
+
+	w, resp = call("PUT", `{"enabled":true,"message":"cutover"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, resp.Active)
+	require.Equal(t, "cutover", resp.Message)
+
+	start := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	end := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	w, resp = call("PUT", `{"enabled":false,"windows":[{"start":"`+start+`","end":"`+end+`"}]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.False(t, resp.Active)
+	require.Len(t, resp.Windows, 1)
+
+	w, _ = call("PUT", `{"windows":[{"start":"`+end+`","end":"`+start+`"}]}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = call("PUT", `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = call("DELETE", "")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
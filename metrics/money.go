@@ -0,0 +1,108 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package metrics implements helpers for recording business metrics (i.e. money movement)
+// on top of go-kit metrics.
+//
+// Amounts are recorded in integer minor units (i.e. cents) with a "currency" label rather
+// than float64 dollars. Values summed from these metrics will match ledger totals exactly.
+package metrics
+
+import (
+	"errors"
+
+	"github.com/moov-io/base"
+
+	kitmetrics "github.com/go-kit/kit/metrics"
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// CurrencyLabel is the label name added to every money metric
+	CurrencyLabel = "currency"
+)
+
+var (
+	// ErrNegativeAmount is returned when a negative Amount is added to a MoneyCounter
+	ErrNegativeAmount = errors.New("negative amount can not be added to a counter")
+
+	// MoneyBuckets are the default histogram buckets (in minor units) used when none are
+	// provided. They range from 1.00 to 10,000,000.00 in a two-decimal currency.
+	MoneyBuckets = []float64{1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9}
+)
+
+// MoneyCounter records the sum of Amounts in minor units, labeled by currency.
+type MoneyCounter struct {
+	counter kitmetrics.Counter
+}
+
+// NewMoneyCounter wraps counter which must accept a "currency" label.
+func NewMoneyCounter(counter kitmetrics.Counter) *MoneyCounter {
+	return &MoneyCounter{
+		counter: counter,
+	}
+}
+
+// NewMoneyCounterFrom creates and registers a Prometheus counter with labelNames plus "currency".
+func NewMoneyCounterFrom(opts stdprom.CounterOpts, labelNames []string) *MoneyCounter {
+	return NewMoneyCounter(kitprom.NewCounterFrom(opts, withCurrency(labelNames)))
+}
+
+// With returns a MoneyCounter with the additional label values applied.
+func (c *MoneyCounter) With(labelValues ...string) *MoneyCounter {
+	return &MoneyCounter{
+		counter: c.counter.With(labelValues...),
+	}
+}
+
+// Add records amt in minor units. Counters can only increase, so negative Amounts
+// are rejected with ErrNegativeAmount.
+func (c *MoneyCounter) Add(amt base.Amount) error {
+	if amt.Value < 0 {
+		return ErrNegativeAmount
+	}
+	c.counter.With(CurrencyLabel, amt.Currency).Add(float64(amt.Value))
+	return nil
+}
+
+// MoneyHistogram records the distribution of Amounts in minor units, labeled by currency.
+type MoneyHistogram struct {
+	histogram kitmetrics.Histogram
+}
+
+// NewMoneyHistogram wraps histogram which must accept a "currency" label.
+func NewMoneyHistogram(histogram kitmetrics.Histogram) *MoneyHistogram {
+	return &MoneyHistogram{
+		histogram: histogram,
+	}
+}
+
+// NewMoneyHistogramFrom creates and registers a Prometheus histogram with labelNames plus "currency".
+//
+// MoneyBuckets are used if opts has no buckets as Prometheus' defaults are designed for latencies.
+func NewMoneyHistogramFrom(opts stdprom.HistogramOpts, labelNames []string) *MoneyHistogram {
+	if len(opts.Buckets) == 0 {
+		opts.Buckets = MoneyBuckets
+	}
+	return NewMoneyHistogram(kitprom.NewHistogramFrom(opts, withCurrency(labelNames)))
+}
+
+// With returns a MoneyHistogram with the additional label values applied.
+func (h *MoneyHistogram) With(labelValues ...string) *MoneyHistogram {
+	return &MoneyHistogram{
+		histogram: h.histogram.With(labelValues...),
+	}
+}
+
+// Observe records amt in minor units.
+func (h *MoneyHistogram) Observe(amt base.Amount) {
+	h.histogram.With(CurrencyLabel, amt.Currency).Observe(float64(amt.Value))
+}
+
+func withCurrency(labelNames []string) []string {
+	out := make([]string, 0, len(labelNames)+1)
+	out = append(out, labelNames...)
+	return append(out, CurrencyLabel)
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/moov-io/base"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyCounter(t *testing.T) {
+	cv := stdprom.NewCounterVec(stdprom.CounterOpts{
+		Name: "test_transfers_amount",
+	}, []string{"direction", CurrencyLabel})
+
+	counter := NewMoneyCounter(kitprom.NewCounter(cv)).With("direction", "credit")
+	require.NoError(t, counter.Add(base.NewAmount(1234, "USD")))
+	require.NoError(t, counter.Add(base.NewAmount(1, "USD")))
+	require.NoError(t, counter.Add(base.NewAmount(500, "JPY")))
+
+	require.Equal(t, float64(1235), testutil.ToFloat64(cv.WithLabelValues("credit", "USD")))
+	require.Equal(t, float64(500), testutil.ToFloat64(cv.WithLabelValues("credit", "JPY")))
+
+	require.Equal(t, ErrNegativeAmount, counter.Add(base.NewAmount(-1, "USD")))
+	require.Equal(t, float64(1235), testutil.ToFloat64(cv.WithLabelValues("credit", "USD")))
+}
+
+func TestMoneyHistogram(t *testing.T) {
+	hist := NewMoneyHistogramFrom(stdprom.HistogramOpts{
+		Name: "test_transfer_amounts",
+	}, []string{"direction"})
+
+	hist.With("direction", "debit").Observe(base.NewAmount(-1234, "USD"))
+	hist.With("direction", "debit").Observe(base.NewAmount(1234, "USD"))
+}
+
+func TestMoney__withCurrency(t *testing.T) {
+	require.Equal(t, []string{"a", "b", CurrencyLabel}, withCurrency([]string{"a", "b"}))
+	require.Equal(t, []string{CurrencyLabel}, withCurrency(nil))
+}
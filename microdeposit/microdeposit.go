@@ -0,0 +1,182 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package microdeposit generates the small amounts sent to verify a bank account and checks
+// the amounts the account holder enters.
+//
+//	v, err := microdeposit.New(microdeposit.Config{Key: secret})
+//	amounts, challenge, err := v.Generate()
+//	// send amounts to the account, store challenge, then once the customer enters them
+//	err = v.Verify(&challenge, entered)
+//	// store challenge again, its attempts have changed
+//
+// Only an HMAC of the amounts is stored. There are few possible amounts, so without the key an
+// unkeyed hash could be reversed by trying each combination.
+package microdeposit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+var (
+	// ErrMismatch is returned when the entered amounts aren't the ones sent
+	ErrMismatch = errors.New("microdeposit amounts don't match")
+
+	// ErrTooManyAttempts is returned once a challenge has no attempts left
+	ErrTooManyAttempts = errors.New("too many microdeposit attempts")
+
+	// ErrExpired is returned for challenges older than Config.Expiration
+	ErrExpired = errors.New("microdeposit challenge expired")
+
+	// ErrVerified is returned for challenges which were already verified
+	ErrVerified = errors.New("microdeposits already verified")
+)
+
+// Config controls the amounts generated and how they're verified
+type Config struct {
+	// Key is the secret of amount hashes, at least 32 bytes. Changing it invalidates every
+	// outstanding challenge.
+	Key []byte
+
+	// Count is how many amounts are sent, 2 by default
+	Count int
+
+	// Min and Max are the range of each amount in minor units, 1 and 99 by default
+	Min, Max int64
+
+	// Currency of amounts, USD by default
+	Currency string
+
+	// MaxAttempts is how many times amounts can be entered, 3 by default
+	MaxAttempts int
+
+	// Expiration is how long challenges can be verified for, forever when zero
+	Expiration time.Duration
+}
+
+// Challenge is the stored state of sent microdeposits
+type Challenge struct {
+	Hash       string     `json:"hash"`
+	Attempts   int        `json:"attempts"`
+	CreatedAt  base.Time  `json:"createdAt"`
+	VerifiedAt *base.Time `json:"verifiedAt,omitempty"`
+}
+
+// Verifier generates and verifies microdeposits
+type Verifier struct {
+	cfg Config
+}
+
+// New returns a Verifier of cfg
+func New(cfg Config) (*Verifier, error) {
+	if len(cfg.Key) < 32 {
+		return nil, fmt.Errorf("microdeposit: key must be at least 32 bytes, got %d", len(cfg.Key))
+	}
+	if cfg.Count <= 0 {
+		cfg.Count = 2
+	}
+	if cfg.Min <= 0 && cfg.Max <= 0 {
+		cfg.Min, cfg.Max = 1, 99
+	}
+	if cfg.Min <= 0 || cfg.Max < cfg.Min {
+		return nil, fmt.Errorf("microdeposit: invalid range %d to %d", cfg.Min, cfg.Max)
+	}
+	if cfg.Max-cfg.Min+1 < int64(cfg.Count) {
+		return nil, fmt.Errorf("microdeposit: range %d to %d has fewer than %d amounts", cfg.Min, cfg.Max, cfg.Count)
+	}
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	if cfg.Currency == "" {
+		cfg.Currency = "USD"
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	return &Verifier{cfg: cfg}, nil
+}
+
+// Generate returns distinct random amounts to send and the Challenge to store for them. Amounts
+// are read from crypto/rand rather than randx, which tests can seed, as they're a secret.
+func (v *Verifier) Generate() ([]base.Amount, Challenge, error) {
+	span := big.NewInt(v.cfg.Max - v.cfg.Min + 1)
+	seen := make(map[int64]bool, v.cfg.Count)
+	amounts := make([]base.Amount, 0, v.cfg.Count)
+	for len(amounts) < v.cfg.Count {
+		r, err := rand.Int(rand.Reader, span)
+		if err != nil {
+			return nil, Challenge{}, fmt.Errorf("microdeposit: generating amounts: %v", err)
+		}
+		n := v.cfg.Min + r.Int64()
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		amounts = append(amounts, base.NewAmount(n, v.cfg.Currency))
+	}
+	hash, err := v.hash(amounts)
+	if err != nil {
+		return nil, Challenge{}, err
+	}
+	return amounts, Challenge{Hash: hash, CreatedAt: base.Now()}, nil
+}
+
+// Remaining returns how many attempts c has left
+func (v *Verifier) Remaining(c Challenge) int {
+	if n := v.cfg.MaxAttempts - c.Attempts; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Verify checks entered, in any order, against c and counts the attempt on c, which must be
+// stored again after Verify returns whether or not it matched. Amounts in another currency or
+// of the wrong count are a mismatch.
+func (v *Verifier) Verify(c *Challenge, entered []base.Amount) error {
+	switch {
+	case c.VerifiedAt != nil:
+		return ErrVerified
+	case v.Remaining(*c) == 0:
+		return ErrTooManyAttempts
+	case v.cfg.Expiration > 0 && base.Now().Sub(c.CreatedAt.Time) > v.cfg.Expiration:
+		return ErrExpired
+	}
+	c.Attempts++
+
+	hash, err := v.hash(entered)
+	if err != nil || len(entered) != v.cfg.Count || !hmac.Equal([]byte(hash), []byte(c.Hash)) {
+		if v.Remaining(*c) == 0 {
+			return ErrTooManyAttempts
+		}
+		return ErrMismatch
+	}
+	now := base.Now()
+	c.VerifiedAt = &now
+	return nil
+}
+
+// hash returns the HMAC of amounts sorted so the order they're entered in doesn't matter
+func (v *Verifier) hash(amounts []base.Amount) (string, error) {
+	values := make([]string, len(amounts))
+	for i, amt := range amounts {
+		if !strings.EqualFold(amt.Currency, v.cfg.Currency) {
+			return "", fmt.Errorf("microdeposit: %s amount, expected %s", amt.Currency, v.cfg.Currency)
+		}
+		values[i] = fmt.Sprint(amt.Value)
+	}
+	sort.Strings(values)
+
+	mac := hmac.New(sha256.New, v.cfg.Key)
+	mac.Write([]byte(strings.Join(values, ",")))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package microdeposit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/testtime"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte("k"), 32)
+
+func TestGenerate(t *testing.T) {
+	v, err := New(Config{Key: testKey})
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		amounts, challenge, err := v.Generate()
+		require.NoError(t, err)
+		require.Len(t, amounts, 2)
+		require.NotEqual(t, amounts[0], amounts[1])
+		for _, amt := range amounts {
+			require.Equal(t, "USD", amt.Currency)
+			require.True(t, amt.Value >= 1 && amt.Value <= 99, amt.String())
+		}
+		require.Len(t, challenge.Hash, 64)
+		require.Zero(t, challenge.Attempts)
+	}
+
+	// the whole range is used when it's only as large as the count
+	v, err = New(Config{Key: testKey, Count: 3, Min: 5, Max: 7, Currency: "eur"})
+	require.NoError(t, err)
+	amounts, _, err := v.Generate()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []base.Amount{
+		base.NewAmount(5, "EUR"), base.NewAmount(6, "EUR"), base.NewAmount(7, "EUR"),
+	}, amounts)
+}
+
+func TestNew__Errors(t *testing.T) {
+	_, err := New(Config{Key: []byte("short")})
+	require.ErrorContains(t, err, "key must be at least 32 bytes")
+
+	_, err = New(Config{Key: testKey, Min: 10, Max: 5})
+	require.ErrorContains(t, err, "invalid range")
+
+	_, err = New(Config{Key: testKey, Count: 5, Min: 1, Max: 3})
+	require.ErrorContains(t, err, "fewer than 5 amounts")
+}
+
+func TestVerify(t *testing.T) {
+	testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	v, err := New(Config{Key: testKey})
+	require.NoError(t, err)
+
+	amounts, challenge, err := v.Generate()
+	require.NoError(t, err)
+
+	// challenges survive a round trip through storage
+	bs, err := json.Marshal(challenge)
+	require.NoError(t, err)
+	var stored Challenge
+	require.NoError(t, json.Unmarshal(bs, &stored))
+
+	wrong := []base.Amount{base.NewAmount(amounts[0].Value, "USD"), base.NewAmount(amounts[0].Value, "USD")}
+	require.ErrorIs(t, v.Verify(&stored, wrong), ErrMismatch)
+	require.Equal(t, 1, stored.Attempts)
+	require.Equal(t, 2, v.Remaining(stored))
+
+	require.ErrorIs(t, v.Verify(&stored, amounts[:1]), ErrMismatch)
+
+	// entered in either order
+	require.NoError(t, v.Verify(&stored, []base.Amount{amounts[1], amounts[0]}))
+	require.NotNil(t, stored.VerifiedAt)
+	require.Equal(t, 3, stored.Attempts)
+
+	require.ErrorIs(t, v.Verify(&stored, amounts), ErrVerified)
+}
+
+func TestVerify__Attempts(t *testing.T) {
+	v, err := New(Config{Key: testKey, Min: 10, Max: 20, MaxAttempts: 2})
+	require.NoError(t, err)
+
+	amounts, challenge, err := v.Generate()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, v.Verify(&challenge, []base.Amount{base.NewAmount(1, "USD"), base.NewAmount(2, "USD")}), ErrMismatch)
+	require.ErrorIs(t, v.Verify(&challenge, []base.Amount{base.NewAmount(amounts[0].Value, "EUR"), amounts[1]}), ErrTooManyAttempts)
+	require.Zero(t, v.Remaining(challenge))
+
+	// correct amounts are rejected once attempts run out
+	require.ErrorIs(t, v.Verify(&challenge, amounts), ErrTooManyAttempts)
+	require.Equal(t, 2, challenge.Attempts)
+}
+
+func TestVerify__Expiration(t *testing.T) {
+	clock := testtime.Freeze(t, time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC))
+
+	v, err := New(Config{Key: testKey, Expiration: 7 * 24 * time.Hour})
+	require.NoError(t, err)
+
+	amounts, challenge, err := v.Generate()
+	require.NoError(t, err)
+
+	clock.Change(clock.Now().Add(8 * 24 * time.Hour))
+	require.ErrorIs(t, v.Verify(&challenge, amounts), ErrExpired)
+	require.Zero(t, challenge.Attempts)
+}
+
+func TestVerify__Key(t *testing.T) {
+	v, err := New(Config{Key: testKey})
+	require.NoError(t, err)
+	amounts, challenge, err := v.Generate()
+	require.NoError(t, err)
+
+	other, err := New(Config{Key: bytes.Repeat([]byte("o"), 32)})
+	require.NoError(t, err)
+	require.ErrorIs(t, other.Verify(&challenge, amounts), ErrMismatch)
+}
@@ -0,0 +1 @@
+create table file_submissions (submission_id varchar(64) not null primary key, size bigint not null, hash char(64) not null, records integer not null, currency char(3) not null, total bigint not null, submitted_at bigint not null)
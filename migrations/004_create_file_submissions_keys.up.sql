@@ -0,0 +1 @@
+create table file_submissions_keys (contents_key varchar(128) not null primary key, updated_at bigint not null)
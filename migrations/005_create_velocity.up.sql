@@ -0,0 +1 @@
+create table velocity (event_key varchar(128) not null, currency char(3) not null, value bigint not null, created_at bigint not null)
@@ -0,0 +1 @@
+create table velocity_keys (event_key varchar(128) not null primary key, updated_at bigint not null)
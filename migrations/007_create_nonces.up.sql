@@ -0,0 +1 @@
+create table nonces (purpose varchar(64) not null, value varchar(128) not null, data text not null, expires_at bigint not null, primary key (purpose, value))
@@ -0,0 +1 @@
+create table transfer_changes (entity varchar(128) not null, sequence bigint not null, change_type varchar(64) not null, data text not null, created_at bigint not null, prev_hash char(64) not null, hash char(64) not null, primary key (entity, sequence))
@@ -0,0 +1 @@
+create table transfer_snapshots (entity varchar(128) not null primary key, sequence bigint not null, hash char(64) not null, state text not null, created_at bigint not null)
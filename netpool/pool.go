@@ -0,0 +1,291 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package netpool implements a pool of persistent TCP (or TLS) connections for partner protocols
+// which keep sockets open between messages.
+//
+//	pool, err := netpool.New(netpool.Config{
+//		Dial: func(ctx context.Context) (net.Conn, error) {
+//			return tls.Dial("tcp", "processor.example.com:7000", tlsConfig)
+//		},
+//		MaxOpen:     4,
+//		MaxLifetime: time.Hour,
+//		IdleTimeout: 5 * time.Minute,
+//	})
+//
+//	conn, err := pool.Get(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Release()
+//
+//	if err := write(conn, msg); err != nil {
+//		conn.MarkUnusable() // closed instead of returned to the pool
+//		return err
+//	}
+package netpool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrClosed is returned from Get after the Pool is closed
+	ErrClosed = errors.New("netpool: pool closed")
+)
+
+// Config describes how a Pool opens and maintains connections
+type Config struct {
+	// Dial opens a new connection. It's required.
+	Dial func(ctx context.Context) (net.Conn, error)
+
+	// MaxOpen limits the connections open at once, including those borrowed. Get blocks once
+	// the limit is reached. Zero means no limit.
+	MaxOpen int
+
+	// MaxIdle limits the connections kept open while not borrowed. It defaults to 2.
+	MaxIdle int
+
+	// MaxLifetime closes connections, once released, which have been open longer than this.
+	// Zero means connections are reused forever.
+	MaxLifetime time.Duration
+
+	// IdleTimeout closes, rather than borrows, connections which have been idle this long.
+	// Zero means no timeout.
+	IdleTimeout time.Duration
+
+	// HealthCheck is called on an idle connection before it's borrowed. Connections which return
+	// an error are closed and another is tried.
+	HealthCheck func(net.Conn) error
+}
+
+const defaultMaxIdle = 2
+
+// Pool holds connections to a single partner. It's safe for concurrent use.
+type Pool struct {
+	cfg Config
+	now func() time.Time
+
+	mu     sync.Mutex
+	idle   []*Conn
+	open   int
+	closed bool
+
+	// slots has a token for each connection which may be opened when MaxOpen is set
+	slots chan struct{}
+}
+
+// New returns a Pool. No connections are opened until Get is called.
+func New(cfg Config) (*Pool, error) {
+	if cfg.Dial == nil {
+		return nil, errors.New("netpool: missing Dial")
+	}
+	if cfg.MaxIdle <= 0 {
+		cfg.MaxIdle = defaultMaxIdle
+	}
+	if cfg.MaxOpen > 0 && cfg.MaxIdle > cfg.MaxOpen {
+		cfg.MaxIdle = cfg.MaxOpen
+	}
+	p := &Pool{
+		cfg: cfg,
+		now: time.Now,
+	}
+	if cfg.MaxOpen > 0 {
+		p.slots = make(chan struct{}, cfg.MaxOpen)
+	}
+	return p, nil
+}
+
+// Get borrows an idle connection or dials a new one. Borrowed connections must be returned
+// with Release.
+func (p *Pool) Get(ctx context.Context) (*Conn, error) {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		conn, err := p.popIdle()
+		if err != nil {
+			p.releaseSlot()
+			return nil, err
+		}
+		if conn == nil {
+			break
+		}
+		if p.cfg.HealthCheck != nil {
+			if err := p.cfg.HealthCheck(conn.Conn); err != nil {
+				p.closeConn(conn)
+				continue
+			}
+		}
+		conn.released = false
+		return conn, nil
+	}
+
+	nc, err := p.cfg.Dial(ctx)
+	if err != nil {
+		p.releaseSlot()
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		nc.Close()
+		p.releaseSlot()
+		return nil, ErrClosed
+	}
+	p.open++
+	p.mu.Unlock()
+
+	return &Conn{
+		Conn:    nc,
+		pool:    p,
+		created: p.now(),
+	}, nil
+}
+
+// popIdle returns the most recently used idle connection, closing any which have expired
+func (p *Pool) popIdle() (*Conn, error) {
+	var expired []*Conn
+	defer func() {
+		for i := range expired {
+			expired[i].Conn.Close()
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClosed
+	}
+	now := p.now()
+	for len(p.idle) > 0 {
+		conn := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if p.expired(conn, now) {
+			p.open--
+			expired = append(expired, conn)
+			continue
+		}
+		return conn, nil
+	}
+	return nil, nil
+}
+
+func (p *Pool) expired(conn *Conn, now time.Time) bool {
+	if p.cfg.MaxLifetime > 0 && now.Sub(conn.created) >= p.cfg.MaxLifetime {
+		return true
+	}
+	if p.cfg.IdleTimeout > 0 && now.Sub(conn.returned) >= p.cfg.IdleTimeout {
+		return true
+	}
+	return false
+}
+
+func (p *Pool) put(conn *Conn, unusable bool) {
+	p.mu.Lock()
+	now := p.now()
+	conn.returned = now
+
+	keep := !unusable && !p.closed && len(p.idle) < p.cfg.MaxIdle
+	if keep && p.cfg.MaxLifetime > 0 && now.Sub(conn.created) >= p.cfg.MaxLifetime {
+		keep = false
+	}
+	if keep {
+		p.idle = append(p.idle, conn)
+	} else {
+		p.open--
+	}
+	p.mu.Unlock()
+
+	if !keep {
+		conn.Conn.Close()
+	}
+	p.releaseSlot()
+}
+
+func (p *Pool) closeConn(conn *Conn) {
+	p.mu.Lock()
+	p.open--
+	p.mu.Unlock()
+	conn.Conn.Close()
+}
+
+func (p *Pool) releaseSlot() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// Stats describes the connections of a Pool
+type Stats struct {
+	Open int // borrowed and idle connections
+	Idle int
+}
+
+// Stats returns the current number of connections
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Stats{
+		Open: p.open,
+		Idle: len(p.idle),
+	}
+}
+
+// Close closes every idle connection. Borrowed connections are closed when they're released.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.open -= len(idle)
+	p.closed = true
+	p.mu.Unlock()
+
+	var firstErr error
+	for i := range idle {
+		if err := idle[i].Conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Conn is a connection borrowed from a Pool
+type Conn struct {
+	net.Conn
+
+	pool     *Pool
+	created  time.Time
+	returned time.Time
+
+	unusable bool
+	released bool
+}
+
+// MarkUnusable flags the connection to be closed on Release, i.e. after a write error left
+// the protocol in an unknown state.
+func (c *Conn) MarkUnusable() {
+	c.unusable = true
+}
+
+// Release returns the connection to its Pool. It's safe to call multiple times.
+func (c *Conn) Release() {
+	if c == nil || c.released {
+		return
+	}
+	c.released = true
+	c.pool.put(c, c.unusable)
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package netpool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func listen(t *testing.T) (string, *int32) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	var accepted int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+			go func() {
+				buf := make([]byte, 16)
+				for {
+					if _, err := conn.Read(buf); err != nil {
+						conn.Close()
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), &accepted
+}
+
+func requireAccepted(t *testing.T, accepted *int32, n int32) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(accepted) == n
+	}, time.Second, time.Millisecond)
+}
+
+func newPool(t *testing.T, cfg Config) (*Pool, *int32) {
+	t.Helper()
+
+	addr, accepted := listen(t)
+	cfg.Dial = func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	pool, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { pool.Close() })
+	return pool, accepted
+}
+
+func TestPool__Reuse(t *testing.T) {
+	pool, accepted := newPool(t, Config{})
+	ctx := context.Background()
+
+	conn, err := pool.Get(ctx)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	conn.Release()
+	conn.Release()
+	require.Equal(t, Stats{Open: 1, Idle: 1}, pool.Stats())
+
+	again, err := pool.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, conn.Conn, again.Conn)
+	require.Equal(t, Stats{Open: 1, Idle: 0}, pool.Stats())
+
+	// unusable connections are closed
+	again.MarkUnusable()
+	again.Release()
+	require.Equal(t, Stats{Open: 0, Idle: 0}, pool.Stats())
+
+	conn, err = pool.Get(ctx)
+	require.NoError(t, err)
+	conn.Release()
+	requireAccepted(t, accepted, 2)
+}
+
+func TestPool__MaxOpen(t *testing.T) {
+	pool, _ := newPool(t, Config{MaxOpen: 1})
+
+	conn, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = pool.Get(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		conn.Release()
+	}()
+	again, err := pool.Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, conn.Conn, again.Conn)
+	again.Release()
+}
+
+func TestPool__Expiry(t *testing.T) {
+	now := time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)
+	pool, accepted := newPool(t, Config{
+		MaxLifetime: time.Hour,
+		IdleTimeout: 5 * time.Minute,
+	})
+	pool.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	conn, _ := pool.Get(ctx)
+	conn.Release()
+
+	// idle too long
+	now = now.Add(10 * time.Minute)
+	conn, _ = pool.Get(ctx)
+	requireAccepted(t, accepted, 2)
+
+	// open too long, closed on release
+	now = now.Add(2 * time.Hour)
+	conn.Release()
+	require.Equal(t, Stats{}, pool.Stats())
+}
+
+func TestPool__HealthCheck(t *testing.T) {
+	var healthy atomic.Value
+	healthy.Store(true)
+	pool, accepted := newPool(t, Config{
+		HealthCheck: func(conn net.Conn) error {
+			if !healthy.Load().(bool) {
+				return errors.New("stale connection")
+			}
+			return nil
+		},
+	})
+	ctx := context.Background()
+
+	conn, _ := pool.Get(ctx)
+	conn.Release()
+
+	healthy.Store(false)
+	conn, err := pool.Get(ctx)
+	require.NoError(t, err)
+	requireAccepted(t, accepted, 2)
+	require.Equal(t, Stats{Open: 1}, pool.Stats())
+	conn.Release()
+}
+
+func TestPool__Close(t *testing.T) {
+	pool, _ := newPool(t, Config{})
+	ctx := context.Background()
+
+	idle, _ := pool.Get(ctx)
+	borrowed, _ := pool.Get(ctx)
+	idle.Release()
+
+	require.NoError(t, pool.Close())
+	require.Equal(t, Stats{Open: 1}, pool.Stats())
+
+	_, err := pool.Get(ctx)
+	require.Equal(t, ErrClosed, err)
+
+	borrowed.Release()
+	require.Equal(t, Stats{}, pool.Stats())
+}
+
+func TestNew__Invalid(t *testing.T) {
+	_, err := New(Config{})
+	require.Error(t, err)
+
+	pool, err := New(Config{
+		Dial:    func(ctx context.Context) (net.Conn, error) { return nil, errors.New("connection refused") },
+		MaxOpen: 1,
+	})
+	require.NoError(t, err)
+	_, err = pool.Get(context.Background())
+	require.EqualError(t, err, "connection refused")
+
+	// the failed dial doesn't hold a slot
+	_, err = pool.Get(context.Background())
+	require.EqualError(t, err, "connection refused")
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package nonce issues single use values which expire, such as webhook challenges, OAuth state
+// and file upload sessions. Consuming a nonce removes it so replayed callbacks are rejected.
+//
+//	nonces := nonce.New(nonce.NewSQLStore(db, "nonces"), nonce.Config{TTL: 10 * time.Minute})
+//	defer nonces.Close()
+//
+//	n, err := nonces.Issue(ctx, "oauth-state", returnURL)
+//	// redirect with state=n.Value, then in the callback
+//	n, err = nonces.Consume(ctx, "oauth-state", r.URL.Query().Get("state"))
+package nonce
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base/log"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrNotFound is returned when a nonce was never issued, has expired or was already consumed
+var ErrNotFound = errors.New("nonce not found")
+
+// Nonce is a single use value
+type Nonce struct {
+	Value string
+
+	// Purpose scopes the nonce so one issued for a webhook challenge can't be used as OAuth state
+	Purpose string
+
+	// Data is stored with the nonce and returned when it's consumed
+	Data string
+
+	ExpiresAt time.Time
+}
+
+// Store saves issued nonces. Implementations must be safe for concurrent use and Consume must
+// only return a nonce once.
+type Store interface {
+	Put(ctx context.Context, n Nonce) error
+
+	// Consume removes and returns the unexpired nonce, or returns ErrNotFound
+	Consume(ctx context.Context, purpose, value string, now time.Time) (Nonce, error)
+
+	// DeleteExpired removes nonces which expired before now and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Config describes how nonces are issued
+type Config struct {
+	// TTL is how long nonces are valid for. It defaults to 10 minutes.
+	TTL time.Duration
+
+	// Length is how many characters nonces have. It defaults to 32.
+	Length int
+
+	// GCInterval is how often expired nonces are deleted. It defaults to TTL, negative disables it.
+	GCInterval time.Duration
+
+	// Logger records failures to delete expired nonces when set
+	Logger log.Logger
+}
+
+// Manager issues and consumes nonces
+type Manager struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// New returns a Manager saving nonces in store and deleting expired ones in the background
+// until Close is called.
+func New(store Store, cfg Config) *Manager {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 10 * time.Minute
+	}
+	if cfg.Length <= 0 {
+		cfg.Length = 32
+	}
+	if cfg.GCInterval == 0 {
+		cfg.GCInterval = cfg.TTL
+	}
+	m := &Manager{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if cfg.GCInterval > 0 {
+		go m.gc()
+	} else {
+		close(m.done)
+	}
+	return m
+}
+
+// Issue returns a new nonce for purpose which stores data
+func (m *Manager) Issue(ctx context.Context, purpose, data string) (Nonce, error) {
+	value, err := random(m.cfg.Length)
+	if err != nil {
+		return Nonce{}, fmt.Errorf("nonce: generating: %w", err)
+	}
+	n := Nonce{
+		Value:     value,
+		Purpose:   purpose,
+		Data:      data,
+		ExpiresAt: m.now().Add(m.cfg.TTL),
+	}
+	if err := m.store.Put(ctx, n); err != nil {
+		return Nonce{}, fmt.Errorf("nonce: saving: %w", err)
+	}
+	return n, nil
+}
+
+// random returns n characters of alphabet read from crypto/rand. Nonces don't use randx because
+// tests can seed it, which would make nonces replayable.
+func random(n int) (string, error) {
+	// bytes past the largest multiple of len(alphabet) are skipped to avoid modulo bias
+	limit := 256 - 256%len(alphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(out) < n {
+				out = append(out, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(out), nil
+}
+
+// Consume returns the nonce issued for purpose and removes it. ErrNotFound is returned when
+// value wasn't issued for purpose, has expired or was already consumed.
+func (m *Manager) Consume(ctx context.Context, purpose, value string) (Nonce, error) {
+	if value == "" {
+		return Nonce{}, ErrNotFound
+	}
+	return m.store.Consume(ctx, purpose, value, m.now())
+}
+
+func (m *Manager) gc() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := m.store.DeleteExpired(context.Background(), m.now()); err != nil && m.cfg.Logger != nil {
+				m.cfg.Logger.Error().LogErrorf("nonce: deleting expired: %v", err)
+			}
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Close stops deleting expired nonces
+func (m *Manager) Close() error {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	<-m.done
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package nonce
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/database"
+	"github.com/moov-io/base/randx/randxtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sqliteStore(t *testing.T) Store {
+	t.Helper()
+
+	db := database.CreateTestSQLiteDB(t)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	return NewSQLStore(db.DB, "nonces")
+}
+
+func stores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore(t),
+	}
+}
+
+func TestManager(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			m := New(store, Config{TTL: time.Minute, GCInterval: -1})
+			defer m.Close()
+
+			now := time.Now()
+			m.now = func() time.Time { return now }
+
+			n, err := m.Issue(ctx, "oauth-state", "/dashboard")
+			require.NoError(t, err)
+			require.Len(t, n.Value, 32)
+
+			_, err = m.Consume(ctx, "webhook", n.Value)
+			require.ErrorIs(t, err, ErrNotFound)
+
+			got, err := m.Consume(ctx, "oauth-state", n.Value)
+			require.NoError(t, err)
+			require.Equal(t, "/dashboard", got.Data)
+			require.True(t, n.ExpiresAt.Equal(got.ExpiresAt))
+
+			// replayed
+			_, err = m.Consume(ctx, "oauth-state", n.Value)
+			require.ErrorIs(t, err, ErrNotFound)
+
+			_, err = m.Consume(ctx, "oauth-state", "")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			// expired
+			n, err = m.Issue(ctx, "oauth-state", "")
+			require.NoError(t, err)
+			now = now.Add(time.Minute)
+			_, err = m.Consume(ctx, "oauth-state", n.Value)
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestManager__Seeded(t *testing.T) {
+	// nonces are read from crypto/rand, so seeding randx doesn't repeat them
+	m := New(NewMemoryStore(), Config{TTL: time.Minute, GCInterval: -1})
+	t.Cleanup(func() { m.Close() })
+
+	randxtest.Seed(t, 42)
+	first, err := m.Issue(context.Background(), "email", "")
+	require.NoError(t, err)
+
+	randxtest.Seed(t, 42)
+	second, err := m.Issue(context.Background(), "email", "")
+	require.NoError(t, err)
+	require.NotEqual(t, first.Value, second.Value)
+}
+
+func TestStore__DeleteExpired(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+
+			require.NoError(t, store.Put(ctx, Nonce{Value: "a", Purpose: "upload", ExpiresAt: now.Add(-time.Second)}))
+			require.NoError(t, store.Put(ctx, Nonce{Value: "b", Purpose: "upload", ExpiresAt: now.Add(time.Hour)}))
+
+			deleted, err := store.DeleteExpired(ctx, now)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), deleted)
+
+			_, err = store.Consume(ctx, "upload", "b", now)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStore__ConsumeOnce(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			require.NoError(t, store.Put(ctx, Nonce{Value: "a", Purpose: "webhook", ExpiresAt: time.Now().Add(time.Hour)}))
+
+			var consumed int32
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := store.Consume(ctx, "webhook", "a", time.Now()); err == nil {
+						atomic.AddInt32(&consumed, 1)
+					}
+				}()
+			}
+			wg.Wait()
+			require.Equal(t, int32(1), consumed)
+		})
+	}
+}
+
+func TestManager__GC(t *testing.T) {
+	store := NewMemoryStore()
+	m := New(store, Config{TTL: time.Millisecond, GCInterval: 5 * time.Millisecond})
+
+	_, err := m.Issue(context.Background(), "upload", "")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.nonces) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, m.Close())
+	require.NoError(t, m.Close())
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package nonce
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type memoryKey struct {
+	purpose, value string
+}
+
+// MemoryStore keeps nonces in memory, which suits a single instance or tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	nonces map[memoryKey]Nonce
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nonces: make(map[memoryKey]Nonce),
+	}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, n Nonce) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nonces[memoryKey{n.Purpose, n.Value}] = n
+	return nil
+}
+
+func (s *MemoryStore) Consume(ctx context.Context, purpose, value string, now time.Time) (Nonce, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey{purpose, value}
+	n, ok := s.nonces[key]
+	if !ok {
+		return Nonce{}, ErrNotFound
+	}
+	delete(s.nonces, key)
+	if !now.Before(n.ExpiresAt) {
+		return Nonce{}, ErrNotFound
+	}
+	return n, nil
+}
+
+func (s *MemoryStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, n := range s.nonces {
+		if !now.Before(n.ExpiresAt) {
+			delete(s.nonces, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// SQLStore keeps nonces in a table shared by every instance of a service. The table is created
+// by the service's migrations:
+//
+//	CREATE TABLE nonces (
+//	    purpose VARCHAR(64) NOT NULL,
+//	    value VARCHAR(128) NOT NULL,
+//	    data TEXT NOT NULL,
+//	    expires_at BIGINT NOT NULL,
+//	    PRIMARY KEY (purpose, value)
+//	);
+//	CREATE INDEX nonces_expires_at ON nonces (expires_at);
+//
+// expires_at holds Unix nanoseconds. Queries use ? placeholders for MySQL and SQLite.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store using table in db
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	return &SQLStore{
+		db:    db,
+		table: table,
+	}
+}
+
+func (s *SQLStore) Put(ctx context.Context, n Nonce) error {
+	query := fmt.Sprintf(`INSERT INTO %s (purpose, value, data, expires_at) VALUES (?, ?, ?, ?)`, s.table)
+	_, err := s.db.ExecContext(ctx, query, n.Purpose, n.Value, n.Data, n.ExpiresAt.UnixNano())
+	return err
+}
+
+func (s *SQLStore) Consume(ctx context.Context, purpose, value string, now time.Time) (Nonce, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Nonce{}, err
+	}
+	defer tx.Rollback()
+
+	n := Nonce{Purpose: purpose, Value: value}
+	var expiresAt int64
+	query := fmt.Sprintf(`SELECT data, expires_at FROM %s WHERE purpose = ? AND value = ?`, s.table)
+	err = tx.QueryRowContext(ctx, query, purpose, value).Scan(&n.Data, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Nonce{}, ErrNotFound
+	}
+	if err != nil {
+		return Nonce{}, err
+	}
+
+	// only the caller whose delete removed the row may use the nonce
+	query = fmt.Sprintf(`DELETE FROM %s WHERE purpose = ? AND value = ?`, s.table)
+	res, err := tx.ExecContext(ctx, query, purpose, value)
+	if err != nil {
+		return Nonce{}, err
+	}
+	if deleted, err := res.RowsAffected(); err != nil || deleted != 1 {
+		return Nonce{}, ErrNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return Nonce{}, err
+	}
+
+	n.ExpiresAt = time.Unix(0, expiresAt)
+	if !now.Before(n.ExpiresAt) {
+		return Nonce{}, ErrNotFound
+	}
+	return n, nil
+}
+
+func (s *SQLStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE expires_at <= ?`, s.table)
+	res, err := s.db.ExecContext(ctx, query, now.UnixNano())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package optional implements a value which may be absent. It's encoded as null in JSON and
+// NULL in SQL when absent.
+//
+//	type UpdateCustomer struct {
+//		Email optional.Optional[string] `json:"email"`
+//	}
+//
+//	if email, ok := req.Email.Get(); ok {
+//		customer.Email = email
+//	}
+package optional
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Optional holds a value of T which may be absent. The zero value is absent.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Of returns a present Optional of v
+func Of[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// None returns an absent Optional
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// FromPtr returns an Optional of the value p points to, or an absent one when p is nil.
+func FromPtr[T any](p *T) Optional[T] {
+	if p == nil {
+		return None[T]()
+	}
+	return Of(*p)
+}
+
+// Present returns true when the Optional holds a value
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+// Get returns the value and if it's present
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// OrElse returns the value when present, otherwise fallback.
+func (o Optional[T]) OrElse(fallback T) T {
+	if o.present {
+		return o.value
+	}
+	return fallback
+}
+
+// Ptr returns a pointer to a copy of the value, or nil when absent.
+func (o Optional[T]) Ptr() *T {
+	if !o.present {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+func (o Optional[T]) String() string {
+	if !o.present {
+		return "None"
+	}
+	return fmt.Sprintf("%v", o.value)
+}
+
+// MarshalJSON encodes an absent value as null
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes null as absent
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Of(v)
+	return nil
+}
+
+// Scan implements sql.Scanner, NULL is absent.
+func (o *Optional[T]) Scan(src interface{}) error {
+	if src == nil {
+		*o = None[T]()
+		return nil
+	}
+
+	var v T
+	if scanner, ok := any(&v).(sql.Scanner); ok {
+		if err := scanner.Scan(src); err != nil {
+			return err
+		}
+		*o = Of(v)
+		return nil
+	}
+	if typed, ok := src.(T); ok {
+		*o = Of(typed)
+		return nil
+	}
+
+	// int64 into int, []byte into string, etc
+	sv, target := reflect.ValueOf(src), reflect.TypeOf(v)
+	if target != nil && convertible(sv.Type(), target) {
+		*o = Of(sv.Convert(target).Interface().(T))
+		return nil
+	}
+	return fmt.Errorf("optional: cannot scan %T into %T", src, v)
+}
+
+// convertible returns true for the conversions of driver values which keep their meaning,
+// unlike reflect's int to string.
+func convertible(src, target reflect.Type) bool {
+	switch {
+	case numeric(src.Kind()) && numeric(target.Kind()):
+		return true
+	case target.Kind() == reflect.String:
+		return src.Kind() == reflect.String || src == reflect.TypeOf([]byte(nil))
+	case target.Kind() == reflect.Bool:
+		return src.Kind() == reflect.Bool
+	}
+	return false
+}
+
+func numeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Value implements driver.Valuer, absent values are NULL.
+func (o Optional[T]) Value() (driver.Value, error) {
+	if !o.present {
+		return nil, nil
+	}
+	if valuer, ok := any(o.value).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
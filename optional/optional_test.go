@@ -0,0 +1,88 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package optional
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestOptional(t *testing.T) {
+	o := Of("memo")
+	require.True(t, o.Present())
+	v, ok := o.Get()
+	require.True(t, ok)
+	require.Equal(t, "memo", v)
+	require.Equal(t, "memo", o.OrElse("none"))
+	require.Equal(t, "memo", *o.Ptr())
+	require.Equal(t, "memo", o.String())
+
+	none := None[int]()
+	require.False(t, none.Present())
+	require.Equal(t, 5, none.OrElse(5))
+	require.Nil(t, none.Ptr())
+	require.Equal(t, "None", none.String())
+
+	require.False(t, FromPtr[int](nil).Present())
+	n := 3
+	require.Equal(t, 3, FromPtr(&n).OrElse(0))
+}
+
+func TestOptional__JSON(t *testing.T) {
+	type request struct {
+		Email  Optional[string] `json:"email"`
+		Amount Optional[int]    `json:"amount"`
+		Memo   Optional[string] `json:"memo"`
+	}
+
+	var req request
+	require.NoError(t, json.Unmarshal([]byte(`{"email":"jane@example.com","amount":null}`), &req))
+	require.Equal(t, "jane@example.com", req.Email.OrElse(""))
+	require.False(t, req.Amount.Present())
+	require.False(t, req.Memo.Present())
+
+	bs, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.Equal(t, `{"email":"jane@example.com","amount":null,"memo":null}`, string(bs))
+
+	require.Error(t, json.Unmarshal([]byte(`{"amount":"12"}`), &req))
+}
+
+func TestOptional__SQL(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`create table customers (name text, age integer, nickname text, created timestamp);`)
+	require.NoError(t, err)
+
+	created := time.Date(2021, time.March, 4, 0, 0, 0, 0, time.UTC)
+	_, err = db.Exec(`insert into customers values (?, ?, ?, ?);`, Of("Jane"), Of(34), None[string](), Of(created))
+	require.NoError(t, err)
+
+	var (
+		name, nickname Optional[string]
+		age            Optional[int]
+		at             Optional[time.Time]
+	)
+	require.NoError(t, db.QueryRow(`select name, age, nickname, created from customers;`).Scan(&name, &age, &nickname, &at))
+	require.Equal(t, "Jane", name.OrElse(""))
+	require.Equal(t, 34, age.OrElse(0))
+	require.False(t, nickname.Present())
+	require.True(t, created.Equal(at.OrElse(time.Time{})))
+
+	var bad Optional[int]
+	require.Error(t, bad.Scan("thirty"))
+	var str Optional[string]
+	require.Error(t, str.Scan(int64(65)))
+	require.NoError(t, str.Scan([]byte("bytes")))
+	require.Equal(t, "bytes", str.OrElse(""))
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package opts implements functional options for constructors. Options are named so Apply can
+// reject combinations which conflict.
+//
+//	type Option = opts.Option[Server]
+//
+//	func WithTimeout(d time.Duration) Option {
+//		return opts.New("timeout", func(s *Server) error {
+//			s.timeout = d
+//			return nil
+//		})
+//	}
+//
+//	func New(addr string, options ...Option) (*Server, error) {
+//		s := &Server{addr: addr}
+//		if err := opts.Apply(s, options...); err != nil {
+//			return nil, err
+//		}
+//		return s, nil
+//	}
+package opts
+
+import (
+	"fmt"
+
+	"github.com/moov-io/base"
+)
+
+// Option configures a T
+type Option[T any] struct {
+	name      string
+	conflicts []string
+	apply     func(*T) error
+}
+
+// New returns an Option called name which calls apply
+func New[T any](name string, apply func(*T) error) Option[T] {
+	return Option[T]{name: name, apply: apply}
+}
+
+// Name returns the name of the Option
+func (o Option[T]) Name() string {
+	return o.name
+}
+
+// ConflictsWith returns a copy of the Option which can't be applied along with options of names.
+func (o Option[T]) ConflictsWith(names ...string) Option[T] {
+	o.conflicts = append(append([]string(nil), o.conflicts...), names...)
+	return o
+}
+
+// Validate returns an error for each pair of conflicting options
+func Validate[T any](options ...Option[T]) error {
+	given := make(map[string]bool, len(options))
+	for i := range options {
+		given[options[i].name] = true
+	}
+
+	var el base.ErrorList
+	reported := make(map[string]bool)
+	for i := range options {
+		for _, other := range options[i].conflicts {
+			if !given[other] || other == options[i].name {
+				continue
+			}
+			// report each pair once regardless of which side declared it
+			key := options[i].name + "\x00" + other
+			if options[i].name > other {
+				key = other + "\x00" + options[i].name
+			}
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			el.Add(fmt.Errorf("opts: %s conflicts with %s", options[i].name, other))
+		}
+	}
+	return el.Err()
+}
+
+// Apply validates options and applies them to target in order. It stops at the first option
+// which returns an error.
+func Apply[T any](target *T, options ...Option[T]) error {
+	if err := Validate(options...); err != nil {
+		return err
+	}
+	for i := range options {
+		if options[i].apply == nil {
+			continue
+		}
+		if err := options[i].apply(target); err != nil {
+			return fmt.Errorf("opts: %s: %w", options[i].name, err)
+		}
+	}
+	return nil
+}
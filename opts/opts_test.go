@@ -0,0 +1,70 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type client struct {
+	retries   int
+	redirects bool
+}
+
+func withRetries(n int) Option[client] {
+	return New("retries", func(c *client) error {
+		if n < 0 {
+			return errors.New("must not be negative")
+		}
+		c.retries = n
+		return nil
+	})
+}
+
+func withoutRetries() Option[client] {
+	return New("without-retries", func(c *client) error {
+		c.retries = 0
+		return nil
+	}).ConflictsWith("retries")
+}
+
+func withRedirects() Option[client] {
+	return New("redirects", func(c *client) error {
+		c.redirects = true
+		return nil
+	})
+}
+
+func TestApply(t *testing.T) {
+	var c client
+	require.NoError(t, Apply(&c, withRetries(3), withRedirects()))
+	require.Equal(t, 3, c.retries)
+	require.True(t, c.redirects)
+
+	require.NoError(t, Apply(&c))
+	require.Equal(t, "retries", withRetries(1).Name())
+
+	err := Apply(&c, withRetries(-1))
+	require.EqualError(t, err, "opts: retries: must not be negative")
+}
+
+func TestApply__Conflicts(t *testing.T) {
+	c := client{retries: 2}
+	err := Apply(&c, withoutRetries(), withRedirects(), withRetries(5))
+	require.EqualError(t, err, "opts: without-retries conflicts with retries")
+	require.Equal(t, 2, c.retries) // nothing was applied
+
+	// declared on both sides, reported once
+	both := withRetries(1).ConflictsWith("without-retries")
+	require.EqualError(t, Validate(withoutRetries(), both), "opts: without-retries conflicts with retries")
+
+	// ConflictsWith doesn't modify the original
+	base := withRedirects()
+	_ = base.ConflictsWith("retries")
+	require.NoError(t, Validate(base, withRetries(1)))
+}
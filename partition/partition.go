@@ -0,0 +1,234 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package partition splits a keyspace, such as customers or files, between the replicas of a
+// service. Keys hash into a fixed number of partitions which are assigned to members with a
+// consistent hash Ring, so every replica agrees on the owners without coordinating and only
+// the partitions of a joining or leaving member move.
+//
+//	p, err := partition.New(partition.Config{
+//		Name:       "file-uploads",
+//		Self:       os.Getenv("POD_NAME"),
+//		Membership: membership,
+//	})
+//	go p.Run(ctx)
+//
+//	for _, customer := range customers {
+//		if p.Owns(customer.ID) {
+//			process(customer)
+//		}
+//	}
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/moov-io/base/log"
+)
+
+var (
+	ownedPartitions = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "partition_owned",
+		Help: "Gauge of partitions owned by this replica",
+	}, []string{"name"})
+
+	partitionMembers = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "partition_members",
+		Help: "Gauge of members sharing the partitions",
+	}, []string{"name"})
+
+	partitionRebalances = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "partition_rebalances_total",
+		Help: "Counter of the times partitions owned by this replica changed",
+	}, []string{"name"})
+)
+
+// Membership returns the current members, such as the pods of a Deployment or instances with
+// a recent heartbeat. Every replica must see the same names.
+type Membership interface {
+	Members(ctx context.Context) ([]string, error)
+}
+
+// StaticMembership is a fixed list of members
+type StaticMembership []string
+
+func (m StaticMembership) Members(ctx context.Context) ([]string, error) {
+	return m, nil
+}
+
+// Config describes how a Partitioner splits keys
+type Config struct {
+	// Name labels metrics and logs
+	Name string
+
+	// Self is this replica's name in Membership
+	Self string
+
+	Membership Membership
+
+	// Partitions is how many partitions keys hash into. It defaults to 64 and must be the same
+	// on every replica. Use several times more partitions than replicas for an even split.
+	Partitions int
+
+	// VirtualNodes is how many points each member has on the Ring, see NewRing.
+	VirtualNodes int
+
+	// Interval is how often Run refreshes the members. It defaults to 15 seconds.
+	Interval time.Duration
+
+	// OnRebalance is called with the owned partitions after they change
+	OnRebalance func(owned []int)
+
+	Logger log.Logger
+}
+
+// Partitioner tracks which partitions this replica owns
+type Partitioner struct {
+	cfg Config
+
+	mu    sync.RWMutex
+	ring  *Ring
+	owned map[int]bool
+}
+
+// New returns a Partitioner which owns nothing until Refresh or Run loads the members.
+func New(cfg Config) (*Partitioner, error) {
+	if cfg.Self == "" {
+		return nil, errors.New("partition: missing Self")
+	}
+	if cfg.Membership == nil {
+		return nil, errors.New("partition: missing Membership")
+	}
+	if cfg.Partitions <= 0 {
+		cfg.Partitions = 64
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewNopLogger()
+	}
+	return &Partitioner{
+		cfg:   cfg,
+		ring:  NewRing(cfg.VirtualNodes),
+		owned: make(map[int]bool),
+	}, nil
+}
+
+// Partition returns the partition of key
+func (p *Partitioner) Partition(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(p.cfg.Partitions))
+}
+
+// Owns reports whether this replica is responsible for key
+func (p *Partitioner) Owns(key string) bool {
+	partition := p.Partition(key)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.owned[partition]
+}
+
+// Owner returns the member responsible for key
+func (p *Partitioner) Owner(key string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.ring.Owner(strconv.Itoa(p.Partition(key)))
+}
+
+// Owned returns the sorted partitions this replica owns
+func (p *Partitioner) Owned() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	owned := make([]int, 0, len(p.owned))
+	for partition := range p.owned {
+		owned = append(owned, partition)
+	}
+	sort.Ints(owned)
+	return owned
+}
+
+// Refresh loads the members and reassigns partitions. The previous assignment is kept when
+// Membership returns an error.
+func (p *Partitioner) Refresh(ctx context.Context) error {
+	members, err := p.cfg.Membership.Members(ctx)
+	if err != nil {
+		return fmt.Errorf("partition: loading members: %w", err)
+	}
+
+	ring := NewRing(p.cfg.VirtualNodes, members...)
+	owned := make(map[int]bool)
+	for i := 0; i < p.cfg.Partitions; i++ {
+		if ring.Owner(strconv.Itoa(i)) == p.cfg.Self {
+			owned[i] = true
+		}
+	}
+
+	p.mu.Lock()
+	changed := !sameKeys(p.owned, owned)
+	p.ring, p.owned = ring, owned
+	p.mu.Unlock()
+
+	ownedPartitions.With("name", p.cfg.Name).Set(float64(len(owned)))
+	partitionMembers.With("name", p.cfg.Name).Set(float64(len(ring.Members())))
+
+	if changed {
+		partitionRebalances.With("name", p.cfg.Name).Add(1)
+		p.cfg.Logger.Info().With(log.Fields{
+			"partitioner": log.String(p.cfg.Name),
+			"members":     log.Int(len(ring.Members())),
+			"owned":       log.Int(len(owned)),
+		}).Log("partitions rebalanced")
+
+		if p.cfg.OnRebalance != nil {
+			p.cfg.OnRebalance(p.Owned())
+		}
+	}
+	return nil
+}
+
+// Run refreshes the members every Interval until ctx is done. Errors are logged and the
+// previous assignment is kept.
+func (p *Partitioner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Warn().With(log.Fields{
+				"partitioner": log.String(p.cfg.Name),
+			}).LogError(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sameKeys(a, b map[int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
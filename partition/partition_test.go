@@ -0,0 +1,147 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	require.Equal(t, "", NewRing(0).Owner("customer-1"))
+
+	ring := NewRing(0, "b", "a", "c", "a")
+	require.Equal(t, []string{"a", "b", "c"}, ring.Members())
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[ring.Owner(strconv.Itoa(i))]++
+	}
+	for member, n := range counts {
+		require.InDelta(t, 1000, n, 300, "member %s", member)
+	}
+
+	// only keys of the removed member move
+	smaller := NewRing(0, "a", "b")
+	for i := 0; i < 3000; i++ {
+		key := strconv.Itoa(i)
+		if before := ring.Owner(key); before != "c" {
+			require.Equal(t, before, smaller.Owner(key))
+		}
+	}
+}
+
+type membership struct {
+	mu      sync.Mutex
+	members []string
+	err     error
+}
+
+func (m *membership) Members(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.members, m.err
+}
+
+func (m *membership) set(err error, members ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.members, m.err = members, err
+}
+
+func TestPartitioner(t *testing.T) {
+	ctx := context.Background()
+	members := &membership{}
+	members.set(nil, "replica-0", "replica-1", "replica-2")
+
+	var partitioners []*Partitioner
+	var rebalances int
+	for i := 0; i < 3; i++ {
+		p, err := New(Config{
+			Name:       "test",
+			Self:       fmt.Sprintf("replica-%d", i),
+			Membership: members,
+			OnRebalance: func(owned []int) {
+				rebalances++
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, p.Owns("customer-1"))
+		require.NoError(t, p.Refresh(ctx))
+		partitioners = append(partitioners, p)
+	}
+	require.Equal(t, 3, rebalances)
+
+	// every key has exactly one owner which all replicas agree on
+	for i := 0; i < 500; i++ {
+		key := fmt.Sprintf("customer-%d", i)
+		owners := 0
+		for _, p := range partitioners {
+			if p.Owns(key) {
+				owners++
+				require.Equal(t, p.cfg.Self, partitioners[0].Owner(key))
+			}
+		}
+		require.Equal(t, 1, owners, key)
+	}
+
+	total := 0
+	for _, p := range partitioners {
+		total += len(p.Owned())
+	}
+	require.Equal(t, 64, total)
+
+	// unchanged members don't rebalance
+	require.NoError(t, partitioners[0].Refresh(ctx))
+	require.Equal(t, 3, rebalances)
+
+	// a failing membership keeps the assignment
+	owned := partitioners[0].Owned()
+	members.set(errors.New("api unavailable"))
+	require.Error(t, partitioners[0].Refresh(ctx))
+	require.Equal(t, owned, partitioners[0].Owned())
+
+	// replica-2 leaves and the others take its partitions
+	members.set(nil, "replica-0", "replica-1")
+	require.NoError(t, partitioners[0].Refresh(ctx))
+	require.NoError(t, partitioners[1].Refresh(ctx))
+	require.Equal(t, 64, len(partitioners[0].Owned())+len(partitioners[1].Owned()))
+	require.Subset(t, partitioners[0].Owned(), owned)
+}
+
+func TestPartitioner__Run(t *testing.T) {
+	p, err := New(Config{
+		Self:       "replica-0",
+		Membership: StaticMembership{"replica-0"},
+		Partitions: 8,
+		Interval:   time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return len(p.Owned()) == 8 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestNew__Errors(t *testing.T) {
+	_, err := New(Config{Membership: StaticMembership{"a"}})
+	require.Error(t, err)
+	_, err = New(Config{Self: "a"})
+	require.Error(t, err)
+}
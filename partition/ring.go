@@ -0,0 +1,87 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// DefaultVirtualNodes is how many points each member has on a Ring by default
+const DefaultVirtualNodes = 128
+
+// Ring is a consistent hash ring. Adding or removing a member only moves the keys it gains or
+// loses, other keys keep their owner. A Ring is immutable and safe for concurrent use.
+type Ring struct {
+	points  []uint64
+	owners  map[uint64]string
+	members []string
+}
+
+// NewRing returns a Ring of members with virtualNodes points each, DefaultVirtualNodes when it's
+// zero or negative. Duplicate members are ignored.
+func NewRing(virtualNodes int, members ...string) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	r := &Ring{
+		owners: make(map[uint64]string, len(members)*virtualNodes),
+	}
+	seen := make(map[string]bool, len(members))
+	for _, m := range members {
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		r.members = append(r.members, m)
+
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(m + "#" + strconv.Itoa(i))
+			if _, exists := r.owners[point]; exists {
+				continue
+			}
+			r.owners[point] = m
+			r.points = append(r.points, point)
+		}
+	}
+	sort.Strings(r.members)
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Owner returns the member responsible for key, or an empty string when the Ring has no members.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Members returns the sorted members of the Ring
+func (r *Ring) Members() []string {
+	return append([]string(nil), r.members...)
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return mix(h.Sum64())
+}
+
+// mix spreads fnv's output, whose high bits barely change between similar inputs
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
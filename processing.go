@@ -0,0 +1,41 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"time"
+)
+
+// ProcessingDate returns the banking day a submission made at now belongs to. Submissions at or after
+// the cutoff, or on a weekend or holiday, belong to the next banking day.
+//
+// The cutoff is the wall clock time since midnight in loc (i.e. 16h45m for 4:45pm) rather than an
+// elapsed duration, so days which are 23 or 25 hours long due to DST changes keep the same local cutoff.
+// America/New_York is used when loc is nil.
+func ProcessingDate(now Time, cutoff time.Duration, loc *time.Location) Date {
+	if loc == nil {
+		loc = eastern()
+	}
+	local := now.Time.In(loc)
+
+	day := DateOf(local)
+	if !local.Before(cutoffTime(day, cutoff, loc)) {
+		day = day.AddDays(1)
+	}
+	return day.AddBankingDays(0)
+}
+
+// cutoffTime returns the instant the wall clock in loc reads cutoff past midnight on day.
+func cutoffTime(day Date, cutoff time.Duration, loc *time.Location) time.Time {
+	return time.Date(day.Year, day.Month, day.Day, 0, 0, 0, int(cutoff), loc)
+}
+
+func eastern() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProcessingDate(t *testing.T) {
+	cutoff := 16*time.Hour + 45*time.Minute
+
+	tests := []struct {
+		when     time.Time
+		expected Date
+	}{
+		// Tuesday before and after the cutoff, Wednesday is Veterans Day
+		{time.Date(2020, time.November, 10, 16, 44, 0, 0, est), NewDate(2020, time.November, 10)},
+		{time.Date(2020, time.November, 10, 16, 45, 0, 0, est), NewDate(2020, time.November, 12)},
+		// Friday after the cutoff rolls to Monday
+		{time.Date(2020, time.November, 13, 18, 0, 0, 0, est), NewDate(2020, time.November, 16)},
+		// Saturday
+		{time.Date(2020, time.November, 14, 9, 0, 0, 0, est), NewDate(2020, time.November, 16)},
+		// 11pm Pacific is after the cutoff in Eastern time
+		{time.Date(2020, time.November, 9, 23, 0, 0, 0, time.FixedZone("PST", -8*3600)), NewDate(2020, time.November, 10)},
+	}
+	for _, test := range tests {
+		if d := ProcessingDate(NewTime(test.when), cutoff, est); d != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.when, test.expected, d)
+		}
+		if d := ProcessingDate(NewTime(test.when), cutoff, nil); d != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.when, test.expected, d)
+		}
+	}
+}
+
+func TestProcessingDate__DST(t *testing.T) {
+	// US clocks change on Sundays, so check the cutoff instant on those days directly
+	springForward := cutoffTime(NewDate(2020, time.March, 8), 17*time.Hour, est)
+	if h, m, _ := springForward.Clock(); h != 17 || m != 0 {
+		t.Errorf("unexpected cutoff on 23 hour day: %v", springForward)
+	}
+	fallBack := cutoffTime(NewDate(2020, time.November, 1), 17*time.Hour, est)
+	if h, m, _ := fallBack.Clock(); h != 17 || m != 0 {
+		t.Errorf("unexpected cutoff on 25 hour day: %v", fallBack)
+	}
+
+	// Tehran moved clocks forward at midnight on Monday 2021-03-22, a banking day.
+	// Only 16h30m passed between midnight and 5:30pm local time, which is still after a 5pm cutoff.
+	tehran, err := time.LoadLocation("Asia/Tehran")
+	if err != nil {
+		t.Skip(err)
+	}
+	when := NewTime(time.Date(2021, time.March, 22, 17, 30, 0, 0, tehran))
+	if d := ProcessingDate(when, 17*time.Hour, tehran); d != NewDate(2021, time.March, 23) {
+		t.Errorf("unexpected processing date: %v", d)
+	}
+	when = NewTime(time.Date(2021, time.March, 22, 16, 30, 0, 0, tehran))
+	if d := ProcessingDate(when, 17*time.Hour, tehran); d != NewDate(2021, time.March, 22) {
+		t.Errorf("unexpected processing date: %v", d)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package proptest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/validate"
+)
+
+var currencies = []string{"USD", "EUR", "GBP", "CAD", "JPY", "KRW", "BHD", "KWD"}
+
+// Amount returns amounts of common currencies, including those without or with three minor
+// units, with edge values (zero, negative, one minor unit and the int64 limits) mixed in.
+func Amount() Gen[base.Amount] {
+	return func(r *rand.Rand) base.Amount {
+		return AmountIn(currencies[r.Intn(len(currencies))])(r)
+	}
+}
+
+// AmountIn returns amounts of currency
+func AmountIn(currency string) Gen[base.Amount] {
+	values := OneOf(
+		Elements[int64](0, 1, -1, 99, 100, -100, math.MaxInt64, math.MinInt64+1),
+		Int64Range(-1e9, 1e9),
+		Int64Range(0, 1e13), // up to $100B
+	)
+	return func(r *rand.Rand) base.Amount {
+		return base.NewAmount(values(r), currency)
+	}
+}
+
+// Date returns dates between 1990 and 2060, biased towards weekends, holidays and the days
+// around them.
+func Date() Gen[base.Date] {
+	return func(r *rand.Rand) base.Date {
+		year := 1990 + r.Intn(71)
+		if r.Intn(2) == 0 {
+			return base.NewDate(year, time.January, 1).AddDays(r.Intn(366))
+		}
+		// near a holiday: New Year's, July 4th, Thanksgiving week or Christmas
+		anchors := []base.Date{
+			base.NewDate(year, time.January, 1),
+			base.NewDate(year, time.July, 4),
+			base.NewDate(year, time.November, 22),
+			base.NewDate(year, time.December, 25),
+		}
+		return anchors[r.Intn(len(anchors))].AddDays(r.Intn(9) - 4)
+	}
+}
+
+// BankingDay returns dates which are Federal Reserve banking days
+func BankingDay() Gen[base.Date] {
+	dates := Date()
+	return func(r *rand.Rand) base.Date {
+		d := dates(r)
+		for !d.IsBankingDay() {
+			d = d.AddDays(1)
+		}
+		return d
+	}
+}
+
+// NonBankingDay returns weekends and Federal Reserve holidays
+func NonBankingDay() Gen[base.Date] {
+	dates := Date()
+	return func(r *rand.Rand) base.Date {
+		d := dates(r)
+		for d.IsBankingDay() {
+			d = d.AddDays(1)
+		}
+		return d
+	}
+}
+
+// Time returns times in America/New_York, half of which are within two hours of a daylight
+// saving transition and the rest around midnight or common ACH cutoffs.
+func Time() Gen[time.Time] {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(fmt.Sprintf("proptest: %v", err))
+	}
+	return func(r *rand.Rand) time.Time {
+		year := 1990 + r.Intn(71)
+		if r.Intn(2) == 0 {
+			transition := dstTransition(year, r.Intn(2) == 0, loc)
+			return transition.Add(time.Duration(r.Intn(240)-120) * time.Minute)
+		}
+		day := Date()(r)
+		hours := []int{0, 0, 10, 14, 16, 23}
+		minutes := []int{0, 0, 30, 59}
+		return time.Date(day.Year, day.Month, day.Day, hours[r.Intn(len(hours))], minutes[r.Intn(len(minutes))], r.Intn(60), 0, loc)
+	}
+}
+
+// dstTransition returns when clocks change in the spring (second Sunday of March) or
+// fall (first Sunday of November). Before 2007 the dates were in April and October.
+func dstTransition(year int, spring bool, loc *time.Location) time.Time {
+	month, week := time.November, 1
+	switch {
+	case spring && year >= 2007:
+		month, week = time.March, 2
+	case spring:
+		month, week = time.April, 1
+	case year < 2007:
+		month, week = time.October, 5
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	day := 1 + (int(time.Sunday)-int(first.Weekday())+7)%7 + 7*(week-1)
+	if month == time.October && year < 2007 {
+		// last Sunday of October
+		for time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Month() != month {
+			day -= 7
+		}
+	}
+	// 2am is 7am UTC in the spring and 6am UTC in the fall
+	hour := 7
+	if !spring {
+		hour = 6
+	}
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC).In(loc)
+}
+
+// routingPrefixes are the valid first two digits of ABA routing numbers
+var routingPrefixes = func() []int {
+	var out []int
+	for _, span := range [][2]int{{0, 12}, {21, 32}, {61, 72}, {80, 80}} {
+		for p := span[0]; p <= span[1]; p++ {
+			out = append(out, p)
+		}
+	}
+	return out
+}()
+
+// RoutingNumber returns nine digit ABA routing numbers with valid prefixes and check digits
+func RoutingNumber() Gen[string] {
+	return func(r *rand.Rand) string {
+		digits := fmt.Sprintf("%02d%06d", routingPrefixes[r.Intn(len(routingPrefixes))], r.Intn(1e6))
+		return digits + fmt.Sprint(CheckDigit(digits))
+	}
+}
+
+// InvalidRoutingNumber returns routing numbers with an incorrect check digit
+func InvalidRoutingNumber() Gen[string] {
+	valid := RoutingNumber()
+	return func(r *rand.Rand) string {
+		rtn := valid(r)
+		check := (int(rtn[8]-'0') + 1 + r.Intn(9)) % 10
+		return rtn[:8] + fmt.Sprint(check)
+	}
+}
+
+// CheckDigit returns the ABA check digit of the first eight digits of a routing number
+func CheckDigit(digits string) int {
+	return validate.RoutingCheckDigit(digits)
+}
+
+const (
+	alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	symbols      = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// NACHAAlphanumeric returns fields of width for NACHA files. Values are left justified and
+// padded with spaces, using uppercase letters, digits and the symbols NACHA allows. Empty
+// (all space) and full width values are mixed in.
+func NACHAAlphanumeric(width int) Gen[string] {
+	return func(r *rand.Rand) string {
+		n := r.Intn(width + 1)
+		switch r.Intn(6) {
+		case 0:
+			n = 0
+		case 1:
+			n = width
+		}
+		var sb strings.Builder
+		for i := 0; i < n; i++ {
+			if r.Intn(8) == 0 {
+				sb.WriteByte(symbols[r.Intn(len(symbols))])
+			} else {
+				sb.WriteByte(alphanumeric[r.Intn(len(alphanumeric))])
+			}
+		}
+		return sb.String() + strings.Repeat(" ", width-n)
+	}
+}
+
+// NACHANumeric returns numeric fields of width, right justified and zero padded.
+func NACHANumeric(width int) Gen[string] {
+	return func(r *rand.Rand) string {
+		var sb strings.Builder
+		digits := r.Intn(width + 1)
+		sb.WriteString(strings.Repeat("0", width-digits))
+		for i := 0; i < digits; i++ {
+			sb.WriteByte(byte('0' + r.Intn(10)))
+		}
+		return sb.String()
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package proptest implements property-based testing with generators of realistic financial data
+// (amounts, banking days, DST edges, routing numbers and NACHA fields).
+//
+//	func TestParseAmount(t *testing.T) {
+//		proptest.Check(t, proptest.Amount(), func(amt base.Amount) bool {
+//			parsed, err := ParseAmount(amt.String())
+//			return err == nil && parsed == amt
+//		})
+//	}
+//
+// Failures report the seed, rerun with -proptest.seed to reproduce them.
+package proptest
+
+import (
+	"flag"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+var (
+	flagSeed  = flag.Int64("proptest.seed", 0, "Seed for generated values, a random seed is used when zero")
+	flagCount = flag.Int("proptest.count", 100, "Number of values checked against each property")
+)
+
+// Gen returns a random value of T
+type Gen[T any] func(r *rand.Rand) T
+
+// Check calls prop with generated values and fails the test with the first value for which
+// prop returns false.
+func Check[T any](t testing.TB, gen Gen[T], prop func(T) bool) {
+	t.Helper()
+
+	seed := *flagSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < *flagCount; i++ {
+		v := gen(r)
+		if !prop(v) {
+			t.Fatalf("proptest: property failed on attempt %d with %#v (rerun with -proptest.seed=%d)", i+1, v, seed)
+		}
+	}
+}
+
+// Const always returns v
+func Const[T any](v T) Gen[T] {
+	return func(*rand.Rand) T {
+		return v
+	}
+}
+
+// OneOf returns a value from one of gens chosen at random
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	return func(r *rand.Rand) T {
+		return gens[r.Intn(len(gens))](r)
+	}
+}
+
+// Elements returns one of values chosen at random
+func Elements[T any](values ...T) Gen[T] {
+	return func(r *rand.Rand) T {
+		return values[r.Intn(len(values))]
+	}
+}
+
+// Map returns fn applied to values of gen
+func Map[T, U any](gen Gen[T], fn func(T) U) Gen[U] {
+	return func(r *rand.Rand) U {
+		return fn(gen(r))
+	}
+}
+
+// Int64Range returns numbers between min and max, inclusive. A quarter of the values are min or max.
+func Int64Range(min, max int64) Gen[int64] {
+	return func(r *rand.Rand) int64 {
+		switch r.Intn(8) {
+		case 0:
+			return min
+		case 1:
+			return max
+		}
+		span := uint64(max - min)
+		if span >= 1<<63-1 {
+			return min + int64(r.Uint64()>>1)
+		}
+		return min + r.Int63n(int64(span)+1)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package proptest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base"
+)
+
+func TestCheck(t *testing.T) {
+	count := 0
+	Check(t, Int64Range(-5, 5), func(n int64) bool {
+		count++
+		return n >= -5 && n <= 5
+	})
+	require.Equal(t, 100, count)
+
+	fake := &fatalT{TB: t}
+	func() {
+		defer func() { recover() }()
+		Check(fake, Const(3), func(n int) bool { return n != 3 })
+	}()
+	require.Contains(t, fake.msg, "property failed on attempt 1 with 3")
+	require.Contains(t, fake.msg, "-proptest.seed=")
+}
+
+type fatalT struct {
+	testing.TB
+	msg string
+}
+
+func (f *fatalT) Helper() {}
+
+func (f *fatalT) Fatalf(format string, args ...interface{}) {
+	f.msg = strings.TrimSpace(fmt.Sprintf(format, args...))
+	panic("fatal")
+}
+
+func TestAmount(t *testing.T) {
+	Check(t, Amount(), func(amt base.Amount) bool {
+		return len(amt.Currency) == 3 && amt.String() != ""
+	})
+	Check(t, AmountIn("usd"), func(amt base.Amount) bool {
+		return amt.Currency == "USD"
+	})
+}
+
+func TestBankingDays(t *testing.T) {
+	Check(t, BankingDay(), func(d base.Date) bool {
+		return d.IsBankingDay() && !d.IsWeekend()
+	})
+	Check(t, NonBankingDay(), func(d base.Date) bool {
+		return !d.IsBankingDay()
+	})
+}
+
+func TestTime(t *testing.T) {
+	Check(t, Time(), func(tt time.Time) bool {
+		return tt.Location().String() == "America/New_York" && tt.Year() >= 1989
+	})
+
+	eastern, _ := time.LoadLocation("America/New_York")
+	for _, tc := range []struct {
+		year   int
+		spring bool
+		date   string
+	}{
+		{2021, true, "2021-03-14"},
+		{2021, false, "2021-11-07"},
+		{2006, true, "2006-04-02"},
+		{2006, false, "2006-10-29"},
+	} {
+		transition := dstTransition(tc.year, tc.spring, eastern)
+		require.Equal(t, tc.date, transition.Format("2006-01-02"))
+
+		_, before := transition.Add(-time.Minute).Zone()
+		_, after := transition.Zone()
+		require.NotEqual(t, before, after, "%d spring=%v", tc.year, tc.spring)
+	}
+}
+
+func TestRoutingNumber(t *testing.T) {
+	valid := func(rtn string) bool {
+		return len(rtn) == 9 && CheckDigit(rtn) == int(rtn[8]-'0')
+	}
+	Check(t, RoutingNumber(), valid)
+	Check(t, InvalidRoutingNumber(), func(rtn string) bool { return !valid(rtn) })
+
+	require.Equal(t, 8, CheckDigit("12100035")) // 121000358
+}
+
+func TestNACHA(t *testing.T) {
+	Check(t, NACHAAlphanumeric(22), func(s string) bool {
+		return len(s) == 22 && strings.ToUpper(s) == s
+	})
+	Check(t, NACHANumeric(10), func(s string) bool {
+		return len(s) == 10 && strings.Trim(s, "0123456789") == ""
+	})
+
+	r := rand.New(rand.NewSource(1))
+	require.Equal(t, Elements("a", "b")(rand.New(rand.NewSource(1))), Elements("a", "b")(r))
+	require.Equal(t, "3", Map(Const(3), func(n int) string { return "3" })(r))
+}
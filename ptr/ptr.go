@@ -0,0 +1,27 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package ptr implements helpers for pointers to values, such as optional fields of API requests.
+package ptr
+
+// To returns a pointer to a copy of v
+func To[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value p points to, or fallback when p is nil.
+func Deref[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
+
+// Equal returns true when a and b are both nil or point to equal values.
+func Equal[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
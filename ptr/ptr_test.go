@@ -0,0 +1,25 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ptr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPtr(t *testing.T) {
+	p := To("memo")
+	require.Equal(t, "memo", *p)
+	require.NotSame(t, To(1), To(1))
+
+	require.Equal(t, "memo", Deref(p, "none"))
+	require.Equal(t, "none", Deref(nil, "none"))
+
+	require.True(t, Equal[int](nil, nil))
+	require.True(t, Equal(To(1), To(1)))
+	require.False(t, Equal(To(1), nil))
+	require.False(t, Equal(To(1), To(2)))
+}
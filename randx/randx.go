@@ -0,0 +1,138 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package randx is the source of randomness for random strings, test data and retry jitter.
+// It reads from crypto/rand unless a test seeds it with randxtest.Seed, which makes failures
+// reproducible. Since any caller can swap the source, secrets such as credentials and nonces
+// must read crypto/rand directly instead.
+package randx
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"sync"
+	"time"
+)
+
+// Source produces random values. Implementations must be safe for concurrent use.
+type Source interface {
+	io.Reader
+
+	// Int63n returns a non-negative number less than n, it panics when n <= 0.
+	Int63n(n int64) int64
+}
+
+var (
+	mu      sync.RWMutex
+	current Source = cryptoSource{}
+)
+
+// Default returns the Source used by this package's functions
+func Default() Source {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault replaces the Source used by this package's functions and returns a func to restore
+// the previous Source.
+func SetDefault(s Source) (restore func()) {
+	mu.Lock()
+	previous := current
+	current = s
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		current = previous
+		mu.Unlock()
+	}
+}
+
+// cryptoSource reads from crypto/rand
+type cryptoSource struct{}
+
+func (cryptoSource) Read(p []byte) (int, error) {
+	return crand.Read(p)
+}
+
+func (cryptoSource) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("randx: invalid argument to Int63n")
+	}
+	// reject values past the largest multiple of n to avoid modulo bias
+	max := int64((1<<63 - 1) - (1<<63)%uint64(n))
+	var buf [8]byte
+	for {
+		if _, err := crand.Read(buf[:]); err != nil {
+			panic(fmt.Sprintf("randx: reading crypto/rand: %v", err))
+		}
+		v := int64(binary.BigEndian.Uint64(buf[:]) >> 1)
+		if v <= max {
+			return v % n
+		}
+	}
+}
+
+// seededSource is a deterministic math/rand Source
+type seededSource struct {
+	mu  sync.Mutex
+	rnd *mrand.Rand
+}
+
+// NewSeeded returns a deterministic Source, intended for tests.
+func NewSeeded(seed int64) Source {
+	return &seededSource{rnd: mrand.New(mrand.NewSource(seed))}
+}
+
+func (s *seededSource) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Read(p)
+}
+
+func (s *seededSource) Int63n(n int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Int63n(n)
+}
+
+// Read fills p from the default Source
+func Read(p []byte) (int, error) {
+	return Default().Read(p)
+}
+
+// Int63n returns a non-negative number less than n from the default Source
+func Int63n(n int64) int64 {
+	return Default().Int63n(n)
+}
+
+// Intn returns a non-negative number less than n from the default Source
+func Intn(n int) int {
+	return int(Default().Int63n(int64(n)))
+}
+
+// String returns n characters chosen from alphabet
+func String(n int, alphabet string) string {
+	runes := []rune(alphabet)
+	out := make([]rune, n)
+	src := Default()
+	for i := range out {
+		out[i] = runes[src.Int63n(int64(len(runes)))]
+	}
+	return string(out)
+}
+
+// Jitter returns d adjusted by a random amount of up to fraction of d in either direction, i.e.
+// a fraction of 0.1 returns between 0.9d and 1.1d.
+func Jitter(d time.Duration, fraction float64) time.Duration {
+	spread := int64(float64(d) * fraction)
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(Default().Int63n(2*spread+1))
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package randx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoSource(t *testing.T) {
+	src := cryptoSource{}
+	for i := 0; i < 100; i++ {
+		n := src.Int63n(10)
+		require.True(t, n >= 0 && n < 10)
+	}
+	buf := make([]byte, 16)
+	n, err := src.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, 16, n)
+	require.Panics(t, func() { src.Int63n(0) })
+}
+
+func TestSetDefault(t *testing.T) {
+	generate := func() (string, int) {
+		return String(10, "abc"), Intn(1000)
+	}
+
+	restore := SetDefault(NewSeeded(42))
+	a, b := generate()
+	restore()
+	require.Equal(t, cryptoSource{}, Default())
+
+	restore = SetDefault(NewSeeded(42))
+	defer restore()
+	c, d := generate()
+	require.Equal(t, a, c)
+	require.Equal(t, b, d)
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := Jitter(time.Second, 0.1)
+		require.True(t, d >= 900*time.Millisecond && d <= 1100*time.Millisecond, d)
+	}
+	require.Equal(t, time.Second, Jitter(time.Second, 0))
+}
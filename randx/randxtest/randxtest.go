@@ -0,0 +1,25 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package randxtest seeds randx in tests. It's separate from randx so binaries don't link the
+// testing package.
+//
+//	func TestRetries(t *testing.T) {
+//		randxtest.Seed(t, 42) // same strings and backoff on every run
+//		...
+//	}
+package randxtest
+
+import (
+	"testing"
+
+	"github.com/moov-io/base/randx"
+)
+
+// Seed replaces randx's default Source with randx.NewSeeded(seed) until the test finishes. Tests
+// calling Seed must not run in parallel with other tests using randx.
+func Seed(t testing.TB, seed int64) {
+	t.Helper()
+	t.Cleanup(randx.SetDefault(randx.NewSeeded(seed)))
+}
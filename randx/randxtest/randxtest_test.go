@@ -0,0 +1,37 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package randxtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/randx"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	generate := func() (string, int, time.Duration) {
+		buf := make([]byte, 8)
+		randx.Read(buf)
+		return string(buf) + randx.String(10, "abc"), randx.Intn(1000), randx.Jitter(time.Second, 0.2)
+	}
+
+	var first [3]interface{}
+	t.Run("first", func(t *testing.T) {
+		Seed(t, 42)
+		a, b, c := generate()
+		first = [3]interface{}{a, b, c}
+	})
+	original := randx.Default()
+
+	t.Run("second", func(t *testing.T) {
+		Seed(t, 42)
+		a, b, c := generate()
+		require.Equal(t, first, [3]interface{}{a, b, c})
+	})
+	require.Equal(t, original, randx.Default())
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package recon matches our records against another party's, such as transfers against a bank
+// statement, and reports which matched, which only partly matched and which are missing.
+//
+//	report := recon.Reconcile(recon.Config{
+//		Keys:            []string{"trace"},
+//		AmountTolerance: 1, // $0.01
+//		DateTolerance:   1, // banking day
+//	}, ours, statement)
+//
+// Records match when their keys are equal and their amounts and dates are within the tolerances.
+// Records with equal keys but amounts or dates outside the tolerances are reported as Partial.
+package recon
+
+import (
+	"strings"
+
+	"github.com/moov-io/base"
+)
+
+// Record is one side of a reconciliation
+type Record struct {
+	ID     string            `json:"id"`
+	Keys   map[string]string `json:"keys,omitempty"`
+	Amount base.Amount       `json:"amount"`
+	Date   base.Date         `json:"date"`
+}
+
+// Config controls how records are matched
+type Config struct {
+	// Keys are the Record.Keys which must be equal for records to match. Without keys
+	// records are matched on their amount and date, and those outside the tolerances are left
+	// unmatched rather than reported as partial matches.
+	Keys []string
+
+	// AmountTolerance is how many minor units amounts may differ by
+	AmountTolerance int64
+
+	// DateTolerance is how many banking days dates may differ by
+	DateTolerance int
+}
+
+// Match pairs our Record with theirs
+type Match struct {
+	Ours   Record `json:"ours"`
+	Theirs Record `json:"theirs"`
+
+	// AmountDiff is their amount minus ours in minor units
+	AmountDiff int64 `json:"amountDiff"`
+
+	// DateDiff is how many banking days their date is after ours, negative when it's before
+	DateDiff int `json:"dateDiff"`
+}
+
+// Exact reports whether the amounts and dates are equal
+func (m Match) Exact() bool {
+	return m.AmountDiff == 0 && m.DateDiff == 0 && m.Ours.Date == m.Theirs.Date
+}
+
+// Report is the outcome of Reconcile. Records in each list are in the order they were given.
+type Report struct {
+	Matched []Match `json:"matched"`
+	Partial []Match `json:"partial"`
+
+	UnmatchedOurs   []Record `json:"unmatchedOurs"`
+	UnmatchedTheirs []Record `json:"unmatchedTheirs"`
+}
+
+// Reconciled reports whether every record matched within the tolerances
+func (r Report) Reconciled() bool {
+	return len(r.Partial) == 0 && len(r.UnmatchedOurs) == 0 && len(r.UnmatchedTheirs) == 0
+}
+
+// Reconcile matches ours against theirs. Exact matches are paired first so a record within the
+// tolerance of several others doesn't take one which matches something else exactly, followed
+// by matches within the tolerances and finally partial matches of records with equal keys.
+func Reconcile(cfg Config, ours, theirs []Record) Report {
+	report := Report{
+		Matched:         []Match{},
+		Partial:         []Match{},
+		UnmatchedOurs:   []Record{},
+		UnmatchedTheirs: []Record{},
+	}
+
+	// group their records by key so each of ours only compares against candidates
+	candidates := make(map[string][]int)
+	for i, r := range theirs {
+		k := cfg.key(r)
+		candidates[k] = append(candidates[k], i)
+	}
+
+	pairedOurs := make([]*Match, len(ours))
+	pairedTheirs := make([]bool, len(theirs))
+
+	pass := func(accept func(m Match) bool) {
+		for i, o := range ours {
+			if pairedOurs[i] != nil {
+				continue
+			}
+			var best *Match
+			var bestIdx int
+			for _, j := range candidates[cfg.key(o)] {
+				if pairedTheirs[j] {
+					continue
+				}
+				m := newMatch(o, theirs[j])
+				if accept(m) && (best == nil || closer(m, *best)) {
+					best, bestIdx = &m, j
+				}
+			}
+			if best != nil {
+				pairedOurs[i] = best
+				pairedTheirs[bestIdx] = true
+			}
+		}
+	}
+	pass(func(m Match) bool { return m.Exact() })
+	pass(cfg.within)
+	matched := make([]bool, len(ours))
+	for i := range ours {
+		matched[i] = pairedOurs[i] != nil
+	}
+	if len(cfg.Keys) > 0 {
+		// without keys any two records of a currency would partially match
+		pass(func(m Match) bool { return true })
+	}
+
+	for i, o := range ours {
+		switch {
+		case pairedOurs[i] == nil:
+			report.UnmatchedOurs = append(report.UnmatchedOurs, o)
+		case !matched[i]:
+			report.Partial = append(report.Partial, *pairedOurs[i])
+		default:
+			report.Matched = append(report.Matched, *pairedOurs[i])
+		}
+	}
+	for j, t := range theirs {
+		if !pairedTheirs[j] {
+			report.UnmatchedTheirs = append(report.UnmatchedTheirs, t)
+		}
+	}
+	return report
+}
+
+// key joins the configured keys and currency of r
+func (cfg Config) key(r Record) string {
+	var buf strings.Builder
+	buf.WriteString(strings.ToUpper(r.Amount.Currency))
+	for _, k := range cfg.Keys {
+		buf.WriteByte(0)
+		buf.WriteString(strings.TrimSpace(r.Keys[k]))
+	}
+	return buf.String()
+}
+
+func (cfg Config) within(m Match) bool {
+	return abs64(m.AmountDiff) <= cfg.AmountTolerance && abs(m.DateDiff) <= cfg.DateTolerance
+}
+
+func newMatch(ours, theirs Record) Match {
+	return Match{
+		Ours:       ours,
+		Theirs:     theirs,
+		AmountDiff: theirs.Amount.Value - ours.Amount.Value,
+		DateDiff:   bankingDaysBetween(ours.Date, theirs.Date),
+	}
+}
+
+// closer reports whether a is a better match than b
+func closer(a, b Match) bool {
+	if abs64(a.AmountDiff) != abs64(b.AmountDiff) {
+		return abs64(a.AmountDiff) < abs64(b.AmountDiff)
+	}
+	if abs(a.DateDiff) != abs(b.DateDiff) {
+		return abs(a.DateDiff) < abs(b.DateDiff)
+	}
+	return abs(a.Theirs.Date.DaysSince(a.Ours.Date)) < abs(b.Theirs.Date.DaysSince(b.Ours.Date))
+}
+
+// bankingDaysBetween returns how many banking days to is after from, so a Friday and the
+// following Monday are one banking day apart.
+func bankingDaysBetween(from, to base.Date) int {
+	sign := 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+	n := 0
+	for d := from.AddDays(1); !d.After(to); d = d.AddDays(1) {
+		if d.IsBankingDay() {
+			n++
+		}
+	}
+	return sign * n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
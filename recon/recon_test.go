@@ -0,0 +1,121 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package recon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+func record(id, trace string, value int64, date base.Date) Record {
+	return Record{
+		ID:     id,
+		Keys:   map[string]string{"trace": trace},
+		Amount: base.NewAmount(value, "USD"),
+		Date:   date,
+	}
+}
+
+func ids(records []Record) []string {
+	out := []string{}
+	for _, r := range records {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+func TestReconcile(t *testing.T) {
+	friday := base.NewDate(2021, time.March, 5)
+	monday := base.NewDate(2021, time.March, 8)
+	tuesday := base.NewDate(2021, time.March, 9)
+
+	ours := []Record{
+		record("o1", "111", 1000, friday),
+		record("o2", "222", 2500, friday),
+		record("o3", "333", 4000, friday),
+		record("o4", "444", 100, friday),
+		record("o5", "555", 700, friday),
+	}
+	theirs := []Record{
+		record("t1", "111", 1000, friday),
+		record("t2", "222", 2501, monday), // within a cent and a banking day
+		record("t3", "333", 4100, friday), // amount is off
+		record("t5", "555", 700, tuesday), // two banking days later
+		record("t6", "666", 900, friday),
+	}
+
+	report := Reconcile(Config{Keys: []string{"trace"}, AmountTolerance: 1, DateTolerance: 1}, ours, theirs)
+	require.False(t, report.Reconciled())
+
+	require.Len(t, report.Matched, 2)
+	require.Equal(t, "t1", report.Matched[0].Theirs.ID)
+	require.True(t, report.Matched[0].Exact())
+	require.Equal(t, "t2", report.Matched[1].Theirs.ID)
+	require.Equal(t, int64(1), report.Matched[1].AmountDiff)
+	require.Equal(t, 1, report.Matched[1].DateDiff)
+	require.False(t, report.Matched[1].Exact())
+
+	require.Len(t, report.Partial, 2)
+	require.Equal(t, "t3", report.Partial[0].Theirs.ID)
+	require.Equal(t, int64(100), report.Partial[0].AmountDiff)
+	require.Equal(t, "t5", report.Partial[1].Theirs.ID)
+	require.Equal(t, 2, report.Partial[1].DateDiff)
+
+	require.Equal(t, []string{"o4"}, ids(report.UnmatchedOurs))
+	require.Equal(t, []string{"t6"}, ids(report.UnmatchedTheirs))
+
+	bs, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `"unmatchedTheirs":[{"id":"t6"`)
+}
+
+func TestReconcile__ExactFirst(t *testing.T) {
+	day := base.NewDate(2021, time.March, 4)
+
+	// without keys o1 is within tolerance of both, but t2 is the exact match of o2
+	ours := []Record{record("o1", "", 1000, day), record("o2", "", 1001, day)}
+	theirs := []Record{record("t1", "", 1000, day), record("t2", "", 1001, day)}
+
+	report := Reconcile(Config{AmountTolerance: 1}, ours, theirs)
+	require.True(t, report.Reconciled())
+	require.Equal(t, "t1", report.Matched[0].Theirs.ID)
+	require.Equal(t, "t2", report.Matched[1].Theirs.ID)
+
+	// currencies must match
+	other := record("t3", "", 1000, day)
+	other.Amount.Currency = "EUR"
+	report = Reconcile(Config{}, ours[:1], []Record{other})
+	require.Equal(t, []string{"o1"}, ids(report.UnmatchedOurs))
+	require.Equal(t, []string{"t3"}, ids(report.UnmatchedTheirs))
+}
+
+func TestReconcile__NoKeys(t *testing.T) {
+	day := base.NewDate(2021, time.March, 4)
+
+	// unrelated records outside the tolerances aren't paired as partial matches
+	ours := []Record{record("o1", "", 1000, day), record("o2", "", 5000, day)}
+	theirs := []Record{record("t1", "", 1001, day), record("t2", "", 9000, day.AddBankingDays(3))}
+
+	report := Reconcile(Config{AmountTolerance: 1}, ours, theirs)
+	require.Len(t, report.Matched, 1)
+	require.Equal(t, "t1", report.Matched[0].Theirs.ID)
+	require.Empty(t, report.Partial)
+	require.Equal(t, []string{"o2"}, ids(report.UnmatchedOurs))
+	require.Equal(t, []string{"t2"}, ids(report.UnmatchedTheirs))
+}
+
+func TestBankingDaysBetween(t *testing.T) {
+	friday := base.NewDate(2021, time.March, 5)
+	require.Equal(t, 0, bankingDaysBetween(friday, friday))
+	require.Equal(t, 1, bankingDaysBetween(friday, friday.AddDays(3)))
+	require.Equal(t, -1, bankingDaysBetween(friday.AddDays(3), friday))
+	require.Equal(t, 1, bankingDaysBetween(friday.AddDays(1), friday.AddDays(3)))
+	require.Equal(t, 0, bankingDaysBetween(friday, friday.AddDays(2)))
+}
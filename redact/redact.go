@@ -0,0 +1,241 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package redact removes sensitive fields from structs before they're returned from an API or logged.
+// Fields are tagged with how they're redacted and, optionally, which roles may see them.
+//
+//	type Customer struct {
+//		Name          string `json:"name"`
+//		AccountNumber string `json:"accountNumber" redact:"mask,reveal=support|admin"`
+//		SSN           string `json:"ssn,omitempty" redact:"omit"`
+//	}
+//
+//	moovhttp.JSON(w, redact.Copy(customer, claims.Roles...))
+//
+// The account number of the copy is "****6789" unless one of the roles is support or admin.
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mask is the prefix of masked strings
+const Mask = "****"
+
+// Copy returns a copy of v with its tagged fields masked or omitted. Fields which reveal one of
+// roles are left as-is. v is never modified.
+//
+// Masked strings keep their last four characters when they're longer than eight and masked
+// values of other types are zeroed. Omitted fields are zeroed, use omitempty to drop them from JSON.
+func Copy[T any](v T, roles ...string) T {
+	return copyOf(v, rules{roles: roles})
+}
+
+// Config returns a copy of v, a service's configuration, for logging or serving. Tagged fields are
+// redacted as with Copy and string fields or map keys named like credentials (see SecretName)
+// are replaced with Mask.
+func Config[T any](v T) T {
+	return copyOf(v, rules{secretNames: true})
+}
+
+// SecretName reports whether a field or key named name holds a credential, such as a password,
+// client secret, API token or signing key. Only the last part of a dotted name is checked.
+func SecretName(name string) bool {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	name = strings.ToLower(name)
+	for _, word := range []string{"password", "secret", "token", "credential"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, "key")
+}
+
+// rules are how a copy is redacted
+type rules struct {
+	roles       []string
+	secretNames bool
+}
+
+func copyOf[T any](v T, r rules) T {
+	rv := reflect.ValueOf(&v).Elem()
+	out := redact(rv, r)
+	return out.Interface().(T)
+}
+
+// Marshal encodes the redacted copy of v as JSON
+func Marshal(v interface{}, roles ...string) ([]byte, error) {
+	return json.Marshal(Copy(v, roles...))
+}
+
+// redact returns a redacted copy of v, always of v's type
+func redact(v reflect.Value, r rules) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(redact(v.Elem(), r))
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redact(v.Elem(), r))
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if r.secretNames && SecretName(field.Name) {
+				if masked, ok := maskAll(v.Field(i)); ok {
+					out.Field(i).Set(masked)
+					continue
+				}
+			}
+			out.Field(i).Set(redactField(v.Field(i), field.Tag.Get("redact"), r))
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redact(v.Index(i), r))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redact(v.Index(i), r))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if r.secretNames && key.Kind() == reflect.String && SecretName(key.String()) {
+				if masked, ok := maskAll(iter.Value()); ok {
+					out.SetMapIndex(key, masked)
+					continue
+				}
+			}
+			out.SetMapIndex(key, redact(iter.Value(), r))
+		}
+		return out
+	}
+	return v
+}
+
+func redactField(v reflect.Value, tag string, r rules) reflect.Value {
+	mode, reveal := parseTag(tag)
+	if mode == "" || revealed(reveal, r.roles) {
+		return redact(v, r)
+	}
+	switch mode {
+	case "mask":
+		return mask(v)
+	case "omit":
+		return reflect.Zero(v.Type())
+	}
+	return redact(v, r)
+}
+
+// parseTag splits a tag such as "mask,reveal=support|admin"
+func parseTag(tag string) (string, []string) {
+	if tag == "" {
+		return "", nil
+	}
+	parts := strings.Split(tag, ",")
+	var reveal []string
+	for _, opt := range parts[1:] {
+		if strings.HasPrefix(opt, "reveal=") {
+			reveal = append(reveal, strings.Split(strings.TrimPrefix(opt, "reveal="), "|")...)
+		}
+	}
+	return parts[0], reveal
+}
+
+func revealed(reveal, roles []string) bool {
+	for i := range reveal {
+		for j := range roles {
+			if strings.EqualFold(reveal[i], roles[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mask(v reflect.Value) reflect.Value {
+	switch {
+	case v.Kind() == reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(MaskString(v.String()))
+		return out
+
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.String:
+		out := reflect.New(v.Type().Elem())
+		out.Elem().SetString(MaskString(v.Elem().String()))
+		return out
+	}
+	return reflect.Zero(v.Type())
+}
+
+// maskAll replaces a non-empty string with Mask, keeping none of it. It returns false when
+// v isn't a string so structs named like credentials are still walked.
+func maskAll(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		out := reflect.New(v.Type()).Elem()
+		if v.Len() > 0 {
+			out.SetString(Mask)
+		}
+		return out, true
+
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.String:
+		out := reflect.New(v.Type().Elem())
+		out.Elem().SetString(Mask)
+		return out, true
+	}
+	return v, false
+}
+
+// MaskString replaces all but the last four characters of s with Mask. Strings of eight or fewer
+// characters are masked entirely and empty strings are left empty.
+func MaskString(s string) string {
+	if s == "" {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n <= 8 {
+		return Mask
+	}
+	runes := []rune(s)
+	return Mask + string(runes[n-4:])
+}
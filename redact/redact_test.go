@@ -0,0 +1,145 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type account struct {
+	Number  string  `json:"number" redact:"mask,reveal=support|admin"`
+	Routing string  `json:"routing"`
+	Balance int64   `json:"balance,omitempty" redact:"mask"`
+	PIN     *string `json:"pin,omitempty" redact:"omit"`
+}
+
+type customer struct {
+	Name     string             `json:"name"`
+	SSN      string             `json:"ssn,omitempty" redact:"omit,reveal=admin"`
+	Email    *string            `json:"email" redact:"mask"`
+	Primary  *account           `json:"primary"`
+	Accounts []account          `json:"accounts"`
+	Byname   map[string]account `json:"byName"`
+	Extra    interface{}        `json:"extra"`
+
+	note string
+}
+
+func TestCopy(t *testing.T) {
+	email, pin := "jane.doe@example.com", "1234"
+	acct := account{Number: "123456789012", Routing: "987654320", Balance: 500, PIN: &pin}
+	cust := customer{
+		Name:     "Jane",
+		SSN:      "123-45-6789",
+		Email:    &email,
+		Primary:  &acct,
+		Accounts: []account{acct},
+		Byname:   map[string]account{"checking": acct},
+		Extra:    acct,
+		note:     "kept",
+	}
+
+	out := Copy(cust)
+	require.Equal(t, "Jane", out.Name)
+	require.Empty(t, out.SSN)
+	require.Equal(t, "****.com", *out.Email)
+	require.Equal(t, "****9012", out.Primary.Number)
+	require.Equal(t, "987654320", out.Primary.Routing)
+	require.Zero(t, out.Primary.Balance)
+	require.Nil(t, out.Primary.PIN)
+	require.Equal(t, "****9012", out.Accounts[0].Number)
+	require.Equal(t, "****9012", out.Byname["checking"].Number)
+	require.Equal(t, "****9012", out.Extra.(account).Number)
+	require.Equal(t, "kept", out.note)
+
+	// original is untouched
+	require.Equal(t, "123-45-6789", cust.SSN)
+	require.Equal(t, "jane.doe@example.com", *cust.Email)
+	require.Equal(t, "123456789012", cust.Primary.Number)
+	require.Equal(t, "123456789012", cust.Accounts[0].Number)
+	require.Equal(t, "123456789012", cust.Byname["checking"].Number)
+	require.Equal(t, "1234", *cust.Primary.PIN)
+
+	support := Copy(&cust, "Support")
+	require.Empty(t, support.SSN)
+	require.Equal(t, "123456789012", support.Primary.Number)
+	require.Nil(t, support.Primary.PIN)
+
+	admin := Copy(cust, "admin")
+	require.Equal(t, "123-45-6789", admin.SSN)
+}
+
+func TestMarshal(t *testing.T) {
+	bs, err := Marshal(account{Number: "123456789012", Routing: "987654320", Balance: 10})
+	require.NoError(t, err)
+	require.Equal(t, `{"number":"****9012","routing":"987654320"}`, string(bs))
+}
+
+func TestMaskString(t *testing.T) {
+	require.Equal(t, "", MaskString(""))
+	require.Equal(t, "****", MaskString("1234"))
+	require.Equal(t, "****", MaskString("12345678"))
+	require.Equal(t, "****6789", MaskString("123456789"))
+	require.Equal(t, "****öäüß", MaskString("ssssöäüßöäüß"))
+}
+
+func TestConfig(t *testing.T) {
+	type database struct {
+		Address  string
+		Password string
+	}
+	type signingKey struct {
+		Path string
+	}
+	type config struct {
+		Database   database
+		Partners   []database
+		SigningKey signingKey
+		APIToken   *string
+		Account    string `redact:"mask"`
+		Headers    map[string]interface{}
+	}
+
+	token := "abc123"
+	cfg := config{
+		Database:   database{Address: "mysql:3306", Password: "hunter2"},
+		Partners:   []database{{Address: "sftp.bank.com:22", Password: "hunter3"}, {Address: "sftp.other.com:22"}},
+		SigningKey: signingKey{Path: "/keys/signing.pem"},
+		APIToken:   &token,
+		Account:    "1234567890",
+		Headers:    map[string]interface{}{"X-Request-Id": "1", "X-Auth-Token": "secret"},
+	}
+	out := Config(cfg)
+
+	require.Equal(t, "mysql:3306", out.Database.Address)
+	require.Equal(t, Mask, out.Database.Password)
+	require.Equal(t, Mask, out.Partners[0].Password)
+	require.Equal(t, "", out.Partners[1].Password)
+	require.Equal(t, "/keys/signing.pem", out.SigningKey.Path)
+	require.Equal(t, Mask, *out.APIToken)
+	require.Equal(t, "****7890", out.Account)
+	require.Equal(t, map[string]interface{}{"X-Request-Id": "1", "X-Auth-Token": Mask}, out.Headers)
+
+	require.Equal(t, "hunter2", cfg.Database.Password)
+	require.Equal(t, "abc123", token)
+}
+
+func TestSecretName(t *testing.T) {
+	cases := map[string]bool{
+		"Database.Password":    true,
+		"OAuth.ClientSecret":   true,
+		"Slack.Token":          true,
+		"Signing.Key":          true,
+		"AWS.AccessKey":        true,
+		"Database.Address":     false,
+		"Keys.Rotation":        false,
+		"Partition.KeyColumns": false,
+	}
+	for name, expected := range cases {
+		require.Equal(t, expected, SecretName(name), name)
+	}
+}
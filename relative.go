@@ -0,0 +1,78 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"time"
+)
+
+// Relative describes t from the point of view of now, such as "3 hours ago" or "in 2 banking days".
+//
+// Differences under a day are given in minutes or hours. Longer ones count the banking days
+// between the Eastern time dates, so an entry settling Monday is "in 1 banking day" on a Friday.
+// Differences over 45 days are given in months and over a year in years.
+func (t Time) Relative(now Time) string {
+	diff := t.Time.Sub(now.Time)
+	future := diff > 0
+	if diff < 0 {
+		diff = -diff
+	}
+
+	switch {
+	case diff < time.Minute:
+		return "just now"
+	case diff < time.Hour:
+		return relative(future, int(diff/time.Minute), "minute")
+	case diff < 24*time.Hour:
+		return relative(future, int(diff/time.Hour), "hour")
+	}
+
+	loc := eastern()
+	from, to := DateOf(now.Time.In(loc)), DateOf(t.Time.In(loc))
+	days := to.DaysSince(from)
+	if days < 0 {
+		days = -days
+	}
+
+	switch {
+	case days >= 365:
+		return relative(future, days/365, "year")
+	case days > 45:
+		return relative(future, days/30, "month")
+	case days == 1 && future:
+		return "tomorrow"
+	case days == 1:
+		return "yesterday"
+	}
+	if n := bankingDaysBetween(from, to); n > 0 {
+		return relative(future, n, "banking day")
+	}
+	return relative(future, days, "day")
+}
+
+// bankingDaysBetween counts the banking days after the earlier of a and b up to and including the later.
+func bankingDaysBetween(a, b Date) int {
+	if b.Before(a) {
+		a, b = b, a
+	}
+	n := 0
+	for d := a.AddDays(1); !d.After(b); d = d.AddDays(1) {
+		if d.IsBankingDay() {
+			n++
+		}
+	}
+	return n
+}
+
+func relative(future bool, n int, unit string) string {
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
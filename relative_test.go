@@ -0,0 +1,43 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package base
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTime__Relative(t *testing.T) {
+	// Friday afternoon, the following Thursday is Thanksgiving
+	now := NewTime(time.Date(2020, time.November, 20, 15, 0, 0, 0, est))
+
+	tests := []struct {
+		when     time.Time
+		expected string
+	}{
+		{time.Date(2020, time.November, 20, 15, 0, 30, 0, est), "just now"},
+		{time.Date(2020, time.November, 20, 14, 59, 0, 0, est), "1 minute ago"},
+		{time.Date(2020, time.November, 20, 15, 25, 0, 0, est), "in 25 minutes"},
+		{time.Date(2020, time.November, 20, 12, 0, 0, 0, est), "3 hours ago"},
+		{time.Date(2020, time.November, 21, 9, 0, 0, 0, est), "in 18 hours"},
+		{time.Date(2020, time.November, 21, 18, 0, 0, 0, est), "tomorrow"},
+		{time.Date(2020, time.November, 19, 9, 0, 0, 0, est), "yesterday"},
+		// the weekend isn't counted
+		{time.Date(2020, time.November, 23, 9, 0, 0, 0, est), "in 1 banking day"},
+		{time.Date(2020, time.November, 24, 9, 0, 0, 0, est), "in 2 banking days"},
+		// nor is Thanksgiving
+		{time.Date(2020, time.November, 27, 9, 0, 0, 0, est), "in 4 banking days"},
+		{time.Date(2020, time.November, 17, 9, 0, 0, 0, est), "3 banking days ago"},
+		// no banking days pass before Sunday
+		{time.Date(2020, time.November, 22, 18, 0, 0, 0, est), "in 2 days"},
+		{time.Date(2021, time.January, 20, 9, 0, 0, 0, est), "in 2 months"},
+		{time.Date(2018, time.June, 1, 9, 0, 0, 0, est), "2 years ago"},
+	}
+	for _, test := range tests {
+		if got := NewTime(test.when).Relative(now); got != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.when, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the header, rows and totals row of r to w
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.Columns); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		if err := cw.Write(texts(row)); err != nil {
+			return err
+		}
+	}
+	if r.Totals != nil {
+		if err := cw.Write(texts(r.Totals)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func texts(cells []Cell) []string {
+	out := make([]string, len(cells))
+	for i := range cells {
+		out[i] = cells[i].Text
+	}
+	return out
+}
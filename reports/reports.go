@@ -0,0 +1,256 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package reports builds tabular reports, such as daily settlements or exceptions, from a slice
+// of structs and writes them as CSV or XLSX. Reports also encode as JSON for APIs and storage.
+//
+//	report, err := reports.Build(reports.Definition[Transfer]{
+//		Title: "Settlement 2021-03-04",
+//		Columns: []reports.Column[Transfer]{
+//			{Header: "ID", Value: func(t Transfer) interface{} { return t.ID }},
+//			{Header: "Created", Value: func(t Transfer) interface{} { return t.Created }},
+//			{Header: "Amount", Value: func(t Transfer) interface{} { return t.Amount }, Total: true},
+//		},
+//	}, transfers)
+//	err = report.WriteCSV(w)
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DefaultTimeFormat is how times are written when Definition.TimeFormat is empty
+const DefaultTimeFormat = time.RFC3339
+
+// Column describes one column of a report
+type Column[T any] struct {
+	Header string
+
+	// Value returns the column of a row. Strings, integers, floats, bools, base.Amount,
+	// base.Date, base.Time and time.Time are supported. A nil value is an empty cell.
+	Value func(row T) interface{}
+
+	// Total adds the column to the totals row. It's only supported for integers and Amounts,
+	// which must all be in one currency.
+	Total bool
+}
+
+// Definition describes a report of T
+type Definition[T any] struct {
+	Title   string
+	Columns []Column[T]
+
+	// Location is where times are written, UTC when nil
+	Location *time.Location
+
+	// TimeFormat is the layout of times, DefaultTimeFormat when empty
+	TimeFormat string
+}
+
+// Cell is one value of a report. Numeric cells, such as amounts, hold a plain decimal in Text
+// ("-1234.56") so spreadsheets treat them as numbers.
+type Cell struct {
+	Text     string `json:"text"`
+	Numeric  bool   `json:"numeric,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// Report is a built report
+type Report struct {
+	Title   string   `json:"title"`
+	Columns []string `json:"columns"`
+	Rows    [][]Cell `json:"rows"`
+
+	// Totals is nil when no columns are totaled
+	Totals []Cell `json:"totals,omitempty"`
+}
+
+// Build returns a Report with a row for each of rows
+func Build[T any](def Definition[T], rows []T) (*Report, error) {
+	if len(def.Columns) == 0 {
+		return nil, errors.New("reports: no columns")
+	}
+	loc := def.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := def.TimeFormat
+	if layout == "" {
+		layout = DefaultTimeFormat
+	}
+
+	report := &Report{
+		Title: def.Title,
+		Rows:  make([][]Cell, 0, len(rows)),
+	}
+	totals := make([]total, len(def.Columns))
+	anyTotals := false
+	for _, col := range def.Columns {
+		if col.Value == nil {
+			return nil, fmt.Errorf("reports: column %q has no Value", col.Header)
+		}
+		report.Columns = append(report.Columns, col.Header)
+		anyTotals = anyTotals || col.Total
+	}
+
+	for n, row := range rows {
+		cells := make([]Cell, len(def.Columns))
+		for i, col := range def.Columns {
+			v := col.Value(row)
+			cell, err := format(v, loc, layout)
+			if err != nil {
+				return nil, fmt.Errorf("reports: row %d column %q: %w", n+1, col.Header, err)
+			}
+			if col.Total {
+				if err := totals[i].add(v); err != nil {
+					return nil, fmt.Errorf("reports: row %d column %q: %w", n+1, col.Header, err)
+				}
+			}
+			cells[i] = cell
+		}
+		report.Rows = append(report.Rows, cells)
+	}
+
+	if anyTotals {
+		report.Totals = make([]Cell, len(def.Columns))
+		for i, col := range def.Columns {
+			if col.Total {
+				report.Totals[i] = totals[i].cell()
+			}
+		}
+		if !def.Columns[0].Total {
+			report.Totals[0] = Cell{Text: "Total"}
+		}
+	}
+	return report, nil
+}
+
+func format(v interface{}, loc *time.Location, layout string) (Cell, error) {
+	switch v := v.(type) {
+	case nil:
+		return Cell{}, nil
+	case string:
+		return Cell{Text: v}, nil
+	case bool:
+		return Cell{Text: strconv.FormatBool(v)}, nil
+	case base.Amount:
+		return amountCell(v), nil
+	case *base.Amount:
+		if v == nil {
+			return Cell{}, nil
+		}
+		return amountCell(*v), nil
+	case base.Date:
+		if v.IsZero() {
+			return Cell{}, nil
+		}
+		return Cell{Text: v.String()}, nil
+	case base.Time:
+		return timeCell(v.Time, loc, layout), nil
+	case time.Time:
+		return timeCell(v, loc, layout), nil
+	case float32:
+		return Cell{Text: strconv.FormatFloat(float64(v), 'f', -1, 32), Numeric: true}, nil
+	case float64:
+		return Cell{Text: strconv.FormatFloat(v, 'f', -1, 64), Numeric: true}, nil
+	case fmt.Stringer:
+		return Cell{Text: v.String()}, nil
+	}
+	if n, ok := integer(v); ok {
+		return Cell{Text: strconv.FormatInt(n, 10), Numeric: true}, nil
+	}
+	return Cell{}, fmt.Errorf("unsupported value %T", v)
+}
+
+func amountCell(a base.Amount) Cell {
+	return Cell{
+		Text:     a.Format(base.FormatOptions{Placement: base.NoCurrency, NoGrouping: true}),
+		Numeric:  true,
+		Currency: a.Currency,
+	}
+}
+
+func timeCell(t time.Time, loc *time.Location, layout string) Cell {
+	if t.IsZero() {
+		return Cell{}
+	}
+	return Cell{Text: t.In(loc).Format(layout)}
+}
+
+func integer(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// total sums a column of integers or Amounts
+type total struct {
+	amount    *base.Amount
+	integer   int64
+	isInteger bool
+}
+
+func (t *total) add(v interface{}) error {
+	if p, ok := v.(*base.Amount); ok {
+		if p == nil {
+			return nil
+		}
+		v = *p
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case base.Amount:
+		if t.isInteger {
+			return errors.New("can't total integers and amounts")
+		}
+		if t.amount == nil {
+			a := base.NewAmount(0, v.Currency)
+			t.amount = &a
+		}
+		if t.amount.Currency != v.Currency {
+			return fmt.Errorf("can't total %s and %s", t.amount.Currency, v.Currency)
+		}
+		t.amount.Value += v.Value
+		return nil
+	}
+	n, ok := integer(v)
+	if !ok {
+		return fmt.Errorf("can't total %T", v)
+	}
+	if t.amount != nil {
+		return errors.New("can't total integers and amounts")
+	}
+	t.isInteger = true
+	t.integer += n
+	return nil
+}
+
+func (t *total) cell() Cell {
+	if t.amount != nil {
+		return amountCell(*t.amount)
+	}
+	return Cell{Text: strconv.FormatInt(t.integer, 10), Numeric: true}
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package reports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+type transfer struct {
+	ID      string
+	Created time.Time
+	Amount  base.Amount
+	Entries int
+}
+
+func settlement() Definition[transfer] {
+	return Definition[transfer]{
+		Title: "Settlement 2021/03/04",
+		Columns: []Column[transfer]{
+			{Header: "ID", Value: func(t transfer) interface{} { return t.ID }},
+			{Header: "Created", Value: func(t transfer) interface{} { return t.Created }},
+			{Header: "Amount", Value: func(t transfer) interface{} { return t.Amount }, Total: true},
+			{Header: "Entries", Value: func(t transfer) interface{} { return t.Entries }, Total: true},
+		},
+	}
+}
+
+var transfers = []transfer{
+	{ID: "a", Created: time.Date(2021, time.March, 4, 15, 0, 0, 0, time.UTC), Amount: base.NewAmount(123456, "USD"), Entries: 2},
+	{ID: "b, c", Created: time.Date(2021, time.March, 4, 16, 30, 0, 0, time.UTC), Amount: base.NewAmount(-50, "USD"), Entries: 1},
+}
+
+func TestBuild__CSV(t *testing.T) {
+	report, err := Build(settlement(), transfers)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	require.Equal(t, `ID,Created,Amount,Entries
+a,2021-03-04T15:00:00Z,1234.56,2
+"b, c",2021-03-04T16:30:00Z,-0.50,1
+Total,,1234.06,3
+`, buf.String())
+
+	eastern, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	def := settlement()
+	def.Location = eastern
+	def.TimeFormat = "2006-01-02 15:04"
+	report, err = Build(def, transfers[:1])
+	require.NoError(t, err)
+	require.Equal(t, "2021-03-04 10:00", report.Rows[0][1].Text)
+}
+
+func TestBuild__JSON(t *testing.T) {
+	report, err := Build(settlement(), transfers[:1])
+	require.NoError(t, err)
+
+	bs, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"title": "Settlement 2021/03/04",
+		"columns": ["ID", "Created", "Amount", "Entries"],
+		"rows": [[
+			{"text": "a"},
+			{"text": "2021-03-04T15:00:00Z"},
+			{"text": "1234.56", "numeric": true, "currency": "USD"},
+			{"text": "2", "numeric": true}
+		]],
+		"totals": [
+			{"text": "Total"},
+			{"text": ""},
+			{"text": "1234.56", "numeric": true, "currency": "USD"},
+			{"text": "2", "numeric": true}
+		]
+	}`, string(bs))
+}
+
+func TestBuild__Errors(t *testing.T) {
+	_, err := Build(Definition[transfer]{}, transfers)
+	require.Error(t, err)
+
+	mixed := append([]transfer{{ID: "d", Amount: base.NewAmount(1, "EUR")}}, transfers...)
+	_, err = Build(settlement(), mixed)
+	require.EqualError(t, err, `reports: row 2 column "Amount": can't total EUR and USD`)
+
+	_, err = Build(Definition[transfer]{Columns: []Column[transfer]{
+		{Header: "Bad", Value: func(t transfer) interface{} { return []string{t.ID} }},
+	}}, transfers)
+	require.EqualError(t, err, `reports: row 1 column "Bad": unsupported value []string`)
+}
+
+func TestWriteXLSX(t *testing.T) {
+	report, err := Build(settlement(), transfers)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteXLSX(&buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		bs, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(bs)
+	}
+
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="Settlement 20210304" sheetId="1" r:id="rId1"/>`)
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t>ID</t></is></c>`)
+	require.Contains(t, sheet, `<c r="C2"><v>1234.56</v></c>`)
+	require.Contains(t, sheet, `<c r="A3" t="inlineStr"><is><t>b, c</t></is></c>`)
+	require.Contains(t, sheet, `<c r="D4" s="1"><v>3</v></c>`)
+
+	// the same report is written the same way
+	var again bytes.Buffer
+	require.NoError(t, report.WriteXLSX(&again))
+	require.Equal(t, buf.Bytes(), again.Bytes())
+}
+
+func TestColumnName(t *testing.T) {
+	require.Equal(t, "A", columnName(0))
+	require.Equal(t, "Z", columnName(25))
+	require.Equal(t, "AA", columnName(26))
+	require.Equal(t, "AZ", columnName(51))
+	require.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,390 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package retention deletes or anonymizes rows once they're older than their retention policy,
+// such as customer details removed seven years after an account closes. Rows under a legal hold
+// are kept.
+//
+//	purger, err := retention.New(db, retention.Config{
+//		Policies: []retention.Policy{{
+//			Name:       "webhook-deliveries",
+//			Table:      "webhook_deliveries",
+//			TimeColumn: "created_at",
+//			Days:       90,
+//		}},
+//		Logger: logger,
+//	})
+//
+//	ticker := jobs.BankingDayTicker(2*time.Hour, calendar) // 2am each banking day
+//	for range ticker.C {
+//		report, err := purger.Run(ctx)
+//	}
+//
+// A context from dryrun.WithDryRun reports what would be purged without changing any rows.
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base/dryrun"
+	"github.com/moov-io/base/log"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	purgedRows = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "retention_purged_rows_total",
+		Help: "Counter of rows deleted or anonymized by retention policies",
+	}, []string{"policy", "action"})
+
+	heldRows = kitprom.NewCounterFrom(stdprom.CounterOpts{
+		Name: "retention_held_rows_total",
+		Help: "Counter of expired rows kept because of a legal hold",
+	}, []string{"policy"})
+)
+
+// DefaultBatchSize is how many rows are purged per statement when Config.BatchSize is zero
+const DefaultBatchSize = 500
+
+// Action is what happens to expired rows
+type Action string
+
+const (
+	// Delete removes expired rows
+	Delete Action = "delete"
+
+	// Anonymize overwrites the Policy's Anonymize columns of expired rows
+	Anonymize Action = "anonymize"
+)
+
+// TimeFormat is how a Policy's TimeColumn is stored
+type TimeFormat int
+
+const (
+	// Timestamp columns are DATETIME or TIMESTAMP values
+	Timestamp TimeFormat = iota
+
+	// UnixNano columns are BIGINT nanoseconds since the Unix epoch
+	UnixNano
+)
+
+// Policy is how long rows of a table are kept
+type Policy struct {
+	Name  string
+	Table string
+
+	// KeyColumn uniquely identifies rows and orders batches, "id" when empty
+	KeyColumn string
+
+	// TimeColumn is when the row's retention period starts, such as created_at or closed_at.
+	// Rows with a NULL time aren't purged.
+	TimeColumn string
+	TimeFormat TimeFormat
+
+	// Days is how many days rows are kept after TimeColumn
+	Days int
+
+	// Action is Delete when empty
+	Action Action
+
+	// Anonymize maps the columns overwritten by Anonymize to their replacement values
+	Anonymize map[string]interface{}
+
+	// AnonymizedColumn, when set, is set to the purge time by Anonymize and rows with it set are skipped
+	AnonymizedColumn string
+
+	// HoldColumn, when set, is a boolean column marking rows under a legal hold
+	HoldColumn string
+}
+
+// Holds reports which rows are under a legal hold kept outside of the policy's table, such as
+// in a case management system.
+type Holds interface {
+	Held(ctx context.Context, policy string, keys []string) (map[string]bool, error)
+}
+
+// Config configures a Purger
+type Config struct {
+	Policies []Policy
+
+	// Holds is consulted for every batch when set
+	Holds Holds
+
+	// BatchSize is DefaultBatchSize when zero
+	BatchSize int
+
+	Logger log.Logger
+}
+
+// PolicyReport is what Run did for one Policy
+type PolicyReport struct {
+	Policy string    `json:"policy"`
+	Action Action    `json:"action"`
+	Cutoff time.Time `json:"cutoff"`
+
+	// Expired rows are older than the cutoff and not marked by HoldColumn. Held of them were
+	// kept by Holds and Purged were deleted or anonymized, which is zero in a dry run.
+	Expired int64 `json:"expired"`
+	Held    int64 `json:"held"`
+	Purged  int64 `json:"purged"`
+}
+
+// Report is what Run did
+type Report struct {
+	DryRun   bool           `json:"dryRun"`
+	Policies []PolicyReport `json:"policies"`
+}
+
+// Purger applies retention policies to a database
+type Purger struct {
+	db     *sql.DB
+	cfg    Config
+	logger log.Logger
+	now    func() time.Time
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// New returns a Purger of cfg's policies, which are validated
+func New(db *sql.DB, cfg Config) (*Purger, error) {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewNopLogger()
+	}
+
+	names := make(map[string]bool)
+	for i := range cfg.Policies {
+		p := &cfg.Policies[i]
+		if p.KeyColumn == "" {
+			p.KeyColumn = "id"
+		}
+		if p.Action == "" {
+			p.Action = Delete
+		}
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("retention: duplicate policy %s", p.Name)
+		}
+		names[p.Name] = true
+	}
+
+	return &Purger{
+		db:     db,
+		cfg:    cfg,
+		logger: cfg.Logger,
+		now:    time.Now,
+	}, nil
+}
+
+func (p Policy) validate() error {
+	if p.Name == "" {
+		return errors.New("retention: policy is missing a name")
+	}
+	if p.Days <= 0 {
+		return fmt.Errorf("retention: %s must keep rows for a positive number of days", p.Name)
+	}
+	columns := []string{p.Table, p.KeyColumn, p.TimeColumn}
+	if p.AnonymizedColumn != "" {
+		columns = append(columns, p.AnonymizedColumn)
+	}
+	if p.HoldColumn != "" {
+		columns = append(columns, p.HoldColumn)
+	}
+	for col := range p.Anonymize {
+		columns = append(columns, col)
+	}
+	for _, c := range columns {
+		if !identifier.MatchString(c) {
+			return fmt.Errorf("retention: %s has an invalid table or column %q", p.Name, c)
+		}
+	}
+	switch p.Action {
+	case Delete:
+	case Anonymize:
+		if len(p.Anonymize) == 0 {
+			return fmt.Errorf("retention: %s anonymizes no columns", p.Name)
+		}
+	default:
+		return fmt.Errorf("retention: %s has an unknown action %q", p.Name, p.Action)
+	}
+	return nil
+}
+
+// Run applies each policy in batches. Policies after one returning an error are still run,
+// the first error is returned with the Report.
+func (p *Purger) Run(ctx context.Context) (Report, error) {
+	report := Report{DryRun: dryrun.IsDryRun(ctx)}
+	var firstErr error
+	for _, policy := range p.cfg.Policies {
+		pr, err := p.apply(ctx, policy, report.DryRun)
+		report.Policies = append(report.Policies, pr)
+		if err != nil {
+			p.logger.Error().With(log.Fields{"policy": log.String(policy.Name)}).LogErrorf("retention: %v", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return report, firstErr
+}
+
+func (p *Purger) apply(ctx context.Context, policy Policy, dryRun bool) (PolicyReport, error) {
+	cutoff := p.now().UTC().AddDate(0, 0, -policy.Days)
+	report := PolicyReport{
+		Policy: policy.Name,
+		Action: policy.Action,
+		Cutoff: cutoff,
+	}
+	logger := p.logger.With(log.Fields{
+		"policy":  log.String(policy.Name),
+		"dry_run": log.Bool(dryRun),
+	})
+
+	var cutoffArg interface{} = cutoff
+	if policy.TimeFormat == UnixNano {
+		cutoffArg = cutoff.UnixNano()
+	}
+
+	last := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		keys, err := p.expired(ctx, policy, cutoffArg, last)
+		if err != nil {
+			return report, fmt.Errorf("finding expired %s rows: %w", policy.Name, err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+		last = keys[len(keys)-1]
+		fetched := len(keys)
+		report.Expired += int64(fetched)
+
+		if p.cfg.Holds != nil {
+			held, err := p.cfg.Holds.Held(ctx, policy.Name, keys)
+			if err != nil {
+				return report, fmt.Errorf("reading %s holds: %w", policy.Name, err)
+			}
+			kept := keys[:0]
+			for _, k := range keys {
+				if held[k] {
+					report.Held++
+					heldRows.With("policy", policy.Name).Add(1)
+				} else {
+					kept = append(kept, k)
+				}
+			}
+			keys = kept
+		}
+
+		if !dryRun && len(keys) > 0 {
+			n, err := p.purge(ctx, policy, keys)
+			if err != nil {
+				return report, fmt.Errorf("purging %s rows: %w", policy.Name, err)
+			}
+			report.Purged += n
+			purgedRows.With("policy", policy.Name, "action", string(policy.Action)).Add(float64(n))
+		}
+		logger.Info().With(log.Fields{
+			"expired": log.Int(int(report.Expired)),
+			"purged":  log.Int(int(report.Purged)),
+		}).Log("retention: batch finished")
+
+		if fetched < p.cfg.BatchSize {
+			break
+		}
+	}
+	return report, nil
+}
+
+// expired returns the keys of up to a batch of expired rows after last, skipping rows with a
+// HoldColumn set or already anonymized.
+func (p *Purger) expired(ctx context.Context, policy Policy, cutoff interface{}, last string) ([]string, error) {
+	where := []string{policy.TimeColumn + " < ?"}
+	args := []interface{}{cutoff}
+	if last != "" {
+		where = append(where, policy.KeyColumn+" > ?")
+		args = append(args, last)
+	}
+	if policy.HoldColumn != "" {
+		where = append(where, "("+policy.HoldColumn+" IS NULL OR "+policy.HoldColumn+" = ?)")
+		args = append(args, false)
+	}
+	if policy.Action == Anonymize && policy.AnonymizedColumn != "" {
+		where = append(where, policy.AnonymizedColumn+" IS NULL")
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d`,
+		policy.KeyColumn, policy.Table, strings.Join(where, " AND "), policy.KeyColumn, p.cfg.BatchSize)
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (p *Purger) purge(ctx context.Context, policy Policy, keys []string) (int64, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+	var args []interface{}
+
+	var query string
+	switch policy.Action {
+	case Anonymize:
+		columns := make([]string, 0, len(policy.Anonymize))
+		for col := range policy.Anonymize {
+			columns = append(columns, col)
+		}
+		sort.Strings(columns)
+
+		var set []string
+		for _, col := range columns {
+			set = append(set, col+" = ?")
+			args = append(args, policy.Anonymize[col])
+		}
+		if policy.AnonymizedColumn != "" {
+			set = append(set, policy.AnonymizedColumn+" = ?")
+			if policy.TimeFormat == UnixNano {
+				args = append(args, p.now().UnixNano())
+			} else {
+				args = append(args, p.now().UTC())
+			}
+		}
+		query = fmt.Sprintf(`UPDATE %s SET %s WHERE %s IN (%s)`, policy.Table, strings.Join(set, ", "), policy.KeyColumn, placeholders)
+	default:
+		query = fmt.Sprintf(`DELETE FROM %s WHERE %s IN (%s)`, policy.Table, policy.KeyColumn, placeholders)
+	}
+	for _, k := range keys {
+		args = append(args, k)
+	}
+
+	res, err := p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/dryrun"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+func setupDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE customers (id INTEGER PRIMARY KEY, email TEXT, created_at BIGINT, legal_hold BOOLEAN, anonymized_at BIGINT)`)
+	require.NoError(t, err)
+
+	// ids 1-7 are 100 days old, 8 and 9 are recent and 3 is on hold
+	for id := 1; id <= 9; id++ {
+		created := now.AddDate(0, 0, -100)
+		if id > 7 {
+			created = now.AddDate(0, 0, -10)
+		}
+		_, err = db.Exec(`INSERT INTO customers (id, email, created_at, legal_hold) VALUES (?, ?, ?, ?)`, id, "user@example.com", created.UnixNano(), id == 3)
+		require.NoError(t, err)
+	}
+	return db
+}
+
+func count(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow(query).Scan(&n))
+	return n
+}
+
+type holds map[string]bool
+
+func (h holds) Held(ctx context.Context, policy string, keys []string) (map[string]bool, error) {
+	return h, nil
+}
+
+func deletePolicy() Policy {
+	return Policy{
+		Name:       "customers",
+		Table:      "customers",
+		TimeColumn: "created_at",
+		TimeFormat: UnixNano,
+		Days:       90,
+		HoldColumn: "legal_hold",
+	}
+}
+
+func TestPurger__Delete(t *testing.T) {
+	db := setupDB(t)
+	purger, err := New(db, Config{Policies: []Policy{deletePolicy()}, Holds: holds{"5": true}, BatchSize: 2})
+	require.NoError(t, err)
+	purger.now = func() time.Time { return now }
+
+	// dry run first
+	report, err := purger.Run(dryrun.WithDryRun(context.Background()))
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, PolicyReport{
+		Policy:  "customers",
+		Action:  Delete,
+		Cutoff:  now.AddDate(0, 0, -90),
+		Expired: 6,
+		Held:    1,
+	}, report.Policies[0])
+	require.Equal(t, 9, count(t, db, `SELECT COUNT(*) FROM customers`))
+
+	report, err = purger.Run(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.DryRun)
+	require.Equal(t, int64(5), report.Policies[0].Purged)
+	require.Equal(t, 4, count(t, db, `SELECT COUNT(*) FROM customers`))
+	require.Equal(t, 2, count(t, db, `SELECT COUNT(*) FROM customers WHERE id IN (3, 5)`))
+
+	// nothing left to do
+	report, err = purger.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), report.Policies[0].Purged)
+}
+
+func TestPurger__Anonymize(t *testing.T) {
+	db := setupDB(t)
+	policy := deletePolicy()
+	policy.Action = Anonymize
+	policy.Anonymize = map[string]interface{}{"email": "redacted"}
+	policy.AnonymizedColumn = "anonymized_at"
+
+	purger, err := New(db, Config{Policies: []Policy{policy}, BatchSize: 4})
+	require.NoError(t, err)
+	purger.now = func() time.Time { return now }
+
+	report, err := purger.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(6), report.Policies[0].Purged)
+	require.Equal(t, 6, count(t, db, `SELECT COUNT(*) FROM customers WHERE email = 'redacted' AND anonymized_at IS NOT NULL`))
+	require.Equal(t, 9, count(t, db, `SELECT COUNT(*) FROM customers`))
+
+	// anonymized rows are skipped
+	report, err = purger.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), report.Policies[0].Expired)
+}
+
+func TestNew__Invalid(t *testing.T) {
+	invalid := []Policy{
+		{Name: "", Table: "t", TimeColumn: "c", Days: 1},
+		{Name: "a", Table: "t", TimeColumn: "c"},
+		{Name: "a", Table: "t; DROP TABLE x", TimeColumn: "c", Days: 1},
+		{Name: "a", Table: "t", TimeColumn: "c", Days: 1, Action: Anonymize},
+		{Name: "a", Table: "t", TimeColumn: "c", Days: 1, Action: "archive"},
+	}
+	for _, p := range invalid {
+		_, err := New(nil, Config{Policies: []Policy{p}})
+		require.Error(t, err, "%#v", p)
+	}
+
+	p := Policy{Name: "a", Table: "t", TimeColumn: "c", Days: 1}
+	_, err := New(nil, Config{Policies: []Policy{p, p}})
+	require.Error(t, err)
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package risk combines signals about a transfer, such as velocity or account age, into a Decision
+// to allow, review or deny it. Decisions encode as JSON so they can be stored for audits.
+//
+//	engine, err := risk.NewEngine(risk.Thresholds{Review: 40, Deny: 80},
+//		risk.Weighted[Transfer]{Signal: newAccount, Weight: 1},
+//		risk.Weighted[Transfer]{Signal: velocity, Weight: 2},
+//	)
+//	decision, err := engine.Evaluate(ctx, xfer)
+//	if decision.Action == risk.Deny {
+//		...
+//	}
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// Action is what a Decision recommends
+type Action string
+
+const (
+	Allow  Action = "allow"
+	Review Action = "review"
+	Deny   Action = "deny"
+)
+
+// Reason explains part of a score, such as "R101 account opened today"
+type Reason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Signal  string `json:"signal,omitempty"`
+}
+
+// Result is what a Signal found. Score is from 0 (no risk) to 100.
+type Result struct {
+	Score   float64
+	Reasons []Reason
+}
+
+// Signal scores one aspect of the input's risk
+type Signal[T any] interface {
+	Name() string
+	Evaluate(ctx context.Context, input T) (Result, error)
+}
+
+type signalFunc[T any] struct {
+	name string
+	fn   func(ctx context.Context, input T) (Result, error)
+}
+
+func (s signalFunc[T]) Name() string { return s.name }
+
+func (s signalFunc[T]) Evaluate(ctx context.Context, input T) (Result, error) {
+	return s.fn(ctx, input)
+}
+
+// SignalFunc returns a Signal calling fn
+func SignalFunc[T any](name string, fn func(ctx context.Context, input T) (Result, error)) Signal[T] {
+	return signalFunc[T]{name: name, fn: fn}
+}
+
+// Weighted is a Signal and how much it counts towards the Score
+type Weighted[T any] struct {
+	Signal Signal[T]
+	Weight float64
+}
+
+// Thresholds are the scores at or above which inputs are reviewed or denied
+type Thresholds struct {
+	Review float64 `json:"review"`
+	Deny   float64 `json:"deny"`
+}
+
+// Action returns what score calls for
+func (t Thresholds) Action(score float64) Action {
+	switch {
+	case score >= t.Deny:
+		return Deny
+	case score >= t.Review:
+		return Review
+	}
+	return Allow
+}
+
+// SignalResult is the outcome of one Signal in a Decision
+type SignalResult struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Decision is the combined outcome of every Signal
+type Decision struct {
+	Action  Action         `json:"action"`
+	Score   float64        `json:"score"`
+	Reasons []Reason       `json:"reasons"`
+	Signals []SignalResult `json:"signals"`
+
+	Thresholds  Thresholds `json:"thresholds"`
+	EvaluatedAt time.Time  `json:"evaluatedAt"`
+}
+
+// Engine evaluates inputs of type T against weighted signals
+type Engine[T any] struct {
+	thresholds Thresholds
+	signals    []Weighted[T]
+	total      float64
+
+	now func() time.Time
+}
+
+// NewEngine returns an Engine whose Score is the weighted average of signals
+func NewEngine[T any](thresholds Thresholds, signals ...Weighted[T]) (*Engine[T], error) {
+	if thresholds.Review > thresholds.Deny {
+		return nil, errors.New("risk: review threshold is above deny")
+	}
+	e := &Engine[T]{
+		thresholds: thresholds,
+		now:        time.Now,
+	}
+	for _, s := range signals {
+		if s.Signal == nil || s.Weight <= 0 {
+			return nil, errors.New("risk: signals need a positive weight")
+		}
+		e.total += s.Weight
+		e.signals = append(e.signals, s)
+	}
+	if len(e.signals) == 0 {
+		return nil, errors.New("risk: no signals")
+	}
+	return e, nil
+}
+
+// Evaluate runs every signal against input and combines their scores. Signals returning an error
+// add nothing to the score but raise an Allow to Review, and their errors are returned as a
+// base.ErrorList alongside the Decision. errors.Is and errors.As do not look inside the list, so
+// callers wanting each signal's error should type assert to base.ErrorList and range over it.
+func (e *Engine[T]) Evaluate(ctx context.Context, input T) (Decision, error) {
+	decision := Decision{
+		Reasons:     []Reason{},
+		Thresholds:  e.thresholds,
+		EvaluatedAt: e.now().UTC(),
+	}
+
+	var weighted float64
+	var failed base.ErrorList
+	for _, s := range e.signals {
+		name := s.Signal.Name()
+		result, err := s.Signal.Evaluate(ctx, input)
+		outcome := SignalResult{Name: name, Weight: s.Weight}
+		if err != nil {
+			outcome.Error = err.Error()
+			failed.Add(fmt.Errorf("%s: %w", name, err))
+		} else {
+			outcome.Score = clamp(result.Score)
+			weighted += outcome.Score * s.Weight
+			for _, r := range result.Reasons {
+				if r.Signal == "" {
+					r.Signal = name
+				}
+				decision.Reasons = append(decision.Reasons, r)
+			}
+		}
+		decision.Signals = append(decision.Signals, outcome)
+	}
+
+	decision.Score = weighted / e.total
+	decision.Action = e.thresholds.Action(decision.Score)
+
+	sort.SliceStable(decision.Reasons, func(i, j int) bool {
+		return decision.Reasons[i].Code < decision.Reasons[j].Code
+	})
+
+	if len(failed) > 0 {
+		if decision.Action == Allow {
+			decision.Action = Review
+		}
+		return decision, failed
+	}
+	return decision, nil
+}
+
+func clamp(score float64) float64 {
+	switch {
+	case score < 0:
+		return 0
+	case score > 100:
+		return 100
+	}
+	return score
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+type transfer struct {
+	AccountAgeDays int
+	Amount         int
+}
+
+var (
+	newAccount = SignalFunc("new-account", func(ctx context.Context, xfer transfer) (Result, error) {
+		if xfer.AccountAgeDays < 7 {
+			return Result{Score: 100, Reasons: []Reason{{Code: "R101", Message: "account opened this week"}}}, nil
+		}
+		return Result{}, nil
+	})
+	largeAmount = SignalFunc("large-amount", func(ctx context.Context, xfer transfer) (Result, error) {
+		if xfer.Amount > 10000 {
+			return Result{Score: 150, Reasons: []Reason{{Code: "R001", Message: "amount over 10,000"}}}, nil
+		}
+		return Result{Score: -5}, nil
+	})
+)
+
+func TestEngine(t *testing.T) {
+	engine, err := NewEngine(Thresholds{Review: 40, Deny: 80},
+		Weighted[transfer]{Signal: newAccount, Weight: 1},
+		Weighted[transfer]{Signal: largeAmount, Weight: 3},
+	)
+	require.NoError(t, err)
+
+	d, err := engine.Evaluate(context.Background(), transfer{AccountAgeDays: 30, Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, Allow, d.Action)
+	require.Equal(t, 0.0, d.Score)
+	require.Empty(t, d.Reasons)
+
+	d, err = engine.Evaluate(context.Background(), transfer{AccountAgeDays: 1, Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, Allow, d.Action)
+	require.Equal(t, 25.0, d.Score)
+
+	d, err = engine.Evaluate(context.Background(), transfer{AccountAgeDays: 30, Amount: 50000})
+	require.NoError(t, err)
+	require.Equal(t, Review, d.Action)
+	require.Equal(t, 75.0, d.Score)
+
+	d, err = engine.Evaluate(context.Background(), transfer{AccountAgeDays: 1, Amount: 50000})
+	require.NoError(t, err)
+	require.Equal(t, Deny, d.Action)
+	require.Equal(t, 100.0, d.Score)
+	require.Equal(t, []Reason{
+		{Code: "R001", Message: "amount over 10,000", Signal: "large-amount"},
+		{Code: "R101", Message: "account opened this week", Signal: "new-account"},
+	}, d.Reasons)
+}
+
+var errTimeout = errors.New("timeout")
+
+func TestEngine__SignalError(t *testing.T) {
+	failing := SignalFunc("failing", func(ctx context.Context, xfer transfer) (Result, error) {
+		return Result{}, errTimeout
+	})
+	engine, err := NewEngine(Thresholds{Review: 40, Deny: 80},
+		Weighted[transfer]{Signal: newAccount, Weight: 1},
+		Weighted[transfer]{Signal: failing, Weight: 1},
+	)
+	require.NoError(t, err)
+
+	d, err := engine.Evaluate(context.Background(), transfer{AccountAgeDays: 30})
+	require.EqualError(t, err, "failing: timeout")
+	require.Equal(t, Review, d.Action)
+	require.Equal(t, "timeout", d.Signals[1].Error)
+
+	var list base.ErrorList
+	require.True(t, errors.As(err, &list))
+	require.Len(t, list, 1)
+	require.ErrorIs(t, list[0], errTimeout)
+}
+
+func TestNewEngine__Invalid(t *testing.T) {
+	_, err := NewEngine[transfer](Thresholds{Review: 40, Deny: 80})
+	require.Error(t, err)
+
+	_, err = NewEngine(Thresholds{Review: 90, Deny: 80}, Weighted[transfer]{Signal: newAccount, Weight: 1})
+	require.Error(t, err)
+
+	_, err = NewEngine(Thresholds{Review: 40, Deny: 80}, Weighted[transfer]{Signal: newAccount})
+	require.Error(t, err)
+}
+
+func TestDecision__JSON(t *testing.T) {
+	engine, err := NewEngine(Thresholds{Review: 40, Deny: 80}, Weighted[transfer]{Signal: newAccount, Weight: 1})
+	require.NoError(t, err)
+	engine.now = func() time.Time { return time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC) }
+
+	d, err := engine.Evaluate(context.Background(), transfer{AccountAgeDays: 1})
+	require.NoError(t, err)
+
+	bs, err := json.Marshal(d)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"action": "deny",
+		"score": 100,
+		"reasons": [{"code": "R101", "message": "account opened this week", "signal": "new-account"}],
+		"signals": [{"name": "new-account", "score": 100, "weight": 1}],
+		"thresholds": {"review": 40, "deny": 80},
+		"evaluatedAt": "2021-03-01T12:00:00Z"
+	}`, string(bs))
+
+	var out Decision
+	require.NoError(t, json.Unmarshal(bs, &out))
+	require.Equal(t, d, out)
+}
@@ -0,0 +1,386 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package rules evaluates declarative business rules, such as which processing path a transfer
+// takes, so they can be changed in config instead of code.
+//
+//	[
+//	  {"id": "large-same-day", "action": "review", "when": {"all": [
+//	    {"field": "speed", "op": "eq", "value": "same-day"},
+//	    {"field": "amount", "op": "gte", "value": {"value": 100000000, "currency": "USD"}}
+//	  ]}},
+//	  {"id": "rtp-banks", "action": "rtp", "when": {"field": "destination.routingNumber", "op": "in", "value": ["021000021", "231380104"]}}
+//	]
+//
+// Inputs are maps or structs, whose fields are named as they're encoded to JSON. Fields are
+// dotted paths into nested objects. Amounts ({"value": ..., "currency": ...}) are compared by
+// value and must be in the same currency.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Operator compares a field to a rule's value
+type Operator string
+
+const (
+	Equal          Operator = "eq"
+	NotEqual       Operator = "ne"
+	Less           Operator = "lt"
+	LessOrEqual    Operator = "lte"
+	Greater        Operator = "gt"
+	GreaterOrEqual Operator = "gte"
+	In             Operator = "in"
+	NotIn          Operator = "notIn"
+
+	// Exists matches fields which are present and not null. It has no value.
+	Exists Operator = "exists"
+)
+
+// Condition is a comparison of one field or a combination of other conditions. Exactly one
+// of All, Any, Not or Field is set.
+type Condition struct {
+	All []Condition `json:"all,omitempty"`
+	Any []Condition `json:"any,omitempty"`
+	Not *Condition  `json:"not,omitempty"`
+
+	Field string      `json:"field,omitempty"`
+	Op    Operator    `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Rule returns Action when its condition matches
+type Rule struct {
+	ID     string    `json:"id"`
+	Action string    `json:"action"`
+	When   Condition `json:"when"`
+}
+
+// Match is a Rule which matched an input
+type Match struct {
+	RuleID string `json:"ruleID"`
+	Action string `json:"action"`
+}
+
+// Engine evaluates rules in the order they were given
+type Engine struct {
+	rules []Rule
+}
+
+// New checks rules and returns an Engine of them
+func New(rules []Rule) (*Engine, error) {
+	ids := make(map[string]bool, len(rules))
+	out := make([]Rule, len(rules))
+	for i, r := range rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("rules: rule %d has no id", i)
+		}
+		if ids[r.ID] {
+			return nil, fmt.Errorf("rules: duplicate rule %s", r.ID)
+		}
+		ids[r.ID] = true
+
+		when, err := compile(r.When)
+		if err != nil {
+			return nil, fmt.Errorf("rules: rule %s: %w", r.ID, err)
+		}
+		r.When = when
+		out[i] = r
+	}
+	return &Engine{rules: out}, nil
+}
+
+// Parse reads a JSON array of rules and returns an Engine of them
+func Parse(data []byte) (*Engine, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	return New(rules)
+}
+
+// compile checks c and normalizes its values the same way inputs are, so values read from
+// YAML or written in Go compare equal to those from JSON.
+func compile(c Condition) (Condition, error) {
+	set := 0
+	for _, ok := range []bool{c.All != nil, c.Any != nil, c.Not != nil, c.Field != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return c, errors.New("condition must have exactly one of all, any, not or field")
+	}
+
+	var err error
+	for i := range c.All {
+		if c.All[i], err = compile(c.All[i]); err != nil {
+			return c, err
+		}
+	}
+	for i := range c.Any {
+		if c.Any[i], err = compile(c.Any[i]); err != nil {
+			return c, err
+		}
+	}
+	if c.Not != nil {
+		not, err := compile(*c.Not)
+		if err != nil {
+			return c, err
+		}
+		c.Not = &not
+	}
+	if c.Field == "" {
+		return c, nil
+	}
+
+	if c.Value, err = normalize(c.Value); err != nil {
+		return c, fmt.Errorf("%s: %w", c.Field, err)
+	}
+	switch c.Op {
+	case Equal, NotEqual, Less, LessOrEqual, Greater, GreaterOrEqual:
+		if c.Value == nil {
+			return c, fmt.Errorf("%s: %s needs a value", c.Field, c.Op)
+		}
+	case In, NotIn:
+		if _, ok := c.Value.([]interface{}); !ok {
+			return c, fmt.Errorf("%s: %s needs a list", c.Field, c.Op)
+		}
+	case Exists:
+		if c.Value != nil {
+			return c, fmt.Errorf("%s: exists has no value", c.Field)
+		}
+	default:
+		return c, fmt.Errorf("%s: unknown operator %q", c.Field, c.Op)
+	}
+	return c, nil
+}
+
+// Evaluate returns the rules whose conditions match input in order. Errors are returned for
+// comparisons which can't be made, such as a string less than a number or amounts in different
+// currencies, rather than treating them as not matching.
+func (e *Engine) Evaluate(input interface{}) ([]Match, error) {
+	doc, err := normalize(input)
+	if err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	var matches []Match
+	for _, r := range e.rules {
+		ok, err := eval(r.When, doc)
+		if err != nil {
+			return nil, fmt.Errorf("rules: rule %s: %w", r.ID, err)
+		}
+		if ok {
+			matches = append(matches, Match{RuleID: r.ID, Action: r.Action})
+		}
+	}
+	return matches, nil
+}
+
+// First returns the first rule matching input, or false when none do
+func (e *Engine) First(input interface{}) (Match, bool, error) {
+	matches, err := e.Evaluate(input)
+	if err != nil || len(matches) == 0 {
+		return Match{}, false, err
+	}
+	return matches[0], true, nil
+}
+
+// normalize round trips v through JSON so structs, maps and numbers of any type are compared
+// as the same generic values
+func normalize(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func eval(c Condition, doc interface{}) (bool, error) {
+	switch {
+	case c.All != nil:
+		for i := range c.All {
+			if ok, err := eval(c.All[i], doc); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case c.Any != nil:
+		for i := range c.Any {
+			if ok, err := eval(c.Any[i], doc); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+
+	case c.Not != nil:
+		ok, err := eval(*c.Not, doc)
+		return !ok && err == nil, err
+	}
+
+	value, found := lookup(doc, c.Field)
+	switch c.Op {
+	case Exists:
+		return found && value != nil, nil
+	case Equal:
+		return equal(value, c.Value), nil
+	case NotEqual:
+		return !equal(value, c.Value), nil
+	case In, NotIn:
+		in := false
+		for _, v := range c.Value.([]interface{}) {
+			if equal(value, v) {
+				in = true
+				break
+			}
+		}
+		return in == (c.Op == In), nil
+	}
+
+	// missing fields don't match ordered comparisons
+	if !found || value == nil {
+		return false, nil
+	}
+	cmp, err := compare(value, c.Value)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", c.Field, err)
+	}
+	switch c.Op {
+	case Less:
+		return cmp < 0, nil
+	case LessOrEqual:
+		return cmp <= 0, nil
+	case Greater:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func lookup(doc interface{}, field string) (interface{}, bool) {
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if doc, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+func equal(a, b interface{}) bool {
+	if _, ok := a.(json.Number); ok {
+		cmp, err := compare(a, b)
+		return err == nil && cmp == 0
+	}
+	if _, ok := amount(a); ok {
+		cmp, err := compare(a, b)
+		return err == nil && cmp == 0
+	}
+	switch a := a.(type) {
+	case nil, string, bool:
+		return a == b
+	}
+	return false
+}
+
+// compare returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compare(a, b interface{}) (int, error) {
+	if x, ok := amount(a); ok {
+		y, ok := amount(b)
+		if !ok {
+			return 0, fmt.Errorf("can't compare an amount to %v", b)
+		}
+		if !strings.EqualFold(x.currency, y.currency) {
+			return 0, fmt.Errorf("can't compare %s to %s", x.currency, y.currency)
+		}
+		a, b = x.value, y.value
+	}
+	switch a := a.(type) {
+	case json.Number:
+		n, ok := b.(json.Number)
+		if !ok {
+			return 0, fmt.Errorf("can't compare number %s to %v", a, b)
+		}
+		return compareNumbers(a, n)
+	case string:
+		str, ok := b.(string)
+		if !ok {
+			return 0, fmt.Errorf("can't compare string %q to %v", a, b)
+		}
+		return strings.Compare(a, str), nil
+	}
+	return 0, fmt.Errorf("can't order %v", a)
+}
+
+func compareNumbers(a, b json.Number) (int, error) {
+	if x, err := a.Int64(); err == nil {
+		if y, err := b.Int64(); err == nil {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	x, err := a.Float64()
+	if err != nil {
+		return 0, err
+	}
+	y, err := b.Float64()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+type amountValue struct {
+	value    json.Number
+	currency string
+}
+
+// amount returns v as an amount when it's an object of only a numeric value and a currency,
+// the JSON form of base.Amount. Keys are matched ignoring case as config files often
+// capitalize them.
+func amount(v interface{}) (amountValue, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) != 2 {
+		return amountValue{}, false
+	}
+	var out amountValue
+	for key, val := range obj {
+		switch strings.ToLower(key) {
+		case "value":
+			out.value, _ = val.(json.Number)
+		case "currency":
+			out.currency, _ = val.(string)
+		}
+	}
+	return out, out.value != "" && out.currency != ""
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moov-io/base"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+type transfer struct {
+	Speed       string      `json:"speed"`
+	Amount      base.Amount `json:"amount"`
+	Destination struct {
+		RoutingNumber string `json:"routingNumber"`
+	} `json:"destination"`
+	Memo  *string `json:"memo"`
+	Count int     `json:"count"`
+}
+
+const testRules = `[
+  {"id": "large-same-day", "action": "review", "when": {"all": [
+    {"field": "speed", "op": "eq", "value": "same-day"},
+    {"field": "amount", "op": "gte", "value": {"value": 100000000, "currency": "USD"}}
+  ]}},
+  {"id": "rtp-banks", "action": "rtp", "when": {"field": "destination.routingNumber", "op": "in", "value": ["021000021", "231380104"]}},
+  {"id": "standard", "action": "ach", "when": {"not": {"field": "speed", "op": "eq", "value": "same-day"}}}
+]`
+
+func TestEvaluate(t *testing.T) {
+	engine, err := Parse([]byte(testRules))
+	require.NoError(t, err)
+
+	var xfer transfer
+	xfer.Speed = "same-day"
+	xfer.Amount = base.NewAmount(150000000, "USD")
+	xfer.Destination.RoutingNumber = "231380104"
+
+	matches, err := engine.Evaluate(xfer)
+	require.NoError(t, err)
+	require.Equal(t, []Match{
+		{RuleID: "large-same-day", Action: "review"},
+		{RuleID: "rtp-banks", Action: "rtp"},
+	}, matches)
+
+	xfer.Speed = "standard"
+	xfer.Destination.RoutingNumber = "011000015"
+	match, ok, err := engine.First(&xfer)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "standard", match.RuleID)
+
+	// maps work like structs
+	matches, err = engine.Evaluate(map[string]interface{}{
+		"speed":  "same-day",
+		"amount": map[string]interface{}{"value": 99999999, "currency": "USD"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	_, err = engine.Evaluate(map[string]interface{}{
+		"speed":  "same-day",
+		"amount": base.NewAmount(100, "EUR"),
+	})
+	require.ErrorContains(t, err, "rules: rule large-same-day: amount: can't compare EUR to USD")
+}
+
+func TestOperators(t *testing.T) {
+	memo := "rent"
+	input := transfer{Speed: "standard", Count: 3, Memo: &memo}
+
+	cases := []struct {
+		cond     Condition
+		expected bool
+	}{
+		{Condition{Field: "count", Op: Equal, Value: 3}, true},
+		{Condition{Field: "count", Op: Equal, Value: 3.0}, true},
+		{Condition{Field: "count", Op: NotEqual, Value: 3}, false},
+		{Condition{Field: "count", Op: Less, Value: 4}, true},
+		{Condition{Field: "count", Op: LessOrEqual, Value: 3}, true},
+		{Condition{Field: "count", Op: Greater, Value: 2.5}, true},
+		{Condition{Field: "count", Op: GreaterOrEqual, Value: 4}, false},
+		{Condition{Field: "speed", Op: Less, Value: "t"}, true},
+		{Condition{Field: "speed", Op: NotIn, Value: []string{"same-day", "instant"}}, true},
+		{Condition{Field: "speed", Op: In, Value: []string{"same-day", "instant"}}, false},
+		{Condition{Field: "memo", Op: Exists}, true},
+		{Condition{Field: "missing", Op: Exists}, false},
+		{Condition{Field: "missing", Op: Greater, Value: 1}, false},
+		{Condition{Field: "speed.nested", Op: Equal, Value: "x"}, false},
+		{Condition{Any: []Condition{
+			{Field: "count", Op: Equal, Value: 1},
+			{Field: "memo", Op: Equal, Value: "rent"},
+		}}, true},
+		{Condition{All: []Condition{
+			{Field: "count", Op: Equal, Value: 3},
+			{Field: "memo", Op: Equal, Value: "utilities"},
+		}}, false},
+	}
+	for i, tc := range cases {
+		engine, err := New([]Rule{{ID: "rule", When: tc.cond}})
+		require.NoError(t, err, i)
+
+		_, ok, err := engine.First(input)
+		require.NoError(t, err, i)
+		require.Equal(t, tc.expected, ok, "case %d", i)
+	}
+
+	engine, err := New([]Rule{{ID: "rule", When: Condition{Field: "speed", Op: Greater, Value: 1}}})
+	require.NoError(t, err)
+	_, _, err = engine.First(input)
+	require.ErrorContains(t, err, `speed: can't compare string "standard" to 1`)
+}
+
+func TestNew__Errors(t *testing.T) {
+	cases := map[string][]Rule{
+		"rule 0 has no id":            {{}},
+		"duplicate rule a":            {{ID: "a", When: Condition{Field: "x", Op: Exists}}, {ID: "a", When: Condition{Field: "x", Op: Exists}}},
+		"exactly one of":              {{ID: "a"}},
+		`unknown operator "like"`:     {{ID: "a", When: Condition{Field: "x", Op: "like", Value: "y"}}},
+		"x: in needs a list":          {{ID: "a", When: Condition{Field: "x", Op: In, Value: "y"}}},
+		"x: eq needs a value":         {{ID: "a", When: Condition{Field: "x", Op: Equal}}},
+		"x: exists has no value":      {{ID: "a", When: Condition{Field: "x", Op: Exists, Value: true}}},
+		"rule a: condition must have": {{ID: "a", When: Condition{All: []Condition{{}}}}},
+	}
+	for msg, rules := range cases {
+		_, err := New(rules)
+		require.ErrorContains(t, err, msg)
+	}
+
+	_, err := Parse([]byte(`{`))
+	require.ErrorContains(t, err, "rules: unexpected end of JSON input")
+}
+
+func TestRules__Config(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(`
+Rules:
+  - ID: big
+    Action: review
+    When:
+      Field: amount
+      Op: gt
+      Value: { Value: 500000, Currency: USD }
+`)))
+
+	var cfg struct {
+		Rules []Rule
+	}
+	require.NoError(t, v.Unmarshal(&cfg))
+
+	engine, err := New(cfg.Rules)
+	require.NoError(t, err)
+
+	_, ok, err := engine.First(transfer{Amount: base.NewAmount(500001, "USD")})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package rungroup runs a service's long lived goroutines together, such as its HTTP servers,
+// consumers and job runners. When any of them returns they're all interrupted, and Run waits
+// for each to finish before returning the first error.
+//
+//	var g rungroup.Group
+//	g.AddSignals(syscall.SIGINT, syscall.SIGTERM)
+//	g.AddHTTP(server, 30*time.Second)
+//	g.Add(adminServer.Listen, func(error) { adminServer.Shutdown() })
+//	g.AddContext(consumer.Run)
+//	g.AddContext(components.Run) // an *app.App, stopped after everything else is interrupted
+//	if err := g.Run(); err != nil {
+//		...
+//	}
+//
+// It's modeled after github.com/oklog/run.
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// SignalError is returned by the actor of AddSignals when a signal is received
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("received signal %v", e.Signal)
+}
+
+type actor struct {
+	execute   func() error
+	interrupt func(error)
+}
+
+// Group is a set of actors. The zero value is ready to use.
+type Group struct {
+	actors []actor
+}
+
+// Add runs execute with the group. interrupt is called with the first error once any actor
+// returns and must make execute return.
+func (g *Group) Add(execute func() error, interrupt func(error)) {
+	g.actors = append(g.actors, actor{execute: execute, interrupt: interrupt})
+}
+
+// AddContext runs fn with a context which is cancelled when the group is interrupted
+func (g *Group) AddContext(fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return fn(ctx)
+	}, func(error) {
+		cancel()
+	})
+}
+
+// AddHTTP serves srv on its Addr. When interrupted srv is given timeout to finish in-flight
+// requests with Shutdown. http.ErrServerClosed isn't returned as an error, and srv must only
+// be shut down by the group.
+func (g *Group) AddHTTP(srv *http.Server, timeout time.Duration) {
+	shutdown := make(chan error, 1)
+	g.Add(func() error {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		// ListenAndServe returns as soon as Shutdown starts, so wait for requests to finish
+		return <-shutdown
+	}, func(error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		shutdown <- srv.Shutdown(ctx)
+	})
+}
+
+// AddSignals returns a *SignalError once one of sigs is received. Signals are caught from
+// when AddSignals is called, not when the group starts running.
+func (g *Group) AddSignals(sigs ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+
+	done := make(chan struct{})
+	g.Add(func() error {
+		defer signal.Stop(received)
+
+		select {
+		case sig := <-received:
+			return &SignalError{Signal: sig}
+		case <-done:
+			return nil
+		}
+	}, func(error) {
+		close(done)
+	})
+}
+
+// Run starts every actor and blocks until they've all returned. The first actor to return
+// interrupts the others and its error, which may be nil, is returned.
+func (g *Group) Run() error {
+	if len(g.actors) == 0 {
+		return nil
+	}
+
+	errs := make(chan error, len(g.actors))
+	for _, a := range g.actors {
+		go func(a actor) {
+			errs <- a.execute()
+		}(a)
+	}
+
+	err := <-errs
+	for _, a := range g.actors {
+		a.interrupt(err)
+	}
+	for i := 1; i < len(g.actors); i++ {
+		<-errs
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var g Group
+	require.NoError(t, g.Run())
+
+	failed := errors.New("consumer failed")
+	var interrupted int64
+
+	g.Add(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return failed
+	}, func(err error) {
+		atomic.AddInt64(&interrupted, 1)
+	})
+
+	block := make(chan struct{})
+	g.Add(func() error {
+		<-block
+		return errors.New("interrupted")
+	}, func(err error) {
+		require.ErrorIs(t, err, failed)
+		atomic.AddInt64(&interrupted, 1)
+		close(block)
+	})
+
+	var cancelled int64
+	g.AddContext(func(ctx context.Context) error {
+		<-ctx.Done()
+		atomic.AddInt64(&cancelled, 1)
+		return ctx.Err()
+	})
+
+	require.ErrorIs(t, g.Run(), failed)
+	require.Equal(t, int64(2), atomic.LoadInt64(&interrupted))
+	require.Equal(t, int64(1), atomic.LoadInt64(&cancelled))
+}
+
+func TestRun__HTTP(t *testing.T) {
+	var g Group
+	g.AddHTTP(&http.Server{Addr: "127.0.0.1:0"}, time.Second)
+	g.Add(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}, func(error) {})
+
+	require.NoError(t, g.Run())
+
+	// listen errors are returned
+	g = Group{}
+	g.AddHTTP(&http.Server{Addr: "127.0.0.1:-1"}, time.Second)
+	g.AddContext(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	require.ErrorContains(t, g.Run(), "invalid port")
+}
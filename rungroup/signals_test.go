@@ -0,0 +1,33 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun__Signals(t *testing.T) {
+	var g Group
+	g.AddSignals(syscall.SIGUSR1)
+	g.AddContext(func(ctx context.Context) error {
+		syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+		<-ctx.Done()
+		return nil
+	})
+
+	err := g.Run()
+	var serr *SignalError
+	require.True(t, errors.As(err, &serr))
+	require.Equal(t, syscall.SIGUSR1, serr.Signal)
+	require.EqualError(t, err, "received signal user defined signal 1")
+}
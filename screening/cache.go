@@ -0,0 +1,111 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package screening
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultCacheTTL = time.Hour
+
+// CacheConfig configures Cached
+type CacheConfig struct {
+	// TTL is how long a Decision is reused, one hour by default. Lists are updated by their
+	// publishers without notice, so keep it short enough for new entries to be picked up.
+	TTL time.Duration
+
+	// DeniedTTL is how long denied decisions are reused, TTL when zero
+	DeniedTTL time.Duration
+
+	// MaxEntries bounds the cache, expired entries are dropped once it's reached. Zero is unbounded.
+	MaxEntries int
+}
+
+// Cache is a Provider reusing the decisions of another. Errors aren't cached.
+type Cache struct {
+	provider Provider
+	cfg      CacheConfig
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	decision Decision
+	expires  time.Time
+}
+
+// Cached returns a Cache of provider's decisions
+func Cached(provider Provider, cfg CacheConfig) *Cache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultCacheTTL
+	}
+	if cfg.DeniedTTL <= 0 {
+		cfg.DeniedTTL = cfg.TTL
+	}
+	return &Cache{
+		provider: provider,
+		cfg:      cfg,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+	}
+}
+
+func (c *Cache) Screen(ctx context.Context, subject Subject) (Decision, error) {
+	key := subject.key()
+	now := c.now()
+
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+
+	if found && now.Before(entry.expires) {
+		d := entry.decision
+		d.Subject = subject
+		return d, nil
+	}
+
+	d, err := c.provider.Screen(ctx, subject)
+	if err != nil {
+		return d, err
+	}
+
+	ttl := c.cfg.TTL
+	if d.Denied {
+		ttl = c.cfg.DeniedTTL
+	}
+
+	c.mu.Lock()
+	if c.cfg.MaxEntries > 0 && len(c.entries) >= c.cfg.MaxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cacheEntry{decision: d, expires: now.Add(ttl)}
+	c.mu.Unlock()
+
+	return d, nil
+}
+
+// Forget removes the cached Decision of subject, such as after a reviewer clears a false positive
+func (c *Cache) Forget(subject Subject) {
+	c.mu.Lock()
+	delete(c.entries, subject.key())
+	c.mu.Unlock()
+}
+
+// evict drops expired entries, or every entry when none have expired, and must be called
+// holding c.mu.
+func (c *Cache) evict(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) >= c.cfg.MaxEntries {
+		c.entries = make(map[string]cacheEntry)
+	}
+}
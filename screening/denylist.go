@@ -0,0 +1,77 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package screening
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DenyList is a static Provider denying subjects with a listed name, country or currency.
+// Names are compared after lowercasing and removing punctuation.
+type DenyList struct {
+	name       string
+	names      map[string]string
+	countries  map[string]bool
+	currencies map[string]bool
+	now        func() time.Time
+}
+
+// DenyListConfig lists what a DenyList denies
+type DenyListConfig struct {
+	// Name identifies the list in matches, "deny-list" when empty
+	Name string
+
+	Names []string
+
+	// Countries are ISO 3166 codes, such as "KP"
+	Countries []string
+
+	// Currencies are ISO 4217 codes
+	Currencies []string
+}
+
+// NewDenyList returns a DenyList of cfg
+func NewDenyList(cfg DenyListConfig) *DenyList {
+	if cfg.Name == "" {
+		cfg.Name = "deny-list"
+	}
+	list := &DenyList{
+		name:       cfg.Name,
+		names:      make(map[string]string),
+		countries:  make(map[string]bool),
+		currencies: make(map[string]bool),
+		now:        time.Now,
+	}
+	for _, n := range cfg.Names {
+		list.names[normalizeName(n)] = n
+	}
+	for _, c := range cfg.Countries {
+		list.countries[strings.ToUpper(strings.TrimSpace(c))] = true
+	}
+	for _, c := range cfg.Currencies {
+		list.currencies[strings.ToUpper(strings.TrimSpace(c))] = true
+	}
+	return list
+}
+
+func (l *DenyList) Screen(ctx context.Context, subject Subject) (Decision, error) {
+	d := Decision{
+		Subject:    subject,
+		ScreenedAt: l.now().UTC(),
+	}
+	if name, ok := l.names[normalizeName(subject.Name)]; ok && subject.Name != "" {
+		d.Matches = append(d.Matches, Match{List: l.name, Field: "name", Value: name})
+	}
+	if country := strings.ToUpper(strings.TrimSpace(subject.Country)); l.countries[country] {
+		d.Matches = append(d.Matches, Match{List: l.name, Field: "country", Value: country})
+	}
+	if currency := strings.ToUpper(strings.TrimSpace(subject.Currency)); l.currencies[currency] {
+		d.Matches = append(d.Matches, Match{List: l.name, Field: "currency", Value: currency})
+	}
+	d.Denied = len(d.Matches) > 0
+	return d, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package screening checks the parties, countries and currencies of a transfer against sanction
+// and deny lists. Providers can be an external screening service or a static DenyList, and
+// Cached wraps either to avoid screening the same party on every transfer.
+//
+//	screener := screening.Cached(provider, screening.CacheConfig{TTL: time.Hour})
+//
+//	err := screening.Check(ctx, screener, xfer.Amount,
+//		screening.Subject{Name: xfer.Source.Name, Country: xfer.Source.Country},
+//		screening.Subject{Name: xfer.Destination.Name, Country: xfer.Destination.Country},
+//	)
+//	var denied *screening.DeniedError
+//	if errors.As(err, &denied) {
+//		// reject the transfer and record denied.Decision
+//	}
+package screening
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// Subject is what a Provider screens. Empty fields aren't screened, so a Subject with only a
+// Currency checks the currency.
+type Subject struct {
+	Name     string `json:"name,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// key identifies a Subject for caching
+func (s Subject) key() string {
+	return normalizeName(s.Name) + "\x00" + strings.ToUpper(strings.TrimSpace(s.Country)) + "\x00" + strings.ToUpper(strings.TrimSpace(s.Currency))
+}
+
+// Match is one entry of a list a Subject matched
+type Match struct {
+	List  string `json:"list"`
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// Decision is the outcome of screening a Subject
+type Decision struct {
+	Subject    Subject   `json:"subject"`
+	Denied     bool      `json:"denied"`
+	Matches    []Match   `json:"matches,omitempty"`
+	ScreenedAt time.Time `json:"screenedAt"`
+}
+
+// Provider screens subjects. Implementations must be safe for concurrent use.
+type Provider interface {
+	Screen(ctx context.Context, subject Subject) (Decision, error)
+}
+
+// DeniedError is returned by Check when a Subject is denied
+type DeniedError struct {
+	Decision Decision
+}
+
+func (e *DeniedError) Error() string {
+	var matches []string
+	for _, m := range e.Decision.Matches {
+		matches = append(matches, fmt.Sprintf("%s %s %q", m.List, m.Field, m.Value))
+	}
+	return "screening: denied by " + strings.Join(matches, ", ")
+}
+
+// Check screens the currency of amount and each of parties with provider. The first denied
+// Subject is returned as a *DeniedError.
+func Check(ctx context.Context, provider Provider, amount base.Amount, parties ...Subject) error {
+	subjects := make([]Subject, 0, len(parties)+1)
+	if amount.Currency != "" {
+		subjects = append(subjects, Subject{Currency: amount.Currency})
+	}
+	subjects = append(subjects, parties...)
+
+	for _, s := range subjects {
+		d, err := provider.Screen(ctx, s)
+		if err != nil {
+			return fmt.Errorf("screening: %w", err)
+		}
+		if d.Denied {
+			return &DeniedError{Decision: d}
+		}
+	}
+	return nil
+}
+
+// normalizeName lowercases name and removes punctuation and repeated spaces, so "ACME, Inc."
+// and "acme inc" are the same.
+func normalizeName(name string) string {
+	var buf strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == ' ' || r == '\t' || r == '-':
+			space = buf.Len() > 0
+		case strings.ContainsRune(".,'\"()&/", r):
+		default:
+			if space {
+				buf.WriteByte(' ')
+				space = false
+			}
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
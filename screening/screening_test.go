@@ -0,0 +1,126 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package screening
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+var denyList = NewDenyList(DenyListConfig{
+	Name:       "internal",
+	Names:      []string{"Bad Actor, LLC"},
+	Countries:  []string{"kp"},
+	Currencies: []string{"IRR"},
+})
+
+func TestDenyList(t *testing.T) {
+	ctx := context.Background()
+
+	d, err := denyList.Screen(ctx, Subject{Name: "bad  actor llc", Country: "US"})
+	require.NoError(t, err)
+	require.True(t, d.Denied)
+	require.Equal(t, []Match{{List: "internal", Field: "name", Value: "Bad Actor, LLC"}}, d.Matches)
+
+	d, err = denyList.Screen(ctx, Subject{Name: "Good Actor", Country: "KP"})
+	require.NoError(t, err)
+	require.True(t, d.Denied)
+	require.Equal(t, "country", d.Matches[0].Field)
+
+	d, err = denyList.Screen(ctx, Subject{Name: "Good Actor", Country: "US", Currency: "usd"})
+	require.NoError(t, err)
+	require.False(t, d.Denied)
+
+	d, err = denyList.Screen(ctx, Subject{})
+	require.NoError(t, err)
+	require.False(t, d.Denied)
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, Check(ctx, denyList, base.NewAmount(100, "USD"), Subject{Name: "Jane Doe", Country: "US"}))
+
+	err := Check(ctx, denyList, base.NewAmount(100, "IRR"), Subject{Name: "Jane Doe"})
+	var denied *DeniedError
+	require.True(t, errors.As(err, &denied))
+	require.Equal(t, "IRR", denied.Decision.Subject.Currency)
+	require.Equal(t, `screening: denied by internal currency "IRR"`, err.Error())
+
+	err = Check(ctx, denyList, base.NewAmount(100, "USD"), Subject{Name: "Jane Doe"}, Subject{Name: "BAD ACTOR LLC"})
+	require.True(t, errors.As(err, &denied))
+	require.Equal(t, "BAD ACTOR LLC", denied.Decision.Subject.Name)
+}
+
+type countingProvider struct {
+	calls int64
+	err   error
+}
+
+func (p *countingProvider) Screen(ctx context.Context, subject Subject) (Decision, error) {
+	atomic.AddInt64(&p.calls, 1)
+	if p.err != nil {
+		return Decision{}, p.err
+	}
+	return denyList.Screen(ctx, subject)
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	provider := &countingProvider{}
+	cache := Cached(provider, CacheConfig{TTL: time.Hour, DeniedTTL: 2 * time.Hour, MaxEntries: 2})
+
+	now := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Screen(ctx, Subject{Name: "Jane Doe"})
+	require.NoError(t, err)
+	d, err := cache.Screen(ctx, Subject{Name: "jane doe"})
+	require.NoError(t, err)
+	require.False(t, d.Denied)
+	require.Equal(t, "jane doe", d.Subject.Name)
+	require.Equal(t, int64(1), provider.calls)
+
+	d, err = cache.Screen(ctx, Subject{Name: "Bad Actor LLC"})
+	require.NoError(t, err)
+	require.True(t, d.Denied)
+	require.Equal(t, int64(2), provider.calls)
+
+	// clear decisions expire first
+	now = now.Add(90 * time.Minute)
+	_, err = cache.Screen(ctx, Subject{Name: "Bad Actor LLC"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), provider.calls)
+	_, err = cache.Screen(ctx, Subject{Name: "Jane Doe"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), provider.calls)
+
+	cache.Forget(Subject{Name: "Jane Doe"})
+	_, err = cache.Screen(ctx, Subject{Name: "Jane Doe"})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), provider.calls)
+	require.Len(t, cache.entries, 2)
+
+	// errors aren't cached
+	provider.err = errors.New("timeout")
+	_, err = cache.Screen(ctx, Subject{Name: "John Doe"})
+	require.Error(t, err)
+	_, err = cache.Screen(ctx, Subject{Name: "John Doe"})
+	require.Error(t, err)
+	require.Equal(t, int64(6), provider.calls)
+}
+
+func TestNormalizeName(t *testing.T) {
+	require.Equal(t, "acme inc", normalizeName("  ACME, Inc. "))
+	require.Equal(t, "o brien", normalizeName("O-Brien"))
+	require.Equal(t, "obrien", normalizeName("O'Brien"))
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sec
+
+var classes = index([]Class{
+	{
+		Code: "ACK", Name: "ACH Payment Acknowledgment", Description: "Zero dollar acknowledgment of a CCD credit sent by the RDFI.",
+		Corporate: true, Credits: true, Single: true, NonMonetary: true, Authorization: None, SameDay: true,
+	},
+	{
+		Code: "ADV", Name: "Automated Accounting Advice", Description: "Advice of ACH activity sent by an ACH operator to a DFI.",
+		Corporate: true, Debits: true, Credits: true, Single: true, Authorization: None,
+	},
+	{
+		Code: "ARC", Name: "Accounts Receivable Entry", Description: "Check mailed or dropped off by the Receiver and converted to a debit.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: Notice, SameDay: true,
+	},
+	{
+		Code: "ATX", Name: "Financial EDI Acknowledgment", Description: "Zero dollar acknowledgment of a CTX credit sent by the RDFI.",
+		Corporate: true, Credits: true, Single: true, NonMonetary: true, Authorization: None, SameDay: true,
+	},
+	{
+		Code: "BOC", Name: "Back Office Conversion", Description: "Check presented in person and converted to a debit in the back office.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: Notice, SameDay: true,
+	},
+	{
+		Code: "CCD", Name: "Corporate Credit or Debit", Description: "Payment between businesses, such as to a vendor or to fund payroll.",
+		Corporate: true, Debits: true, Credits: true, Single: true, Recurring: true, Authorization: Agreement, SameDay: true,
+	},
+	{
+		Code: "CIE", Name: "Customer Initiated Entry", Description: "Credit initiated by a consumer to a business, such as through online bill payment.",
+		Corporate: true, Credits: true, Single: true, Recurring: true, Authorization: Written, SameDay: true,
+	},
+	{
+		Code: "COR", Name: "Notification of Change", Description: "Zero dollar entry sent by the RDFI to correct information of an earlier entry.",
+		Consumer: true, Corporate: true, Debits: true, Credits: true, Single: true, NonMonetary: true, Authorization: None, SameDay: true,
+	},
+	{
+		Code: "CTX", Name: "Corporate Trade Exchange", Description: "Payment between businesses carrying remittance information in EDI addenda.",
+		Corporate: true, Debits: true, Credits: true, Single: true, Recurring: true, Authorization: Agreement, SameDay: true,
+	},
+	{
+		Code: "DNE", Name: "Death Notification Entry", Description: "Zero dollar entry sent by a federal agency to report a beneficiary's death.",
+		Consumer: true, Credits: true, Single: true, NonMonetary: true, Authorization: None,
+	},
+	{
+		Code: "ENR", Name: "Automated Enrollment Entry", Description: "Zero dollar entry enrolling a Receiver in a federal agency's payments.",
+		Consumer: true, Corporate: true, Credits: true, Single: true, NonMonetary: true, Authorization: None,
+	},
+	{
+		Code: "IAT", Name: "International ACH Transaction", Description: "Entry funded from or sent to a financial institution outside the US.",
+		Consumer: true, Corporate: true, Debits: true, Credits: true, Single: true, Recurring: true, Authorization: Written,
+	},
+	{
+		Code: "MTE", Name: "Machine Transfer Entry", Description: "Transfer made from an ATM.",
+		Consumer: true, Debits: true, Credits: true, Single: true, Authorization: Written, SameDay: true,
+	},
+	{
+		Code: "POP", Name: "Point-of-Purchase Entry", Description: "Check presented at a point of purchase and converted to a debit.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: Notice, SameDay: true,
+	},
+	{
+		Code: "POS", Name: "Point-of-Sale Entry", Description: "Purchase made at a terminal with a debit card.",
+		Consumer: true, Debits: true, Credits: true, Single: true, Authorization: Written, SameDay: true,
+	},
+	{
+		Code: "PPD", Name: "Prearranged Payment and Deposit", Description: "Payment to or from a consumer account, such as payroll or a bill payment.",
+		Consumer: true, Debits: true, Credits: true, Single: true, Recurring: true, Authorization: Written, SameDay: true,
+	},
+	{
+		Code: "RCK", Name: "Re-presented Check Entry", Description: "Check returned for insufficient or uncollected funds and presented again as a debit.",
+		Consumer: true, Debits: true, Single: true, Authorization: Notice, SameDay: true,
+	},
+	{
+		Code: "SHR", Name: "Shared Network Transaction", Description: "Purchase made at a terminal of a shared network with a debit card.",
+		Consumer: true, Debits: true, Credits: true, Single: true, Authorization: Written, SameDay: true,
+	},
+	{
+		Code: "TEL", Name: "Telephone-Initiated Entry", Description: "Debit authorized by a consumer over the phone.",
+		Consumer: true, Debits: true, Single: true, Recurring: true, Authorization: Oral, SameDay: true,
+	},
+	{
+		Code: "TRC", Name: "Truncated Entry", Description: "Check truncated by the collecting institution and presented as an entry.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: None,
+	},
+	{
+		Code: "TRX", Name: "Truncated Entries Exchange", Description: "Batch of truncated checks presented as one entry.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: None,
+	},
+	{
+		Code: "WEB", Name: "Internet-Initiated/Mobile Entry", Description: "Debit authorized online or through a mobile device, or a person-to-person credit.",
+		Consumer: true, Debits: true, Credits: true, Single: true, Recurring: true, Authorization: Online, SameDay: true,
+	},
+	{
+		Code: "XCK", Name: "Destroyed Check Entry", Description: "Check lost or destroyed during collection and presented as a debit.",
+		Consumer: true, Corporate: true, Debits: true, Single: true, Authorization: None,
+	},
+})
+
+func index(classes []Class) map[string]Class {
+	out := make(map[string]Class, len(classes))
+	for _, c := range classes {
+		out[c.Code] = c
+	}
+	return out
+}
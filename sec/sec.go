@@ -0,0 +1,144 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package sec describes Nacha Standard Entry Class codes, such as who can receive an entry,
+// how it's authorized and whether it can settle the same day. Origination services validate
+// requests against a Class and UIs use it for hints.
+//
+//	class, ok := sec.Lookup(req.SECCode)
+//	if !ok {
+//		return fmt.Errorf("unknown SEC code %q", req.SECCode)
+//	}
+//	err := class.Validate(sec.Entry{
+//		Debit:     req.Debit,
+//		Recurring: req.Recurring,
+//		Consumer:  req.Receiver.Consumer,
+//		SameDay:   req.SameDay,
+//		Amount:    req.Amount,
+//	})
+package sec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moov-io/base"
+)
+
+// SameDayLimit is the largest entry, in cents, which can settle the same day
+const SameDayLimit int64 = 1_000_000_00
+
+// Authorization is how the Receiver authorizes an entry
+type Authorization string
+
+const (
+	// Written authorizations are signed or similarly authenticated by the Receiver (PPD, CIE)
+	Written Authorization = "written"
+
+	// Oral authorizations are recorded or confirmed in writing after a phone call (TEL)
+	Oral Authorization = "oral"
+
+	// Online authorizations are given over the internet or a wireless network (WEB)
+	Online Authorization = "online"
+
+	// Notice authorizations are given by the Receiver presenting a check after notice the
+	// check will be converted (ARC, BOC, POP)
+	Notice Authorization = "notice"
+
+	// Agreement authorizations are part of an agreement between businesses (CCD, CTX)
+	Agreement Authorization = "agreement"
+
+	// None is for entries which aren't authorized by their Receiver, such as notifications
+	// of change or entries sent by an ACH operator.
+	None Authorization = "none"
+)
+
+// Class describes a Standard Entry Class code
+type Class struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Consumer and Corporate report which kind of accounts can receive the entry
+	Consumer  bool `json:"consumer"`
+	Corporate bool `json:"corporate"`
+
+	Debits  bool `json:"debits"`
+	Credits bool `json:"credits"`
+
+	// Single and Recurring report whether one-time and recurring entries are allowed
+	Single    bool `json:"single"`
+	Recurring bool `json:"recurring"`
+
+	// NonMonetary entries, such as acknowledgments and notifications of change, carry a zero amount
+	NonMonetary bool `json:"nonMonetary,omitempty"`
+
+	Authorization Authorization `json:"authorization"`
+
+	// SameDay is true when entries up to SameDayLimit can settle the same day
+	SameDay bool `json:"sameDay"`
+}
+
+// Entry is what Validate checks against a Class
+type Entry struct {
+	Debit     bool
+	Recurring bool
+	Consumer  bool
+	SameDay   bool
+	Amount    base.Amount
+}
+
+// Validate returns an error when entry isn't allowed for the Class, such as a credit with ARC
+func (c Class) Validate(entry Entry) error {
+	var list base.ErrorList
+	if entry.Debit && !c.Debits {
+		list.Add(fmt.Errorf("%s entries can't be debits", c.Code))
+	}
+	if !entry.Debit && !c.Credits {
+		list.Add(fmt.Errorf("%s entries can't be credits", c.Code))
+	}
+	if entry.Recurring && !c.Recurring {
+		list.Add(fmt.Errorf("%s entries can't be recurring", c.Code))
+	}
+	if !entry.Recurring && !c.Single {
+		list.Add(fmt.Errorf("%s entries must be recurring", c.Code))
+	}
+	if entry.Consumer && !c.Consumer {
+		list.Add(fmt.Errorf("%s entries can't be sent to consumer accounts", c.Code))
+	}
+	if !entry.Consumer && !c.Corporate {
+		list.Add(fmt.Errorf("%s entries can't be sent to corporate accounts", c.Code))
+	}
+	if c.NonMonetary && entry.Amount.Value != 0 {
+		list.Add(fmt.Errorf("%s entries must have a zero amount", c.Code))
+	}
+	if !c.NonMonetary && entry.Amount.Value <= 0 {
+		list.Add(fmt.Errorf("%s entries must have a positive amount", c.Code))
+	}
+	if entry.SameDay {
+		if !c.SameDay {
+			list.Add(fmt.Errorf("%s entries can't settle the same day", c.Code))
+		} else if entry.Amount.Value > SameDayLimit {
+			list.Add(fmt.Errorf("same day entries can't be over %v", base.NewAmount(SameDayLimit, "USD")))
+		}
+	}
+	return list.Err()
+}
+
+// Lookup returns the Class of code, such as "WEB"
+func Lookup(code string) (Class, bool) {
+	c, ok := classes[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// All returns every Class ordered by code
+func All() []Class {
+	out := make([]Class, 0, len(classes))
+	for _, c := range classes {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sec
+
+import (
+	"testing"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	web, ok := Lookup(" web")
+	require.True(t, ok)
+	require.Equal(t, "WEB", web.Code)
+	require.True(t, web.Consumer)
+	require.False(t, web.Corporate)
+	require.Equal(t, Online, web.Authorization)
+	require.True(t, web.SameDay)
+
+	iat, ok := Lookup("IAT")
+	require.True(t, ok)
+	require.False(t, iat.SameDay)
+
+	_, ok = Lookup("ABC")
+	require.False(t, ok)
+
+	all := All()
+	require.Len(t, all, len(classes))
+	require.Equal(t, "ACK", all[0].Code)
+	for _, c := range all {
+		require.NotEmpty(t, c.Name, c.Code)
+		require.NotEmpty(t, c.Authorization, c.Code)
+		require.True(t, c.Consumer || c.Corporate, c.Code)
+		require.True(t, c.Debits || c.Credits, c.Code)
+	}
+}
+
+func TestClass__Validate(t *testing.T) {
+	ppd, _ := Lookup("PPD")
+	require.NoError(t, ppd.Validate(Entry{Debit: true, Recurring: true, Consumer: true, SameDay: true, Amount: base.NewAmount(1000, "USD")}))
+	require.EqualError(t, ppd.Validate(Entry{Consumer: false, Amount: base.NewAmount(1000, "USD")}), "PPD entries can't be sent to corporate accounts")
+	require.EqualError(t, ppd.Validate(Entry{Consumer: true, SameDay: true, Amount: base.NewAmount(SameDayLimit+1, "USD")}), "same day entries can't be over USD 1000000.00")
+
+	arc, _ := Lookup("ARC")
+	require.EqualError(t, arc.Validate(Entry{Consumer: true, Amount: base.NewAmount(1000, "USD")}), "ARC entries can't be credits")
+	require.EqualError(t, arc.Validate(Entry{Debit: true, Recurring: true, Amount: base.NewAmount(1000, "USD")}), "ARC entries can't be recurring")
+
+	iat, _ := Lookup("IAT")
+	require.EqualError(t, iat.Validate(Entry{SameDay: true, Amount: base.NewAmount(1000, "USD")}), "IAT entries can't settle the same day")
+
+	cor, _ := Lookup("COR")
+	require.NoError(t, cor.Validate(Entry{Consumer: true}))
+	require.EqualError(t, cor.Validate(Entry{Consumer: true, Amount: base.NewAmount(1, "USD")}), "COR entries must have a zero amount")
+	require.EqualError(t, ppd.Validate(Entry{Consumer: true}), "PPD entries must have a positive amount")
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sequence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fileIDModifiers are the File ID Modifiers of a day's ACH files in the order they're used
+const fileIDModifiers = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// maxBatchNumber is the largest seven digit ACH batch number
+const maxBatchNumber = 9999999
+
+// ACHAllocator issues the File ID Modifiers and batch numbers of ACH files, which restart
+// each day for every ODFI routing number. Both are gapless so files aren't skipped.
+type ACHAllocator struct {
+	files   *Generator
+	batches *Generator
+}
+
+// NewACHAllocator returns an ACHAllocator storing its sequences in table of db
+func NewACHAllocator(db *sql.DB, table string) *ACHAllocator {
+	return &ACHAllocator{
+		files:   New(db, Config{Table: table, Max: int64(len(fileIDModifiers))}),
+		batches: New(db, Config{Table: table, Max: maxBatchNumber}),
+	}
+}
+
+// FileIDModifier returns the next File ID Modifier sent by odfi on the date of day, starting
+// at "A" and continuing after "Z" with "0" through "9". ErrExhausted is returned after 36 files.
+func (a *ACHAllocator) FileIDModifier(ctx context.Context, odfi string, day time.Time) (string, error) {
+	key, err := achKey("file-id-modifier", odfi, day)
+	if err != nil {
+		return "", err
+	}
+	n, err := a.files.Next(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("file ID modifier for %s: %w", odfi, err)
+	}
+	return fileIDModifiers[n-1 : n], nil
+}
+
+// FileIDModifierTx is like FileIDModifier but reserved in tx, see Generator.NextTx.
+func (a *ACHAllocator) FileIDModifierTx(ctx context.Context, tx *sql.Tx, odfi string, day time.Time) (string, error) {
+	key, err := achKey("file-id-modifier", odfi, day)
+	if err != nil {
+		return "", err
+	}
+	n, err := a.files.NextTx(ctx, tx, key)
+	if err != nil {
+		return "", fmt.Errorf("file ID modifier for %s: %w", odfi, err)
+	}
+	return fileIDModifiers[n-1 : n], nil
+}
+
+// BatchNumber returns the next batch number sent by odfi on the date of day, starting at 1.
+// ErrExhausted is returned after 9999999 batches.
+func (a *ACHAllocator) BatchNumber(ctx context.Context, odfi string, day time.Time) (int64, error) {
+	key, err := achKey("batch-number", odfi, day)
+	if err != nil {
+		return 0, err
+	}
+	n, err := a.batches.Next(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("batch number for %s: %w", odfi, err)
+	}
+	return n, nil
+}
+
+func achKey(name, odfi string, day time.Time) (string, error) {
+	if len(odfi) != 9 || strings.Trim(odfi, "0123456789") != "" {
+		return "", fmt.Errorf("sequence: invalid ODFI routing number %q", odfi)
+	}
+	return Daily(name+":"+odfi, day), nil
+}
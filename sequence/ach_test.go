@@ -0,0 +1,65 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sequence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestACHAllocator__FileIDModifier(t *testing.T) {
+	ctx := context.Background()
+	alloc := NewACHAllocator(testDB(t), "sequences")
+	day := time.Date(2021, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	var modifiers string
+	for i := 0; i < 36; i++ {
+		mod, err := alloc.FileIDModifier(ctx, "231380104", day)
+		require.NoError(t, err)
+		modifiers += mod
+	}
+	require.Equal(t, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", modifiers)
+
+	_, err := alloc.FileIDModifier(ctx, "231380104", day)
+	require.ErrorIs(t, err, ErrExhausted)
+
+	// other ODFIs and days have their own modifiers
+	mod, err := alloc.FileIDModifier(ctx, "121042882", day)
+	require.NoError(t, err)
+	require.Equal(t, "A", mod)
+
+	tx, err := alloc.files.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	mod, err = alloc.FileIDModifierTx(ctx, tx, "231380104", day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, "A", mod)
+	require.NoError(t, tx.Commit())
+
+	_, err = alloc.FileIDModifier(ctx, "23138010", day)
+	require.Error(t, err)
+}
+
+func TestACHAllocator__BatchNumber(t *testing.T) {
+	ctx := context.Background()
+	alloc := NewACHAllocator(testDB(t), "sequences")
+	day := time.Date(2021, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := alloc.BatchNumber(ctx, "231380104", day)
+		require.NoError(t, err)
+		require.Equal(t, i, n)
+	}
+
+	// batch numbers are separate from file ID modifiers
+	mod, err := alloc.FileIDModifier(ctx, "231380104", day)
+	require.NoError(t, err)
+	require.Equal(t, "A", mod)
+
+	_, err = alloc.BatchNumber(ctx, "abc", day)
+	require.Error(t, err)
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package sequence issues increasing numbers per key from a database table, such as the File ID
+// Modifier of each ACH file sent in a day.
+//
+// With a BlockSize of one every number is reserved in the database and, using NextTx inside the
+// transaction which records the number, sequences are gapless. Larger blocks reserve several
+// numbers at once and hand them out from memory, which is faster but loses the unused numbers
+// of a block when the process stops, so sequences are only increasing.
+//
+//	seq := sequence.New(db, sequence.Config{Table: "sequences", Max: 36})
+//	n, err := seq.Next(ctx, sequence.Daily("ach-file-id-modifier", time.Now()))
+//
+// The table is created by the service's migrations:
+//
+//	CREATE TABLE sequences (
+//	    name VARCHAR(128) NOT NULL PRIMARY KEY,
+//	    value BIGINT NOT NULL
+//	);
+package sequence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base/database"
+)
+
+// ErrExhausted is returned once a sequence would pass Config.Max
+var ErrExhausted = errors.New("sequence exhausted")
+
+// Config describes how numbers are reserved
+type Config struct {
+	// Table holds the last reserved number of each sequence. It defaults to "sequences".
+	Table string
+
+	// BlockSize is how many numbers are reserved at once. It defaults to one, which is gapless.
+	BlockSize int64
+
+	// Max is the largest number issued, zero means there's no limit.
+	Max int64
+}
+
+// Generator issues numbers starting from 1 for each key
+type Generator struct {
+	db  *sql.DB
+	cfg Config
+
+	mu     sync.Mutex
+	blocks map[string]*block
+}
+
+// block is a reserved range of numbers, next through last
+type block struct {
+	next, last int64
+}
+
+// New returns a Generator storing sequences in db
+func New(db *sql.DB, cfg Config) *Generator {
+	if cfg.Table == "" {
+		cfg.Table = "sequences"
+	}
+	if cfg.BlockSize <= 0 {
+		cfg.BlockSize = 1
+	}
+	return &Generator{
+		db:     db,
+		cfg:    cfg,
+		blocks: make(map[string]*block),
+	}
+}
+
+// Daily returns a key for name which resets each day, using the date of t in its location
+func Daily(name string, t time.Time) string {
+	return name + ":" + t.Format("2006-01-02")
+}
+
+// Next returns the next number of key
+func (g *Generator) Next(ctx context.Context, key string) (int64, error) {
+	if g.cfg.BlockSize == 1 {
+		return g.reserveOnce(ctx, key, 1)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := g.blocks[key]
+	if b == nil || b.next > b.last {
+		size := g.cfg.BlockSize
+		last, err := g.reserveOnce(ctx, key, size)
+		if errors.Is(err, ErrExhausted) {
+			// fewer than a block of numbers are left
+			size = 1
+			last, err = g.reserveOnce(ctx, key, size)
+		}
+		if err != nil {
+			return 0, err
+		}
+		b = &block{next: last - size + 1, last: last}
+		g.blocks[key] = b
+	}
+	n := b.next
+	b.next++
+	return n, nil
+}
+
+// NextTx returns the next number of key reserved in tx, so it's released if tx is rolled back.
+// The row of key stays locked until tx ends. Block caching isn't used.
+func (g *Generator) NextTx(ctx context.Context, tx *sql.Tx, key string) (int64, error) {
+	return g.reserve(ctx, tx, key, 1)
+}
+
+func (g *Generator) reserveOnce(ctx context.Context, key string, n int64) (int64, error) {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("sequence: %w", err)
+	}
+	defer tx.Rollback()
+
+	last, err := g.reserve(ctx, tx, key, n)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("sequence: %w", err)
+	}
+	return last, nil
+}
+
+// reserve adds n to the sequence of key and returns its new value
+func (g *Generator) reserve(ctx context.Context, tx *sql.Tx, key string, n int64) (int64, error) {
+	update := fmt.Sprintf(`UPDATE %s SET value = value + ? WHERE name = ?`, g.cfg.Table)
+	updated, err := execAffected(ctx, tx, update, n, key)
+	if err != nil {
+		return 0, fmt.Errorf("sequence: updating %s: %w", key, err)
+	}
+	if updated == 0 {
+		query := fmt.Sprintf(`INSERT INTO %s (name, value) VALUES (?, ?)`, g.cfg.Table)
+		if _, err := tx.ExecContext(ctx, query, key, n); err != nil {
+			if !database.UniqueViolation(err) {
+				return 0, fmt.Errorf("sequence: creating %s: %w", key, err)
+			}
+			// another reservation created the sequence first, so update its row instead
+			if updated, err = execAffected(ctx, tx, update, n, key); err != nil {
+				return 0, fmt.Errorf("sequence: updating %s: %w", key, err)
+			} else if updated == 0 {
+				return 0, fmt.Errorf("sequence: %s was created concurrently but not found", key)
+			}
+		}
+	}
+
+	var value int64
+	query := fmt.Sprintf(`SELECT value FROM %s WHERE name = ?`, g.cfg.Table)
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&value); err != nil {
+		return 0, fmt.Errorf("sequence: reading %s: %w", key, err)
+	}
+	if g.cfg.Max > 0 && value > g.cfg.Max {
+		return 0, ErrExhausted
+	}
+	return value, nil
+}
+
+// execAffected runs query and returns how many rows it changed
+func execAffected(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sequence
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE sequences (name VARCHAR(128) NOT NULL PRIMARY KEY, value BIGINT NOT NULL)`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestGenerator__Gapless(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	seq := New(db, Config{Max: 3})
+
+	day := time.Date(2021, time.March, 4, 23, 0, 0, 0, time.UTC)
+	key := Daily("ach-file-id-modifier", day)
+	require.Equal(t, "ach-file-id-modifier:2021-03-04", key)
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := seq.Next(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, i, n)
+	}
+	_, err := seq.Next(ctx, key)
+	require.ErrorIs(t, err, ErrExhausted)
+
+	// the next day starts over
+	n, err := seq.Next(ctx, Daily("ach-file-id-modifier", day.Add(time.Hour)))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+func TestGenerator__NextTx(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	seq := New(db, Config{})
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	n, err := seq.NextTx(ctx, tx, "files")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, tx.Rollback())
+
+	// the rolled back number is issued again
+	tx, err = db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	n, err = seq.NextTx(ctx, tx, "files")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, tx.Commit())
+
+	n, err = seq.Next(ctx, "files")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+}
+
+func TestGenerator__Blocks(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	seq := New(db, Config{BlockSize: 10, Max: 25})
+
+	seen := make(map[int64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := seq.Next(ctx, "batches")
+			require.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.False(t, seen[n], "duplicate %d", n)
+			seen[n] = true
+		}()
+	}
+	wg.Wait()
+	require.Len(t, seen, 25)
+
+	_, err := seq.Next(ctx, "batches")
+	require.ErrorIs(t, err, ErrExhausted)
+
+	// numbers come from the database in blocks
+	var value int64
+	require.NoError(t, db.QueryRow(`SELECT value FROM sequences WHERE name = 'batches'`).Scan(&value))
+	require.Equal(t, int64(25), value)
+
+	// another process continues after the reserved blocks
+	other := New(db, Config{BlockSize: 10})
+	n, err := other.Next(ctx, "batches")
+	require.NoError(t, err)
+	require.Equal(t, int64(26), n)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package settlement computes when ACH entries are processed, settle and can no longer be returned.
+//
+// The rules are based on US Federal Reserve banking days and Eastern time submission cutoffs:
+//
+//   - Entries submitted after a cutoff (or on a non-banking day) are processed the next banking day.
+//   - Same-day entries settle on their processing date, next-day entries one banking day later.
+//   - Returns are due a number of banking days after settlement (2 for most return codes, 60 for
+//     unauthorized consumer debits).
+package settlement
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// Speed is how quickly an entry settles according to its SEC code and the ODFI's processing.
+type Speed string
+
+const (
+	// NextDay entries settle one banking day after they're processed
+	NextDay Speed = "next-day"
+
+	// SameDay entries settle on the banking day they're processed
+	SameDay Speed = "same-day"
+)
+
+// ReturnWindow is the number of banking days after settlement a return can be received.
+type ReturnWindow int
+
+const (
+	// StandardReturnWindow applies to most return reason codes (i.e. R01 insufficient funds)
+	StandardReturnWindow ReturnWindow = 2
+
+	// ExtendedReturnWindow applies to unauthorized consumer debits (i.e. R10)
+	ExtendedReturnWindow ReturnWindow = 60
+)
+
+var (
+	// DefaultCutoffs are the submission cutoffs in Eastern time used by Calculate.
+	// SameDay is the Federal Reserve's last same-day window.
+	DefaultCutoffs = Cutoffs{
+		SameDay: 16*time.Hour + 45*time.Minute,
+		NextDay: 20 * time.Hour,
+	}
+)
+
+// Cutoffs are the latest submission times for each Speed, expressed as the wall clock time since
+// midnight in Location (i.e. 16h45m for 4:45pm).
+type Cutoffs struct {
+	SameDay time.Duration
+	NextDay time.Duration
+
+	// Location cutoffs are in, America/New_York is used when nil
+	Location *time.Location
+}
+
+// Window describes the key dates of an entry.
+type Window struct {
+	// ProcessingDate is the banking day the entry is submitted to the network
+	ProcessingDate base.Date `json:"processingDate"`
+
+	// SettlementDate is when funds move between financial institutions
+	SettlementDate base.Date `json:"settlementDate"`
+
+	// ReturnDeadline is the last banking day a return can be received
+	ReturnDeadline base.Date `json:"returnDeadline"`
+}
+
+// Calculate returns the Window of an entry originated at origination.
+func (c Cutoffs) Calculate(origination base.Time, speed Speed, returns ReturnWindow) (Window, error) {
+	var cutoff time.Duration
+	switch speed {
+	case SameDay:
+		cutoff = c.SameDay
+	case NextDay:
+		cutoff = c.NextDay
+	default:
+		return Window{}, fmt.Errorf("unknown settlement speed %q", string(speed))
+	}
+	if cutoff <= 0 || cutoff >= 24*time.Hour {
+		return Window{}, fmt.Errorf("invalid %s cutoff %v", speed, cutoff)
+	}
+	if returns < 0 {
+		return Window{}, fmt.Errorf("invalid return window of %d banking days", returns)
+	}
+
+	processing := base.ProcessingDate(origination, cutoff, c.Location)
+	settlement := processing
+	if speed == NextDay {
+		settlement = processing.AddBankingDays(1)
+	}
+	return Window{
+		ProcessingDate: processing,
+		SettlementDate: settlement,
+		ReturnDeadline: settlement.AddBankingDays(int(returns)),
+	}, nil
+}
+
+// Calculate returns the Window of an entry originated at origination using DefaultCutoffs.
+func Calculate(origination base.Time, speed Speed, returns ReturnWindow) (Window, error) {
+	return DefaultCutoffs.Calculate(origination, speed, returns)
+}
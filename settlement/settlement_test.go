@@ -0,0 +1,81 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package settlement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+var eastern, _ = time.LoadLocation("America/New_York")
+
+func at(t *testing.T, year int, month time.Month, day, hour, min int) base.Time {
+	t.Helper()
+	return base.NewTime(time.Date(year, month, day, hour, min, 0, 0, eastern))
+}
+
+func TestCalculate__SameDay(t *testing.T) {
+	// Tuesday morning
+	w, err := Calculate(at(t, 2020, time.November, 10, 9, 0), SameDay, StandardReturnWindow)
+	require.NoError(t, err)
+	require.Equal(t, base.NewDate(2020, time.November, 10), w.ProcessingDate)
+	require.Equal(t, base.NewDate(2020, time.November, 10), w.SettlementDate)
+	// Wednesday is Veterans Day
+	require.Equal(t, base.NewDate(2020, time.November, 13), w.ReturnDeadline)
+
+	// After the cutoff rolls to the next banking day
+	w, err = Calculate(at(t, 2020, time.November, 10, 17, 0), SameDay, StandardReturnWindow)
+	require.NoError(t, err)
+	require.Equal(t, base.NewDate(2020, time.November, 12), w.ProcessingDate)
+	require.Equal(t, base.NewDate(2020, time.November, 12), w.SettlementDate)
+	require.Equal(t, base.NewDate(2020, time.November, 16), w.ReturnDeadline)
+}
+
+func TestCalculate__NextDay(t *testing.T) {
+	// Friday afternoon settles Monday
+	w, err := Calculate(at(t, 2020, time.November, 13, 15, 0), NextDay, StandardReturnWindow)
+	require.NoError(t, err)
+	require.Equal(t, base.NewDate(2020, time.November, 13), w.ProcessingDate)
+	require.Equal(t, base.NewDate(2020, time.November, 16), w.SettlementDate)
+	require.Equal(t, base.NewDate(2020, time.November, 18), w.ReturnDeadline)
+
+	// Saturday submissions are processed Monday
+	w, err = Calculate(at(t, 2020, time.November, 14, 9, 0), NextDay, ExtendedReturnWindow)
+	require.NoError(t, err)
+	require.Equal(t, base.NewDate(2020, time.November, 16), w.ProcessingDate)
+	require.Equal(t, base.NewDate(2020, time.November, 17), w.SettlementDate)
+	require.Equal(t, base.NewDate(2020, time.November, 17).AddBankingDays(60), w.ReturnDeadline)
+}
+
+func TestCalculate__Location(t *testing.T) {
+	pacific, _ := time.LoadLocation("America/Los_Angeles")
+	cutoffs := Cutoffs{
+		SameDay:  14 * time.Hour,
+		NextDay:  18 * time.Hour,
+		Location: pacific,
+	}
+
+	// 4pm Eastern is 1pm Pacific, before the same-day cutoff
+	w, err := cutoffs.Calculate(at(t, 2020, time.November, 10, 16, 0), SameDay, StandardReturnWindow)
+	require.NoError(t, err)
+	require.Equal(t, base.NewDate(2020, time.November, 10), w.SettlementDate)
+}
+
+func TestCalculate__errors(t *testing.T) {
+	now := at(t, 2020, time.November, 10, 9, 0)
+
+	_, err := Calculate(now, Speed("instant"), StandardReturnWindow)
+	require.Error(t, err)
+
+	_, err = Cutoffs{}.Calculate(now, SameDay, StandardReturnWindow)
+	require.Error(t, err)
+
+	_, err = Calculate(now, SameDay, ReturnWindow(-1))
+	require.Error(t, err)
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package slo computes how quickly a service consumes the error budget of its service level
+// objectives and which multi-window burn rate alerts (from Google's SRE Workbook) are firing.
+//
+//	availability := slo.Objective{Name: "transfers-availability", Target: 0.999}
+//	tracker := slo.NewTracker(availability, slo.DefaultAlerts())
+//
+//	// every few seconds, with the cumulative counts behind the service's metrics
+//	tracker.Record(time.Now(), requests, failures)
+//	for _, a := range tracker.Firing(time.Now()) {
+//		logger.Warn().Logf("%s %s: burning its error budget %.1fx too fast", availability.Name, a.Alert.Name, a.LongBurn)
+//	}
+//
+// Latency objectives work the same way, counting requests slower than the threshold as bad.
+package slo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	burnRates = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "slo_burn_rate",
+		Help: "Gauge of how many times faster than sustainable the error budget is being spent",
+	}, []string{"slo", "window"})
+)
+
+// Objective is a target fraction of good events, such as 99.9% of requests succeeding
+type Objective struct {
+	Name string
+
+	// Target is between 0 and 1 exclusive, 0.999 for "three nines"
+	Target float64
+}
+
+// Budget returns the fraction of events which may be bad
+func (o Objective) Budget() float64 {
+	return 1 - o.Target
+}
+
+// Validate checks the target is a fraction
+func (o Objective) Validate() error {
+	if o.Target <= 0 || o.Target >= 1 {
+		return fmt.Errorf("slo: %s target %v must be between 0 and 1", o.Name, o.Target)
+	}
+	return nil
+}
+
+// BurnRate returns how many times faster than sustainable bad out of total events spend the
+// budget of o. A burn rate of 1 spends exactly the budget over the period.
+func (o Objective) BurnRate(bad, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return (bad / total) / o.Budget()
+}
+
+// Remaining returns the fraction of the budget left after bad out of total events, which is
+// negative once the objective is missed
+func (o Objective) Remaining(bad, total float64) float64 {
+	if total <= 0 {
+		return 1
+	}
+	return 1 - (bad/total)/o.Budget()
+}
+
+// Alert fires when the burn rate over both its Long and Short windows is at least Burn. The
+// short window makes alerts stop soon after the problem is fixed.
+type Alert struct {
+	Name  string
+	Long  time.Duration
+	Short time.Duration
+	Burn  float64
+}
+
+// BudgetSpent returns the fraction of the budget of an SLO period spent by the time the alert fires
+func (a Alert) BudgetSpent(period time.Duration) float64 {
+	return a.Burn * float64(a.Long) / float64(period)
+}
+
+// DefaultAlerts are the SRE Workbook's recommended alerts for a 30 day period. Pages fire after
+// 2% of the budget is spent in an hour or 5% in six hours and tickets after 10% in three days.
+func DefaultAlerts() []Alert {
+	return []Alert{
+		{Name: "page", Long: time.Hour, Short: 5 * time.Minute, Burn: 14.4},
+		{Name: "page", Long: 6 * time.Hour, Short: 30 * time.Minute, Burn: 6},
+		{Name: "ticket", Long: 3 * 24 * time.Hour, Short: 6 * time.Hour, Burn: 1},
+	}
+}
+
+// Firing is an Alert whose windows are both burning too fast
+type Firing struct {
+	Alert     Alert
+	LongBurn  float64
+	ShortBurn float64
+}
+
+type sample struct {
+	at         time.Time
+	total, bad float64
+}
+
+// Tracker computes burn rates over windows from samples of cumulative counters
+type Tracker struct {
+	objective Objective
+	alerts    []Alert
+	retention time.Duration
+
+	mu      sync.Mutex
+	samples []sample
+}
+
+// NewTracker returns a Tracker of objective keeping enough samples for alerts. It panics when
+// the objective's target isn't a fraction.
+func NewTracker(objective Objective, alerts []Alert) *Tracker {
+	if err := objective.Validate(); err != nil {
+		panic(err)
+	}
+	retention := time.Duration(0)
+	for _, a := range alerts {
+		if a.Long > retention {
+			retention = a.Long
+		}
+	}
+	return &Tracker{
+		objective: objective,
+		alerts:    alerts,
+		retention: retention,
+	}
+}
+
+// Record adds a sample of the cumulative total and bad event counts. Samples must be recorded
+// in time order. Counts lower than the last sample mean the counters were reset and the
+// earlier samples are discarded.
+func (t *Tracker) Record(at time.Time, total, bad float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n := len(t.samples); n > 0 {
+		last := t.samples[n-1]
+		if total < last.total || bad < last.bad {
+			t.samples = t.samples[:0]
+		}
+	}
+	t.samples = append(t.samples, sample{at: at, total: total, bad: bad})
+
+	// keep one sample older than the retention so the longest window is complete
+	cutoff := at.Add(-t.retention)
+	drop := 0
+	for drop+1 < len(t.samples) && !t.samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		t.samples = append(t.samples[:0], t.samples[drop:]...)
+	}
+}
+
+// BurnRate returns the burn rate over the window ending at now. Windows longer than the
+// recorded samples use every sample once they cover half of the window, and return zero
+// before then so a few failures just after starting don't read as a budget burning away.
+func (t *Tracker) BurnRate(window time.Duration, now time.Time) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.burnRate(window, now)
+}
+
+func (t *Tracker) burnRate(window time.Duration, now time.Time) float64 {
+	// the last sample at or before now
+	end := sort.Search(len(t.samples), func(i int) bool {
+		return t.samples[i].at.After(now)
+	}) - 1
+	if end < 1 {
+		return 0
+	}
+	// the last sample at or before the start of the window, or the oldest one
+	start := sort.Search(end, func(i int) bool {
+		return t.samples[i].at.After(now.Add(-window))
+	}) - 1
+	if start < 0 {
+		start = 0
+	}
+	from, to := t.samples[start], t.samples[end]
+	if to.at.Sub(from.at) < time.Duration(minCoverage*float64(window)) {
+		return 0
+	}
+	return t.objective.BurnRate(to.bad-from.bad, to.total-from.total)
+}
+
+// minCoverage is the share of a window samples must span before its burn rate is reported
+const minCoverage = 0.5
+
+// Firing returns the alerts whose long and short windows are both burning at least their
+// Burn at now, and updates the slo_burn_rate gauges
+func (t *Tracker) Firing(now time.Time) []Firing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []Firing
+	for _, a := range t.alerts {
+		long, short := t.burnRate(a.Long, now), t.burnRate(a.Short, now)
+		burnRates.With("slo", t.objective.Name, "window", a.Long.String()).Set(long)
+		burnRates.With("slo", t.objective.Name, "window", a.Short.String()).Set(short)
+
+		if long >= a.Burn && short >= a.Burn {
+			out = append(out, Firing{Alert: a, LongBurn: long, ShortBurn: short})
+		}
+	}
+	return out
+}
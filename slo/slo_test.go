@@ -0,0 +1,107 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var availability = Objective{Name: "availability", Target: 0.999}
+
+func TestObjective(t *testing.T) {
+	require.InDelta(t, 0.001, availability.Budget(), 1e-9)
+	require.InDelta(t, 1, availability.BurnRate(1, 1000), 1e-9)
+	require.InDelta(t, 10, availability.BurnRate(10, 1000), 1e-9)
+	require.Zero(t, availability.BurnRate(0, 0))
+
+	require.InDelta(t, 0.5, availability.Remaining(5, 10000), 1e-9)
+	require.InDelta(t, -1, availability.Remaining(2, 1000), 1e-9)
+	require.Equal(t, 1.0, availability.Remaining(0, 0))
+
+	require.NoError(t, availability.Validate())
+	require.ErrorContains(t, Objective{Name: "x", Target: 1}.Validate(), "slo: x target 1 must be between 0 and 1")
+	require.Panics(t, func() { NewTracker(Objective{}, nil) })
+}
+
+func TestDefaultAlerts(t *testing.T) {
+	period := 30 * 24 * time.Hour
+	spent := []float64{0.02, 0.05, 0.10}
+	for i, a := range DefaultAlerts() {
+		require.InDelta(t, spent[i], a.BudgetSpent(period), 0.001, a.Long)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	start := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(availability, DefaultAlerts())
+
+	require.Zero(t, tracker.BurnRate(time.Hour, start))
+
+	// healthy for six hours: 1000 requests a minute with one failure every ten minutes
+	var total, bad float64
+	at := start
+	for i := 0; i < 6*60; i++ {
+		total += 1000
+		if i%10 == 0 {
+			bad++
+		}
+		at = at.Add(time.Minute)
+		tracker.Record(at, total, bad)
+	}
+	require.InDelta(t, 0.1, tracker.BurnRate(time.Hour, at), 0.01)
+	require.Empty(t, tracker.Firing(at))
+
+	// then 2% of requests fail for an hour
+	for i := 0; i < 60; i++ {
+		total += 1000
+		bad += 20
+		at = at.Add(time.Minute)
+		tracker.Record(at, total, bad)
+	}
+	require.InDelta(t, 20, tracker.BurnRate(5*time.Minute, at), 0.01)
+
+	firing := tracker.Firing(at)
+	require.Len(t, firing, 1) // an hour isn't enough for the six hour page
+	require.Equal(t, 14.4, firing[0].Alert.Burn)
+	require.InDelta(t, 20, firing[0].LongBurn, 0.1)
+
+	// seven hours of samples are too few for the three day ticket
+	require.Zero(t, tracker.BurnRate(3*24*time.Hour, at))
+
+	// fixed, the short window stops the page within minutes
+	for i := 0; i < 10; i++ {
+		total += 1000
+		at = at.Add(time.Minute)
+		tracker.Record(at, total, bad)
+	}
+	require.Greater(t, tracker.BurnRate(time.Hour, at), 14.4)
+	require.Empty(t, tracker.Firing(at))
+
+	// earlier windows can still be read
+	require.InDelta(t, 20, tracker.BurnRate(5*time.Minute, at.Add(-10*time.Minute)), 0.01)
+}
+
+func TestTracker__Retention(t *testing.T) {
+	tracker := NewTracker(availability, []Alert{{Long: time.Hour, Short: 5 * time.Minute, Burn: 10}})
+
+	at := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 180; i++ {
+		tracker.Record(at, float64(i*1000), float64(i))
+		at = at.Add(time.Minute)
+	}
+	require.Len(t, tracker.samples, 61)
+
+	// windows longer than the samples use what's there once it's half the window
+	require.InDelta(t, 1, tracker.BurnRate(90*time.Minute, at), 1e-9)
+	require.Zero(t, tracker.BurnRate(24*time.Hour, at))
+
+	// a counter reset discards the samples before it
+	tracker.Record(at, 10, 1)
+	require.Len(t, tracker.samples, 1)
+	require.Zero(t, tracker.BurnRate(time.Hour, at))
+}
@@ -7,6 +7,8 @@ package strx
 import (
 	"strconv"
 	"strings"
+
+	"github.com/moov-io/base/randx"
 )
 
 // Or returns the first non-empty string
@@ -28,3 +30,11 @@ func Yes(in string) bool {
 	v, _ := strconv.ParseBool(in)
 	return v
 }
+
+// Alphanumeric are the characters used by Random
+const Alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Random returns n random alphanumeric characters read from randx.
+func Random(n int) string {
+	return randx.String(n, Alphanumeric)
+}
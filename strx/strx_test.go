@@ -5,7 +5,10 @@
 package strx
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/moov-io/base/randx/randxtest"
 )
 
 func TestOr(t *testing.T) {
@@ -42,3 +45,16 @@ func TestYes(t *testing.T) {
 		t.Error("expected no")
 	}
 }
+
+func TestRandom(t *testing.T) {
+	if v := Random(12); len(v) != 12 || strings.Trim(v, Alphanumeric) != "" {
+		t.Errorf("got %q", v)
+	}
+
+	randxtest.Seed(t, 7)
+	first := Random(8)
+	randxtest.Seed(t, 7)
+	if second := Random(8); first != second {
+		t.Errorf("seeded values differ: %q and %q", first, second)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Scope adds a "column = ?" predicate for the tenant of ctx to query and appends the tenant ID to
+// args. An existing WHERE clause is wrapped in parentheses and joined with AND so an OR inside it
+// can't widen the query, and the predicate is placed before any GROUP BY, ORDER BY, LIMIT or
+// FOR UPDATE. Clauses inside subqueries are left alone.
+//
+// ErrMissingTenant is returned when ctx isn't scoped so queries are never run across tenants.
+// Compound queries (UNION, INTERSECT or EXCEPT), comments (--, # and /* */) and unbalanced
+// parentheses or quotes return an error rather than being scoped incorrectly, such as by appending
+// the predicate inside a trailing comment.
+func Scope(ctx context.Context, query string, column string, args ...interface{}) (string, []interface{}, error) {
+	id, err := Require(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where, tail, err := clauses(query)
+	if err != nil {
+		return "", nil, err
+	}
+	rest := query[tail:]
+
+	var buf strings.Builder
+	if where >= 0 {
+		buf.WriteString(strings.TrimRight(query[:where], " \t\r\n"))
+		buf.WriteString(" (")
+		buf.WriteString(strings.TrimSpace(query[where:tail]))
+		buf.WriteString(") AND ")
+	} else {
+		buf.WriteString(strings.TrimRight(query[:tail], " \t\r\n"))
+		buf.WriteString(" WHERE ")
+	}
+	buf.WriteString(column)
+	buf.WriteString(" = ?")
+	if rest != "" {
+		buf.WriteString(" ")
+		buf.WriteString(rest)
+	}
+
+	// the predicate's placeholder comes after any before the trailing clauses, but before those in rest
+	before := strings.Count(stripQuoted(query[:tail]), "?")
+	if before > len(args) {
+		before = len(args)
+	}
+	out := make([]interface{}, 0, len(args)+1)
+	out = append(out, args[:before]...)
+	out = append(out, string(id))
+	out = append(out, args[before:]...)
+
+	return buf.String(), out, nil
+}
+
+var (
+	trailingClauses = []string{"GROUP BY", "ORDER BY", "LIMIT", "FOR UPDATE"}
+	compoundClauses = []string{"UNION", "INTERSECT", "EXCEPT"}
+)
+
+// clauses returns the offset just past a top level WHERE, or -1 when query has none, and the
+// offset of its first trailing clause, or len(query) when it has none.
+func clauses(query string) (int, int, error) {
+	upper := strings.ToUpper(query)
+	where, tail := -1, -1
+
+	depth := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			continue
+		case c == '#', strings.HasPrefix(query[i:], "--"), strings.HasPrefix(query[i:], "/*"):
+			return 0, 0, fmt.Errorf("tenancy: unable to scope query with comments %q", query)
+		case c == '(':
+			depth++
+			continue
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return 0, 0, fmt.Errorf("tenancy: unbalanced parentheses in %q", query)
+			}
+			continue
+		}
+		if depth != 0 || (i > 0 && isWordByte(query[i-1])) {
+			continue
+		}
+		for _, kw := range compoundClauses {
+			if keywordAt(upper, i, kw) {
+				return 0, 0, fmt.Errorf("tenancy: unable to scope %s query", kw)
+			}
+		}
+		if tail >= 0 {
+			continue
+		}
+		if keywordAt(upper, i, "WHERE") {
+			where = i + len("WHERE")
+		}
+		for _, kw := range trailingClauses {
+			if keywordAt(upper, i, kw) {
+				tail = i
+			}
+		}
+	}
+	if quote != 0 {
+		return 0, 0, fmt.Errorf("tenancy: unterminated quote in %q", query)
+	}
+	if depth != 0 {
+		return 0, 0, fmt.Errorf("tenancy: unbalanced parentheses in %q", query)
+	}
+	if tail < 0 {
+		tail = len(query)
+	}
+	return where, tail, nil
+}
+
+func keywordAt(upper string, i int, kw string) bool {
+	if !strings.HasPrefix(upper[i:], kw) {
+		return false
+	}
+	end := i + len(kw)
+	return end == len(upper) || !isWordByte(upper[end])
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// stripQuoted removes quoted strings so '?' inside them isn't counted as a placeholder
+func stripQuoted(query string) string {
+	var buf strings.Builder
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package tenancy scopes requests to a tenant. Middleware reads the tenant ID from a header or the
+// caller's claims into the request's context, where it's used to filter SQL queries and label logs
+// and metrics.
+//
+//	handler := tenancy.Middleware(tenancy.Config{Claims: auth.Claims}, router)
+//
+//	func (r *repo) list(ctx context.Context) (*sql.Rows, error) {
+//		query, args, err := tenancy.Scope(ctx, "SELECT id FROM transfers WHERE deleted_at IS NULL ORDER BY created_at", "tenant_id")
+//		if err != nil {
+//			return nil, err
+//		}
+//		return r.db.QueryContext(ctx, query, args...)
+//	}
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/moov-io/base/ctxkeys"
+	moovhttp "github.com/moov-io/base/http"
+	"github.com/moov-io/base/log"
+)
+
+// ID identifies a tenant
+type ID string
+
+var (
+	// ErrMissingTenant is returned when a request or context has no tenant ID
+	ErrMissingTenant = errors.New("missing tenant ID")
+
+	// ErrTenantMismatch is returned when the tenant header doesn't match the caller's claims
+	ErrTenantMismatch = errors.New("tenant ID does not match credentials")
+
+	// ErrMissingClaim is returned when Claims is configured but the caller's claims have no tenant ID
+	ErrMissingClaim = errors.New("credentials have no tenant ID")
+
+	tenantKey = ctxkeys.New[ID]("tenant")
+)
+
+// WithTenant returns a copy of ctx scoped to id
+func WithTenant(ctx context.Context, id ID) context.Context {
+	return tenantKey.Set(ctx, id)
+}
+
+// FromContext returns the tenant ctx is scoped to
+func FromContext(ctx context.Context) (ID, bool) {
+	id, ok := tenantKey.Get(ctx)
+	return id, ok && id != ""
+}
+
+// Require returns the tenant ctx is scoped to or ErrMissingTenant
+func Require(ctx context.Context) (ID, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrMissingTenant
+	}
+	return id, nil
+}
+
+// Config describes where Middleware reads tenant IDs from
+type Config struct {
+	// Header holds the tenant ID. It defaults to X-Tenant-Id.
+	Header string
+
+	// Claims returns the authenticated caller's claims, such as those of a verified JWT stored in
+	// the context by earlier middleware. The claim is authoritative and required: requests without
+	// it, or with a header naming another tenant, are rejected.
+	Claims func(ctx context.Context) map[string]interface{}
+
+	// Claim is the name of the tenant ID claim. It defaults to "tenant_id".
+	Claim string
+
+	// Skip are paths served without a tenant, such as health checks. Each matches the path itself
+	// and paths beneath it, so "/ping" skips "/ping/db" but not "/pingdom".
+	Skip []string
+}
+
+// Middleware scopes each request's context to its tenant. Requests without a tenant ID
+// are rejected with a 400 response, and those whose claims are missing the tenant or disagree
+// with the header with a 403.
+func Middleware(cfg Config, next http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = "X-Tenant-Id"
+	}
+	if cfg.Claim == "" {
+		cfg.Claim = "tenant_id"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.skipped(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, err := cfg.extract(r)
+		if err != nil {
+			if errors.Is(err, ErrTenantMismatch) || errors.Is(err, ErrMissingClaim) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			moovhttp.Problem(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), id)))
+	})
+}
+
+func (cfg Config) skipped(path string) bool {
+	for _, prefix := range cfg.Skip {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/' {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg Config) extract(r *http.Request) (ID, error) {
+	header := ID(strings.TrimSpace(r.Header.Get(cfg.Header)))
+
+	if cfg.Claims == nil {
+		if header == "" {
+			return "", ErrMissingTenant
+		}
+		return header, nil
+	}
+
+	var claim ID
+	if v, ok := cfg.Claims(r.Context())[cfg.Claim].(string); ok {
+		claim = ID(strings.TrimSpace(v))
+	}
+	switch {
+	case claim == "":
+		return "", ErrMissingClaim
+	case header != "" && claim != header:
+		return "", ErrTenantMismatch
+	}
+	return claim, nil
+}
+
+// Fields returns the tenant of ctx for log lines
+//
+//	logger.With(tenancy.Fields(ctx)).Info().Log("created transfer")
+func Fields(ctx context.Context) log.Fields {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return log.Fields{}
+	}
+	return log.Fields{
+		"tenant": log.String(string(id)),
+	}
+}
+
+// Label returns the tenant of ctx for a metric label, or "unknown" when ctx isn't scoped.
+//
+//	transfersCreated.With("tenant", tenancy.Label(ctx)).Add(1)
+func Label(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return string(id)
+	}
+	return "unknown"
+}
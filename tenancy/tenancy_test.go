@@ -0,0 +1,181 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package tenancy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/moov-io/base/ctxkeys"
+	"github.com/moov-io/base/log"
+
+	"github.com/stretchr/testify/require"
+)
+
+var claimsKey = ctxkeys.New[map[string]interface{}]("claims")
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(Config{
+		Claims: claimsKey.Value,
+		Skip:   []string{"/ping"},
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Label(r.Context())))
+	}))
+
+	serve := func(path, header, claim string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if header != "" {
+			req.Header.Set("X-Tenant-Id", header)
+		}
+		if claim != "" {
+			req = req.WithContext(claimsKey.Set(req.Context(), map[string]interface{}{"tenant_id": claim}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/transfers", "acme", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), ErrMissingClaim.Error())
+
+	w = serve("/transfers", "", "globex")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "globex", w.Body.String())
+
+	w = serve("/transfers", "globex", "globex")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve("/transfers", "acme", "globex")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), ErrTenantMismatch.Error())
+
+	w = serve("/transfers", "", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), ErrMissingClaim.Error())
+
+	w = serve("/ping", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "unknown", w.Body.String())
+
+	w = serve("/ping/db", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve("/pingdom", "", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMiddleware__Header(t *testing.T) {
+	handler := Middleware(Config{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Label(r.Context())))
+	}))
+
+	req := httptest.NewRequest("GET", "/transfers", nil)
+	req.Header.Set("X-Tenant-Id", "acme")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "acme", w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/transfers", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), ErrMissingTenant.Error())
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := Require(ctx)
+	require.ErrorIs(t, err, ErrMissingTenant)
+	require.Empty(t, Fields(ctx))
+
+	ctx = WithTenant(ctx, "acme")
+	id, err := Require(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ID("acme"), id)
+
+	buf, logger := log.NewBufferLogger()
+	logger.With(Fields(ctx)).Info().Log("created transfer")
+	require.Contains(t, buf.String(), "tenant=acme")
+}
+
+func TestScope(t *testing.T) {
+	ctx := WithTenant(context.Background(), "acme")
+
+	cases := []struct {
+		query    string
+		args     []interface{}
+		expected string
+		outArgs  []interface{}
+	}{
+		{
+			query:    "SELECT id FROM transfers",
+			expected: "SELECT id FROM transfers WHERE tenant_id = ?",
+			outArgs:  []interface{}{"acme"},
+		},
+		{
+			query:    "SELECT id FROM transfers WHERE status = ? ORDER BY created_at LIMIT ?",
+			args:     []interface{}{"pending", 10},
+			expected: "SELECT id FROM transfers WHERE (status = ?) AND tenant_id = ? ORDER BY created_at LIMIT ?",
+			outArgs:  []interface{}{"pending", "acme", 10},
+		},
+		{
+			query:    "select id from transfers where memo = 'order by ?' limit 5",
+			expected: "select id from transfers where (memo = 'order by ?') AND tenant_id = ? limit 5",
+			outArgs:  []interface{}{"acme"},
+		},
+		{
+			query:    "SELECT id FROM transfers WHERE id IN (SELECT transfer_id FROM events WHERE kind = ? LIMIT 5) FOR UPDATE",
+			args:     []interface{}{"sent"},
+			expected: "SELECT id FROM transfers WHERE (id IN (SELECT transfer_id FROM events WHERE kind = ? LIMIT 5)) AND tenant_id = ? FOR UPDATE",
+			outArgs:  []interface{}{"sent", "acme"},
+		},
+		{
+			query:    "SELECT id FROM transfers WHERE status = ? OR status = ?",
+			args:     []interface{}{"pending", "sent"},
+			expected: "SELECT id FROM transfers WHERE (status = ? OR status = ?) AND tenant_id = ?",
+			outArgs:  []interface{}{"pending", "sent", "acme"},
+		},
+		{
+			query:    "SELECT id FROM transfers WHERE memo = '-- #1 /* note */'",
+			expected: "SELECT id FROM transfers WHERE (memo = '-- #1 /* note */') AND tenant_id = ?",
+			outArgs:  []interface{}{"acme"},
+		},
+		{
+			query:    "SELECT group_id, order_limit FROM batches\n",
+			expected: "SELECT group_id, order_limit FROM batches WHERE tenant_id = ?",
+			outArgs:  []interface{}{"acme"},
+		},
+	}
+	for _, tc := range cases {
+		query, args, err := Scope(ctx, tc.query, "tenant_id", tc.args...)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, query)
+		require.Equal(t, tc.outArgs, args)
+	}
+
+	_, _, err := Scope(context.Background(), "SELECT id FROM transfers", "tenant_id")
+	require.ErrorIs(t, err, ErrMissingTenant)
+
+	invalid := []string{
+		"SELECT id FROM transfers UNION SELECT id FROM archived_transfers",
+		"SELECT id FROM transfers WHERE status = ? INTERSECT SELECT id FROM returns",
+		"SELECT id FROM transfers WHERE memo = 'unterminated",
+		"SELECT id FROM transfers WHERE (status = ?",
+		"SELECT id FROM transfers WHERE status = ?)",
+		// the predicate would be appended inside these trailing comments
+		"SELECT id FROM transfers -- note",
+		"SELECT id FROM transfers # note",
+		"SELECT id FROM transfers /* note",
+		"SELECT id FROM transfers /* note */ WHERE status = ?",
+	}
+	for _, query := range invalid {
+		_, _, err := Scope(ctx, query, "tenant_id")
+		require.Error(t, err, query)
+	}
+}
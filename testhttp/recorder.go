@@ -0,0 +1,294 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package testhttp records HTTP interactions with partner APIs into golden files and replays them
+// in later test runs, so contract tests don't make live calls.
+//
+//	func TestBankClient(t *testing.T) {
+//		rec := testhttp.New(t, testhttp.Config{
+//			Path:      "testdata/bank-transfers.json",
+//			Scrubbers: []testhttp.Scrubber{testhttp.ScrubTimestamps()},
+//		})
+//		client := bank.NewClient(rec.Client())
+//		...
+//	}
+//
+// Interactions are replayed unless tests run with -testhttp.record, which calls the real API and
+// rewrites the file when the test finishes.
+package testhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var flagRecord = flag.Bool("testhttp.record", false, "Record HTTP interactions against live APIs instead of replaying them")
+
+// Interaction is a request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded http.Request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded http.Response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Scrubber removes values such as credentials and timestamps which shouldn't be saved or differ
+// between runs. Scrubbers are applied to interactions before they're saved and to requests
+// before they're matched during replay.
+type Scrubber func(*Interaction)
+
+// ScrubHeaders replaces the values of request and response headers with "REDACTED".
+// Authorization, Cookie and Set-Cookie are always scrubbed.
+func ScrubHeaders(names ...string) Scrubber {
+	return func(i *Interaction) {
+		for _, name := range names {
+			for _, h := range []http.Header{i.Request.Header, i.Response.Header} {
+				if h.Get(name) != "" {
+					h.Set(name, "REDACTED")
+				}
+			}
+		}
+	}
+}
+
+// ScrubRegexp replaces matches of re in URLs and bodies
+func ScrubRegexp(re *regexp.Regexp, replacement string) Scrubber {
+	return func(i *Interaction) {
+		i.Request.URL = re.ReplaceAllString(i.Request.URL, replacement)
+		i.Request.Body = re.ReplaceAllString(i.Request.Body, replacement)
+		i.Response.Body = re.ReplaceAllString(i.Response.Body, replacement)
+	}
+}
+
+var timestamps = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// ScrubTimestamps replaces RFC 3339 timestamps in URLs and bodies and drops the Date header
+// of responses.
+func ScrubTimestamps() Scrubber {
+	replace := ScrubRegexp(timestamps, "2006-01-02T15:04:05Z")
+	return func(i *Interaction) {
+		replace(i)
+		i.Response.Header.Del("Date")
+	}
+}
+
+// Config describes where interactions are stored and how they're matched
+type Config struct {
+	// Path of the golden file
+	Path string
+
+	// Transport makes live calls while recording. It defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	Scrubbers []Scrubber
+
+	// Match returns true when a recorded request answers req. It defaults to comparing the
+	// method, URL and body.
+	Match func(req Request, recorded Request) bool
+
+	// Record forces recording regardless of -testhttp.record
+	Record bool
+}
+
+// Recorder is an http.RoundTripper which records or replays interactions
+type Recorder struct {
+	t   testing.TB
+	cfg Config
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a Recorder. When replaying the golden file is read immediately and a missing file
+// fails the test. When recording the file is written with t.Cleanup once the test passes.
+func New(t testing.TB, cfg Config) *Recorder {
+	t.Helper()
+
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	if cfg.Match == nil {
+		cfg.Match = matchRequest
+	}
+	cfg.Scrubbers = append([]Scrubber{ScrubHeaders("Authorization", "Cookie", "Set-Cookie")}, cfg.Scrubbers...)
+	cfg.Record = cfg.Record || *flagRecord
+
+	r := &Recorder{t: t, cfg: cfg}
+	if cfg.Record {
+		t.Cleanup(func() {
+			if t.Failed() {
+				return
+			}
+			if err := r.save(); err != nil {
+				t.Errorf("testhttp: saving %s: %v", cfg.Path, err)
+			}
+		})
+		return r
+	}
+
+	bs, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		t.Fatalf("testhttp: %v (record interactions with -testhttp.record)", err)
+	}
+	if err := json.Unmarshal(bs, &r.interactions); err != nil {
+		t.Fatalf("testhttp: reading %s: %v", cfg.Path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r
+}
+
+// Client returns an *http.Client which uses the Recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays req
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.cfg.Record {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.cfg.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(body),
+		},
+	}
+	interaction.Response.Header.Del("Content-Length") // scrubbers may change the body
+	r.scrub(&interaction)
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	interaction := Interaction{Request: recorded}
+	r.scrub(&interaction)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.interactions {
+		if r.used[i] || !r.cfg.Match(interaction.Request, r.interactions[i].Request) {
+			continue
+		}
+		r.used[i] = true
+		resp := r.interactions[i].Response
+		header := resp.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+	err := fmt.Errorf("testhttp: no recorded interaction for %s %s in %s", recorded.Method, interaction.Request.URL, r.cfg.Path)
+	r.t.Error(err)
+	return nil, err
+}
+
+func (r *Recorder) scrub(i *Interaction) {
+	if i.Request.Header == nil {
+		i.Request.Header = make(http.Header)
+	}
+	if i.Response.Header == nil {
+		i.Response.Header = make(http.Header)
+	}
+	for _, scrub := range r.cfg.Scrubbers {
+		scrub(i)
+	}
+}
+
+func (r *Recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bs, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cfg.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.cfg.Path, append(bs, '\n'), 0644)
+}
+
+// recordRequest reads the body of req, which is replaced so it can be sent again.
+func recordRequest(req *http.Request) (Request, error) {
+	out := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return out, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		out.Body = string(body)
+	}
+	return out, nil
+}
+
+func matchRequest(req Request, recorded Request) bool {
+	return req.Method == recorded.Method && req.URL == recorded.URL && req.Body == recorded.Body
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package testhttp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "partner.json")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"received":%q,"createdAt":%q}`, body, time.Now().Format(time.RFC3339Nano))
+	}))
+
+	// record against the live server
+	t.Run("record", func(t *testing.T) {
+		rec := New(t, Config{Path: path, Record: true, Scrubbers: []Scrubber{ScrubTimestamps()}})
+		resp := post(t, rec.Client(), server.URL+"/transfers", `{"amount":100}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, "session=secret", resp.Header.Get("Set-Cookie"))
+	})
+	server.Close()
+	require.Equal(t, 1, calls)
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	saved := string(bs)
+	require.Contains(t, saved, `"REDACTED"`)
+	require.NotContains(t, saved, "Content-Length")
+	require.Contains(t, saved, `2006-01-02T15:04:05Z`)
+	require.NotContains(t, saved, "secret")
+
+	// replay without the server
+	rec := New(t, Config{Path: path})
+	resp := post(t, rec.Client(), server.URL+"/transfers", `{"amount":100}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, `{"received":"{\"amount\":100}","createdAt":"2006-01-02T15:04:05Z"}`, string(body))
+
+	// each interaction is replayed once
+	replay := New(&testing.T{}, Config{Path: path})
+	_, err = replay.Client().Post(server.URL+"/transfers", "application/json", strings.NewReader(`{"amount":200}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no recorded interaction for POST")
+}
+
+func post(t *testing.T, client *http.Client, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
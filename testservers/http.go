@@ -0,0 +1,221 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package testservers
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/randx"
+)
+
+// Route is a canned response of a Partner
+type Route struct {
+	// Method matches any method when empty
+	Method string
+
+	// Path is matched exactly, or as a prefix when it ends with "/"
+	Path string
+
+	// Status defaults to 200
+	Status int
+	Header http.Header
+
+	// Body is returned unless File is set, which is read on each request
+	Body []byte
+	File string
+
+	// Handler replaces the canned response when set
+	Handler http.HandlerFunc
+
+	// Latency and ErrorRate override the Partner's defaults for this route when set
+	Latency   time.Duration
+	ErrorRate float64
+}
+
+// PartnerConfig describes an HTTP partner stub from NewPartner
+type PartnerConfig struct {
+	Routes []Route
+
+	// Files are served to GET requests which don't match a route
+	Files fs.FS
+
+	// Latency delays every response. Requests cancelled by the client stop waiting.
+	Latency time.Duration
+
+	// ErrorRate is the fraction, between 0 and 1, of requests answered with ErrorStatus
+	// instead of their route's response.
+	ErrorRate float64
+
+	// ErrorStatus defaults to 503
+	ErrorStatus int
+
+	// Seed makes the requests chosen by ErrorRate repeatable
+	Seed int64
+}
+
+// RecordedRequest is a request received by a Partner
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Partner is an HTTP server answering with canned responses, injected latency and errors
+type Partner struct {
+	// URL is the base URL of the server, such as http://127.0.0.1:51234
+	URL string
+
+	server *httptest.Server
+	cfg    PartnerConfig
+	random randx.Source
+
+	mu       sync.Mutex
+	routes   []Route
+	requests []RecordedRequest
+}
+
+// NewPartner starts a partner stub which is closed when t finishes
+func NewPartner(t testing.TB, cfg PartnerConfig) *Partner {
+	t.Helper()
+
+	if cfg.ErrorStatus == 0 {
+		cfg.ErrorStatus = http.StatusServiceUnavailable
+	}
+	p := &Partner{
+		cfg:    cfg,
+		random: randx.NewSeeded(cfg.Seed),
+		routes: append([]Route(nil), cfg.Routes...),
+	}
+	p.server = httptest.NewServer(p)
+	p.URL = p.server.URL
+
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// Client returns an *http.Client for calling the server
+func (p *Partner) Client() *http.Client {
+	return p.server.Client()
+}
+
+// Handle adds route, taking precedence over earlier routes with the same method and path
+func (p *Partner) Handle(route Route) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.routes = append([]Route{route}, p.routes...)
+}
+
+// Requests returns every request received so far
+func (p *Partner) Requests() []RecordedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]RecordedRequest(nil), p.requests...)
+}
+
+func (p *Partner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	p.mu.Lock()
+	p.requests = append(p.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	route, found := p.match(r)
+	p.mu.Unlock()
+
+	latency, errorRate := p.cfg.Latency, p.cfg.ErrorRate
+	if found && route.Latency > 0 {
+		latency = route.Latency
+	}
+	if found && route.ErrorRate > 0 {
+		errorRate = route.ErrorRate
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if p.fail(errorRate) {
+		http.Error(w, http.StatusText(p.cfg.ErrorStatus), p.cfg.ErrorStatus)
+		return
+	}
+
+	if !found {
+		if p.cfg.Files != nil && r.Method == http.MethodGet {
+			http.FileServer(http.FS(p.cfg.Files)).ServeHTTP(w, r)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	route.serve(w, r)
+}
+
+// match returns the first route for r. p.mu must be held.
+func (p *Partner) match(r *http.Request) (Route, bool) {
+	for i := range p.routes {
+		route := p.routes[i]
+		if route.Method != "" && route.Method != r.Method {
+			continue
+		}
+		if route.Path == r.URL.Path || (strings.HasSuffix(route.Path, "/") && strings.HasPrefix(r.URL.Path, route.Path)) {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+func (p *Partner) fail(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	const scale = 1_000_000
+	return p.random.Int63n(scale) < int64(rate*scale)
+}
+
+func (route Route) serve(w http.ResponseWriter, r *http.Request) {
+	if route.Handler != nil {
+		route.Handler(w, r)
+		return
+	}
+
+	body := route.Body
+	if route.File != "" {
+		bs, err := os.ReadFile(route.File)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body = bs
+	}
+	for k, v := range route.Header {
+		w.Header()[k] = v
+	}
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package testservers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, p *Partner, path string) (int, string) {
+	t.Helper()
+
+	resp, err := p.Client().Get(p.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(bs)
+}
+
+func TestPartner(t *testing.T) {
+	dir := t.TempDir()
+	where := filepath.Join(dir, "statement.txt")
+	require.NoError(t, os.WriteFile(where, []byte("statement"), 0o600))
+
+	p := NewPartner(t, PartnerConfig{
+		Routes: []Route{
+			{Method: "POST", Path: "/transfers", Status: http.StatusCreated, Body: []byte(`{"id":"1"}`)},
+			{Method: "GET", Path: "/statements/", File: where},
+		},
+		Files: fstest.MapFS{
+			"files/returns.ach": &fstest.MapFile{Data: []byte("101 returns")},
+		},
+	})
+
+	resp, err := p.Client().Post(p.URL+"/transfers", "application/json", strings.NewReader(`{"amount":100}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, body := get(t, p, "/statements/2021-11")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "statement", body)
+
+	status, body = get(t, p, "/files/returns.ach")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "101 returns", body)
+
+	status, _ = get(t, p, "/missing")
+	require.Equal(t, http.StatusNotFound, status)
+
+	reqs := p.Requests()
+	require.Len(t, reqs, 4)
+	require.Equal(t, "POST", reqs[0].Method)
+	require.Equal(t, "/transfers", reqs[0].Path)
+	require.Equal(t, `{"amount":100}`, string(reqs[0].Body))
+	require.Equal(t, "application/json", reqs[0].Header.Get("Content-Type"))
+}
+
+func TestPartner__Handle(t *testing.T) {
+	p := NewPartner(t, PartnerConfig{
+		Routes: []Route{{Path: "/ping", Body: []byte("pong")}},
+	})
+	p.Handle(Route{
+		Path: "/ping",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		},
+	})
+
+	status, _ := get(t, p, "/ping")
+	require.Equal(t, http.StatusTeapot, status)
+}
+
+func TestPartner__ErrorRate(t *testing.T) {
+	p := NewPartner(t, PartnerConfig{
+		Routes:    []Route{{Path: "/ping", Body: []byte("pong")}},
+		ErrorRate: 0.5,
+		Seed:      10,
+	})
+
+	failures := 0
+	for i := 0; i < 200; i++ {
+		status, _ := get(t, p, "/ping")
+		if status == http.StatusServiceUnavailable {
+			failures++
+		}
+	}
+	require.InDelta(t, 100, failures, 30)
+
+	p.Handle(Route{Path: "/always", ErrorRate: 1})
+	status, _ := get(t, p, "/always")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestPartner__Latency(t *testing.T) {
+	p := NewPartner(t, PartnerConfig{
+		Routes: []Route{
+			{Path: "/slow", Latency: 50 * time.Millisecond},
+			{Path: "/stuck", Latency: time.Minute},
+		},
+	})
+
+	start := time.Now()
+	status, _ := get(t, p, "/slow")
+	require.Equal(t, http.StatusOK, status)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", p.URL+"/stuck", nil)
+	require.NoError(t, err)
+	_, err = p.Client().Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,290 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package testservers runs in-process stand-ins for partner endpoints so integration tests of
+// transmission code don't need Docker or real servers.
+//
+//	server := testservers.NewSFTP(t, testservers.SFTPConfig{
+//		Files: map[string][]byte{
+//			"outbound/returns.ach": returns,
+//		},
+//	})
+//	conn, err := ssh.Dial("tcp", server.Addr, server.ClientConfig())
+//	...
+//	uploaded, err := server.ReadFile("inbound/20211101.ach")
+//
+// Servers listen on a random localhost port and are stopped when the test finishes.
+package testservers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPConfig describes an SFTP server from NewSFTP
+type SFTPConfig struct {
+	// Username and Password are the accepted credentials. Both default to "test".
+	Username string
+	Password string
+
+	// Root is the directory served to clients. It defaults to a new temporary directory.
+	Root string
+
+	// Files are written beneath Root, creating parent directories, before the server starts.
+	// Keys are slash separated paths relative to Root.
+	Files map[string][]byte
+}
+
+// SFTPServer is an SFTP server backed by a local directory
+type SFTPServer struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	Username string
+	Password string
+
+	// Root is the directory served as "/"
+	Root string
+
+	// HostKey is the server's generated host key
+	HostKey ssh.PublicKey
+
+	listener net.Listener
+	config   *ssh.ServerConfig
+	wg       sync.WaitGroup
+}
+
+// NewSFTP starts an SFTP server which is closed when t finishes
+func NewSFTP(t testing.TB, cfg SFTPConfig) *SFTPServer {
+	t.Helper()
+
+	if cfg.Username == "" {
+		cfg.Username = "test"
+	}
+	if cfg.Password == "" {
+		cfg.Password = "test"
+	}
+	if cfg.Root == "" {
+		cfg.Root = t.TempDir()
+	}
+
+	server := &SFTPServer{
+		Username: cfg.Username,
+		Password: cfg.Password,
+		Root:     cfg.Root,
+	}
+	for name, data := range cfg.Files {
+		if err := server.WriteFile(name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("testservers: generating host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("testservers: host key: %v", err)
+	}
+	server.HostKey = signer.PublicKey()
+
+	server.config = &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == server.Username && string(password) == server.Password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials for %s", conn.User())
+		},
+	}
+	server.config.AddHostKey(signer)
+
+	server.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("testservers: listening: %v", err)
+	}
+	server.Addr = server.listener.Addr().String()
+
+	server.wg.Add(1)
+	go server.serve()
+
+	t.Cleanup(func() {
+		server.listener.Close()
+		server.wg.Wait()
+	})
+	return server
+}
+
+// ClientConfig returns an SSH client config with the server's credentials and host key
+func (s *SFTPServer) ClientConfig() *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            s.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(s.Password)},
+		HostKeyCallback: ssh.FixedHostKey(s.HostKey),
+	}
+}
+
+// Path returns the local path of name, a slash separated path relative to Root
+func (s *SFTPServer) Path(name string) string {
+	return filepath.Join(s.Root, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// WriteFile writes data to name beneath Root, creating parent directories
+func (s *SFTPServer) WriteFile(name string, data []byte) error {
+	where := s.Path(name)
+	if err := os.MkdirAll(filepath.Dir(where), 0o755); err != nil {
+		return fmt.Errorf("testservers: %v", err)
+	}
+	if err := os.WriteFile(where, data, 0o644); err != nil {
+		return fmt.Errorf("testservers: %v", err)
+	}
+	return nil
+}
+
+// ReadFile returns the contents of name beneath Root, such as a file uploaded by a client
+func (s *SFTPServer) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(s.Path(name))
+}
+
+func (s *SFTPServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *SFTPServer) handle(nc net.Conn) {
+	defer nc.Close()
+
+	conn, chans, reqs, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+			}
+		}()
+
+		h := &dirHandler{server: s}
+		server := sftp.NewRequestServer(channel, sftp.Handlers{
+			FileGet:  h,
+			FilePut:  h,
+			FileCmd:  h,
+			FileList: h,
+		})
+		if err := server.Serve(); err == io.EOF {
+			server.Close()
+		}
+		channel.Close()
+	}
+}
+
+// dirHandler serves SFTP requests from files beneath the server's Root
+type dirHandler struct {
+	server *SFTPServer
+}
+
+func (h *dirHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return os.Open(h.server.Path(r.Filepath))
+}
+
+func (h *dirHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	flags := os.O_WRONLY | os.O_CREATE
+	if r.Pflags().Trunc {
+		flags |= os.O_TRUNC
+	}
+	return os.OpenFile(h.server.Path(r.Filepath), flags, 0o644)
+}
+
+func (h *dirHandler) Filecmd(r *sftp.Request) error {
+	where := h.server.Path(r.Filepath)
+	switch r.Method {
+	case "Setstat":
+		if r.AttrFlags().Size {
+			return os.Truncate(where, int64(r.Attributes().Size))
+		}
+		return nil
+	case "Rename":
+		return os.Rename(where, h.server.Path(r.Target))
+	case "Rmdir", "Remove":
+		return os.Remove(where)
+	case "Mkdir":
+		return os.Mkdir(where, 0o755)
+	}
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+func (h *dirHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	where := h.server.Path(r.Filepath)
+	switch r.Method {
+	case "List":
+		entries, err := os.ReadDir(where)
+		if err != nil {
+			return nil, err
+		}
+		infos := make(listerAt, 0, len(entries))
+		for i := range entries {
+			info, err := entries[i].Info()
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	case "Stat":
+		info, err := os.Stat(where)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{info}, nil
+	}
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(ls, l[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package testservers
+
+import (
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func dialSFTP(t *testing.T, server *SFTPServer, cfg *ssh.ClientConfig) *sftp.Client {
+	t.Helper()
+
+	conn, err := ssh.Dial("tcp", server.Addr, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	client, err := sftp.NewClient(conn)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestSFTP(t *testing.T) {
+	server := NewSFTP(t, SFTPConfig{
+		Files: map[string][]byte{
+			"outbound/returns.ach": []byte("101 returns"),
+		},
+	})
+	client := dialSFTP(t, server, server.ClientConfig())
+
+	// download a canned file
+	fd, err := client.Open("/outbound/returns.ach")
+	require.NoError(t, err)
+	bs, err := io.ReadAll(fd)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	require.Equal(t, "101 returns", string(bs))
+
+	// upload a file
+	require.NoError(t, client.Mkdir("/inbound"))
+	fd, err = client.Create("/inbound/20211101.ach")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte("101 transfers"))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	bs, err = server.ReadFile("inbound/20211101.ach")
+	require.NoError(t, err)
+	require.Equal(t, "101 transfers", string(bs))
+
+	// list, rename and remove
+	require.NoError(t, client.Rename("/inbound/20211101.ach", "/inbound/done.ach"))
+	infos, err := client.ReadDir("/inbound")
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, "done.ach", infos[0].Name())
+
+	require.NoError(t, client.Remove("/inbound/done.ach"))
+	_, err = os.Stat(server.Path("inbound/done.ach"))
+	require.True(t, os.IsNotExist(err))
+
+	infos, err = client.ReadDir("/")
+	require.NoError(t, err)
+	var names []string
+	for i := range infos {
+		names = append(names, infos[i].Name())
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"inbound", "outbound"}, names)
+}
+
+func TestSFTP__Root(t *testing.T) {
+	server := NewSFTP(t, SFTPConfig{})
+	client := dialSFTP(t, server, server.ClientConfig())
+
+	// paths can't escape Root
+	fd, err := client.Create("/../../escape.txt")
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	_, err = os.Stat(server.Path("escape.txt"))
+	require.NoError(t, err)
+}
+
+func TestSFTP__InvalidPassword(t *testing.T) {
+	server := NewSFTP(t, SFTPConfig{
+		Username: "moov",
+		Password: "secret",
+	})
+
+	cfg := server.ClientConfig()
+	cfg.Auth = []ssh.AuthMethod{ssh.Password("wrong")}
+
+	_, err := ssh.Dial("tcp", server.Addr, cfg)
+	require.Error(t, err)
+}
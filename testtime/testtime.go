@@ -0,0 +1,119 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package testtime freezes base.Clock in tests and steps it across banking days, cutoffs and
+// daylight saving changes.
+//
+//	func TestSameDayCutoff(t *testing.T) {
+//		clock := testtime.Freeze(t, time.Date(2021, time.March, 12, 16, 0, 0, 0, eastern))
+//		clock.ToCutoff(16*time.Hour + 45*time.Minute) // 4:45pm
+//		...
+//		clock.AddBankingDays(1) // Monday the 15th, after the DST change
+//	}
+//
+// Tests using Freeze must not run in parallel with others reading base.Clock.
+package testtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/stime"
+)
+
+// Clock is a frozen clock installed as base.Clock. Steps are calculated in its location,
+// which defaults to America/New_York.
+type Clock struct {
+	stime.StaticTimeService
+
+	loc *time.Location
+}
+
+// Freeze replaces base.Clock with a Clock stopped at at until the test finishes.
+func Freeze(t testing.TB, at time.Time) *Clock {
+	t.Helper()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("testtime: %v", err)
+	}
+	static := stime.NewStaticTimeService()
+	static.Change(at)
+	clock := &Clock{StaticTimeService: static, loc: loc}
+
+	previous := base.Clock
+	base.Clock = clock
+	t.Cleanup(func() {
+		base.Clock = previous
+	})
+	return clock
+}
+
+// In sets the location used for steps
+func (c *Clock) In(loc *time.Location) *Clock {
+	c.loc = loc
+	return c
+}
+
+// Set moves the clock to at
+func (c *Clock) Set(at time.Time) time.Time {
+	return c.Change(at)
+}
+
+// AddBankingDays moves the clock forward n banking days, or back when n is negative, keeping
+// the wall clock time. Zero moves to the next banking day unless today is one.
+func (c *Clock) AddBankingDays(n int) time.Time {
+	now := c.Now().In(c.loc)
+	day := base.DateOf(now).AddBankingDays(n)
+	return c.Change(c.onDay(day, now))
+}
+
+// ToCutoff moves the clock forward to the next time the wall clock reads cutoff past midnight,
+// which may be later today.
+func (c *Clock) ToCutoff(cutoff time.Duration) time.Time {
+	now := c.Now().In(c.loc)
+	day := base.DateOf(now)
+	at := time.Date(day.Year, day.Month, day.Day, 0, 0, 0, int(cutoff), c.loc)
+	if at.Before(now) {
+		day = day.AddDays(1)
+		at = time.Date(day.Year, day.Month, day.Day, 0, 0, 0, int(cutoff), c.loc)
+	}
+	return c.Change(at)
+}
+
+// AcrossDST moves the clock to the instant its location's UTC offset next changes, returning
+// the new time. The clock isn't moved when there's no change within two years.
+func (c *Clock) AcrossDST() time.Time {
+	now := c.Now()
+	_, offset := now.In(c.loc).Zone()
+
+	// find the hour the offset changes, then the exact instant
+	lo := now
+	for i := 0; i < 2*366*24; i++ {
+		next := lo.Add(time.Hour)
+		if _, o := next.In(c.loc).Zone(); o != offset {
+			for hi := next; hi.Sub(lo) > time.Second; {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.In(c.loc).Zone(); o != offset {
+					hi = mid
+				} else {
+					lo = mid
+				}
+			}
+			at := lo.Truncate(time.Second)
+			for _, o := at.In(c.loc).Zone(); o == offset; _, o = at.In(c.loc).Zone() {
+				at = at.Add(time.Second)
+			}
+			return c.Change(at.In(c.loc))
+		}
+		lo = next
+	}
+	return now
+}
+
+// onDay returns the time on day with the wall clock of now
+func (c *Clock) onDay(day base.Date, now time.Time) time.Time {
+	return time.Date(day.Year, day.Month, day.Day, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), c.loc)
+}
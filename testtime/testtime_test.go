@@ -0,0 +1,75 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package testtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base"
+)
+
+var eastern, _ = time.LoadLocation("America/New_York")
+
+func TestFreeze(t *testing.T) {
+	at := time.Date(2021, time.March, 12, 16, 0, 0, 0, eastern)
+
+	t.Run("frozen", func(t *testing.T) {
+		clock := Freeze(t, at)
+		require.True(t, base.Now().Time.Equal(at))
+
+		clock.Add(time.Minute)
+		require.True(t, base.Now().Time.Equal(at.Add(time.Minute)))
+
+		// ProcessingDate rolls over at the cutoff
+		cutoff := 16*time.Hour + 45*time.Minute
+		require.Equal(t, base.NewDate(2021, time.March, 12), base.ProcessingDate(base.Now(), cutoff, eastern))
+		clock.ToCutoff(cutoff)
+		require.Equal(t, "2021-03-12 16:45:00 EST", clock.Now().In(eastern).Format("2006-01-02 15:04:05 MST"))
+		require.Equal(t, base.NewDate(2021, time.March, 15), base.ProcessingDate(base.Now(), cutoff, eastern))
+
+		// Monday keeps the wall clock across the DST change
+		clock.AddBankingDays(1)
+		require.Equal(t, "2021-03-15 16:45:00 EDT", clock.Now().In(eastern).Format("2006-01-02 15:04:05 MST"))
+
+		// earlier today's cutoff moves to tomorrow
+		clock.ToCutoff(9 * time.Hour)
+		require.Equal(t, "2021-03-16 09:00:00 EDT", clock.Now().In(eastern).Format("2006-01-02 15:04:05 MST"))
+	})
+
+	// restored after the test
+	require.WithinDuration(t, time.Now(), base.Now().Time, 2*time.Second)
+}
+
+func TestAcrossDST(t *testing.T) {
+	clock := Freeze(t, time.Date(2021, time.October, 1, 12, 0, 0, 0, eastern))
+
+	after := clock.AcrossDST()
+	require.Equal(t, "2021-11-07 01:00:00 EST", after.Format("2006-01-02 15:04:05 MST"))
+
+	after = clock.AcrossDST()
+	require.Equal(t, "2022-03-13 03:00:00 EDT", after.Format("2006-01-02 15:04:05 MST"))
+
+	clock.In(time.UTC)
+	require.Equal(t, after, clock.AcrossDST())
+}
+
+func TestAddBankingDays(t *testing.T) {
+	// Independence Day 2021 was a Sunday, observed on Monday the 5th
+	clock := Freeze(t, time.Date(2021, time.July, 2, 10, 0, 0, 0, eastern))
+
+	clock.AddBankingDays(1)
+	require.Equal(t, base.NewDate(2021, time.July, 6), base.DateOf(clock.Now().In(eastern)))
+	require.Equal(t, 10, clock.Now().In(eastern).Hour())
+
+	clock.AddBankingDays(-1)
+	require.Equal(t, base.NewDate(2021, time.July, 2), base.DateOf(clock.Now().In(eastern)))
+
+	clock.Set(time.Date(2021, time.July, 4, 10, 0, 0, 0, eastern))
+	clock.AddBankingDays(0)
+	require.Equal(t, base.NewDate(2021, time.July, 6), base.DateOf(clock.Now().In(eastern)))
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LeakyBucket spaces events evenly at a Rate with no bursts. Up to capacity events may be
+// waiting for their turn before more are refused. It's safe for concurrent use.
+type LeakyBucket struct {
+	interval time.Duration
+	capacity int
+	now      func() time.Time
+
+	mu   sync.Mutex
+	next time.Time // when the next event may happen
+}
+
+var _ Limiter = (*LeakyBucket)(nil)
+
+// NewLeakyBucket returns an empty LeakyBucket. capacity is raised to 1 when lower.
+func NewLeakyBucket(rate Rate, capacity int) *LeakyBucket {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LeakyBucket{
+		interval: rate.interval(),
+		capacity: capacity,
+		now:      time.Now,
+	}
+}
+
+// Allow returns true if an event may happen now without waiting
+func (b *LeakyBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if b.next.After(now) {
+		return false
+	}
+	b.next = now.Add(b.interval)
+	return true
+}
+
+// Reserve schedules an event after those already waiting. The Reservation isn't OK when
+// capacity events are already waiting.
+func (b *LeakyBucket) Reserve() *Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	at := b.next
+	if at.Before(now) {
+		at = now
+	}
+	if at.Sub(now) > time.Duration(b.capacity)*b.interval {
+		return &Reservation{now: b.now}
+	}
+	b.next = at.Add(b.interval)
+
+	return &Reservation{
+		ok:  true,
+		at:  at,
+		now: b.now,
+		cancel: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			// only the latest reservation can give its turn back without reordering others
+			if b.next.Equal(at.Add(b.interval)) {
+				b.next = at
+			}
+		},
+	}
+}
+
+// Wait blocks until the event's turn or ctx is done. ErrExceedsBurst is returned when
+// capacity events are already waiting.
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return wait(ctx, b.Reserve())
+}
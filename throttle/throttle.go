@@ -0,0 +1,111 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package throttle implements rate limiters for pacing work such as partner API calls and
+// SFTP uploads.
+//
+//	limiter := throttle.NewTokenBucket(throttle.Per(10, time.Second), 5) // 10/s with bursts of 5
+//	for _, req := range requests {
+//		if err := limiter.Wait(ctx); err != nil {
+//			return err
+//		}
+//		send(req)
+//	}
+//
+// A TokenBucket allows bursts after idle periods while a LeakyBucket spaces every event evenly.
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rate is a number of events per second
+type Rate float64
+
+// Per returns the Rate of n events every d
+func Per(n int, d time.Duration) Rate {
+	return Rate(float64(n) / d.Seconds())
+}
+
+// interval returns the time between events at r
+func (r Rate) interval() time.Duration {
+	return time.Duration(float64(time.Second) / float64(r))
+}
+
+// ErrExceedsBurst is returned when more tokens are asked for than a limiter can ever hold
+var ErrExceedsBurst = errors.New("throttle: request exceeds burst")
+
+// Limiter is implemented by TokenBucket and LeakyBucket
+type Limiter interface {
+	// Allow reports whether an event may happen now, taking its token if so
+	Allow() bool
+
+	// Reserve takes a token which is usable after the Reservation's Delay
+	Reserve() *Reservation
+
+	// Wait blocks until an event may happen or ctx is done
+	Wait(ctx context.Context) error
+}
+
+// Reservation is permission for an event after Delay. Cancel it if the event won't happen so
+// others aren't delayed.
+type Reservation struct {
+	ok     bool
+	at     time.Time
+	now    func() time.Time
+	cancel func()
+}
+
+// OK reports whether the reservation was granted. Tokens beyond a limiter's capacity can't
+// be reserved.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns how long to wait before acting on the reservation
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	if d := r.at.Sub(r.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Cancel returns the reservation's tokens when it hasn't been reached yet
+func (r *Reservation) Cancel() {
+	if r.ok && r.cancel != nil && r.Delay() > 0 {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// wait sleeps for r, cancelling it if ctx is done first or can't last long enough
+func wait(ctx context.Context, r *Reservation) error {
+	if !r.OK() {
+		return ErrExceedsBurst
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(r.at) {
+		r.Cancel()
+		return fmt.Errorf("throttle: waiting %v would exceed context deadline", delay)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestPer(t *testing.T) {
+	require.Equal(t, Rate(10), Per(600, time.Minute))
+	require.Equal(t, 100*time.Millisecond, Per(10, time.Second).interval())
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewTokenBucket(Per(10, time.Second), 3)
+	b.now = clock.Now
+
+	// the burst is available at once
+	require.True(t, b.Allow())
+	require.True(t, b.Allow())
+	require.True(t, b.Allow())
+	require.False(t, b.Allow())
+
+	// one token refills every 100ms
+	clock.Add(100 * time.Millisecond)
+	require.True(t, b.Allow())
+	require.False(t, b.Allow())
+
+	// tokens don't build past the burst
+	clock.Add(time.Hour)
+	require.True(t, b.AllowN(3))
+	require.False(t, b.AllowN(1))
+}
+
+func TestTokenBucket__Reserve(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewTokenBucket(Per(10, time.Second), 1)
+	b.now = clock.Now
+
+	r := b.Reserve()
+	require.True(t, r.OK())
+	require.Equal(t, time.Duration(0), r.Delay())
+
+	r = b.Reserve()
+	require.Equal(t, 100*time.Millisecond, r.Delay())
+	r2 := b.Reserve()
+	require.Equal(t, 200*time.Millisecond, r2.Delay())
+
+	// cancelling returns the tokens
+	r2.Cancel()
+	r.Cancel()
+	clock.Add(100 * time.Millisecond)
+	require.True(t, b.Allow())
+
+	require.False(t, b.ReserveN(2).OK())
+	require.ErrorIs(t, b.WaitN(context.Background(), 2), ErrExceedsBurst)
+}
+
+func TestTokenBucket__Wait(t *testing.T) {
+	b := NewTokenBucket(Per(100, time.Second), 1)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, b.Wait(context.Background()))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond)
+
+	// waits which can't finish before the deadline return at once
+	b = NewTokenBucket(Per(1, time.Minute), 1)
+	require.True(t, b.Allow())
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+	require.ErrorContains(t, b.Wait(ctx), "exceed context deadline")
+
+	// and give their token back
+	b.now = func() time.Time { return time.Now().Add(time.Minute) }
+	require.True(t, b.Allow())
+}
+
+func TestLeakyBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewLeakyBucket(Per(10, time.Second), 2)
+	b.now = clock.Now
+
+	require.True(t, b.Allow())
+	require.False(t, b.Allow()) // no bursts
+
+	clock.Add(100 * time.Millisecond)
+	require.True(t, b.Allow())
+
+	// events are spaced evenly until capacity are waiting
+	r1 := b.Reserve()
+	require.Equal(t, 100*time.Millisecond, r1.Delay())
+	r2 := b.Reserve()
+	require.Equal(t, 200*time.Millisecond, r2.Delay())
+	require.False(t, b.Reserve().OK())
+
+	// the last reservation gives its turn back
+	r2.Cancel()
+	r3 := b.Reserve()
+	require.Equal(t, 200*time.Millisecond, r3.Delay())
+}
+
+func TestLeakyBucket__Wait(t *testing.T) {
+	b := NewLeakyBucket(Per(100, time.Second), 10)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, b.Wait(context.Background()))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	require.ErrorIs(t, b.Wait(ctx), context.Canceled)
+}
+
+func TestWriter(t *testing.T) {
+	bucket := NewTokenBucket(Per(1000, time.Second), 10)
+
+	var buf bytes.Buffer
+	w := NewWriter(context.Background(), &buf, bucket)
+
+	start := time.Now()
+	n, err := w.Write([]byte(strings.Repeat("a", 50)))
+	require.NoError(t, err)
+	require.Equal(t, 50, n)
+	require.Equal(t, 50, buf.Len())
+	require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond) // 40 bytes beyond the burst
+}
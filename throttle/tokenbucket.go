@@ -0,0 +1,128 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket refills tokens at a Rate up to its burst. Events take a token, so after an idle
+// period up to burst events happen at once. It's safe for concurrent use.
+type TokenBucket struct {
+	rate  Rate
+	burst int
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// NewTokenBucket returns a full TokenBucket. burst is raised to 1 when lower.
+func NewTokenBucket(rate Rate, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  burst,
+		now:    time.Now,
+		tokens: float64(burst),
+	}
+}
+
+// Burst returns the most tokens the bucket holds
+func (b *TokenBucket) Burst() int {
+	return b.burst
+}
+
+// advance refills tokens for the time since the last call. b.mu must be held.
+func (b *TokenBucket) advance(now time.Time) {
+	if !b.last.IsZero() {
+		if elapsed := now.Sub(b.last); elapsed > 0 {
+			b.tokens += elapsed.Seconds() * float64(b.rate)
+		}
+	}
+	if max := float64(b.burst); b.tokens > max {
+		b.tokens = max
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+}
+
+// Allow is AllowN(1)
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN takes n tokens and returns true if they're available now
+func (b *TokenBucket) AllowN(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(b.now())
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// Reserve is ReserveN(1)
+func (b *TokenBucket) Reserve() *Reservation {
+	return b.ReserveN(1)
+}
+
+// ReserveN takes n tokens, which may leave the bucket in debt until it refills. The
+// Reservation isn't OK when n is more than the burst.
+func (b *TokenBucket) ReserveN(n int) *Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if n > b.burst {
+		return &Reservation{now: b.now}
+	}
+	b.advance(now)
+	b.tokens -= float64(n)
+
+	at := now
+	if b.tokens < 0 {
+		at = now.Add(time.Duration(-b.tokens / float64(b.rate) * float64(time.Second)))
+	}
+	return &Reservation{
+		ok:  true,
+		at:  at,
+		now: b.now,
+		cancel: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.advance(b.now())
+			b.tokens += float64(n)
+			if max := float64(b.burst); b.tokens > max {
+				b.tokens = max
+			}
+		},
+	}
+}
+
+// Wait is WaitN(ctx, 1)
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return b.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are taken or ctx is done. It returns immediately, without taking
+// tokens, when ctx's deadline would pass first.
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return wait(ctx, b.ReserveN(n))
+}
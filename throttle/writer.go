@@ -0,0 +1,44 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package throttle
+
+import (
+	"context"
+	"io"
+)
+
+// Writer limits writes to w at one byte per token of a TokenBucket, such as for uploading files
+// without saturating a partner's link.
+type Writer struct {
+	ctx    context.Context
+	w      io.Writer
+	bucket *TokenBucket
+}
+
+// NewWriter returns a Writer which stops waiting and returns ctx's error once it's done
+func NewWriter(ctx context.Context, w io.Writer, bucket *TokenBucket) *Writer {
+	return &Writer{ctx: ctx, w: w, bucket: bucket}
+}
+
+// Write writes p in chunks of at most the bucket's burst, waiting for tokens before each
+func (w *Writer) Write(p []byte) (int, error) {
+	var total int
+	for len(p) > 0 {
+		n := len(p)
+		if burst := w.bucket.Burst(); n > burst {
+			n = burst
+		}
+		if err := w.bucket.WaitN(w.ctx, n); err != nil {
+			return total, err
+		}
+		n, err := w.w.Write(p[:n])
+		total += n
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+	}
+	return total, nil
+}
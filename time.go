@@ -7,7 +7,7 @@ package base
 import (
 	"time"
 
-	"github.com/rickar/cal"
+	"github.com/moov-io/base/stime"
 )
 
 const (
@@ -28,64 +28,66 @@ const (
 //
 // Holiday Schedule: https://www.frbservices.org/holidayschedules/
 //
+// Holidays are read from the schedule embedded in this package (see FederalReserveCalendar).
+// IsBankingDay and AddBankingDay compute holidays from rules for dates outside of it, which may
+// miss holidays declared after the schedule was generated. CheckBankingDay returns a
+// HolidayRangeError for those dates instead.
+//
 // All logic is based on ET(Eastern) time as defined by the Federal Reserve
 // https://www.frbservices.org/operations/fedwire/fedwire_hours.html
 type Time struct {
 	time.Time
-
-	cal *cal.Calendar
 }
 
+// Clock is the source of the current time for Now. Tests can replace it with testtime.Freeze.
+var Clock stime.TimeService = stime.NewSystemTimeService()
+
 // Now returns a Time object with the current clock time set.
 // By default, America/New_York will be the chosen time zone.
 func Now() Time {
-	// Create our calendar to attach on Time
-	calendar := cal.NewCalendar()
-	cal.AddUsHolidays(calendar)
-	calendar.Observed = cal.ObservedMonday
-
 	return Time{
-		cal:  calendar,
-		Time: time.Now().UTC().Truncate(1 * time.Second),
+		Time: Clock.Now().UTC().Truncate(1 * time.Second),
 	}
 }
 
 // NewTime wraps a time.Time value in Moov's base.Time struct.
 // If you need the underlying time.Time value call .Time:
 //
-// The time zone will be changed to DefaultLocation.
+// The time zone will be changed to UTC. Use ParseACHDate to read the date and time fields of ACH files.
 //
 // now := Now()
 // fmt.Println(start.Sub(now.Time))
 func NewTime(t time.Time) Time {
-	tt := Now()
-	tt.Time = t.UTC() // overwrite underlying Time
-	return tt
+	return Time{Time: t.UTC()}
 }
 
 // MarshalJSON returns JSON for the given Time
 func (t Time) MarshalJSON() ([]byte, error) {
-	var bs []byte
-	bs = append(bs, '"')
-
-	t.Time = t.Time.Truncate(1 * time.Second) // drop milliseconds
-	bs = t.AppendFormat(bs, ISO8601Format)
+	return t.AppendJSON(make([]byte, 0, len(jsonTimeFormat))), nil
+}
 
-	bs = append(bs, '"')
-	return bs, nil
+// AppendJSON appends the Time as a quoted ISO 8601 string to dst and returns the extended
+// buffer. It doesn't allocate when dst has room, so encoders writing many timestamps can
+// reuse (or pool) their buffers.
+func (t Time) AppendJSON(dst []byte) []byte {
+	dst = append(dst, '"')
+	dst = t.Time.Truncate(1*time.Second).AppendFormat(dst, ISO8601Format) // drop milliseconds
+	return append(dst, '"')
 }
 
-// UnmarshalJSON unpacks a JSON string to populate a Time instance
+// jsonTimeFormat is ISO8601Format quoted as a JSON string
+const jsonTimeFormat = `"` + ISO8601Format + `"`
+
+// UnmarshalJSON unpacks a JSON string to populate a Time instance. Strings which aren't in
+// ISO 8601 (which matches Go's RFC 3339 layout) are read as the zero Time.
 func (t *Time) UnmarshalJSON(data []byte) error {
 	// Ignore null, like in the main JSON package.
 	if string(data) == "null" {
 		return nil
 	}
-	tt, err := time.Parse(`"`+ISO8601Format+`"`, string(data))
-	if err != nil || tt.IsZero() {
-		// Try in RFC3339 format (default Go time)
-		tt, _ = time.Parse(time.RFC3339, string(data))
-		*t = NewTime(tt)
+	tt, err := time.Parse(jsonTimeFormat, string(data))
+	if err != nil {
+		tt = time.Time{}
 	}
 
 	t.Time = tt.UTC().Truncate(1 * time.Second) // convert to UTC and drop millis
@@ -102,21 +104,15 @@ func (t Time) Equal(other Time) bool {
 }
 
 // IsBankingDay checks the rules around holidays (i.e. weekends) to determine if the given day is a banking day.
+// Results are cached for each year, so checking many dates doesn't repeat the calendar math.
 func (t Time) IsBankingDay() bool {
-	// if date is not a weekend and not a holiday it is banking day.
-	if t.IsWeekend() {
-		return false
-	}
-	// and not a holiday
-	if t.cal.IsHoliday(t.Time) {
-		return false
-	}
-	// and not a monday after a holiday
-	if t.Time.Weekday() == time.Monday {
-		sun := t.Time.AddDate(0, 0, -1)
-		return !t.cal.IsHoliday(sun)
-	}
-	return true
+	return isBankingDay(DateOf(t.Time))
+}
+
+// CheckBankingDay reports whether the given day is a banking day from the embedded holiday schedule.
+// A HolidayRangeError is returned for days outside of it.
+func (t Time) CheckBankingDay() (bool, error) {
+	return DateOf(t.Time).CheckBankingDay()
 }
 
 // AddBankingDay takes an integer for the number of valid banking days to add and returns a Time
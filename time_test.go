@@ -47,6 +47,21 @@ func TestTime__NewTime(t *testing.T) {
 	fmt.Println(start.Sub(now.Time))
 }
 
+func TestTime__AppendJSON(t *testing.T) {
+	t1 := NewTime(time.Date(2021, time.November, 1, 14, 4, 5, 123, time.UTC))
+
+	buf := []byte(`{"created":`)
+	buf = t1.AppendJSON(buf)
+	if v := string(buf); v != `{"created":"2021-11-01T14:04:05Z"` {
+		t.Errorf("unexpected JSON: %s", v)
+	}
+
+	bs, _ := t1.MarshalJSON()
+	if v := string(bs); v != `"2021-11-01T14:04:05Z"` {
+		t.Errorf("unexpected JSON: %s", v)
+	}
+}
+
 func TestTime__JSON(t *testing.T) {
 	// marshal and then unmarshal
 	t1 := Now()
@@ -0,0 +1,144 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package timing implements a Stopwatch for measuring the phases of an operation.
+//
+//	sw := timing.Start()
+//	defer sw.ObserveInto(fileProcessingDuration)
+//
+//	file, err := parse(r)
+//	sw.Segment("parse")
+//
+//	err = validate(file)
+//	sw.Segment("validate")
+//
+// Each Lap records when it started, so ExportInto can send laps to a tracer as child spans.
+// A Stopwatch also implements log.Context to add its timings onto log lines.
+package timing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	"github.com/moov-io/base/log"
+)
+
+// Lap is a named segment of time recorded by a Stopwatch
+type Lap struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Stopwatch measures the total time of an operation along with named laps.
+// It's safe for concurrent use.
+type Stopwatch struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	start time.Time
+	last  time.Time
+	laps  []Lap
+}
+
+var _ log.Context = (*Stopwatch)(nil)
+
+// Start returns a running Stopwatch
+func Start() *Stopwatch {
+	return start(time.Now)
+}
+
+func start(now func() time.Time) *Stopwatch {
+	t := now()
+	return &Stopwatch{
+		now:   now,
+		start: t,
+		last:  t,
+	}
+}
+
+// Segment records a Lap named name covering the time since the previous Segment (or Start).
+// The Lap's duration is returned.
+func (sw *Stopwatch) Segment(name string) time.Duration {
+	now := sw.now()
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	lap := Lap{
+		Name:     name,
+		Start:    sw.last,
+		Duration: now.Sub(sw.last),
+	}
+	sw.laps = append(sw.laps, lap)
+	sw.last = now
+	return lap.Duration
+}
+
+// Elapsed returns the time since the Stopwatch was started
+func (sw *Stopwatch) Elapsed() time.Duration {
+	return sw.now().Sub(sw.start)
+}
+
+// Laps returns each recorded Lap in order
+func (sw *Stopwatch) Laps() []Lap {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	out := make([]Lap, len(sw.laps))
+	copy(out, sw.laps)
+	return out
+}
+
+// ObserveInto records the elapsed time in seconds into h
+func (sw *Stopwatch) ObserveInto(h metrics.Histogram) {
+	if h == nil {
+		return
+	}
+	h.Observe(sw.Elapsed().Seconds())
+}
+
+// ObserveSegmentsInto records each Lap in seconds into h with a "segment" label of the Lap's name
+func (sw *Stopwatch) ObserveSegmentsInto(h metrics.Histogram) {
+	if h == nil {
+		return
+	}
+	for _, lap := range sw.Laps() {
+		h.With("segment", lap.Name).Observe(lap.Duration.Seconds())
+	}
+}
+
+// SpanRecorder receives laps as spans. Adapters for a tracing library usually start a child span
+// of the current trace at start and end it at end, such as with OpenTelemetry:
+//
+//	span := trace.SpanFromContext(ctx)
+//	_, child := span.TracerProvider().Tracer("timing").Start(ctx, name, trace.WithTimestamp(start))
+//	child.End(trace.WithTimestamp(end))
+type SpanRecorder interface {
+	RecordSpan(name string, start, end time.Time)
+}
+
+// ExportInto sends each Lap to r as a span
+func (sw *Stopwatch) ExportInto(r SpanRecorder) {
+	if r == nil {
+		return
+	}
+	for _, lap := range sw.Laps() {
+		r.RecordSpan(lap.Name, lap.Start, lap.Start.Add(lap.Duration))
+	}
+}
+
+// Context returns the elapsed time and each Lap (as duration_<name>) for logging
+func (sw *Stopwatch) Context() map[string]log.Valuer {
+	laps := sw.Laps()
+	kv := make(map[string]log.Valuer, len(laps)+1)
+	kv["elapsed"] = log.TimeDuration(sw.Elapsed())
+	for _, lap := range laps {
+		kv[fmt.Sprintf("duration_%s", lap.Name)] = log.TimeDuration(lap.Duration)
+	}
+	return kv
+}
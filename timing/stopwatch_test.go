@@ -0,0 +1,109 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package timing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base/log"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestStopwatch(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)}
+	sw := start(clock.Now)
+
+	clock.Add(2 * time.Second)
+	require.Equal(t, 2*time.Second, sw.Segment("parse"))
+
+	clock.Add(500 * time.Millisecond)
+	require.Equal(t, 500*time.Millisecond, sw.Segment("validate"))
+
+	clock.Add(time.Second)
+	require.Equal(t, 3500*time.Millisecond, sw.Elapsed())
+
+	laps := sw.Laps()
+	require.Len(t, laps, 2)
+	require.Equal(t, "parse", laps[0].Name)
+	require.Equal(t, clock.now.Add(-3500*time.Millisecond), laps[0].Start)
+	require.Equal(t, "validate", laps[1].Name)
+	require.Equal(t, clock.now.Add(-1500*time.Millisecond), laps[1].Start)
+
+	hist := generic.NewHistogram("file_processing_seconds", 10)
+	sw.ObserveInto(hist)
+	require.Equal(t, 3.5, hist.Quantile(0.5))
+
+	segments := generic.NewHistogram("file_processing_segment_seconds", 10)
+	sw.ObserveSegmentsInto(segments)
+
+	sw.ObserveInto(nil)
+	sw.ObserveSegmentsInto(nil)
+}
+
+type recordedSpan struct {
+	name       string
+	start, end time.Time
+}
+
+type spanRecorder []recordedSpan
+
+func (r *spanRecorder) RecordSpan(name string, start, end time.Time) {
+	*r = append(*r, recordedSpan{name: name, start: start, end: end})
+}
+
+func TestStopwatch__ExportInto(t *testing.T) {
+	started := time.Date(2020, time.December, 1, 10, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: started}
+	sw := start(clock.Now)
+
+	clock.Add(time.Second)
+	sw.Segment("parse")
+	clock.Add(2 * time.Second)
+	sw.Segment("upload")
+
+	var spans spanRecorder
+	sw.ExportInto(&spans)
+	require.Equal(t, spanRecorder{
+		{name: "parse", start: started, end: started.Add(time.Second)},
+		{name: "upload", start: started.Add(time.Second), end: started.Add(3 * time.Second)},
+	}, spans)
+
+	sw.ExportInto(nil)
+}
+
+func TestStopwatch__Log(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	sw := start(clock.Now)
+
+	clock.Add(time.Second)
+	sw.Segment("upload")
+
+	buf, logger := log.NewBufferLogger()
+	logger.With(sw).Log("processed file")
+
+	require.Contains(t, buf.String(), "duration_upload=1s")
+	require.Contains(t, buf.String(), "elapsed=1s")
+}
+
+func TestStart(t *testing.T) {
+	sw := Start()
+	sw.Segment("a")
+	require.True(t, sw.Elapsed() >= 0)
+}
@@ -0,0 +1,239 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package tlsutil implements helpers for managing TLS certificates.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	kitprom "github.com/go-kit/kit/metrics/prometheus"
+	stdprom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/log"
+)
+
+var (
+	certificateExpiryDays = kitprom.NewGaugeFrom(stdprom.GaugeOpts{
+		Name: "tls_certificate_expiry_days",
+		Help: "Days until a watched TLS certificate expires",
+	}, []string{"source", "subject"})
+)
+
+const (
+	defaultWatchInterval  = time.Hour
+	defaultWatchThreshold = 14 * 24 * time.Hour
+
+	dialTimeout = 10 * time.Second
+)
+
+// WatcherConfig lists the certificates a Watcher inspects
+type WatcherConfig struct {
+	// Files are paths to PEM encoded certificates. Every certificate in a file is watched.
+	Files []string
+
+	// Endpoints are host:port addresses whose served certificate is watched
+	Endpoints []string
+
+	// Interval is how often certificates are inspected. It defaults to one hour.
+	Interval time.Duration
+
+	// Threshold is how close to expiring a certificate is before OnExpiring is called.
+	// It defaults to 14 days.
+	Threshold time.Duration
+
+	// OnExpiring is called on each check for every certificate expiring within Threshold
+	OnExpiring func(Expiry)
+
+	// Logger records errors reading certificates and expiring certificates
+	Logger log.Logger
+}
+
+// Expiry describes when a watched certificate expires
+type Expiry struct {
+	// Source is the file or endpoint the certificate was read from
+	Source   string
+	Subject  string
+	NotAfter time.Time
+}
+
+// Remaining returns the time left before the certificate expires at now. It's negative for
+// expired certificates.
+func (e Expiry) Remaining(now time.Time) time.Duration {
+	return e.NotAfter.Sub(now)
+}
+
+// Days returns how many days remain before the certificate expires at now
+func (e Expiry) Days(now time.Time) float64 {
+	return e.Remaining(now).Hours() / 24
+}
+
+// Watcher periodically inspects certificates and records days until expiry in the
+// tls_certificate_expiry_days gauge.
+type Watcher struct {
+	cfg WatcherConfig
+	now func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher returns a Watcher of cfg's certificates. Call Start to begin watching.
+func NewWatcher(cfg WatcherConfig) *Watcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultWatchInterval
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaultWatchThreshold
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewNopLogger()
+	}
+	return &Watcher{
+		cfg:  cfg,
+		now:  time.Now,
+		stop: make(chan struct{}),
+	}
+}
+
+// Start checks certificates immediately and then every Interval until Stop is called.
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			w.Check()
+			select {
+			case <-ticker.C:
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the checks started by Start. It's safe to call multiple times.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+// Check inspects each certificate once, updating the gauge and calling OnExpiring for those within
+// the threshold. Certificates which couldn't be read are returned as a base.ErrorList.
+func (w *Watcher) Check() ([]Expiry, error) {
+	var expiries []Expiry
+	var errs base.ErrorList
+
+	for _, path := range w.cfg.Files {
+		certs, err := readCertificateFile(path)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		expiries = append(expiries, expiriesOf(path, certs)...)
+	}
+	for _, addr := range w.cfg.Endpoints {
+		certs, err := fetchCertificates(addr)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		// only the leaf, intermediates are the partner's CA's concern
+		expiries = append(expiries, expiriesOf(addr, certs[:1])...)
+	}
+
+	now := w.now()
+	for _, exp := range expiries {
+		certificateExpiryDays.With("source", exp.Source, "subject", exp.Subject).Set(exp.Days(now))
+
+		if exp.Remaining(now) < w.cfg.Threshold {
+			w.cfg.Logger.Warn().With(log.Fields{
+				"source":   log.String(exp.Source),
+				"subject":  log.String(exp.Subject),
+				"notAfter": log.Time(exp.NotAfter),
+			}).Logf("certificate expires in %.1f days", exp.Days(now))
+
+			if w.cfg.OnExpiring != nil {
+				w.cfg.OnExpiring(exp)
+			}
+		}
+	}
+	for i := range errs {
+		w.cfg.Logger.Error().LogError(errs[i])
+	}
+
+	if errs.Empty() {
+		return expiries, nil
+	}
+	return expiries, errs
+}
+
+func expiriesOf(source string, certs []*x509.Certificate) []Expiry {
+	out := make([]Expiry, 0, len(certs))
+	for _, cert := range certs {
+		out = append(out, Expiry{
+			Source:   source,
+			Subject:  cert.Subject.String(),
+			NotAfter: cert.NotAfter,
+		})
+	}
+	return out
+}
+
+func readCertificateFile(path string) ([]*x509.Certificate, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tlsutil: %v", err)
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, bs = pem.Decode(bs)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("tlsutil: parsing %s: %v", path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("tlsutil: no certificates found in %s", path)
+	}
+	return certs, nil
+}
+
+func fetchCertificates(addr string) ([]*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		// expiring (or expired) certificates should still be reported
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tlsutil: %v", err)
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, errors.New("tlsutil: no certificates sent by " + addr)
+	}
+	return certs, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base"
+)
+
+func writeCertificates(t *testing.T, notAfter ...time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	for i := range notAfter {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 1)),
+			Subject:      pkix.Name{CommonName: "partner-" + string(rune('a'+i))},
+			NotBefore:    notAfter[i].Add(-365 * 24 * time.Hour),
+			NotAfter:     notAfter[i],
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		require.NoError(t, err)
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	path := filepath.Join(t.TempDir(), "chain.pem")
+	require.NoError(t, os.WriteFile(path, []byte(buf.String()), 0600))
+	return path
+}
+
+func TestWatcher__Check(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	path := writeCertificates(t, now.Add(5*24*time.Hour), now.Add(60*24*time.Hour))
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	endpoint := strings.TrimPrefix(server.URL, "https://")
+
+	var expiring []Expiry
+	w := NewWatcher(WatcherConfig{
+		Files:     []string{path},
+		Endpoints: []string{endpoint},
+		OnExpiring: func(exp Expiry) {
+			expiring = append(expiring, exp)
+		},
+	})
+	w.now = func() time.Time { return now }
+
+	expiries, err := w.Check()
+	require.NoError(t, err)
+	require.Len(t, expiries, 3)
+	require.Equal(t, "CN=partner-a", expiries[0].Subject)
+	require.Equal(t, path, expiries[0].Source)
+	require.InDelta(t, 5.0, expiries[0].Days(now), 0.01)
+	require.InDelta(t, 60.0, expiries[1].Days(now), 0.01)
+	require.Equal(t, endpoint, expiries[2].Source)
+
+	require.Len(t, expiring, 1)
+	require.Equal(t, "CN=partner-a", expiring[0].Subject)
+}
+
+func TestWatcher__Errors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0600))
+
+	w := NewWatcher(WatcherConfig{
+		Files: []string{"missing.pem", empty},
+	})
+	expiries, err := w.Check()
+	require.Empty(t, expiries)
+
+	var errs base.ErrorList
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	require.Contains(t, errs[1].Error(), "no certificates found")
+}
+
+func TestWatcher__Start(t *testing.T) {
+	path := writeCertificates(t, time.Now().Add(time.Hour))
+
+	var mu sync.Mutex
+	var calls int
+	w := NewWatcher(WatcherConfig{
+		Files:    []string{path},
+		Interval: 10 * time.Millisecond,
+		OnExpiring: func(exp Expiry) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+		},
+	})
+	w.Start()
+	defer w.Stop()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	w.Stop()
+	w.Stop()
+}
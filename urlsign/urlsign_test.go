@@ -0,0 +1,85 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package urlsign
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("shh")
+
+func TestSign(t *testing.T) {
+	link, err := Sign("https://api.moov.io/reports/123.csv?format=csv", time.Now().Add(time.Hour), secret)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(link, "https://api.moov.io/reports/123.csv?"))
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.NoError(t, Check(u, secret))
+	require.ErrorIs(t, Check(u, []byte("other")), ErrInvalidSignature)
+
+	// altering the path or query
+	altered := *u
+	altered.Path = "/reports/124.csv"
+	require.ErrorIs(t, Check(&altered, secret), ErrInvalidSignature)
+
+	query := u.Query()
+	query.Set("format", "json")
+	altered = *u
+	altered.RawQuery = query.Encode()
+	require.ErrorIs(t, Check(&altered, secret), ErrInvalidSignature)
+
+	query = u.Query()
+	query.Set(expiresParam, "9999999999")
+	altered = *u
+	altered.RawQuery = query.Encode()
+	require.ErrorIs(t, Check(&altered, secret), ErrInvalidSignature)
+
+	// re-signing replaces the signature
+	again, err := Sign(link, time.Now().Add(time.Minute), secret)
+	require.NoError(t, err)
+	u, err = url.Parse(again)
+	require.NoError(t, err)
+	require.Len(t, u.Query()[signatureParam], 1)
+	require.NoError(t, Check(u, secret))
+
+	_, err = Sign("https://api.moov.io/", time.Now(), nil)
+	require.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	handler := Verify(secret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("report"))
+	}))
+
+	serve := func(link string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", link, nil))
+		return w
+	}
+
+	link, err := Sign("/reports/123.csv", time.Now().Add(time.Hour), secret)
+	require.NoError(t, err)
+	w := serve(link)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "report", w.Body.String())
+
+	link, err = Sign("/reports/123.csv", time.Now().Add(-time.Second), secret)
+	require.NoError(t, err)
+	w = serve(link)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.JSONEq(t, `{"error":"link has expired"}`, w.Body.String())
+
+	w = serve("/reports/123.csv")
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.JSONEq(t, `{"error":"link signature is invalid"}`, w.Body.String())
+}
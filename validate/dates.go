@@ -0,0 +1,86 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DateLimit bounds how far a date may be from today, such as an entry's effective date
+type DateLimit struct {
+	// Days is how many days away a date may be
+	Days int
+
+	// BankingDays counts Days as banking days, skipping weekends and holidays
+	BankingDays bool
+
+	// Now is today's date, it defaults to base.Now(). Dates are compared in Eastern time.
+	Now base.Time
+}
+
+// DateRangeError is returned when a date is further away than a DateLimit allows
+type DateRangeError struct {
+	Date base.Date
+
+	// Limit is the earliest (when backdated) or latest allowed date
+	Limit base.Date
+
+	Backdated   bool
+	Days        int
+	BankingDays bool
+}
+
+func (e *DateRangeError) Error() string {
+	unit := "days"
+	if e.BankingDays {
+		unit = "banking days"
+	}
+	if e.Backdated {
+		return fmt.Sprintf("must not be more than %d %s in the past (before %s)", e.Days, unit, e.Limit)
+	}
+	return fmt.Sprintf("must not be more than %d %s in the future (after %s)", e.Days, unit, e.Limit)
+}
+
+// MaxBackdate returns a *DateRangeError when t is before the date limit.Days before today
+func MaxBackdate(t base.Time, limit DateLimit) error {
+	today, date := limit.dates(t)
+	earliest := today.AddDays(-limit.Days)
+	if limit.BankingDays {
+		earliest = today.AddBankingDays(-limit.Days)
+	}
+	if date.Before(earliest) {
+		return &DateRangeError{Date: date, Limit: earliest, Backdated: true, Days: limit.Days, BankingDays: limit.BankingDays}
+	}
+	return nil
+}
+
+// MaxFuturedate returns a *DateRangeError when t is after the date limit.Days after today
+func MaxFuturedate(t base.Time, limit DateLimit) error {
+	today, date := limit.dates(t)
+	latest := today.AddDays(limit.Days)
+	if limit.BankingDays {
+		latest = today.AddBankingDays(limit.Days)
+	}
+	if date.After(latest) {
+		return &DateRangeError{Date: date, Limit: latest, Days: limit.Days, BankingDays: limit.BankingDays}
+	}
+	return nil
+}
+
+// dates returns today and the date of t in Eastern time
+func (l DateLimit) dates(t base.Time) (base.Date, base.Date) {
+	now := l.Now
+	if now.IsZero() {
+		now = base.Now()
+	}
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	return base.DateOf(now.Time.In(loc)), base.DateOf(t.Time.In(loc))
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBackdate(t *testing.T) {
+	// Thursday 2020-11-12, the day after Veterans Day
+	now := base.NewTime(time.Date(2020, time.November, 12, 12, 0, 0, 0, time.UTC))
+	day := func(d int) base.Time {
+		return base.NewTime(time.Date(2020, time.November, d, 15, 0, 0, 0, time.UTC))
+	}
+
+	banking := DateLimit{Days: 2, BankingDays: true, Now: now}
+	require.NoError(t, MaxBackdate(day(12), banking))
+	require.NoError(t, MaxBackdate(day(9), banking))
+
+	err := MaxBackdate(day(6), banking)
+	var rangeErr *DateRangeError
+	require.True(t, errors.As(err, &rangeErr))
+	require.True(t, rangeErr.Backdated)
+	require.Equal(t, base.NewDate(2020, time.November, 9), rangeErr.Limit)
+	require.Equal(t, "must not be more than 2 banking days in the past (before 2020-11-09)", err.Error())
+
+	calendar := DateLimit{Days: 2, Now: now}
+	require.NoError(t, MaxBackdate(day(10), calendar))
+	require.Error(t, MaxBackdate(day(9), calendar))
+
+	// in a Validator
+	v := New()
+	v.Field("effectiveDate", MaxBackdate(day(6), banking))
+	require.Equal(t, "/effectiveDate: must not be more than 2 banking days in the past (before 2020-11-09)", v.Err().Error())
+}
+
+func TestMaxFuturedate(t *testing.T) {
+	now := base.NewTime(time.Date(2020, time.November, 10, 12, 0, 0, 0, time.UTC))
+	day := func(d int) base.Time {
+		return base.NewTime(time.Date(2020, time.November, d, 15, 0, 0, 0, time.UTC))
+	}
+
+	banking := DateLimit{Days: 2, BankingDays: true, Now: now}
+	require.NoError(t, MaxFuturedate(day(13), banking))
+
+	err := MaxFuturedate(day(16), banking)
+	var rangeErr *DateRangeError
+	require.True(t, errors.As(err, &rangeErr))
+	require.False(t, rangeErr.Backdated)
+	require.Equal(t, base.NewDate(2020, time.November, 13), rangeErr.Limit)
+	require.Equal(t, "must not be more than 2 banking days in the future (after 2020-11-13)", err.Error())
+
+	require.Error(t, MaxFuturedate(day(13), DateLimit{Days: 2, Now: now}))
+
+	// dates are compared in Eastern time, 3am UTC is still the previous day
+	late := base.NewTime(time.Date(2020, time.November, 14, 3, 0, 0, 0, time.UTC))
+	require.NoError(t, MaxFuturedate(late, banking))
+
+	require.NoError(t, MaxFuturedate(base.Now(), DateLimit{}))
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"regexp"
+)
+
+var routingNumberRe = regexp.MustCompile(`^\d{9}$`)
+
+// RoutingNumber returns an error unless s is a nine digit ABA routing number with a valid check digit
+func RoutingNumber(s string) error {
+	if !routingNumberRe.MatchString(s) {
+		return errors.New("must be 9 digits")
+	}
+	if RoutingCheckDigit(s) != int(s[8]-'0') {
+		return errors.New("has an invalid check digit")
+	}
+	return nil
+}
+
+// RoutingCheckDigit returns the ABA check digit of the first eight digits of a routing number
+func RoutingCheckDigit(digits string) int {
+	weights := []int{3, 7, 1, 3, 7, 1, 3, 7}
+	sum := 0
+	for i := 0; i < len(weights) && i < len(digits); i++ {
+		sum += int(digits[i]-'0') * weights[i]
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package validate implements helpers for validating request and domain structs. Errors are
+// collected into a base.ErrorList of FieldErrors whose paths are JSON pointers into the struct's
+// JSON form.
+//
+//	func (c CreateTransfer) Validate() error {
+//		v := validate.New()
+//		v.Field("description", validate.Required(c.Description), validate.Length(c.Description, 1, 80))
+//		v.Field("speed", validate.OneOf(c.Speed, "standard", "same-day"))
+//		v.Nested("amount", c.Amount)
+//		validate.Each(v, "postings", c.Postings)
+//		return v.Err()
+//	}
+//
+// A missing currency would be returned as "/amount/currency: is required".
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moov-io/base"
+)
+
+var (
+	// ErrRequired is returned by Required for zero values
+	ErrRequired = errors.New("is required")
+)
+
+// Validatable is implemented by structs which check their own fields
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError is a validation error of the value at Path, a JSON pointer (RFC 6901) such as
+// "/postings/0/amount".
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying rule error
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as {"path": "...", "error": "..."}
+func (e FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"path":  e.Path,
+		"error": e.Err.Error(),
+	})
+}
+
+// Validator collects the errors of a struct's fields
+type Validator struct {
+	errs base.ErrorList
+}
+
+// New returns an empty Validator
+func New() *Validator {
+	return &Validator{}
+}
+
+// Field records the first non-nil error of rules against name. Later rules are ignored once one
+// fails so a missing value isn't also reported as too short.
+func (v *Validator) Field(name string, rules ...error) {
+	for _, err := range rules {
+		if err != nil {
+			v.errs.Add(FieldError{Path: "/" + escape(name), Err: err})
+			return
+		}
+	}
+}
+
+// Nested validates value and records its errors beneath name. Nil values are skipped, use
+// Required to reject them.
+func (v *Validator) Nested(name string, value Validatable) {
+	v.nested("/"+escape(name), value)
+}
+
+func (v *Validator) nested(path string, value Validatable) {
+	if isNil(value) {
+		return
+	}
+	v.add(path, value.Validate())
+}
+
+// Each validates every item of a slice, recording errors beneath name and the item's index.
+func Each[T Validatable](v *Validator, name string, items []T) {
+	for i := range items {
+		v.nested(fmt.Sprintf("/%s/%d", escape(name), i), items[i])
+	}
+}
+
+// add records err with each FieldError's path prefixed by prefix
+func (v *Validator) add(prefix string, err error) {
+	if err == nil {
+		return
+	}
+	var list base.ErrorList
+	if errors.As(err, &list) {
+		for i := range list {
+			v.add(prefix, list[i])
+		}
+		return
+	}
+	var fe FieldError
+	if errors.As(err, &fe) {
+		v.errs.Add(FieldError{Path: prefix + fe.Path, Err: fe.Err})
+		return
+	}
+	v.errs.Add(FieldError{Path: prefix, Err: err})
+}
+
+// Errors returns each recorded FieldError
+func (v *Validator) Errors() base.ErrorList {
+	return v.errs
+}
+
+// Err returns the recorded errors as a base.ErrorList, or nil when every field was valid.
+func (v *Validator) Err() error {
+	if v.errs.Empty() {
+		return nil
+	}
+	return v.errs
+}
+
+// escape encodes a reference token of a JSON pointer
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func isNil(value Validatable) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// Required returns ErrRequired when value is the zero value of its type
+func Required[T comparable](value T) error {
+	var zero T
+	if value == zero {
+		return ErrRequired
+	}
+	return nil
+}
+
+// Length returns an error unless s has between min and max characters (runes). A max of zero
+// means there's no upper limit.
+func Length(s string, min, max int) error {
+	n := utf8.RuneCountInString(s)
+	if n < min {
+		return fmt.Errorf("must be at least %d characters", min)
+	}
+	if max > 0 && n > max {
+		return fmt.Errorf("must be at most %d characters", max)
+	}
+	return nil
+}
+
+// OneOf returns an error unless value is one of allowed
+func OneOf[T comparable](value T, allowed ...T) error {
+	for i := range allowed {
+		if value == allowed[i] {
+			return nil
+		}
+	}
+	vals := make([]string, len(allowed))
+	for i := range allowed {
+		vals[i] = fmt.Sprintf("%v", allowed[i])
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(vals, ", "))
+}
+
+// Match returns an error unless s matches re
+func Match(s string, re *regexp.Regexp) error {
+	if !re.MatchString(s) {
+		return fmt.Errorf("must match %s", re.String())
+	}
+	return nil
+}
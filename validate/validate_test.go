@@ -0,0 +1,137 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/base"
+)
+
+type amount struct {
+	Value    int64
+	Currency string
+}
+
+func (a amount) Validate() error {
+	v := New()
+	v.Field("value", Required(a.Value))
+	v.Field("currency", Required(a.Currency), OneOf(a.Currency, "USD", "CAD"))
+	return v.Err()
+}
+
+type posting struct {
+	Account string
+	Amount  *amount
+}
+
+func (p posting) Validate() error {
+	if p.Account == "closed" {
+		return errors.New("account is closed")
+	}
+	v := New()
+	v.Field("account", Match(p.Account, regexp.MustCompile(`^[0-9]{4,17}$`)))
+	v.Nested("amount", p.Amount)
+	return v.Err()
+}
+
+type transfer struct {
+	Description string
+	Amount      amount
+	Postings    []posting
+	Metadata    map[string]string
+}
+
+func (t transfer) Validate() error {
+	v := New()
+	v.Field("description", Required(t.Description), Length(t.Description, 3, 10))
+	v.Nested("amount", t.Amount)
+	Each(v, "postings", t.Postings)
+	for k := range t.Metadata {
+		v.Field(k, Length(t.Metadata[k], 0, 5))
+	}
+	return v.Err()
+}
+
+func TestValidate(t *testing.T) {
+	valid := transfer{
+		Description: "payroll",
+		Amount:      amount{Value: 100, Currency: "USD"},
+		Postings: []posting{
+			{Account: "123456789", Amount: &amount{Value: 100, Currency: "USD"}},
+			{Account: "987654321"},
+		},
+	}
+	require.NoError(t, valid.Validate())
+
+	invalid := transfer{
+		Amount: amount{Value: 100, Currency: "EUR"},
+		Postings: []posting{
+			{Account: "12", Amount: &amount{Currency: "USD"}},
+			{Account: "closed"},
+		},
+		Metadata: map[string]string{"a/b~c": "too long"},
+	}
+	err := invalid.Validate()
+
+	var list base.ErrorList
+	require.ErrorAs(t, err, &list)
+
+	var paths []string
+	for i := range list {
+		var fe FieldError
+		require.ErrorAs(t, list[i], &fe)
+		paths = append(paths, fe.Error())
+	}
+	require.Equal(t, []string{
+		"/description: is required",
+		"/amount/currency: must be one of USD, CAD",
+		"/postings/0/account: must match ^[0-9]{4,17}$",
+		"/postings/0/amount/value: is required",
+		"/postings/1: account is closed",
+		"/a~1b~0c: must be at most 5 characters",
+	}, paths)
+
+	require.True(t, errors.Is(list[0], ErrRequired))
+
+	bs, err := json.Marshal(list[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"path":"/description","error":"is required"}`, string(bs))
+}
+
+func TestRules(t *testing.T) {
+	require.NoError(t, Required(1))
+	require.Equal(t, ErrRequired, Required(""))
+	require.Equal(t, ErrRequired, Required[*amount](nil))
+
+	require.NoError(t, Length("ñandú", 5, 5))
+	require.EqualError(t, Length("ab", 3, 0), "must be at least 3 characters")
+	require.NoError(t, Length("a long description", 3, 0))
+
+	require.NoError(t, OneOf(2, 1, 2, 3))
+	require.EqualError(t, OneOf(4, 1, 2, 3), "must be one of 1, 2, 3")
+
+	require.NoError(t, Match("abc", regexp.MustCompile("^[a-z]+$")))
+	require.Error(t, Match("ABC", regexp.MustCompile("^[a-z]+$")))
+
+	require.NoError(t, RoutingNumber("121000358"))
+	require.EqualError(t, RoutingNumber("121000359"), "has an invalid check digit")
+	require.EqualError(t, RoutingNumber("12100035"), "must be 9 digits")
+	require.Equal(t, 8, RoutingCheckDigit("12100035"))
+}
+
+func TestValidator__Empty(t *testing.T) {
+	v := New()
+	v.Field("name", nil, nil)
+	v.Nested("amount", (*amount)(nil))
+	v.Nested("other", nil)
+	require.NoError(t, v.Err())
+	require.True(t, v.Errors().Empty())
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package velocity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+)
+
+type event struct {
+	at     time.Time
+	amount base.Amount
+}
+
+// MemoryStore keeps events in memory, which suits a single instance or tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	events map[string][]event
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		events: make(map[string][]event),
+	}
+}
+
+func (s *MemoryStore) Totals(ctx context.Context, key, currency string, now time.Time, windows []time.Duration) (Totals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.totals(key, currency, now, windows), nil
+}
+
+func (s *MemoryStore) totals(key, currency string, now time.Time, windows []time.Duration) Totals {
+	return totals(currency, now, windows, func(yield func(time.Time, int64)) {
+		for _, e := range s.events[key] {
+			if e.amount.Currency == currency {
+				yield(e.at, e.amount.Value)
+			}
+		}
+	})
+}
+
+func (s *MemoryStore) Add(ctx context.Context, key string, amount base.Amount, now time.Time, windows []time.Duration, check CheckFunc) (Totals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := s.totals(key, amount.Currency, now, windows)
+	for i := range out {
+		out[i].Count++
+		out[i].Sum.Value += amount.Value
+	}
+	if check != nil {
+		if err := check(out); err != nil {
+			return out, err
+		}
+	}
+	s.events[key] = append(s.events[key], event{at: now, amount: amount})
+	return out, nil
+}
+
+func (s *MemoryStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, events := range s.events {
+		kept := events[:0]
+		for _, e := range events {
+			if e.at.Before(before) {
+				deleted++
+			} else {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.events, key)
+		} else {
+			s.events[key] = kept
+		}
+	}
+	return deleted, nil
+}
+
+// SQLStore keeps events in tables shared by every instance of a service. The tables are created
+// by the service's migrations, where the second is locked to make Add atomic:
+//
+//	CREATE TABLE velocity (
+//	    event_key VARCHAR(128) NOT NULL,
+//	    currency CHAR(3) NOT NULL,
+//	    value BIGINT NOT NULL,
+//	    created_at BIGINT NOT NULL
+//	);
+//	CREATE INDEX velocity_key_created_at ON velocity (event_key, created_at);
+//
+//	CREATE TABLE velocity_keys (
+//	    event_key VARCHAR(128) NOT NULL PRIMARY KEY,
+//	    updated_at BIGINT NOT NULL
+//	);
+//
+// created_at and updated_at hold Unix nanoseconds. Queries use ? placeholders for MySQL and SQLite.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a Store using table and table+"_keys" in db
+func NewSQLStore(db *sql.DB, table string) *SQLStore {
+	return &SQLStore{
+		db:    db,
+		table: table,
+	}
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (s *SQLStore) Totals(ctx context.Context, key, currency string, now time.Time, windows []time.Duration) (Totals, error) {
+	return s.totals(ctx, s.db, key, currency, now, windows)
+}
+
+func (s *SQLStore) totals(ctx context.Context, q querier, key, currency string, now time.Time, windows []time.Duration) (Totals, error) {
+	var longest time.Duration
+	for _, w := range windows {
+		if w > longest {
+			longest = w
+		}
+	}
+
+	query := fmt.Sprintf(`SELECT created_at, value FROM %s WHERE event_key = ? AND currency = ? AND created_at > ?`, s.table)
+	rows, err := q.QueryContext(ctx, query, key, currency, now.Add(-longest).UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("velocity: reading %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	var scanErr error
+	out := totals(currency, now, windows, func(yield func(time.Time, int64)) {
+		for rows.Next() {
+			var at, value int64
+			if scanErr = rows.Scan(&at, &value); scanErr != nil {
+				return
+			}
+			yield(time.Unix(0, at), value)
+		}
+		scanErr = rows.Err()
+	})
+	if scanErr != nil {
+		return nil, fmt.Errorf("velocity: reading %s: %w", key, scanErr)
+	}
+	return out, nil
+}
+
+func (s *SQLStore) Add(ctx context.Context, key string, amount base.Amount, now time.Time, windows []time.Duration, check CheckFunc) (Totals, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("velocity: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.lock(ctx, tx, key, now); err != nil {
+		return nil, fmt.Errorf("velocity: locking %s: %w", key, err)
+	}
+
+	out, err := s.totals(ctx, tx, key, amount.Currency, now, windows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Count++
+		out[i].Sum.Value += amount.Value
+	}
+	if check != nil {
+		if err := check(out); err != nil {
+			return out, err
+		}
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (event_key, currency, value, created_at) VALUES (?, ?, ?, ?)`, s.table)
+	if _, err := tx.ExecContext(ctx, query, key, amount.Currency, amount.Value, now.UnixNano()); err != nil {
+		return nil, fmt.Errorf("velocity: recording %s: %w", key, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("velocity: %w", err)
+	}
+	return out, nil
+}
+
+// lock updates the row of key so concurrent adds wait for this one, inserting it for new keys
+func (s *SQLStore) lock(ctx context.Context, tx *sql.Tx, key string, now time.Time) error {
+	update := fmt.Sprintf(`UPDATE %s_keys SET updated_at = ? WHERE event_key = ?`, s.table)
+	res, err := tx.ExecContext(ctx, update, now.UnixNano(), key)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s_keys (event_key, updated_at) VALUES (?, ?)`, s.table)
+	_, err = tx.ExecContext(ctx, insert, key, now.UnixNano())
+	if err == nil || !database.UniqueViolation(err) {
+		return err
+	}
+	// another add inserted the key first, so wait on its row instead
+	_, err = tx.ExecContext(ctx, update, now.UnixNano(), key)
+	return err
+}
+
+func (s *SQLStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE created_at < ?`, s.table)
+	res, err := s.db.ExecContext(ctx, query, before.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("velocity: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package velocity counts events and sums their amounts per key over sliding windows, such as
+// how many transfers a customer originated in the last hour, day and week.
+//
+//	counter := velocity.New(velocity.NewSQLStore(db, "velocity"))
+//
+//	totals, err := counter.Add(ctx, "customer:"+customerID, xfer.Amount, func(totals velocity.Totals) error {
+//		day, _ := totals.Window(24 * time.Hour)
+//		if day.Count > 20 {
+//			return errTooManyTransfers
+//		}
+//		return nil
+//	})
+//
+// Add checks and records an event atomically, so concurrent transfers can't both pass a check
+// which only one of them should. Totals are kept per key and currency.
+package velocity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// DefaultWindows are the windows of New when none are given
+var DefaultWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// Total is the count and sum of events within a window
+type Total struct {
+	Window time.Duration `json:"window"`
+	Count  int64         `json:"count"`
+	Sum    base.Amount   `json:"sum"`
+}
+
+// Totals are the Total of each window
+type Totals []Total
+
+// Window returns the Total of window
+func (ts Totals) Window(window time.Duration) (Total, bool) {
+	for _, t := range ts {
+		if t.Window == window {
+			return t, true
+		}
+	}
+	return Total{}, false
+}
+
+// CheckFunc decides whether an event is recorded given the Totals including it. Returning an
+// error leaves the event out and the error is returned from Add.
+type CheckFunc func(totals Totals) error
+
+// Store saves events. Add must check and record an event atomically.
+type Store interface {
+	Totals(ctx context.Context, key, currency string, now time.Time, windows []time.Duration) (Totals, error)
+	Add(ctx context.Context, key string, amount base.Amount, now time.Time, windows []time.Duration, check CheckFunc) (Totals, error)
+
+	// DeleteBefore removes events older than before
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Counter tracks events over its windows
+type Counter struct {
+	store   Store
+	windows []time.Duration
+	longest time.Duration
+	now     func() time.Time
+}
+
+// New returns a Counter over windows, or DefaultWindows when none are given
+func New(store Store, windows ...time.Duration) *Counter {
+	if len(windows) == 0 {
+		windows = DefaultWindows
+	}
+	c := &Counter{
+		store:   store,
+		windows: append([]time.Duration(nil), windows...),
+		now:     time.Now,
+	}
+	for _, w := range windows {
+		if w > c.longest {
+			c.longest = w
+		}
+	}
+	return c
+}
+
+// Totals returns the events of key in currency within each window
+func (c *Counter) Totals(ctx context.Context, key, currency string) (Totals, error) {
+	return c.store.Totals(ctx, key, base.NewAmount(0, currency).Currency, c.now(), c.windows)
+}
+
+// Add records an event of amount for key when check, if it's not nil, accepts the Totals
+// including it. The Totals are returned either way.
+func (c *Counter) Add(ctx context.Context, key string, amount base.Amount, check CheckFunc) (Totals, error) {
+	if key == "" {
+		return nil, errors.New("velocity: missing key")
+	}
+	amount = base.NewAmount(amount.Value, amount.Currency)
+	return c.store.Add(ctx, key, amount, c.now(), c.windows, check)
+}
+
+// Prune deletes events older than the longest window, which no longer count towards any Total.
+// Call it periodically, such as from a jobs.Pool.
+func (c *Counter) Prune(ctx context.Context) (int64, error) {
+	return c.store.DeleteBefore(ctx, c.now().Add(-c.longest))
+}
+
+// totals sums events into windows, where each event is inside a window when it happened after
+// now minus the window.
+func totals(currency string, now time.Time, windows []time.Duration, events func(yield func(at time.Time, value int64))) Totals {
+	out := make(Totals, len(windows))
+	for i, w := range windows {
+		out[i] = Total{Window: w, Sum: base.NewAmount(0, currency)}
+	}
+	events(func(at time.Time, value int64) {
+		for i, w := range windows {
+			if at.After(now.Add(-w)) {
+				out[i].Count++
+				out[i].Sum.Value += value
+			}
+		}
+	})
+	return out
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package velocity
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+	"github.com/moov-io/base/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sqliteStore(t *testing.T) Store {
+	t.Helper()
+
+	db := database.CreateTestSQLiteDB(t)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	return NewSQLStore(db.DB, "velocity")
+}
+
+func stores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqliteStore(t),
+	}
+}
+
+func TestCounter(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			counter := New(store)
+
+			now := time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC)
+			counter.now = func() time.Time { return now }
+
+			add := func(ago time.Duration, value int64) {
+				now = now.Add(-ago)
+				_, err := counter.Add(ctx, "customer:1", base.NewAmount(value, "USD"), nil)
+				require.NoError(t, err)
+				now = now.Add(ago)
+			}
+			add(30*time.Minute, 100)
+			add(2*time.Hour, 200)
+			add(3*24*time.Hour, 400)
+			add(8*24*time.Hour, 800)
+
+			totals, err := counter.Totals(ctx, "customer:1", "usd")
+			require.NoError(t, err)
+			require.Equal(t, Totals{
+				{Window: time.Hour, Count: 1, Sum: base.NewAmount(100, "USD")},
+				{Window: 24 * time.Hour, Count: 2, Sum: base.NewAmount(300, "USD")},
+				{Window: 7 * 24 * time.Hour, Count: 3, Sum: base.NewAmount(700, "USD")},
+			}, totals)
+
+			// other keys and currencies are separate
+			totals, err = counter.Totals(ctx, "customer:2", "USD")
+			require.NoError(t, err)
+			day, ok := totals.Window(24 * time.Hour)
+			require.True(t, ok)
+			require.Equal(t, int64(0), day.Count)
+
+			totals, err = counter.Totals(ctx, "customer:1", "EUR")
+			require.NoError(t, err)
+			require.Equal(t, int64(0), totals[2].Count)
+
+			deleted, err := counter.Prune(ctx)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), deleted)
+		})
+	}
+}
+
+func TestCounter__Check(t *testing.T) {
+	errTooMany := errors.New("too many transfers")
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			counter := New(store, time.Hour)
+
+			check := func(totals Totals) error {
+				if hour, _ := totals.Window(time.Hour); hour.Count > 5 {
+					return errTooMany
+				}
+				return nil
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			var accepted, rejected int
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := counter.Add(ctx, "customer:1", base.NewAmount(100, "USD"), check)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if errors.Is(err, errTooMany) {
+						rejected++
+					} else {
+						require.NoError(t, err)
+						accepted++
+					}
+				}()
+			}
+			wg.Wait()
+			require.Equal(t, 5, accepted)
+			require.Equal(t, 5, rejected)
+
+			// rejected events aren't recorded
+			totals, err := counter.Totals(ctx, "customer:1", "USD")
+			require.NoError(t, err)
+			require.Equal(t, base.NewAmount(500, "USD"), totals[0].Sum)
+
+			_, err = counter.Add(ctx, "", base.NewAmount(100, "USD"), nil)
+			require.Error(t, err)
+		})
+	}
+}
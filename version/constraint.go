@@ -0,0 +1,204 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Constraint is a set of version ranges. Ranges are separated by "||" and each is one or more
+// comparisons separated by commas or spaces, all of which must match.
+//
+//	"1.2.3"       exactly 1.2.3, "=1.2.3" is the same
+//	">=1.4, <2"   comparison operators >, >=, <, <= and !=, ">= 1.4" is the same
+//	"^1.2"        compatible releases, >=1.2.0 <2.0.0 (^0.2 is >=0.2.0 <0.3.0)
+//	"~1.2.3"      patch releases, >=1.2.3 <1.3.0
+//	"1.x"         any 1.x.y release, as does "1" or "1.*"
+//
+// Prerelease versions only match ranges containing a comparison on a prerelease of the same
+// major, minor and patch numbers, so "^1.2" doesn't match "1.3.0-rc.1".
+type Constraint struct {
+	raw    string
+	ranges [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version Version
+
+	// upper is set for != of a partial version, which excludes [version, upper)
+	upper *Version
+}
+
+func (c comparison) matches(v Version) bool {
+	n := v.Compare(c.version)
+	switch c.op {
+	case "!=":
+		if c.upper != nil {
+			return n < 0 || v.Compare(*c.upper) >= 0
+		}
+		return n != 0
+	case "=":
+		return n == 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	}
+	return false
+}
+
+// operatorSpace matches an operator followed by spaces, which are dropped before splitting terms
+var operatorSpace = regexp.MustCompile(`(>=|<=|!=|[><=^~])\s+`)
+
+// ParseConstraint reads a Constraint
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+	for _, part := range strings.Split(s, "||") {
+		part = operatorSpace.ReplaceAllString(part, "$1")
+		var cmps []comparison
+		for _, term := range strings.FieldsFunc(part, func(r rune) bool { return r == ',' || r == ' ' }) {
+			expanded, err := parseTerm(term)
+			if err != nil {
+				return Constraint{}, err
+			}
+			cmps = append(cmps, expanded...)
+		}
+		if len(cmps) == 0 {
+			return Constraint{}, fmt.Errorf("version: empty constraint in %q", s)
+		}
+		c.ranges = append(c.ranges, cmps)
+	}
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics on invalid constraints.
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// parseTerm expands an operator and (partial) version into plain comparisons
+func parseTerm(term string) ([]comparison, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op = prefix
+			break
+		}
+	}
+	v, parts, err := parse(strings.TrimPrefix(term, op), op == "" || op == "=")
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []comparison{{op: "=", version: v}}, nil
+		}
+		return between(v, next(v, parts)), nil
+
+	case "^":
+		var upper Version
+		switch {
+		case v.Major > 0 || parts == 1:
+			upper = Version{Major: v.Major + 1}
+		case v.Minor > 0 || parts == 2:
+			upper = Version{Minor: v.Minor + 1}
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return between(v, upper), nil
+
+	case "~":
+		if parts == 1 {
+			return between(v, Version{Major: v.Major + 1}), nil
+		}
+		return between(v, Version{Major: v.Major, Minor: v.Minor + 1}), nil
+
+	case ">":
+		if parts < 3 {
+			return []comparison{{op: ">=", version: next(v, parts)}}, nil
+		}
+	case "<=":
+		if parts < 3 {
+			return []comparison{{op: "<", version: next(v, parts)}}, nil
+		}
+	case "!=":
+		if parts < 3 {
+			upper := next(v, parts)
+			return []comparison{{op: "!=", version: v, upper: &upper}}, nil
+		}
+	}
+	return []comparison{{op: op, version: v}}, nil
+}
+
+func between(lower, upper Version) []comparison {
+	return []comparison{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+// next returns the first version after every version matching the first parts of v
+func next(v Version, parts int) Version {
+	switch parts {
+	case 0:
+		return Version{Major: 1 << 30}
+	case 1:
+		return Version{Major: v.Major + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// Check returns true when v satisfies the Constraint
+func (c Constraint) Check(v Version) bool {
+	for _, cmps := range c.ranges {
+		if matchesAll(cmps, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(cmps []comparison, v Version) bool {
+	prereleaseAllowed := v.Prerelease == ""
+	for _, cmp := range cmps {
+		if !cmp.matches(v) {
+			return false
+		}
+		cv := cmp.version
+		if cv.Prerelease != "" && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// MarshalText encodes the Constraint as it was parsed
+func (c Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.raw), nil
+}
+
+// UnmarshalText parses a Constraint
+func (c *Constraint) UnmarshalText(data []byte) error {
+	parsed, err := ParseConstraint(string(data))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
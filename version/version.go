@@ -0,0 +1,234 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package version implements parsing and comparison of semantic versions (https://semver.org)
+// along with constraints such as "^1.2" or ">=1.4.0, <2".
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version
+type Version struct {
+	Major, Minor, Patch int
+
+	// Prerelease is the dot separated identifiers after "-", i.e. "rc.1"
+	Prerelease string
+
+	// Build is the metadata after "+", it's ignored when comparing versions.
+	Build string
+}
+
+// Parse reads a version such as "1.4.2", "v2.0.0-rc.1" or "1.2". Missing minor and patch numbers
+// are zero.
+func Parse(s string) (Version, error) {
+	v, parts, err := parse(s, false)
+	if err != nil {
+		return Version{}, err
+	}
+	if parts == 0 {
+		return Version{}, fmt.Errorf("version: invalid version %q", s)
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics on invalid versions. It's intended for constants.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// parse reads a version which may be partial ("1.2") and returns how many numbers were given.
+// Wildcards ("1.x", "1.*") are only accepted when allowed and end the version.
+func parse(s string, wildcards bool) (Version, int, error) {
+	invalid := fmt.Errorf("version: invalid version %q", s)
+
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	var v Version
+	if idx := strings.Index(str, "+"); idx >= 0 {
+		v.Build = str[idx+1:]
+		str = str[:idx]
+		if !validIdentifiers(v.Build, false) {
+			return Version{}, 0, invalid
+		}
+	}
+	if idx := strings.Index(str, "-"); idx >= 0 {
+		v.Prerelease = str[idx+1:]
+		str = str[:idx]
+		if !validIdentifiers(v.Prerelease, true) {
+			return Version{}, 0, invalid
+		}
+	}
+
+	numbers := strings.Split(str, ".")
+	if len(numbers) > 3 {
+		return Version{}, 0, invalid
+	}
+	parts := 0
+	for i, n := range numbers {
+		if wildcards && (n == "x" || n == "X" || n == "*") {
+			if i != len(numbers)-1 || v.Prerelease != "" {
+				return Version{}, 0, invalid
+			}
+			break
+		}
+		num, err := number(n)
+		if err != nil {
+			return Version{}, 0, invalid
+		}
+		switch i {
+		case 0:
+			v.Major = num
+		case 1:
+			v.Minor = num
+		case 2:
+			v.Patch = num
+		}
+		parts++
+	}
+	if v.Prerelease != "" && parts != 3 {
+		return Version{}, 0, invalid
+	}
+	return v, parts, nil
+}
+
+func number(s string) (int, error) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+func validIdentifiers(s string, prerelease bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		numeric := true
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+		if prerelease && numeric && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 when v has lower, equal or higher precedence than other.
+func (v Version) Compare(other Version) int {
+	if c := compareInt(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// LessThan returns true when v has lower precedence than other
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease orders prereleases before their release and compares identifiers in order,
+// numeric identifiers numerically and before alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, anum := numeric(as[i])
+		bn, bnum := numeric(bs[i])
+		switch {
+		case anum && bnum:
+			if c := compareInt(an, bn); c != 0 {
+				return c
+			}
+		case anum:
+			return -1
+		case bnum:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// numeric parses an identifier made only of digits. strconv.Atoi alone would read the
+// alphanumeric identifier "-1" as a number.
+func numeric(id string) (int, bool) {
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(id)
+	return n, err == nil
+}
+
+// MarshalText encodes the version as its String
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses a version
+func (v *Version) UnmarshalText(data []byte) error {
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
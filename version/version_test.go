@@ -0,0 +1,103 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package version
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.4.2-rc.1+build.5")
+	require.NoError(t, err)
+	require.Equal(t, Version{Major: 1, Minor: 4, Patch: 2, Prerelease: "rc.1", Build: "build.5"}, v)
+	require.Equal(t, "1.4.2-rc.1+build.5", v.String())
+
+	v, err = Parse("2.1")
+	require.NoError(t, err)
+	require.Equal(t, "2.1.0", v.String())
+
+	for _, invalid := range []string{"", "v", "1.2.3.4", "01.2.3", "1.2.x", "1.-2", "1.2.3-", "1.2.3-01", "1.2-rc.1", "1.2.3+", "1.2.3-a..b", "one"} {
+		_, err := Parse(invalid)
+		require.Error(t, err, invalid)
+	}
+	require.Panics(t, func() { MustParse("bad") })
+}
+
+func TestCompare(t *testing.T) {
+	// in ascending order, from semver.org with numeric and "-1" identifiers added
+	ordered := []string{
+		"1.0.0-1", "1.0.0--1",
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0", "10.0.0",
+	}
+	versions := make([]Version, len(ordered))
+	for i := range ordered {
+		versions[len(ordered)-1-i] = MustParse(ordered[i])
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].LessThan(versions[j]) })
+	for i := range versions {
+		require.Equal(t, ordered[i], versions[i].String())
+	}
+
+	require.Equal(t, 0, MustParse("1.0.0+a").Compare(MustParse("1.0.0+b")))
+}
+
+func TestConstraint(t *testing.T) {
+	cases := map[string]map[string]bool{
+		"1.2.3":          {"1.2.3": true, "1.2.4": false},
+		"^1.2":           {"1.2.0": true, "1.9.9": true, "2.0.0": false, "1.1.9": false, "1.3.0-rc.1": false},
+		"^0.2.3":         {"0.2.3": true, "0.2.9": true, "0.3.0": false},
+		"^0.0.3":         {"0.0.3": true, "0.0.4": false},
+		"~1.2.3":         {"1.2.3": true, "1.2.9": true, "1.3.0": false},
+		"~1":             {"1.9.0": true, "2.0.0": false},
+		">=1.4, <2":      {"1.4.0": true, "1.99.0": true, "2.0.0": false, "1.3.9": false},
+		">1.2 <=1.4":     {"1.2.9": false, "1.3.0": true, "1.4.5": true, "1.5.0": false},
+		"1.x || >=3.1":   {"1.0.0": true, "2.0.0": false, "3.1.0": true},
+		"*":              {"0.0.1": true, "99.0.0": true},
+		"!=1.2":          {"1.1.9": true, "1.2.5": false, "1.3.0": true},
+		">=2.0.0-rc.1":   {"2.0.0-rc.2": true, "2.0.0-beta": false, "2.0.0": true, "2.1.0-rc.1": false},
+		">=1.0.0-1":      {"1.0.0-0": false, "1.0.0-2": true, "1.0.0--1": true, "1.0.0": true},
+		">= 1.4, < 2":    {"1.4.0": true, "1.3.9": false, "2.0.0": false},
+		"^ 1.2 || = 3.0": {"1.5.0": true, "3.0.0": true, "3.1.0": false},
+		"=2.0.0-rc.1 ||": nil,
+	}
+	for constraint, versions := range cases {
+		c, err := ParseConstraint(constraint)
+		if versions == nil {
+			require.Error(t, err, constraint)
+			continue
+		}
+		require.NoError(t, err, constraint)
+		require.Equal(t, constraint, c.String())
+		for v, expected := range versions {
+			require.Equal(t, expected, c.Check(MustParse(v)), "%s %s", constraint, v)
+		}
+	}
+
+	for _, invalid := range []string{"", "^", ">=x", "~1.2.3.4", ">1.*"} {
+		_, err := ParseConstraint(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type partner struct {
+		MinClient Version    `json:"minClient"`
+		Supported Constraint `json:"supported"`
+	}
+	var p partner
+	require.NoError(t, json.Unmarshal([]byte(`{"minClient":"v2.3.0","supported":"^2.3"}`), &p))
+	require.True(t, p.Supported.Check(p.MinClient))
+
+	bs, err := json.Marshal(p)
+	require.NoError(t, err)
+	require.Equal(t, `{"minClient":"2.3.0","supported":"^2.3"}`, string(bs))
+
+	require.Error(t, json.Unmarshal([]byte(`{"minClient":"two"}`), &p))
+}
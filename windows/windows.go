@@ -0,0 +1,240 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package windows parses schedules of when operations are active, such as submission or support hours.
+//
+//	hours, err := windows.Parse("Mon-Fri 08:00-17:00 America/New_York; Sat 09:00-12:00 America/New_York")
+//	if hours.Contains(base.Now()) {
+//		...
+//	}
+//
+// Each window is a set of days, a time range and an optional IANA time zone (America/New_York when
+// it's left out) separated by spaces. Days are listed with commas ("Mon,Wed,Fri"), as ranges ("Mon-Fri"),
+// as "daily" or as "banking" for Federal Reserve banking days. A range ending before it starts, such
+// as "22:00-02:00", ends the following day. Windows are joined with semicolons.
+package windows
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/base"
+)
+
+// Schedule is the union of one or more windows. It's immutable and safe for concurrent use.
+type Schedule struct {
+	expr    string
+	windows []window
+}
+
+type window struct {
+	days    [7]bool // by time.Weekday
+	banking bool
+
+	start, end time.Duration // since midnight, end may be past 24h
+	loc        *time.Location
+}
+
+// Parse reads a Schedule from expr
+func Parse(expr string) (*Schedule, error) {
+	s := &Schedule{expr: strings.TrimSpace(expr)}
+	for _, part := range strings.Split(expr, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		w, err := parseWindow(part)
+		if err != nil {
+			return nil, fmt.Errorf("windows: %q: %v", strings.TrimSpace(part), err)
+		}
+		s.windows = append(s.windows, w)
+	}
+	if len(s.windows) == 0 {
+		return nil, errors.New("windows: empty schedule")
+	}
+	return s, nil
+}
+
+// MustParse is like Parse but panics on invalid expressions. It's meant for package level variables.
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the expression the Schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseWindow(expr string) (window, error) {
+	fields := strings.Fields(expr)
+	if len(fields) < 2 || len(fields) > 3 {
+		return window{}, errors.New("expected days, a time range and an optional time zone")
+	}
+
+	var w window
+	if err := w.parseDays(strings.ToLower(fields[0])); err != nil {
+		return window{}, err
+	}
+
+	start, end, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return window{}, fmt.Errorf("invalid time range %q", fields[1])
+	}
+	var err error
+	if w.start, err = parseClock(start); err != nil {
+		return window{}, err
+	}
+	if w.end, err = parseClock(end); err != nil {
+		return window{}, err
+	}
+	if w.start >= 24*time.Hour {
+		return window{}, fmt.Errorf("invalid start %q", start)
+	}
+	if w.end == w.start {
+		return window{}, fmt.Errorf("empty time range %q", fields[1])
+	}
+	if w.end < w.start {
+		w.end += 24 * time.Hour
+	}
+
+	zone := "America/New_York"
+	if len(fields) == 3 {
+		zone = fields[2]
+	}
+	if w.loc, err = time.LoadLocation(zone); err != nil {
+		return window{}, fmt.Errorf("invalid time zone %q", zone)
+	}
+	return w, nil
+}
+
+func (w *window) parseDays(days string) error {
+	switch days {
+	case "daily", "*":
+		for i := range w.days {
+			w.days[i] = true
+		}
+		return nil
+	case "banking":
+		w.banking = true
+		return nil
+	}
+	for _, part := range strings.Split(days, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return fmt.Errorf("invalid day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return fmt.Errorf("invalid day %q", to)
+			}
+		}
+		// ranges may wrap around the week, i.e. Fri-Mon
+		for d := first; ; d = (d + 1) % 7 {
+			w.days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock reads HH:MM, allowing 24:00 for the end of a day
+func parseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok || len(hh) != 2 || len(mm) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// activeOn reports whether a window starts on the day of d
+func (w window) activeOn(d time.Time) bool {
+	if w.banking {
+		return base.DateOf(d).IsBankingDay()
+	}
+	return w.days[d.Weekday()]
+}
+
+// bounds returns when the window starting on the date of day opens and closes.
+func (w window) bounds(day time.Time) (time.Time, time.Time) {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, int(w.start), w.loc)
+	end := time.Date(y, m, d, 0, 0, 0, int(w.end), w.loc)
+	return start, end
+}
+
+// Contains reports whether t is within one of the Schedule's windows
+func (s *Schedule) Contains(t base.Time) bool {
+	for _, w := range s.windows {
+		local := t.Time.In(w.loc)
+		// windows crossing midnight may have started the day before
+		for _, day := range []time.Time{local.AddDate(0, 0, -1), local} {
+			if !w.activeOn(day) {
+				continue
+			}
+			start, end := w.bounds(day)
+			if !t.Time.Before(start) && t.Time.Before(end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maxSearchDays bounds how far ahead Next looks, enough to pass long holiday weekends
+const maxSearchDays = 31
+
+// Next returns the earliest time at or after t within the Schedule, which is t when Contains(t).
+// False is returned when no window opens in the following month.
+func (s *Schedule) Next(t base.Time) (base.Time, bool) {
+	if s.Contains(t) {
+		return t, true
+	}
+	var next time.Time
+	for _, w := range s.windows {
+		local := t.Time.In(w.loc)
+		for i := 0; i <= maxSearchDays; i++ {
+			day := local.AddDate(0, 0, i)
+			if !w.activeOn(day) {
+				continue
+			}
+			start, _ := w.bounds(day)
+			if start.Before(t.Time) {
+				continue
+			}
+			if next.IsZero() || start.Before(next) {
+				next = start
+			}
+			break
+		}
+	}
+	if next.IsZero() {
+		return base.Time{}, false
+	}
+	return base.NewTime(next), true
+}
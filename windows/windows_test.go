@@ -0,0 +1,101 @@
+// Copyright 2020 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package windows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/base"
+
+	"github.com/stretchr/testify/require"
+)
+
+var est, _ = time.LoadLocation("America/New_York")
+
+func at(year int, month time.Month, day, hour, min int, loc *time.Location) base.Time {
+	return base.NewTime(time.Date(year, month, day, hour, min, 0, 0, loc))
+}
+
+func TestSchedule__Contains(t *testing.T) {
+	hours := MustParse("Mon-Fri 08:00-17:00 America/New_York; Sat 09:00-12:00")
+
+	// Thursday 2021-03-04
+	require.True(t, hours.Contains(at(2021, time.March, 4, 8, 0, est)))
+	require.True(t, hours.Contains(at(2021, time.March, 4, 16, 59, est)))
+	require.False(t, hours.Contains(at(2021, time.March, 4, 17, 0, est)))
+	require.False(t, hours.Contains(at(2021, time.March, 4, 7, 59, est)))
+	require.True(t, hours.Contains(at(2021, time.March, 4, 14, 0, time.UTC)))
+
+	require.True(t, hours.Contains(at(2021, time.March, 6, 11, 0, est)))
+	require.False(t, hours.Contains(at(2021, time.March, 6, 13, 0, est)))
+	require.False(t, hours.Contains(at(2021, time.March, 7, 10, 0, est)))
+}
+
+func TestSchedule__Overnight(t *testing.T) {
+	batch := MustParse("Fri-Sun 22:00-02:00 UTC")
+
+	require.True(t, batch.Contains(at(2021, time.March, 5, 23, 0, time.UTC)))
+	require.True(t, batch.Contains(at(2021, time.March, 6, 1, 0, time.UTC)))
+	require.True(t, batch.Contains(at(2021, time.March, 8, 1, 59, time.UTC)))
+	require.False(t, batch.Contains(at(2021, time.March, 8, 2, 0, time.UTC)))
+	require.False(t, batch.Contains(at(2021, time.March, 5, 1, 0, time.UTC)))
+}
+
+func TestSchedule__Banking(t *testing.T) {
+	submissions := MustParse("banking 00:00-24:00")
+
+	require.True(t, submissions.Contains(at(2020, time.November, 10, 12, 0, est)))
+	// Veterans Day
+	require.False(t, submissions.Contains(at(2020, time.November, 11, 12, 0, est)))
+
+	next, ok := submissions.Next(at(2020, time.November, 11, 0, 0, est))
+	require.True(t, ok)
+	require.True(t, next.Time.Equal(time.Date(2020, time.November, 12, 0, 0, 0, 0, est)), next.Time.String())
+}
+
+func TestSchedule__Next(t *testing.T) {
+	hours := MustParse("Mon-Fri 08:00-17:00; Sat 09:00-12:00")
+
+	now := at(2021, time.March, 4, 10, 0, est)
+	next, ok := hours.Next(now)
+	require.True(t, ok)
+	require.Equal(t, now, next)
+
+	next, ok = hours.Next(at(2021, time.March, 4, 18, 0, est))
+	require.True(t, ok)
+	require.True(t, next.Time.Equal(time.Date(2021, time.March, 5, 8, 0, 0, 0, est)))
+
+	next, ok = hours.Next(at(2021, time.March, 5, 18, 0, est))
+	require.True(t, ok)
+	require.True(t, next.Time.Equal(time.Date(2021, time.March, 6, 9, 0, 0, 0, est)))
+
+	// across the spring DST change
+	next, ok = hours.Next(at(2021, time.March, 13, 13, 0, est))
+	require.True(t, ok)
+	require.True(t, next.Time.Equal(time.Date(2021, time.March, 15, 8, 0, 0, 0, est)))
+	require.Equal(t, 12, next.Time.UTC().Hour())
+}
+
+func TestParse__Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		";",
+		"Mon-Fri",
+		"Mon-Fri 08:00",
+		"Mon-Fri 8:00-17:00",
+		"Mon-Fri 08:00-25:00",
+		"Mon-Fri 08:00-08:00",
+		"Mon-Fri 24:00-08:00",
+		"Mon-Fry 08:00-17:00",
+		"Mon-Fri 08:00-17:00 Mars/Olympus",
+		"Mon-Fri 08:00-17:00 UTC extra",
+	} {
+		_, err := Parse(expr)
+		require.Error(t, err, expr)
+	}
+	require.Panics(t, func() { MustParse("") })
+	require.Equal(t, "daily 00:00-24:00", MustParse("  daily 00:00-24:00 ").String())
+}